@@ -1,35 +1,235 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"resume_backend/internal/apikey"
 	"resume_backend/internal/config"
 	httptransport "resume_backend/internal/http"
+	"resume_backend/internal/jobs"
 	"resume_backend/internal/latexclient"
+	"resume_backend/internal/pdfdoc"
+	"resume_backend/internal/ratelimit"
+	"resume_backend/internal/render"
 	"resume_backend/internal/resume"
+	"resume_backend/internal/share"
+	"resume_backend/internal/storage/filedb"
+	"resume_backend/internal/storage/fsstore"
+
+	"resume_contract/requestid"
 )
 
 func main() {
+	// JSON-логи; записи в контексте запроса получают его request_id
+	logger := slog.New(requestid.NewHandler(slog.NewJSONHandler(os.Stdout, nil)))
+	slog.SetDefault(logger)
+
 	cfg := config.Load()
+	latexAddr := strings.Join(cfg.LaTeXServiceURLs, ", ")
+	if cfg.LaTeXTransport == "grpc" {
+		latexAddr = "grpc " + cfg.LaTeXGRPCTarget
+	}
+	logger.Info("starting resume-backend", "addr", cfg.HTTPAddr, "latex_service", latexAddr)
+
+	// Клиент к узлам latex-service с балансировкой, повторами и предохранителем
+	latexClient, err := newLaTeXClient(cfg, logger)
+	if err != nil {
+		fatal(logger, "failed to create latex-service client", err)
+	}
 
-	logger := log.Default()
-	logger.Printf(
-		"starting resume-backend on %s (latex-service: %s)",
-		cfg.HTTPAddr,
-		cfg.LaTeXServiceURL,
+	// Доменный сервис резюме, который валидирует данные и зовёт latex-service;
+	// остальные форматы рендерятся прямо в backend
+	resumeService := resume.NewService(
+		latexClient,
+		logger,
+		render.NewHTMLRenderer(),
+		render.NewMarkdownRenderer(),
+		render.NewTextRenderer(),
+		render.NewDOCXRenderer(),
 	)
 
-	// HTTP-клиент к latex-service
-	latexClient := latexclient.NewClient(cfg.LaTeXServiceURL, logger)
+	// Запасной PDF-рендер на время, пока latex-service отключён предохранителем
+	if cfg.PDFFallback {
+		fallback, err := newFallbackRenderer(cfg)
+		if err != nil {
+			fatal(logger, "failed to load fallback PDF fonts", err)
+		}
+		resumeService.SetFallback(fallback)
+	}
+
+	// Хранилище сохранённых резюме
+	repo, err := openRepository(cfg, logger)
+	if err != nil {
+		fatal(logger, "failed to open storage", err)
+	}
+	logger.Info("resume storage opened", "driver", cfg.StorageDriver, "path", cfg.StoragePath)
 
-	// Доменный сервис резюме, который валидирует данные и зовёт latex-service
-	resumeService := resume.NewService(latexClient, logger)
+	// Публичные ссылки на сохранённые резюме
+	shareKey, err := share.LoadOrCreateKey(cfg.ShareKeyPath)
+	if err != nil {
+		fatal(logger, "failed to load share key", err)
+	}
+	shares, err := share.Open(cfg.SharePath, shareKey, cfg.ShareFlushInterval, logger)
+	if err != nil {
+		fatal(logger, "failed to open share links", err)
+	}
+
+	// Очередь фоновых задач рендера
+	jobQueue := jobs.NewManager(jobs.Config{
+		Workers:   cfg.JobWorkers,
+		QueueSize: cfg.JobQueueSize,
+		TTL:       cfg.JobTTL,
+		Timeout:   cfg.JobTimeout,
+	}, logger)
+
+	// API-ключи и учёт их использования
+	var keys *apikey.Store
+	if cfg.APIKeysPath != "" {
+		keys, err = apikey.Open(cfg.APIKeysPath, cfg.APIUsagePath, cfg.APIUsageFlushInterval, logger)
+		if err != nil {
+			fatal(logger, "failed to load API keys", err)
+		}
+		logger.Info("API keys loaded", "keys", keys.Len(), "required", cfg.APIKeysRequired, "usage_path", cfg.APIUsagePath)
+	}
 
 	// HTTP-слой (REST API)
-	server := httptransport.NewServer(resumeService, logger)
+	httpCfg := httptransport.Config{
+		BatchMaxItems:    cfg.BatchMaxItems,
+		BatchConcurrency: cfg.BatchConcurrency,
+		MaxBodySize:      int64(cfg.MaxBodySize),
+		MaxBatchBodySize: int64(cfg.MaxBatchBodySize),
+		MaxPhotoSize:     int64(cfg.MaxPhotoSize),
+		RequireAPIKey:    cfg.APIKeysRequired,
+	}
+	httpCfg.TrustedProxies, err = ratelimit.ParseProxies(cfg.TrustedProxies)
+	if err != nil {
+		fatal(logger, "invalid TRUSTED_PROXIES", err)
+	}
+	if cfg.RateLimit {
+		httpCfg.RateLimitPDF = ratelimit.Limit{
+			Rate:        float64(cfg.RateLimitPDF) / 60,
+			Burst:       cfg.RateLimitPDFBurst,
+			MaxInFlight: cfg.RateLimitPDFConcurrency,
+		}
+		httpCfg.RateLimitValidate = ratelimit.Limit{
+			Rate:  float64(cfg.RateLimitValidate) / 60,
+			Burst: cfg.RateLimitValidateBurst,
+		}
+	}
+	server := httptransport.NewServer(resumeService, repo, shares, jobQueue, keys, httpCfg, logger)
+
+	// По SIGINT/SIGTERM сервер дожидается текущих запросов, после чего
+	// учёт API-ключей сбрасывается в файл
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	if err := http.ListenAndServe(cfg.HTTPAddr, server); err != nil {
-		logger.Fatalf("server exited with error: %v", err)
+	srv := &http.Server{Addr: cfg.HTTPAddr, Handler: server}
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			logger.Error("server shutdown failed", "error", err)
+		}
+	}()
+
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		fatal(logger, "server exited with error", err)
+	}
+	// ListenAndServe возвращается сразу, Shutdown — после текущих запросов
+	<-stopped
+	if err := shares.Close(); err != nil {
+		logger.Error("failed to save share links", "error", err)
 	}
+	if keys != nil {
+		if err := keys.Close(); err != nil {
+			logger.Error("failed to save API key usage", "error", err)
+		}
+	}
+	logger.Info("resume-backend stopped")
+}
+
+// shutdownTimeout — сколько сервер ждёт текущие запросы при остановке.
+const shutdownTimeout = 30 * time.Second
+
+// newLaTeXClient создаёт клиент latex-service для протокола из конфигурации.
+func newLaTeXClient(cfg config.Config, logger *slog.Logger) (resume.PDFRenderer, error) {
+	clientCfg := latexclient.Config{
+		MaxAttempts:      cfg.LaTeXRetryAttempts,
+		BaseBackoff:      cfg.LaTeXRetryBackoff,
+		MaxBackoff:       cfg.LaTeXRetryMaxBackoff,
+		BreakerThreshold: cfg.LaTeXBreakerThreshold,
+		BreakerCooldown:  cfg.LaTeXBreakerCooldown,
+		HedgeDelay:       cfg.LaTeXHedgeDelay,
+		HealthInterval:   cfg.LaTeXHealthInterval,
+		MaxOutputSize:    int64(cfg.MaxPDFSize),
+		MaxRequestSize:   int64(cfg.LaTeXMaxRequestSize),
+	}
+
+	switch cfg.LaTeXTransport {
+	case "http":
+		return latexclient.NewClient(cfg.LaTeXServiceURLs, clientCfg, logger), nil
+	case "grpc":
+		return latexclient.NewGRPCClient(cfg.LaTeXGRPCTarget, clientCfg, logger)
+	default:
+		return nil, fmt.Errorf("unknown LATEX_TRANSPORT %q (want http or grpc)", cfg.LaTeXTransport)
+	}
+}
+
+// openRepository выбирает реализацию resume.Repository по конфигурации.
+func openRepository(cfg config.Config, logger *slog.Logger) (resume.Repository, error) {
+	switch cfg.StorageDriver {
+	case "fs":
+		return fsstore.Open(cfg.StoragePath)
+	case "filedb":
+		return filedb.Open(cfg.StoragePath, logger)
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_DRIVER %q (want fs or filedb)", cfg.StorageDriver)
+	}
+}
+
+// newFallbackRenderer создаёт запасной PDF-рендерер со шрифтами из
+// конфигурации; незаданный шрифт заменяется встроенным.
+func newFallbackRenderer(cfg config.Config) (*render.PDFRenderer, error) {
+	load := func(path string) (*pdfdoc.TrueType, error) {
+		if path == "" {
+			return nil, nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		tt, err := pdfdoc.ParseTrueType(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return tt, nil
+	}
+
+	regular, err := load(cfg.PDFFallbackFont)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := load(cfg.PDFFallbackBoldFont)
+	if err != nil {
+		return nil, err
+	}
+	return render.NewPDFRenderer(regular, bold), nil
+}
+
+// fatal пишет ошибку в лог и завершает процесс.
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
 }
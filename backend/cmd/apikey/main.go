@@ -0,0 +1,43 @@
+// Команда apikey создаёт новый API-ключ: печатает сам ключ (его нужно
+// передать клиенту, сервер его не хранит) и запись для файла API_KEYS_PATH.
+//
+//	go run ./cmd/apikey -id partner-hr -name "HR-портал" -scopes render,batch
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"resume_backend/internal/apikey"
+)
+
+func main() {
+	id := flag.String("id", "", "идентификатор ключа (обязателен)")
+	name := flag.String("name", "", "описание владельца ключа")
+	scopes := flag.String("scopes", string(apikey.ScopeRender), "права через запятую: render, batch, admin")
+	flag.Parse()
+
+	if *id == "" {
+		log.Fatal("apikey: -id is required")
+	}
+
+	raw, err := apikey.Generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	k := apikey.Key{ID: *id, Name: *name, Hash: apikey.Hash(raw)}
+	for _, s := range strings.Split(*scopes, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			k.Scopes = append(k.Scopes, apikey.Scope(s))
+		}
+	}
+
+	entry, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("key: %s\n\n%s\n", raw, entry)
+}
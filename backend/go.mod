@@ -1,3 +1,21 @@
 module resume_backend
 
 go 1.22
+
+require (
+	github.com/BurntSushi/toml v1.5.0
+	golang.org/x/crypto v0.31.0
+	google.golang.org/grpc v1.70.0
+	gopkg.in/yaml.v3 v3.0.1
+	resume_contract v0.0.0
+)
+
+require (
+	golang.org/x/net v0.32.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20241202173237-19429a94021a // indirect
+	google.golang.org/protobuf v1.36.6 // indirect
+)
+
+replace resume_contract => ../contract
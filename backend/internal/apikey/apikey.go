@@ -0,0 +1,164 @@
+// Package apikey — API-ключи для внутренних инструментов и команд-партнёров.
+// Ключи описываются в JSON-файле конфигурации: там хранится только хеш
+// ключа, его права (scopes), лимит запросов и месячная квота. Учёт
+// использования (рендеры и байты по месяцам) ведётся в отдельном файле.
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Scope — право ключа.
+type Scope string
+
+const (
+	// ScopeRender — рендер одиночных документов (PDF и другие форматы,
+	// ATS-проверка, diff, фоновые задачи).
+	ScopeRender Scope = "render"
+	// ScopeBatch — пакетный рендер и импорт CSV.
+	ScopeBatch Scope = "batch"
+	// ScopeAdmin — всё остальное и использование всех ключей.
+	ScopeAdmin Scope = "admin"
+)
+
+var (
+	// ErrInvalidKey — ключа нет в конфигурации.
+	ErrInvalidKey = errors.New("invalid API key")
+	// ErrDisabled — ключ отключён в конфигурации.
+	ErrDisabled = errors.New("API key is disabled")
+)
+
+// hashPrefix — схема хеша в поле hash.
+const hashPrefix = "sha256:"
+
+// RateLimit — лимит запросов рендера для ключа вместо общего.
+type RateLimit struct {
+	PerMinute   int `json:"perMinute"`
+	Burst       int `json:"burst"`
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// Quota — месячная квота ключа; нулевое поле — без ограничения.
+type Quota struct {
+	Renders int64 `json:"renders,omitempty"`
+	Bytes   int64 `json:"bytes,omitempty"`
+}
+
+// Key — ключ в том виде, в каком он описан в файле конфигурации.
+type Key struct {
+	ID       string     `json:"id"`
+	Name     string     `json:"name,omitempty"`
+	Hash     string     `json:"hash"`
+	Scopes   []Scope    `json:"scopes"`
+	Disabled bool       `json:"disabled,omitempty"`
+	Limit    *RateLimit `json:"rateLimit,omitempty"`
+	Quota    *Quota     `json:"monthlyQuota,omitempty"`
+}
+
+// Allows сообщает, есть ли у ключа право scope; admin включает все права.
+func (k *Key) Allows(scope Scope) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type configFile struct {
+	Keys []*Key `json:"keys"`
+}
+
+// loadKeys читает ключи из файла и проверяет их описание. Ключи
+// индексируются по хешу: сам ключ сервер не хранит.
+func loadKeys(path string) (map[string]*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read API keys: %w", err)
+	}
+	var cf configFile
+	if err := json.Unmarshal(data, &cf); err != nil {
+		return nil, fmt.Errorf("decode API keys %s: %w", path, err)
+	}
+
+	byHash := make(map[string]*Key, len(cf.Keys))
+	ids := make(map[string]bool, len(cf.Keys))
+	for i, k := range cf.Keys {
+		switch {
+		case k.ID == "":
+			return nil, fmt.Errorf("API key #%d: id is required", i+1)
+		case ids[k.ID]:
+			return nil, fmt.Errorf("API key %q: duplicate id", k.ID)
+		case !validHash(k.Hash):
+			return nil, fmt.Errorf("API key %q: hash must be %s followed by 64 hex digits", k.ID, hashPrefix)
+		case byHash[strings.ToLower(k.Hash)] != nil:
+			return nil, fmt.Errorf("API key %q: duplicate hash", k.ID)
+		}
+		for _, s := range k.Scopes {
+			if s != ScopeRender && s != ScopeBatch && s != ScopeAdmin {
+				return nil, fmt.Errorf("API key %q: unknown scope %q", k.ID, s)
+			}
+		}
+		ids[k.ID] = true
+		byHash[strings.ToLower(k.Hash)] = k
+	}
+	return byHash, nil
+}
+
+func validHash(h string) bool {
+	hexPart, ok := strings.CutPrefix(strings.ToLower(h), hashPrefix)
+	if !ok || len(hexPart) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(hexPart)
+	return err == nil
+}
+
+// Hash — значение поля hash для ключа raw.
+func Hash(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hashPrefix + hex.EncodeToString(sum[:])
+}
+
+// Generate создаёт новый случайный ключ.
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate API key: %w", err)
+	}
+	return "rk_" + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type ctxKey struct{}
+
+// WithKey возвращает контекст с ключом, которым аутентифицирован запрос.
+func WithKey(ctx context.Context, k *Key) context.Context {
+	return context.WithValue(ctx, ctxKey{}, k)
+}
+
+// FromContext возвращает ключ запроса; ok == false для анонимного запроса.
+func FromContext(ctx context.Context) (*Key, bool) {
+	k, ok := ctx.Value(ctxKey{}).(*Key)
+	return k, ok
+}
+
+// monthOf — учётный месяц (UTC) в виде "2006-01".
+func monthOf(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// nextMonth — начало следующего учётного месяца.
+func nextMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,384 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// historyMonths — сколько месяцев учёта хранится в файле.
+const historyMonths = 12
+
+// defaultFlushInterval — как часто учёт сбрасывается в файл по умолчанию.
+const defaultFlushInterval = 10 * time.Second
+
+// Period — использование ключа за учётный месяц.
+type Period struct {
+	Month   string `json:"month"`
+	Renders int64  `json:"renders"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// Usage — использование ключа в ответе API.
+type Usage struct {
+	KeyID string `json:"keyId"`
+	Name  string `json:"name,omitempty"`
+	// Current — текущий месяц, History — предыдущие, от новых к старым.
+	Current Period   `json:"current"`
+	Quota   *Quota   `json:"monthlyQuota,omitempty"`
+	History []Period `json:"history,omitempty"`
+	// ResetAt — когда начнётся следующий учётный месяц.
+	ResetAt time.Time `json:"resetAt"`
+}
+
+// QuotaExceededError — месячная квота ключа исчерпана.
+type QuotaExceededError struct {
+	KeyID   string
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("monthly quota of API key %q exceeded until %s", e.KeyID, e.ResetAt.Format(time.RFC3339))
+}
+
+// Store проверяет ключи и ведёт учёт их использования. Учёт меняется в
+// памяти, а в файл сбрасывается периодически и при Close: запросы не ждут
+// записи на диск.
+type Store struct {
+	keys   map[string]*Key // по хешу
+	path   string
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	usage map[string][]Period // по id ключа, от новых месяцев к старым
+	gen   uint64              // номер изменения учёта
+
+	fileMu  sync.Mutex // упорядочивает запись файла
+	written uint64     // gen последнего записанного снимка
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type usageFile struct {
+	Usage map[string][]Period `json:"usage"`
+}
+
+// Open читает ключи из keysPath и учёт использования из usagePath
+// (отсутствующий файл учёта — пустой учёт) и запускает сброс учёта в файл
+// раз в flushInterval (<= 0 — 10 секунд). Store нужно закрыть (Close),
+// чтобы сохранить последние изменения.
+func Open(keysPath, usagePath string, flushInterval time.Duration, logger *slog.Logger) (*Store, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+
+	keys, err := loadKeys(keysPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(usagePath), 0o755); err != nil {
+		return nil, fmt.Errorf("create API usage dir: %w", err)
+	}
+
+	s := &Store{
+		keys:   keys,
+		path:   usagePath,
+		now:    time.Now,
+		logger: logger,
+		usage:  make(map[string][]Period),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	data, err := os.ReadFile(usagePath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("read API usage: %w", err)
+	default:
+		var uf usageFile
+		if err := json.Unmarshal(data, &uf); err != nil {
+			return nil, fmt.Errorf("decode API usage %s: %w", usagePath, err)
+		}
+		if uf.Usage != nil {
+			s.usage = uf.Usage
+		}
+	}
+
+	go s.flushLoop(flushInterval)
+	return s, nil
+}
+
+// Close останавливает периодический сброс и записывает учёт в файл.
+func (s *Store) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	<-s.done
+	return s.flush()
+}
+
+// Len возвращает число ключей в конфигурации.
+func (s *Store) Len() int {
+	return len(s.keys)
+}
+
+// Authenticate находит ключ по его значению.
+func (s *Store) Authenticate(raw string) (*Key, error) {
+	k, ok := s.keys[Hash(raw)]
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	if k.Disabled {
+		return nil, ErrDisabled
+	}
+	return k, nil
+}
+
+// Reservation — рендеры, заранее учтённые Reserve. Запрос завершает её
+// ровно один раз: Commit с фактическим использованием или Release при
+// неудаче.
+type Reservation struct {
+	store   *Store
+	keyID   string
+	quota   *Quota
+	month   string
+	renders int64
+	done    bool
+}
+
+// Reserve проверяет месячную квоту ключа k и в том же критическом участке
+// учитывает renders рендеров, так что параллельные запросы не превысят
+// квоту вместе. Исчерпанная квота (рендеров или байт) —
+// *QuotaExceededError; при renders == 0 квота не проверяется.
+func (s *Store) Reserve(k *Key, renders int64) (*Reservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if renders > 0 && exceeds(k.Quota, s.current(k.ID, now), renders) {
+		return nil, &QuotaExceededError{KeyID: k.ID, ResetAt: nextMonth(now)}
+	}
+
+	r := &Reservation{store: s, keyID: k.ID, quota: k.Quota, month: monthOf(now), renders: renders}
+	s.add(r.keyID, r.month, renders, 0)
+	return r, nil
+}
+
+// Resize меняет число зарезервированных рендеров, например когда пакет
+// разобран и известно число резюме. Увеличение проверяется по квоте так же,
+// как в Reserve; не поместившееся резервирование не меняется.
+func (r *Reservation) Resize(renders int64) error {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.done {
+		return nil
+	}
+	now := s.now()
+	if delta := renders - r.renders; delta > 0 && exceeds(r.quota, s.current(r.keyID, now), delta) {
+		return &QuotaExceededError{KeyID: r.keyID, ResetAt: nextMonth(now)}
+	}
+	s.add(r.keyID, r.month, renders-r.renders, 0)
+	r.renders = renders
+	return nil
+}
+
+// FitsBytes сообщает, останется ли ключ в месячной квоте байт, если
+// запрос отдаст ещё bytes байт сверх уже учтённых.
+func (r *Reservation) FitsBytes(bytes int64) bool {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.quota == nil || r.quota.Bytes <= 0 {
+		return true
+	}
+	return s.current(r.keyID, s.now()).Bytes+bytes <= r.quota.Bytes
+}
+
+// Commit заменяет зарезервированные рендеры фактическими (например, числом
+// отданных резюме пакета) и учитывает bytes байт ответа.
+func (r *Reservation) Commit(renders, bytes int64) {
+	r.finish(renders, bytes)
+}
+
+// Release отменяет резервирование: запрос не выполнен.
+func (r *Reservation) Release() {
+	r.finish(0, 0)
+}
+
+func (r *Reservation) finish(renders, bytes int64) {
+	s := r.store
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.done {
+		return
+	}
+	r.done = true
+	s.add(r.keyID, r.month, renders-r.renders, bytes)
+}
+
+// exceeds сообщает, выйдет ли использование cur за квоту q, если учесть
+// ещё renders рендеров. Исчерпанная квота байт не пускает новые рендеры.
+func exceeds(q *Quota, cur Period, renders int64) bool {
+	if q == nil {
+		return false
+	}
+	return (q.Renders > 0 && cur.Renders+renders > q.Renders) || (q.Bytes > 0 && cur.Bytes >= q.Bytes)
+}
+
+// add изменяет учёт ключа id за месяц month. Вызывается под s.mu.
+func (s *Store) add(id, month string, renders, bytes int64) {
+	if renders == 0 && bytes == 0 {
+		return
+	}
+	periods := s.usage[id]
+	i := 0
+	for i < len(periods) && periods[i].Month > month {
+		i++
+	}
+	if i == len(periods) || periods[i].Month != month {
+		if i >= historyMonths {
+			// месяц уже вышел из истории
+			return
+		}
+		periods = append(periods[:i], append([]Period{{Month: month}}, periods[i:]...)...)
+		if len(periods) > historyMonths {
+			periods = periods[:historyMonths]
+		}
+	}
+	periods[i].Renders += renders
+	periods[i].Bytes += bytes
+	s.usage[id] = periods
+	s.gen++
+}
+
+// Usage возвращает использование ключа k.
+func (s *Store) Usage(k *Key) Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usageOf(k, s.now())
+}
+
+// All возвращает использование всех ключей, упорядоченное по id.
+func (s *Store) All() []Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	out := make([]Usage, 0, len(s.keys))
+	for _, k := range s.keys {
+		out = append(out, s.usageOf(k, now))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].KeyID < out[j].KeyID })
+	return out
+}
+
+// Lookup находит ключ по id.
+func (s *Store) Lookup(id string) (*Key, bool) {
+	for _, k := range s.keys {
+		if k.ID == id {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// usageOf вызывается под s.mu.
+func (s *Store) usageOf(k *Key, now time.Time) Usage {
+	u := Usage{KeyID: k.ID, Name: k.Name, Quota: k.Quota, Current: s.current(k.ID, now), ResetAt: nextMonth(now)}
+	for _, p := range s.usage[k.ID] {
+		if p.Month != u.Current.Month {
+			u.History = append(u.History, p)
+		}
+	}
+	return u
+}
+
+// current — использование ключа id за месяц now. Вызывается под s.mu.
+func (s *Store) current(id string, now time.Time) Period {
+	month := monthOf(now)
+	if periods := s.usage[id]; len(periods) > 0 && periods[0].Month == month {
+		return periods[0]
+	}
+	return Period{Month: month}
+}
+
+// flushLoop периодически сбрасывает учёт в файл до Close.
+func (s *Store) flushLoop(interval time.Duration) {
+	defer close(s.done)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+			if err := s.flush(); err != nil {
+				s.logger.Error("flush API key usage failed", "path", s.path, "error", err)
+			}
+		}
+	}
+}
+
+// flush записывает снимок учёта, если он изменился с прошлой записи.
+// Снимок делается под s.mu, файл пишется вне его.
+func (s *Store) flush() error {
+	s.mu.Lock()
+	gen := s.gen
+	data, err := json.MarshalIndent(usageFile{Usage: s.usage}, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("encode API usage: %w", err)
+	}
+
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if gen <= s.written {
+		return nil
+	}
+	if err := s.writeFile(data); err != nil {
+		return err
+	}
+	s.written = gen
+	return nil
+}
+
+// writeFile атомарно перезаписывает файл учёта. Вызывается под s.fileMu.
+func (s *Store) writeFile(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write API usage: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync API usage: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close API usage: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("rename API usage: %w", err)
+	}
+	return nil
+}
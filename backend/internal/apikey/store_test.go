@@ -0,0 +1,229 @@
+package apikey
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openStore создаёт Store с одним ключом "k1" и квотой quota; сброс в
+// файл — только при Close.
+func openStore(t *testing.T, quota *Quota) (*Store, *Key, string) {
+	t.Helper()
+	dir := t.TempDir()
+	keysPath := filepath.Join(dir, "keys.json")
+	usagePath := filepath.Join(dir, "usage.json")
+
+	data, err := json.Marshal(configFile{Keys: []*Key{{ID: "k1", Hash: Hash("secret"), Scopes: []Scope{ScopeRender}, Quota: quota}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keysPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(keysPath, usagePath, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	k, err := s.Authenticate("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, k, usagePath
+}
+
+func TestReserveIsAtomic(t *testing.T) {
+	s, k, _ := openStore(t, &Quota{Renders: 5})
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := s.Reserve(k, 1)
+			var qe *QuotaExceededError
+			switch {
+			case errors.As(err, &qe):
+			case err != nil:
+				t.Error(err)
+			default:
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+				res.Commit(1, 10)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reserved != 5 {
+		t.Errorf("reserved = %d, want 5", reserved)
+	}
+	if cur := s.Usage(k).Current; cur.Renders != 5 || cur.Bytes != 50 {
+		t.Errorf("usage = %+v, want 5 renders and 50 bytes", cur)
+	}
+}
+
+func TestReservation(t *testing.T) {
+	tests := []struct {
+		name    string
+		finish  func(*Reservation)
+		renders int64
+		bytes   int64
+	}{
+		{"commit", func(r *Reservation) { r.Commit(1, 100) }, 1, 100},
+		{"commit batch", func(r *Reservation) { r.Commit(3, 100) }, 3, 100},
+		{"release", func(r *Reservation) { r.Release() }, 0, 0},
+		{"release after commit", func(r *Reservation) { r.Commit(1, 100); r.Release() }, 1, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, k, _ := openStore(t, &Quota{Renders: 10})
+			res, err := s.Reserve(k, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Usage(k).Current.Renders; got != 1 {
+				t.Fatalf("renders while reserved = %d, want 1", got)
+			}
+			tt.finish(res)
+			if cur := s.Usage(k).Current; cur.Renders != tt.renders || cur.Bytes != tt.bytes {
+				t.Errorf("usage = %+v, want %d renders and %d bytes", cur, tt.renders, tt.bytes)
+			}
+		})
+	}
+}
+
+func TestReservationAcrossMonths(t *testing.T) {
+	s, k, _ := openStore(t, nil)
+	now := time.Date(2026, 1, 31, 23, 59, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	res, err := s.Reserve(k, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(time.Hour)
+	res.Commit(2, 100)
+
+	u := s.Usage(k)
+	if u.Current.Month != "2026-02" || u.Current.Renders != 0 {
+		t.Errorf("current = %+v, want empty 2026-02", u.Current)
+	}
+	if len(u.History) != 1 || u.History[0] != (Period{Month: "2026-01", Renders: 2, Bytes: 100}) {
+		t.Errorf("history = %+v, want 2026-01 with 2 renders and 100 bytes", u.History)
+	}
+}
+
+func TestCloseFlushesUsage(t *testing.T) {
+	s, k, usagePath := openStore(t, nil)
+	res, err := s.Reserve(k, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Commit(1, 42)
+
+	if _, err := os.Stat(usagePath); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("usage file written before flush: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(usagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var uf usageFile
+	if err := json.Unmarshal(data, &uf); err != nil {
+		t.Fatal(err)
+	}
+	if p := uf.Usage["k1"]; len(p) != 1 || p[0].Renders != 1 || p[0].Bytes != 42 {
+		t.Errorf("saved usage = %+v, want 1 render and 42 bytes", p)
+	}
+}
+
+func TestReservationResize(t *testing.T) {
+	tests := []struct {
+		name    string
+		used    int64 // рендеры, уже учтённые другими запросами
+		resize  int64
+		wantErr bool
+		renders int64 // учтено после Resize
+	}{
+		{"fits", 0, 5, false, 5},
+		{"fits exactly", 5, 5, false, 10},
+		{"over the quota", 6, 5, true, 7},
+		{"shrink", 9, 0, false, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, k, _ := openStore(t, &Quota{Renders: 10})
+			if tt.used > 0 {
+				other, err := s.Reserve(k, tt.used)
+				if err != nil {
+					t.Fatal(err)
+				}
+				other.Commit(tt.used, 0)
+			}
+			res, err := s.Reserve(k, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = res.Resize(tt.resize)
+			var qe *QuotaExceededError
+			if tt.wantErr != errors.As(err, &qe) {
+				t.Fatalf("Resize: err = %v, want quota error: %v", err, tt.wantErr)
+			}
+			if got := s.Usage(k).Current.Renders; got != tt.renders {
+				t.Errorf("renders after Resize = %d, want %d", got, tt.renders)
+			}
+			res.Release()
+			if got := s.Usage(k).Current.Renders; got != tt.used {
+				t.Errorf("renders after Release = %d, want %d", got, tt.used)
+			}
+		})
+	}
+}
+
+func TestReservationFitsBytes(t *testing.T) {
+	s, k, _ := openStore(t, &Quota{Bytes: 100})
+	first, err := s.Reserve(k, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.Commit(1, 60)
+
+	res, err := s.Reserve(k, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.FitsBytes(40) {
+		t.Error("FitsBytes(40) = false with 40 bytes left")
+	}
+	if res.FitsBytes(41) {
+		t.Error("FitsBytes(41) = true with 40 bytes left")
+	}
+
+	unlimited, other, _ := openStore(t, nil)
+	res, err = unlimited.Reserve(other, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.FitsBytes(1 << 40) {
+		t.Error("FitsBytes = false without a quota")
+	}
+}
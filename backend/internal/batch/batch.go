@@ -0,0 +1,178 @@
+// Package batch разбирает вход пакетного рендера (JSON-массив или NDJSON)
+// и описывает отчёт report.json. Каждый элемент декодируется независимо:
+// ошибка в одном резюме не мешает остальным.
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"resume_backend/internal/resume"
+)
+
+// ContentTypeNDJSON — тип тела с одним резюме на строку.
+const ContentTypeNDJSON = "application/x-ndjson"
+
+// maxLineSize — предельная длина строки NDJSON (резюме с фото в base64).
+const maxLineSize = 8 << 20
+
+// Статусы элементов в отчёте.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// TooManyItemsError возвращается, если во входе больше Max резюме.
+type TooManyItemsError struct {
+	Max int
+}
+
+func (e *TooManyItemsError) Error() string {
+	return fmt.Sprintf("batch contains more than %d resumes", e.Max)
+}
+
+// Item — элемент пакета. Если Err != nil, документ не удалось разобрать,
+// и Resume пуст.
+type Item struct {
+	Index int
+	// Line — номер строки таблицы, если пакет пришёл в CSV.
+	Line   int
+	Resume resume.Resume
+	Err    error
+}
+
+// ReadJSON читает JSON-массив резюме. Синтаксическая ошибка самого массива
+// прерывает разбор, ошибки отдельных документов попадают в Item.Err.
+func ReadJSON(r io.Reader, max int) ([]Item, error) {
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("read batch: %w", err)
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return nil, errors.New("batch must be a JSON array of resumes")
+	}
+
+	var items []Item
+	for dec.More() {
+		if len(items) == max {
+			return nil, &TooManyItemsError{Max: max}
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("read batch item %d: %w", len(items), err)
+		}
+		items = append(items, decodeItem(len(items), raw))
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, fmt.Errorf("read batch: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("unexpected data after the batch array")
+	}
+	return items, nil
+}
+
+// ReadNDJSON читает по одному резюме на строку; пустые строки пропускаются.
+// Номер элемента — порядковый номер непустой строки.
+func ReadNDJSON(r io.Reader, max int) ([]Item, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var items []Item
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if len(items) == max {
+			return nil, &TooManyItemsError{Max: max}
+		}
+		items = append(items, decodeItem(len(items), line))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read batch line %d: %w", len(items)+1, err)
+	}
+	return items, nil
+}
+
+func decodeItem(i int, raw []byte) Item {
+	r, err := resume.DecodeJSON(raw)
+	return Item{Index: i, Resume: r, Err: err}
+}
+
+// ItemResult — строка report.json.
+type ItemResult struct {
+	Index    int                 `json:"index"`
+	Line     int                 `json:"line,omitempty"`
+	FullName string              `json:"fullName,omitempty"`
+	File     string              `json:"file,omitempty"`
+	Status   string              `json:"status"`
+	Error    string              `json:"error,omitempty"`
+	Message  string              `json:"message,omitempty"`
+	Details  []resume.FieldError `json:"details,omitempty"`
+	// Simplified — PDF собран запасным рендерером (см. resume.Output).
+	Simplified bool `json:"simplified,omitempty"`
+}
+
+// Report — содержимое report.json. Items упорядочены по Index.
+type Report struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Items     []ItemResult `json:"items"`
+}
+
+// NewReport собирает отчёт из результатов, выставленных по индексам.
+func NewReport(items []ItemResult) Report {
+	rep := Report{Total: len(items), Items: items}
+	for _, it := range items {
+		if it.Status == StatusOK {
+			rep.Succeeded++
+		} else {
+			rep.Failed++
+		}
+	}
+	return rep
+}
+
+// FileName — имя файла элемента в архиве: номер с ведущими нулями (с 1,
+// как строки в таблице) и имя из резюме, например "007-jane-doe.pdf".
+func FileName(index, total int, fullName, ext string) string {
+	width := len(fmt.Sprint(total))
+	name := fmt.Sprintf("%0*d", max(width, 3), index+1)
+	if slug := slugify(fullName); slug != "" {
+		name += "-" + slug
+	}
+	return name + "." + ext
+}
+
+// slugify оставляет буквы и цифры (включая кириллицу) в нижнем регистре,
+// остальное заменяет одиночными дефисами.
+func slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	out := b.String()
+	// длинные имена обрезаются по руне, чтобы не ломать UTF-8
+	if runes := []rune(out); len(runes) > 60 {
+		out = strings.TrimRight(string(runes[:60]), "-")
+	}
+	return out
+}
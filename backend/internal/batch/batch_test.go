@@ -0,0 +1,157 @@
+package batch
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// checkItems сверяет индексы, имена и ошибки разобранных элементов.
+func checkItems(t *testing.T, items []Item, names []string, bad []bool) {
+	t.Helper()
+	if len(items) != len(names) {
+		t.Fatalf("got %d items, want %d", len(items), len(names))
+	}
+	for i, it := range items {
+		if it.Index != i {
+			t.Errorf("item %d has Index %d", i, it.Index)
+		}
+		if (it.Err != nil) != bad[i] {
+			t.Errorf("item %d: err = %v, want error %v", i, it.Err, bad[i])
+		}
+		if it.Resume.FullName != names[i] {
+			t.Errorf("item %d: fullName = %q, want %q", i, it.Resume.FullName, names[i])
+		}
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		max     int
+		names   []string
+		bad     []bool
+		wantErr string
+	}{
+		{"empty", `[]`, 2, nil, nil, ""},
+		{"items", `[{"fullName": "Ann"}, {"fullName": 1}, {"fullName": "Bob", "extra": true}]`, 3,
+			[]string{"Ann", "", ""}, []bool{false, true, true}, ""},
+		{"exactly max", `[{"fullName": "Ann"}, {"fullName": "Bob"}]`, 2,
+			[]string{"Ann", "Bob"}, []bool{false, false}, ""},
+		{"too many", `[{}, {}, {}]`, 2, nil, nil, "batch contains more than 2 resumes"},
+		{"not an array", `{"fullName": "Ann"}`, 2, nil, nil, "batch must be a JSON array of resumes"},
+		{"trailing data", `[{"fullName": "Ann"}] {}`, 2, nil, nil, "unexpected data after the batch array"},
+		{"trailing array", `[] []`, 2, nil, nil, "unexpected data after the batch array"},
+		{"truncated", `[{"fullName": "Ann"}`, 2, nil, nil, "read batch"},
+		{"broken item", `[{"fullName": }]`, 2, nil, nil, "read batch item 0"},
+		{"empty body", ``, 2, nil, nil, "read batch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ReadJSON(strings.NewReader(tt.body), tt.max)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkItems(t, items, tt.names, tt.bad)
+		})
+	}
+}
+
+func TestReadJSONTooManyItems(t *testing.T) {
+	_, err := ReadJSON(strings.NewReader(`[{}, {}]`), 1)
+	var tm *TooManyItemsError
+	if !errors.As(err, &tm) || tm.Max != 1 {
+		t.Errorf("err = %v, want TooManyItemsError{Max: 1}", err)
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		max     int
+		names   []string
+		bad     []bool
+		wantErr string
+	}{
+		{"empty", "", 2, nil, nil, ""},
+		{"blank lines are skipped", "\n{\"fullName\": \"Ann\"}\n  \n{\"fullName\": 1}\r\n{\"fullName\": \"Bob\"}", 3,
+			[]string{"Ann", "", "Bob"}, []bool{false, true, false}, ""},
+		{"broken line", "{\"fullName\": \n{\"fullName\": \"Bob\"}\n", 2,
+			[]string{"", "Bob"}, []bool{true, false}, ""},
+		{"too many", "{}\n\n{}\n{}\n", 2, nil, nil, "batch contains more than 2 resumes"},
+		{"line too long", "{}\n{\"fullName\": \"" + strings.Repeat("a", maxLineSize) + "\"}\n", 5, nil, nil, "read batch line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := ReadNDJSON(strings.NewReader(tt.body), tt.max)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkItems(t, items, tt.names, tt.bad)
+		})
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		index, total int
+		fullName     string
+		want         string
+	}{
+		{0, 5, "Jane Doe", "001-jane-doe.pdf"},
+		{6, 1000, "Jane Doe", "0007-jane-doe.pdf"},
+		{999, 1000, "Jane Doe", "1000-jane-doe.pdf"},
+		{1, 5, "", "002.pdf"},
+		{1, 5, " --- ", "002.pdf"},
+		{2, 5, "Иван Иванов", "003-иван-иванов.pdf"},
+		{3, 5, "  O'Brien, Jr. ", "004-o-brien-jr.pdf"},
+		{4, 5, "../../etc/passwd", "005-etc-passwd.pdf"},
+	}
+	for _, tt := range tests {
+		if got := FileName(tt.index, tt.total, tt.fullName, "pdf"); got != tt.want {
+			t.Errorf("FileName(%d, %d, %q) = %q, want %q", tt.index, tt.total, tt.fullName, got, tt.want)
+		}
+	}
+}
+
+func TestSlugifyTruncates(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{strings.Repeat("a", 70), strings.Repeat("a", 60)},
+		// обрезка не оставляет дефис в конце
+		{strings.Repeat("a", 59) + " b", strings.Repeat("a", 59)},
+		// и не режет многобайтовую руну
+		{strings.Repeat("я", 61), strings.Repeat("я", 60)},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.in); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	rep := NewReport([]ItemResult{
+		{Index: 0, Status: StatusOK},
+		{Index: 1, Status: StatusError, Error: "validation_error"},
+		{Index: 2, Status: StatusOK},
+	})
+	if rep.Total != 3 || rep.Succeeded != 2 || rep.Failed != 1 || len(rep.Items) != 3 {
+		t.Errorf("report = %+v", rep)
+	}
+}
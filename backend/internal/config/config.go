@@ -1,15 +1,121 @@
 package config
 
 import (
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	contract "resume_contract"
 )
 
 // Config описывает конфигурацию backend-сервиса.
 type Config struct {
 	// HTTPAddr — адрес, на котором слушает HTTP-сервер, например ":8080".
 	HTTPAddr string
-	// LaTeXServiceURL — базовый URL latex-service, например "http://latex-service:8081".
-	LaTeXServiceURL string
+	// LaTeXTransport — протокол вызова latex-service: "http" (по умолчанию)
+	// или "grpc".
+	LaTeXTransport string
+	// LaTeXGRPCTarget — адрес gRPC-API latex-service в синтаксисе gRPC,
+	// например "dns:///latex-service:9090"; используется при LaTeXTransport "grpc".
+	LaTeXGRPCTarget string
+	// LaTeXServiceURLs — базовые URL узлов latex-service, например
+	// "http://latex-service:8081". Адрес вида "dns+http://latex-service:8081"
+	// раскрывается в узел на каждую A-запись имени.
+	LaTeXServiceURLs []string
+	// LaTeXRetryAttempts — попыток на запрос к latex-service, включая первую.
+	LaTeXRetryAttempts int
+	// LaTeXRetryBackoff и LaTeXRetryMaxBackoff — начальная и предельная
+	// пауза между попытками.
+	LaTeXRetryBackoff    time.Duration
+	LaTeXRetryMaxBackoff time.Duration
+	// LaTeXBreakerThreshold — отказов подряд до размыкания предохранителя.
+	LaTeXBreakerThreshold int
+	// LaTeXBreakerCooldown — сколько предохранитель остаётся разомкнутым.
+	LaTeXBreakerCooldown time.Duration
+	// LaTeXHedgeDelay — задержка дублирующего запроса; 0 — без дублирования.
+	LaTeXHedgeDelay time.Duration
+	// LaTeXHealthInterval — период проверки /healthz узлов и перечитывания DNS.
+	LaTeXHealthInterval time.Duration
+	// LaTeXMaxRequestSize — предельный размер запроса, который принимает
+	// latex-service (его MAX_RENDER_BODY_SIZE). Больший запрос не
+	// отправляется; от этого значения считаются MaxBodySize и MaxPhotoSize
+	// по умолчанию.
+	LaTeXMaxRequestSize int
+	// MaxPDFSize — предельный размер PDF от latex-service в байтах; больший
+	// документ не передаётся клиенту (422 output_too_large).
+	MaxPDFSize int
+	// PDFFallback — рендерить PDF упрощённой вёрсткой прямо в backend, пока
+	// предохранитель latex-service разомкнут.
+	PDFFallback bool
+	// PDFFallbackFont и PDFFallbackBoldFont — TrueType-шрифты запасного
+	// рендера; без них используется Helvetica, и кириллица транслитерируется.
+	PDFFallbackFont     string
+	PDFFallbackBoldFont string
+	// StorageDriver — реализация хранилища резюме: "fs" (каталог с JSON-файлами)
+	// или "filedb" (один файл-журнал).
+	StorageDriver string
+	// StoragePath — каталог (fs) или файл (filedb) хранилища.
+	StoragePath string
+	// SharePath — JSON-файл с публичными ссылками на резюме.
+	SharePath string
+	// ShareKeyPath — файл с секретом подписи ссылок; создаётся при первом
+	// запуске, если его нет.
+	ShareKeyPath string
+	// ShareFlushInterval — как часто счётчики обращений по ссылкам
+	// сбрасываются в файл; при остановке сервера они записываются в любом случае.
+	ShareFlushInterval time.Duration
+	// JobWorkers — сколько фоновых задач рендера выполняется одновременно.
+	JobWorkers int
+	// JobQueueSize — сколько задач может ждать свободного воркера.
+	JobQueueSize int
+	// JobTTL — сколько хранится результат завершённой задачи.
+	JobTTL time.Duration
+	// JobTimeout — предельная длительность одной задачи.
+	JobTimeout time.Duration
+	// BatchMaxItems — предельное число резюме в пакетном запросе.
+	BatchMaxItems int
+	// BatchConcurrency — параллельность пакетного рендера; 0 — по размеру
+	// пула latex-service.
+	BatchConcurrency int
+	// MaxBodySize — предельный размер тела запроса с одним резюме в байтах;
+	// по умолчанию равен LaTeXMaxRequestSize.
+	MaxBodySize int
+	// MaxBatchBodySize — то же для пакетного рендера и импорта CSV.
+	MaxBatchBodySize int
+	// MaxPhotoSize — предельный размер фото после декодирования base64; по
+	// умолчанию половина LaTeXMaxRequestSize: в base64 фото занимает 4/3
+	// своего размера, и на остальное резюме остаётся треть запроса.
+	MaxPhotoSize int
+
+	// RateLimit включает лимиты запросов на клиента.
+	RateLimit bool
+	// RateLimitPDF — запросов в минуту к маршрутам рендера на клиента,
+	// RateLimitPDFBurst — сколько из них можно сделать подряд,
+	// RateLimitPDFConcurrency — сколько рендеров клиента выполняется
+	// одновременно.
+	RateLimitPDF            int
+	RateLimitPDFBurst       int
+	RateLimitPDFConcurrency int
+	// RateLimitValidate и RateLimitValidateBurst — то же для остальных
+	// маршрутов API.
+	RateLimitValidate      int
+	RateLimitValidateBurst int
+	// TrustedProxies — адреса и подсети прокси через запятую, чьему
+	// X-Forwarded-For можно верить.
+	TrustedProxies string
+
+	// APIKeysPath — JSON-файл с API-ключами; пусто — ключи не проверяются.
+	APIKeysPath string
+	// APIUsagePath — JSON-файл учёта использования ключей.
+	APIUsagePath string
+	// APIUsageFlushInterval — как часто учёт использования сбрасывается в
+	// файл; при остановке сервера он записывается в любом случае.
+	APIUsageFlushInterval time.Duration
+	// APIKeysRequired — отклонять запросы к API без ключа; false оставляет
+	// анонимный доступ (например, для встроенного фронтенда).
+	APIKeysRequired bool
 }
 
 // Load загружает конфигурацию из переменных окружения с дефолтами.
@@ -19,13 +125,143 @@ func Load() Config {
 		httpAddr = ":8080"
 	}
 
-	latexURL := os.Getenv("LATEX_SERVICE_URL")
-	if latexURL == "" {
-		latexURL = "http://latex-service:8081"
+	// несколько узлов перечисляются через запятую
+	var latexURLs []string
+	for _, u := range strings.Split(os.Getenv("LATEX_SERVICE_URL"), ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			latexURLs = append(latexURLs, strings.TrimRight(u, "/"))
+		}
+	}
+	if len(latexURLs) == 0 {
+		latexURLs = []string{"http://latex-service:8081"}
 	}
 
+	latexTransport := os.Getenv("LATEX_TRANSPORT")
+	if latexTransport == "" {
+		latexTransport = "http"
+	}
+
+	latexGRPCTarget := os.Getenv("LATEX_GRPC_TARGET")
+	if latexGRPCTarget == "" {
+		latexGRPCTarget = "dns:///latex-service:9090"
+	}
+
+	storageDriver := os.Getenv("STORAGE_DRIVER")
+	if storageDriver == "" {
+		storageDriver = "fs"
+	}
+
+	storagePath := os.Getenv("STORAGE_PATH")
+	if storagePath == "" {
+		storagePath = "data/resumes"
+		if storageDriver == "filedb" {
+			storagePath = "data/resumes.db"
+		}
+	}
+
+	sharePath := os.Getenv("SHARE_PATH")
+	if sharePath == "" {
+		sharePath = "data/shares.json"
+	}
+
+	shareKeyPath := os.Getenv("SHARE_KEY_PATH")
+	if shareKeyPath == "" {
+		shareKeyPath = "data/share.key"
+	}
+
+	apiUsagePath := os.Getenv("API_USAGE_PATH")
+	if apiUsagePath == "" {
+		apiUsagePath = "data/apikey-usage.json"
+	}
+
+	latexMaxRequestSize := intEnv("LATEX_MAX_REQUEST_SIZE", contract.DefaultMaxRequestSize)
+
 	return Config{
-		HTTPAddr:        httpAddr,
-		LaTeXServiceURL: latexURL,
+		HTTPAddr:              httpAddr,
+		LaTeXTransport:        latexTransport,
+		LaTeXGRPCTarget:       latexGRPCTarget,
+		LaTeXServiceURLs:      latexURLs,
+		LaTeXRetryAttempts:    intEnv("LATEX_RETRY_ATTEMPTS", 3),
+		LaTeXRetryBackoff:     durationEnv("LATEX_RETRY_BACKOFF", 200*time.Millisecond),
+		LaTeXRetryMaxBackoff:  durationEnv("LATEX_RETRY_MAX_BACKOFF", 2*time.Second),
+		LaTeXBreakerThreshold: intEnv("LATEX_BREAKER_THRESHOLD", 5),
+		LaTeXBreakerCooldown:  durationEnv("LATEX_BREAKER_COOLDOWN", 30*time.Second),
+		LaTeXHedgeDelay:       durationEnv("LATEX_HEDGE_DELAY", 0),
+		LaTeXHealthInterval:   durationEnv("LATEX_HEALTH_INTERVAL", 5*time.Second),
+		LaTeXMaxRequestSize:   latexMaxRequestSize,
+		MaxPDFSize:            intEnv("MAX_PDF_SIZE", 20<<20),
+		PDFFallback:           boolEnv("PDF_FALLBACK", true),
+		PDFFallbackFont:       os.Getenv("PDF_FALLBACK_FONT"),
+		PDFFallbackBoldFont:   os.Getenv("PDF_FALLBACK_BOLD_FONT"),
+		StorageDriver:         storageDriver,
+		StoragePath:           storagePath,
+		SharePath:             sharePath,
+		ShareKeyPath:          shareKeyPath,
+		ShareFlushInterval:    durationEnv("SHARE_FLUSH_INTERVAL", 10*time.Second),
+		JobWorkers:            intEnv("JOB_WORKERS", 2),
+		JobQueueSize:          intEnv("JOB_QUEUE_SIZE", 64),
+		JobTTL:                durationEnv("JOB_TTL", 15*time.Minute),
+		JobTimeout:            durationEnv("JOB_TIMEOUT", 5*time.Minute),
+		BatchMaxItems:         intEnv("BATCH_MAX_ITEMS", 200),
+		BatchConcurrency:      intEnv("BATCH_CONCURRENCY", 0),
+		MaxBodySize:           intEnv("MAX_BODY_SIZE", latexMaxRequestSize),
+		MaxBatchBodySize:      intEnv("MAX_BATCH_BODY_SIZE", 64<<20),
+		MaxPhotoSize:          intEnv("MAX_PHOTO_SIZE", latexMaxRequestSize/2),
+
+		RateLimit:               boolEnv("RATE_LIMIT", true),
+		RateLimitPDF:            intEnv("RATE_LIMIT_PDF", 30),
+		RateLimitPDFBurst:       intEnv("RATE_LIMIT_PDF_BURST", 10),
+		RateLimitPDFConcurrency: intEnv("RATE_LIMIT_PDF_CONCURRENCY", 2),
+		RateLimitValidate:       intEnv("RATE_LIMIT_VALIDATE", 300),
+		RateLimitValidateBurst:  intEnv("RATE_LIMIT_VALIDATE_BURST", 60),
+		TrustedProxies:          os.Getenv("TRUSTED_PROXIES"),
+
+		APIKeysPath:           os.Getenv("API_KEYS_PATH"),
+		APIUsagePath:          apiUsagePath,
+		APIUsageFlushInterval: durationEnv("API_USAGE_FLUSH_INTERVAL", 10*time.Second),
+		APIKeysRequired:       boolEnv("API_KEYS_REQUIRED", true),
+	}
+}
+
+// intEnv читает положительное целое из переменной окружения; пустое или
+// некорректное значение заменяется дефолтом.
+func intEnv(name string, def int) int {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		slog.Warn("config: invalid value, using default", "name", name, "value", v, "default", def)
+		return def
+	}
+	return n
+}
+
+// durationEnv читает длительность в формате time.ParseDuration ("90s", "15m").
+func durationEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		slog.Warn("config: invalid value, using default", "name", name, "value", v, "default", def.String())
+		return def
+	}
+	return d
+}
+
+// boolEnv читает флаг в формате strconv.ParseBool ("true", "0" и т.п.).
+func boolEnv(name string, def bool) bool {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		slog.Warn("config: invalid value, using default", "name", name, "value", v, "default", def)
+		return def
 	}
+	return b
 }
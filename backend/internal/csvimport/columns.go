@@ -0,0 +1,375 @@
+package csvimport
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+// maxIndex ограничивает номер элемента в имени колонки: лимиты на число
+// элементов проверяет валидация, а здесь важно не раздуть срез опечаткой
+// вроде experience[1000].
+const maxIndex = 99
+
+// setter записывает значение ячейки в строящееся резюме. Ошибка относится
+// к ячейке и попадает в отчёт строки.
+type setter func(b *builder, v string) error
+
+// column — разобранная колонка заголовка.
+type column struct {
+	// name — каноническое имя (путь поля), под ним колонка фигурирует в ошибках.
+	name string
+	set  setter
+	// list — имя списка, который колонка заполняет целиком ("skills") или
+	// поэлементно ("skills[N]"); смешивать оба способа нельзя.
+	list    string
+	indexed bool
+}
+
+var segmentRe = regexp.MustCompile(`^([A-Za-z]+)(?:\[(\d+)\])?$`)
+
+type segment struct {
+	name  string
+	index int // -1, если индекса нет
+}
+
+// parsePath разбирает путь вида experience[0].bullets[2].
+func parsePath(path string) ([]segment, error) {
+	parts := strings.Split(path, ".")
+	segs := make([]segment, 0, len(parts))
+	for _, p := range parts {
+		m := segmentRe.FindStringSubmatch(strings.TrimSpace(p))
+		if m == nil {
+			return nil, errors.New("Malformed column name")
+		}
+		s := segment{name: strings.ToLower(m[1]), index: -1}
+		if m[2] != "" {
+			n, err := strconv.Atoi(m[2])
+			if err != nil || n > maxIndex {
+				return nil, fmt.Errorf("Index is too large (max %d)", maxIndex)
+			}
+			s.index = n
+		}
+		segs = append(segs, s)
+	}
+	return segs, nil
+}
+
+// scalarColumns — поля без индексов.
+var scalarColumns = map[string]struct {
+	name string
+	set  func(b *builder, v string)
+}{
+	"fullname":          {"fullName", func(b *builder, v string) { b.r.FullName = v }},
+	"position":          {"position", func(b *builder, v string) { b.r.Position = v }},
+	"summary":           {"summary", func(b *builder, v string) { b.r.Summary = v }},
+	"contacts.email":    {"contacts.email", func(b *builder, v string) { b.r.Contacts.Email = v }},
+	"contacts.phone":    {"contacts.phone", func(b *builder, v string) { b.r.Contacts.Phone = v }},
+	"contacts.location": {"contacts.location", func(b *builder, v string) { b.r.Contacts.Location = v }},
+	"photo.mimetype":    {"photo.mimeType", func(b *builder, v string) { b.photo().MimeType = v }},
+	"photo.data":        {"photo.data", func(b *builder, v string) { b.photo().Data = v }},
+}
+
+// parseColumn сопоставляет заголовок колонки с полем резюме. Имена
+// нечувствительны к регистру; в ошибках используется каноническое написание.
+func parseColumn(header string) (column, error) {
+	segs, err := parsePath(header)
+	if err != nil {
+		return column{}, err
+	}
+
+	names := make([]string, len(segs))
+	indexed := false
+	for i, s := range segs {
+		names[i] = s.name
+		indexed = indexed || s.index >= 0
+	}
+	if sc, ok := scalarColumns[strings.Join(names, ".")]; ok && !indexed {
+		set := sc.set
+		return column{name: sc.name, set: func(b *builder, v string) error {
+			set(b, v)
+			return nil
+		}}, nil
+	}
+
+	head := segs[0]
+	switch {
+	case head.name == "skills" && len(segs) == 1:
+		if head.index < 0 {
+			return column{name: "skills", list: "skills", set: func(b *builder, v string) error {
+				b.skills = append(b.skills, resume.Untagged(splitList(v, true))...)
+				return nil
+			}}, nil
+		}
+		i := head.index
+		return column{name: fmt.Sprintf("skills[%d]", i), list: "skills", indexed: true, set: func(b *builder, v string) error {
+			b.skills = grow(b.skills, i)
+			b.skills[i] = resume.TaggedText{Text: v}
+			b.touch("skills", i)
+			return nil
+		}}, nil
+
+	case head.name == "contacts" && len(segs) == 3 && head.index < 0 && segs[1].name == "links" && segs[1].index >= 0 && segs[2].index < 0:
+		i := segs[1].index
+		prefix := fmt.Sprintf("contacts.links[%d]", i)
+		var set func(l *resume.Link, v string)
+		switch segs[2].name {
+		case "label":
+			set = func(l *resume.Link, v string) { l.Label = v }
+		case "url":
+			set = func(l *resume.Link, v string) { l.URL = v }
+		default:
+			return column{}, unknownField(prefix, segs[2].name)
+		}
+		return column{name: prefix + "." + fieldName(segs[2].name), set: func(b *builder, v string) error {
+			b.r.Contacts.Links = grow(b.r.Contacts.Links, i)
+			set(&b.r.Contacts.Links[i], v)
+			b.touch("contacts.links", i)
+			return nil
+		}}, nil
+
+	case head.name == "experience" && head.index >= 0 && len(segs) == 2:
+		return experienceColumn(head.index, segs[1])
+
+	case head.name == "education" && head.index >= 0 && len(segs) == 2 && segs[1].index < 0:
+		return educationColumn(head.index, segs[1].name)
+
+	case head.name == "customsections" && head.index >= 0 && len(segs) == 2:
+		return customSectionColumn(head.index, segs[1])
+	}
+
+	return column{}, errors.New("Unknown column")
+}
+
+func experienceColumn(i int, field segment) (column, error) {
+	prefix := fmt.Sprintf("experience[%d]", i)
+	at := func(b *builder) *resume.ExperienceItem {
+		b.r.Experience = grow(b.r.Experience, i)
+		b.touch("experience", i)
+		return &b.r.Experience[i]
+	}
+	text := func(set func(e *resume.ExperienceItem, v string)) setter {
+		return func(b *builder, v string) error {
+			set(at(b), v)
+			return nil
+		}
+	}
+	date := func(end bool, set func(e *resume.ExperienceItem, v string)) setter {
+		return func(b *builder, v string) error {
+			m, err := parseMonth(v, end)
+			if err != nil {
+				return err
+			}
+			set(at(b), m)
+			return nil
+		}
+	}
+
+	if field.name == "bullets" {
+		list := prefix + ".bullets"
+		if field.index < 0 {
+			return column{name: list, list: list, set: func(b *builder, v string) error {
+				e := at(b)
+				e.Bullets = append(e.Bullets, resume.Untagged(splitList(v, false))...)
+				return nil
+			}}, nil
+		}
+		j := field.index
+		return column{name: fmt.Sprintf("%s[%d]", list, j), list: list, indexed: true, set: func(b *builder, v string) error {
+			e := at(b)
+			e.Bullets = grow(e.Bullets, j)
+			e.Bullets[j] = resume.TaggedText{Text: v}
+			return nil
+		}}, nil
+	}
+	if field.index >= 0 {
+		return column{}, unknownField(prefix, field.name)
+	}
+
+	var set setter
+	switch field.name {
+	case "company":
+		set = text(func(e *resume.ExperienceItem, v string) { e.Company = v })
+	case "position":
+		set = text(func(e *resume.ExperienceItem, v string) { e.Position = v })
+	case "location":
+		set = text(func(e *resume.ExperienceItem, v string) { e.Location = v })
+	case "description":
+		set = text(func(e *resume.ExperienceItem, v string) { e.Description = v })
+	case "startdate":
+		set = date(false, func(e *resume.ExperienceItem, v string) { e.StartDate = v })
+	case "enddate":
+		set = date(true, func(e *resume.ExperienceItem, v string) { e.EndDate = v })
+	case "tags":
+		set = text(func(e *resume.ExperienceItem, v string) { e.Tags = append(e.Tags, splitList(v, true)...) })
+	default:
+		return column{}, unknownField(prefix, field.name)
+	}
+	return column{name: prefix + "." + fieldName(field.name), set: set}, nil
+}
+
+func educationColumn(i int, field string) (column, error) {
+	prefix := fmt.Sprintf("education[%d]", i)
+	at := func(b *builder) *resume.EducationItem {
+		b.r.Education = grow(b.r.Education, i)
+		b.touch("education", i)
+		return &b.r.Education[i]
+	}
+
+	var set func(e *resume.EducationItem, v string)
+	dateField := false
+	switch field {
+	case "institution":
+		set = func(e *resume.EducationItem, v string) { e.Institution = v }
+	case "degree":
+		set = func(e *resume.EducationItem, v string) { e.Degree = v }
+	case "location":
+		set = func(e *resume.EducationItem, v string) { e.Location = v }
+	case "details":
+		set = func(e *resume.EducationItem, v string) { e.Details = v }
+	case "startdate":
+		set, dateField = func(e *resume.EducationItem, v string) { e.StartDate = v }, true
+	case "enddate":
+		set, dateField = func(e *resume.EducationItem, v string) { e.EndDate = v }, true
+	default:
+		return column{}, unknownField(prefix, field)
+	}
+
+	end := field == "enddate"
+	return column{name: prefix + "." + fieldName(field), set: func(b *builder, v string) error {
+		if dateField {
+			m, err := parseMonth(v, end)
+			if err != nil {
+				return err
+			}
+			v = m
+		}
+		set(at(b), v)
+		return nil
+	}}, nil
+}
+
+func customSectionColumn(i int, field segment) (column, error) {
+	prefix := fmt.Sprintf("customSections[%d]", i)
+	at := func(b *builder) *resume.CustomSection {
+		b.r.CustomSections = grow(b.r.CustomSections, i)
+		b.touch("customSections", i)
+		return &b.r.CustomSections[i]
+	}
+
+	switch {
+	case field.name == "items" && field.index < 0:
+		list := prefix + ".items"
+		return column{name: list, list: list, set: func(b *builder, v string) error {
+			s := at(b)
+			s.Items = append(s.Items, resume.Untagged(splitList(v, false))...)
+			return nil
+		}}, nil
+	case field.name == "items":
+		list, j := prefix+".items", field.index
+		return column{name: fmt.Sprintf("%s[%d]", list, j), list: list, indexed: true, set: func(b *builder, v string) error {
+			s := at(b)
+			s.Items = grow(s.Items, j)
+			s.Items[j] = resume.TaggedText{Text: v}
+			return nil
+		}}, nil
+	case field.name == "title" && field.index < 0:
+		return column{name: prefix + ".title", set: func(b *builder, v string) error {
+			at(b).Title = v
+			return nil
+		}}, nil
+	case field.name == "type" && field.index < 0:
+		return column{name: prefix + ".type", set: func(b *builder, v string) error {
+			at(b).Type = v
+			return nil
+		}}, nil
+	case field.name == "bulletsymbol" && field.index < 0:
+		return column{name: prefix + ".bulletSymbol", set: func(b *builder, v string) error {
+			at(b).BulletSymbol = v
+			return nil
+		}}, nil
+	}
+	return column{}, unknownField(prefix, field.name)
+}
+
+// canonicalFields восстанавливает регистр имён полей после ToLower.
+var canonicalFields = map[string]string{
+	"startdate":    "startDate",
+	"enddate":      "endDate",
+	"bulletsymbol": "bulletSymbol",
+}
+
+func fieldName(lower string) string {
+	if n, ok := canonicalFields[lower]; ok {
+		return n
+	}
+	return lower
+}
+
+func unknownField(prefix, field string) error {
+	return fmt.Errorf("Unknown field %q in %s", field, prefix)
+}
+
+// grow удлиняет срез так, чтобы в нём был элемент с индексом i.
+func grow[T any](s []T, i int) []T {
+	if i < len(s) {
+		return s
+	}
+	return append(s, make([]T, i+1-len(s))...)
+}
+
+// splitList делит ячейку со списком на элементы: по переводам строк
+// (Alt+Enter в таблице) и, для коротких значений вроде навыков и тегов,
+// дополнительно по точке с запятой и запятой. Пустые элементы пропускаются.
+func splitList(v string, short bool) []string {
+	sep := func(r rune) bool { return r == '\n' || r == '\r' }
+	if short {
+		sep = func(r rune) bool { return r == '\n' || r == '\r' || r == ';' || r == ',' }
+	}
+	var out []string
+	for _, item := range strings.FieldsFunc(v, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+var (
+	monthRe    = regexp.MustCompile(`^(\d{4})-(\d{1,2})(?:-\d{1,2})?(?:[T ].*)?$`)
+	dayMonthRe = regexp.MustCompile(`^(?:\d{1,2}[./])?(\d{1,2})[./](\d{4})$`)
+)
+
+// presentValues — как в таблицах пишут «по настоящее время» в дате окончания.
+var presentValues = map[string]bool{
+	"present": true, "now": true, "current": true,
+	"по н.в.": true, "н.в.": true, "по настоящее время": true, "настоящее время": true,
+}
+
+// parseMonth приводит дату из ячейки к YYYY-MM. Таблицы часто сохраняют
+// даты по-своему, поэтому кроме YYYY-MM принимаются YYYY-MM-DD, MM.YYYY,
+// MM/YYYY и DD.MM.YYYY; в дате окончания — ещё и «present»/«по н.в.».
+func parseMonth(v string, end bool) (string, error) {
+	if end && presentValues[strings.ToLower(v)] {
+		return "", nil
+	}
+
+	var year, month string
+	if m := monthRe.FindStringSubmatch(v); m != nil {
+		year, month = m[1], m[2]
+	} else if m := dayMonthRe.FindStringSubmatch(v); m != nil {
+		year, month = m[2], m[1]
+	} else {
+		return "", errors.New("Date must be in YYYY-MM format")
+	}
+
+	n, _ := strconv.Atoi(month)
+	if n < 1 || n > 12 {
+		return "", errors.New("Month must be between 01 and 12")
+	}
+	return fmt.Sprintf("%s-%02d", year, n), nil
+}
@@ -0,0 +1,295 @@
+// Package csvimport превращает CSV-выгрузку таблицы в резюме: по резюме на
+// строку, колонки называются путями полей (fullName, contacts.email,
+// experience[0].company, skills и т.п., см. README). Ошибки заголовка
+// прерывают разбор, ошибки ячеек и валидации относятся к своей строке и
+// возвращаются в формате resume.FieldError.
+package csvimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+// ContentType — тип тела запроса с CSV.
+const ContentType = "text/csv"
+
+// HeaderError — заголовок таблицы не удалось сопоставить с полями резюме.
+// Field в Problems — текст заголовка колонки или имя списка.
+type HeaderError struct {
+	Problems []resume.FieldError
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("csv header has %d problem(s)", len(e.Problems))
+}
+
+// TooManyRowsError возвращается, если строк с данными больше Max.
+type TooManyRowsError struct {
+	Max int
+}
+
+func (e *TooManyRowsError) Error() string {
+	return fmt.Sprintf("csv contains more than %d rows", e.Max)
+}
+
+// Row — строка таблицы, превращённая в резюме.
+type Row struct {
+	// Line — номер строки в файле (заголовок — 1), как его покажет таблица.
+	Line   int
+	Resume resume.Resume
+	// Errors — ошибки ячеек и валидации резюме; Field — путь поля, то есть
+	// имя колонки, в которой нужно исправить значение.
+	Errors []resume.FieldError
+}
+
+// Read разбирает CSV. Разделитель — запятая или точка с запятой (так
+// сохраняет Excel в русской локали), он определяется по заголовку.
+// Полностью пустые строки пропускаются, колонки без заголовка игнорируются.
+func Read(r io.Reader, max int) ([]Row, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read csv: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM из Excel
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = detectComma(data)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols, err := parseHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(rec) {
+			continue
+		}
+		if len(rows) == max {
+			return nil, &TooManyRowsError{Max: max}
+		}
+		line, _ := cr.FieldPos(0)
+		rows = append(rows, buildRow(line, cols, rec))
+	}
+	return rows, nil
+}
+
+// detectComma выбирает разделитель, которого больше в первой строке.
+func detectComma(data []byte) rune {
+	first, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// parseHeader сопоставляет колонки с полями. nil в результате — колонка
+// без заголовка.
+func parseHeader(header []string) ([]*column, error) {
+	var he HeaderError
+	cols := make([]*column, len(header))
+	seen := make(map[string]bool)
+	lists := make(map[string]map[bool]bool) // список -> способ заполнения
+
+	for i, h := range header {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
+		}
+		c, err := parseColumn(h)
+		if err != nil {
+			he.Problems = append(he.Problems, resume.FieldError{Field: h, Message: err.Error()})
+			continue
+		}
+		if seen[c.name] {
+			he.Problems = append(he.Problems, resume.FieldError{Field: h, Message: fmt.Sprintf("Duplicate column %s", c.name)})
+			continue
+		}
+		seen[c.name] = true
+		if c.list != "" {
+			if lists[c.list] == nil {
+				lists[c.list] = make(map[bool]bool)
+			}
+			lists[c.list][c.indexed] = true
+		}
+		cols[i] = &c
+	}
+
+	var mixed []string
+	for list, ways := range lists {
+		if len(ways) > 1 {
+			mixed = append(mixed, list)
+		}
+	}
+	sort.Strings(mixed)
+	for _, list := range mixed {
+		he.Problems = append(he.Problems, resume.FieldError{
+			Field:   list,
+			Message: fmt.Sprintf("Use either a single %s column or %s[N] columns, not both", list, list),
+		})
+	}
+
+	if len(seen) == 0 && len(he.Problems) == 0 {
+		he.Problems = append(he.Problems, resume.FieldError{Field: "column 1", Message: "Header row has no columns"})
+	}
+	if len(he.Problems) > 0 {
+		return nil, &he
+	}
+	return cols, nil
+}
+
+func blank(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// builder собирает резюме из ячеек одной строки.
+type builder struct {
+	r resume.Resume
+	// skills — навыки из колонок skills и skills[N]; в резюме они попадают
+	// одной группой без названия.
+	skills []resume.TaggedText
+	// touched — заполненные индексы списков верхнего уровня; незаполненные
+	// элементы (у кого-то три места работы, у кого-то одно) выбрасываются.
+	touched map[string]map[int]bool
+}
+
+func (b *builder) touch(list string, i int) {
+	if b.touched[list] == nil {
+		b.touched[list] = make(map[int]bool)
+	}
+	b.touched[list][i] = true
+}
+
+func (b *builder) photo() *resume.Photo {
+	if b.r.Photo == nil {
+		b.r.Photo = &resume.Photo{}
+	}
+	return b.r.Photo
+}
+
+func buildRow(line int, cols []*column, rec []string) Row {
+	b := &builder{
+		r:       resume.Resume{SchemaVersion: resume.CurrentSchemaVersion},
+		touched: make(map[string]map[int]bool),
+	}
+	row := Row{Line: line}
+
+	for i, c := range cols {
+		if c == nil || i >= len(rec) {
+			continue
+		}
+		v := strings.TrimSpace(rec[i])
+		if v == "" {
+			continue
+		}
+		if err := c.set(b, v); err != nil {
+			row.Errors = append(row.Errors, resume.FieldError{Field: c.name, Message: err.Error()})
+		}
+	}
+
+	// после сжатия индексы в ошибках валидации указывают на элементы без
+	// пропусков; origin возвращает их к номерам колонок
+	origin := make(map[string][]int)
+	b.skills, origin["skills"] = compact(b.skills, b.touched["skills"])
+	if len(b.skills) > 0 {
+		b.r.Skills = []resume.SkillGroup{{Items: b.skills}}
+	}
+	b.r.Contacts.Links, origin["contacts.links"] = compact(b.r.Contacts.Links, b.touched["contacts.links"])
+	b.r.Experience, origin["experience"] = compact(b.r.Experience, b.touched["experience"])
+	b.r.Education, origin["education"] = compact(b.r.Education, b.touched["education"])
+	b.r.CustomSections, origin["customSections"] = compact(b.r.CustomSections, b.touched["customSections"])
+	for i := range b.r.Experience {
+		b.r.Experience[i].Bullets = dropEmpty(b.r.Experience[i].Bullets)
+	}
+	for i := range b.r.CustomSections {
+		cs := &b.r.CustomSections[i]
+		cs.Items = dropEmpty(cs.Items)
+		if cs.Type == "" {
+			cs.Type = resume.SectionTypeForTitle(cs.Title)
+		}
+	}
+
+	var ve *resume.ValidationError
+	if err := resume.ValidateResume(b.r); errors.As(err, &ve) {
+		for _, fe := range ve.Errors {
+			fe.Field = columnPath(strings.Replace(fe.Field, "skills[0].items[", "skills[", 1), origin)
+			row.Errors = append(row.Errors, fe)
+		}
+	}
+
+	row.Resume = b.r
+	return row
+}
+
+// compact оставляет элементы с заполненными индексами и возвращает исходный
+// индекс каждого оставшегося. Списки, заполненные одной колонкой (skills),
+// не отмечаются в touched и остаются как есть.
+func compact[T any](items []T, touched map[int]bool) ([]T, []int) {
+	if touched == nil {
+		return items, nil
+	}
+	out := make([]T, 0, len(touched))
+	origin := make([]int, 0, len(touched))
+	for i, it := range items {
+		if touched[i] {
+			out = append(out, it)
+			origin = append(origin, i)
+		}
+	}
+	return out, origin
+}
+
+func dropEmpty(items []resume.TaggedText) []resume.TaggedText {
+	out := items[:0]
+	for _, it := range items {
+		if it.Text != "" {
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+var indexedFieldRe = regexp.MustCompile(`^(skills|contacts\.links|experience|education|customSections)\[(\d+)\]`)
+
+// columnPath переводит путь поля из ошибки валидации в имя колонки.
+func columnPath(field string, origin map[string][]int) string {
+	m := indexedFieldRe.FindStringSubmatchIndex(field)
+	if m == nil {
+		return field
+	}
+	list := field[m[2]:m[3]]
+	i, _ := strconv.Atoi(field[m[4]:m[5]])
+	if o := origin[list]; i < len(o) {
+		return fmt.Sprintf("%s[%d]%s", list, o[i], field[m[1]:])
+	}
+	return field
+}
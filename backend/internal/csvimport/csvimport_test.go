@@ -0,0 +1,239 @@
+package csvimport
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"resume_backend/internal/resume"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name  string
+		csv   string
+		lines []int
+		names []string
+	}{
+		{
+			name:  "comma",
+			csv:   "fullName,position,summary\nAnn,Dev,Go\n\nBob,QA,Tests\n",
+			lines: []int{2, 4},
+			names: []string{"Ann", "Bob"},
+		},
+		{
+			name:  "semicolon with BOM",
+			csv:   "\xef\xbb\xbffullName;position;summary\r\nАнна;Dev;Go, SQL\r\n",
+			lines: []int{2},
+			names: []string{"Анна"},
+		},
+		{
+			name:  "multiline cell",
+			csv:   "fullName,summary,position\nAnn,\"line 1\nline 2\",Dev\nBob,x,QA\n",
+			lines: []int{2, 4},
+			names: []string{"Ann", "Bob"},
+		},
+		{
+			name:  "blank row of separators",
+			csv:   "fullName,position,summary\n , ,\nAnn,Dev,Go\n",
+			lines: []int{3},
+			names: []string{"Ann"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := Read(strings.NewReader(tt.csv), 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var lines []int
+			var names []string
+			for _, r := range rows {
+				lines = append(lines, r.Line)
+				names = append(names, r.Resume.FullName)
+				if len(r.Errors) > 0 {
+					t.Errorf("line %d errors: %+v", r.Line, r.Errors)
+				}
+			}
+			if !reflect.DeepEqual(lines, tt.lines) || !reflect.DeepEqual(names, tt.names) {
+				t.Errorf("rows = %v %q, want %v %q", lines, names, tt.lines, tt.names)
+			}
+		})
+	}
+}
+
+func TestReadLimits(t *testing.T) {
+	_, err := Read(strings.NewReader("fullName\nA\nB\nC\n"), 2)
+	var tm *TooManyRowsError
+	if !errors.As(err, &tm) || tm.Max != 2 {
+		t.Errorf("err = %v, want TooManyRowsError{Max: 2}", err)
+	}
+
+	if _, err := Read(strings.NewReader(""), 2); err == nil || err.Error() != "csv is empty" {
+		t.Errorf("empty csv: err = %v", err)
+	}
+	if _, err := Read(strings.NewReader("\xef\xbb\xbf"), 2); err == nil || err.Error() != "csv is empty" {
+		t.Errorf("BOM only: err = %v", err)
+	}
+}
+
+func TestReadHeaderErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   []resume.FieldError
+	}{
+		{"duplicate column", "fullName,FULLNAME", []resume.FieldError{
+			{Field: "FULLNAME", Message: "Duplicate column fullName"},
+		}},
+		{"duplicate indexed column", "experience[0].startDate,Experience[0].StartDate", []resume.FieldError{
+			{Field: "Experience[0].StartDate", Message: "Duplicate column experience[0].startDate"},
+		}},
+		{"mixed skills", "skills,skills[0]", []resume.FieldError{
+			{Field: "skills", Message: "Use either a single skills column or skills[N] columns, not both"},
+		}},
+		{"mixed bullets and items", "customSections[0].items[1],experience[0].bullets,customSections[0].items,experience[0].bullets[0]", []resume.FieldError{
+			{Field: "customSections[0].items", Message: "Use either a single customSections[0].items column or customSections[0].items[N] columns, not both"},
+			{Field: "experience[0].bullets", Message: "Use either a single experience[0].bullets column or experience[0].bullets[N] columns, not both"},
+		}},
+		{"index over maxIndex", "experience[100].company", []resume.FieldError{
+			{Field: "experience[100].company", Message: "Index is too large (max 99)"},
+		}},
+		{"unknown column", "fullName,nickname", []resume.FieldError{
+			{Field: "nickname", Message: "Unknown column"},
+		}},
+		{"unknown field", "experience[0].titel", []resume.FieldError{
+			{Field: "experience[0].titel", Message: `Unknown field "titel" in experience[0]`},
+		}},
+		{"malformed", "experience[0]..company", []resume.FieldError{
+			{Field: "experience[0]..company", Message: "Malformed column name"},
+		}},
+		{"no columns", " , ", []resume.FieldError{
+			{Field: "column 1", Message: "Header row has no columns"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.header+"\n"), 10)
+			var he *HeaderError
+			if !errors.As(err, &he) {
+				t.Fatalf("err = %v, want *HeaderError", err)
+			}
+			if !reflect.DeepEqual(he.Problems, tt.want) {
+				t.Errorf("problems = %+v, want %+v", he.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMaxIndex(t *testing.T) {
+	rows, err := Read(strings.NewReader("fullName,position,summary,experience[99].company\nAnn,Dev,Go,Acme\n"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := rows[0].Resume.Experience; len(exp) != 1 || exp[0].Company != "Acme" {
+		t.Errorf("experience = %+v, want one compacted entry", exp)
+	}
+}
+
+func TestReadColumns(t *testing.T) {
+	csv := "fullName;position;summary;skills;experience[2].company;experience[2].startDate;experience[2].endDate;experience[2].bullets;contacts.links[1].url\n" +
+		"Ann;Dev;Go;\"Go, SQL\nDocker\";Acme;03.2020;по н.в.;\"Built\nShipped\";https://ann.dev\n"
+	rows, err := Read(strings.NewReader(csv), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rows[0]
+	if len(r.Errors) > 0 {
+		t.Fatalf("errors: %+v", r.Errors)
+	}
+	if got := resume.SkillTexts(r.Resume.Skills); !reflect.DeepEqual(got, []string{"Go", "SQL", "Docker"}) {
+		t.Errorf("skills = %q", got)
+	}
+	want := []resume.ExperienceItem{{
+		Company:   "Acme",
+		StartDate: "2020-03",
+		Bullets:   resume.Untagged([]string{"Built", "Shipped"}),
+	}}
+	if !reflect.DeepEqual(r.Resume.Experience, want) {
+		t.Errorf("experience = %+v, want %+v", r.Resume.Experience, want)
+	}
+	if l := r.Resume.Contacts.Links; len(l) != 1 || l[0].URL != "https://ann.dev" {
+		t.Errorf("links = %+v", l)
+	}
+}
+
+func TestReadRowErrors(t *testing.T) {
+	long := strings.Repeat("x", 51)
+	csv := "fullName,position,summary,skills[4],contacts.links[3].url,customSections[2].title,customSections[2].type,experience[1].startDate\n" +
+		"Ann,Dev,Go," + long + ",ann.dev,Talks,poems,13.2020\n"
+	rows, err := Read(strings.NewReader(csv), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// индексы в ошибках валидации указывают на колонки, а не на сжатые списки
+	got := make(map[string]bool)
+	for _, fe := range rows[0].Errors {
+		got[fe.Field] = true
+	}
+	for _, field := range []string{"experience[1].startDate", "skills[4]", "contacts.links[3].url", "customSections[2].type"} {
+		if !got[field] {
+			t.Errorf("no error for column %s in %+v", field, rows[0].Errors)
+		}
+	}
+	if len(rows[0].Errors) != 4 {
+		t.Errorf("errors = %+v, want 4", rows[0].Errors)
+	}
+}
+
+func TestColumnPath(t *testing.T) {
+	origin := map[string][]int{
+		"skills":         {4, 7},
+		"contacts.links": {3},
+		"experience":     {2},
+	}
+	tests := []struct {
+		field, want string
+	}{
+		{"skills[1]", "skills[7]"},
+		{"contacts.links[0].url", "contacts.links[3].url"},
+		{"experience[0].bullets[1]", "experience[2].bullets[1]"},
+		{"education[0].degree", "education[0].degree"},
+		{"experience[5].company", "experience[5].company"},
+		{"fullName", "fullName"},
+	}
+	for _, tt := range tests {
+		if got := columnPath(tt.field, origin); got != tt.want {
+			t.Errorf("columnPath(%q) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	tests := []struct {
+		in      string
+		end     bool
+		want    string
+		wantErr bool
+	}{
+		{"2020-03", false, "2020-03", false},
+		{"2020-3-15", false, "2020-03", false},
+		{"2020-03-15T00:00:00", false, "2020-03", false},
+		{"03.2020", false, "2020-03", false},
+		{"3/2020", false, "2020-03", false},
+		{"15.03.2020", false, "2020-03", false},
+		{"present", true, "", false},
+		{"По н.в.", true, "", false},
+		{"present", false, "", true},
+		{"13.2020", false, "", true},
+		{"2020", false, "", true},
+	}
+	for _, tt := range tests {
+		got, err := parseMonth(tt.in, tt.end)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("parseMonth(%q, %v) = %q, %v", tt.in, tt.end, got, err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"resume_backend/internal/apikey"
+	"resume_backend/internal/ratelimit"
+)
+
+// AuthMiddleware проверяет API-ключ из заголовка X-API-Key или
+// Authorization: Bearer и кладёт его в контекст запроса (apikey.WithKey).
+// Ключу без права scope отвечает 403; пустой scope — достаточно любого
+// действующего ключа. Запрос без ключа получает 401, если required, иначе
+// проходит анонимно. nil keys — аутентификация выключена.
+func AuthMiddleware(keys *apikey.Store, required bool, scope apikey.Scope) Middleware {
+	return func(next stdhttp.Handler) stdhttp.Handler {
+		if keys == nil {
+			return next
+		}
+		return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+			raw := requestAPIKey(r)
+			if raw == "" {
+				if required {
+					writeUnauthorized(w, "API key is required")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			k, err := keys.Authenticate(raw)
+			switch {
+			case errors.Is(err, apikey.ErrDisabled):
+				writeUnauthorized(w, "API key is disabled")
+				return
+			case err != nil:
+				writeUnauthorized(w, "Invalid API key")
+				return
+			case scope != "" && !k.Allows(scope):
+				writeJSONError(w, stdhttp.StatusForbidden, "forbidden", fmt.Sprintf("API key lacks the %q scope", scope))
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(apikey.WithKey(r.Context(), k)))
+		})
+	}
+}
+
+// requestAPIKey достаёт ключ из X-API-Key или Authorization: Bearer.
+func requestAPIKey(r *stdhttp.Request) string {
+	if k := strings.TrimSpace(r.Header.Get("X-API-Key")); k != "" {
+		return k
+	}
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return ""
+}
+
+func writeUnauthorized(w stdhttp.ResponseWriter, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="resume-api"`)
+	writeJSONError(w, stdhttp.StatusUnauthorized, "unauthorized", msg)
+}
+
+// UsageMiddleware учитывает использование API-ключа: байты успешных
+// ответов и, если renders, один рендер на запрос (пакетный рендер
+// резервирует число резюме через reserveRenders и сообщает отданные через
+// setRenderCount). Рендер резервируется вместе с проверкой месячной квоты
+// ключа (исчерпанная даёт 429 quota_exceeded) и отменяется, если запрос не
+// удался. Анонимные запросы не учитываются; nil keys — учёт выключен.
+func UsageMiddleware(keys *apikey.Store, renders bool) Middleware {
+	return func(next stdhttp.Handler) stdhttp.Handler {
+		if keys == nil {
+			return next
+		}
+		return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+			k, ok := apikey.FromContext(r.Context())
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			m := &usageMeter{}
+			if renders {
+				m.renders = 1
+			}
+			res, err := keys.Reserve(k, m.renders)
+			if err != nil {
+				writeQuotaExceeded(w, err)
+				return
+			}
+
+			mw := &meteredWriter{ResponseWriter: w, status: stdhttp.StatusOK}
+			m.res, m.w = res, mw
+			// паника обработчика тоже отменяет резервирование
+			defer res.Release()
+			next.ServeHTTP(mw, r.WithContext(context.WithValue(r.Context(), usageMeterKey{}, m)))
+
+			if mw.status < 300 {
+				res.Commit(m.renders, mw.bytes)
+			}
+		})
+	}
+}
+
+func writeQuotaExceeded(w stdhttp.ResponseWriter, err error) {
+	var qe *apikey.QuotaExceededError
+	if errors.As(err, &qe) {
+		w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(time.Until(qe.ResetAt)), 1)))
+	}
+	writeJSONError(w, stdhttp.StatusTooManyRequests, "quota_exceeded", "Monthly quota of the API key is exhausted")
+}
+
+// usageMeter — учёт одного запроса.
+type usageMeter struct {
+	renders int64
+	res     *apikey.Reservation
+	w       *meteredWriter
+}
+
+type usageMeterKey struct{}
+
+// setRenderCount задаёт число рендеров запроса, если он учитывается
+// (по умолчанию — один).
+func setRenderCount(ctx context.Context, n int) {
+	if m, ok := ctx.Value(usageMeterKey{}).(*usageMeter); ok {
+		m.renders = int64(n)
+	}
+}
+
+// reserveRenders резервирует n рендеров вместо одного по умолчанию.
+// Если они не помещаются в квоту ключа — *apikey.QuotaExceededError.
+func reserveRenders(ctx context.Context, n int) error {
+	m, ok := ctx.Value(usageMeterKey{}).(*usageMeter)
+	if !ok {
+		return nil
+	}
+	if err := m.res.Resize(int64(n)); err != nil {
+		return err
+	}
+	m.renders = int64(n)
+	return nil
+}
+
+// fitsByteQuota сообщает, поместятся ли ещё n байт ответа в квоту байт
+// ключа вместе с уже отданными.
+func fitsByteQuota(ctx context.Context, n int) bool {
+	m, ok := ctx.Value(usageMeterKey{}).(*usageMeter)
+	if !ok {
+		return true
+	}
+	return m.res.FitsBytes(m.w.bytes + int64(n))
+}
+
+// meteredWriter считает статус и байты тела ответа.
+type meteredWriter struct {
+	stdhttp.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *meteredWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *meteredWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap даёт http.ResponseController доступ к исходному ResponseWriter.
+func (w *meteredWriter) Unwrap() stdhttp.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// clientKey — ключ клиента для лимитов запросов: id API-ключа, которым
+// аутентифицирован запрос, иначе адрес клиента. Непроверенный заголовок
+// X-API-Key ключом не считается: новый заголовок на каждый запрос давал бы
+// новую корзину. Лимит — общий для группы маршрутов.
+func (s *Server) clientKey(r *stdhttp.Request) (string, ratelimit.Limit) {
+	if k, ok := apikey.FromContext(r.Context()); ok {
+		return "key:" + k.ID, ratelimit.Limit{}
+	}
+	return "ip:" + s.cfg.TrustedProxies.ClientIP(r), ratelimit.Limit{}
+}
+
+// renderClient — clientKey для маршрутов рендера: у API-ключа может быть
+// свой лимит вместо общего.
+func (s *Server) renderClient(r *stdhttp.Request) (string, ratelimit.Limit) {
+	key, limit := s.clientKey(r)
+	if k, ok := apikey.FromContext(r.Context()); ok && k.Limit != nil {
+		limit = ratelimit.Limit{
+			Rate:        float64(k.Limit.PerMinute) / 60,
+			Burst:       k.Limit.Burst,
+			MaxInFlight: k.Limit.Concurrency,
+		}
+	}
+	return key, limit
+}
+
+// handleUsage возвращает использование API-ключа запроса за текущий и
+// прошлые месяцы. Ключ с правом admin видит все ключи или, с ?key=,
+// один из них.
+func (s *Server) handleUsage(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+	if s.keys == nil {
+		writeJSONError(w, stdhttp.StatusNotFound, "not_found", "API keys are not configured")
+		return
+	}
+	k, ok := apikey.FromContext(r.Context())
+	if !ok {
+		writeUnauthorized(w, "API key is required")
+		return
+	}
+
+	id := r.URL.Query().Get("key")
+	switch {
+	case id == "" && k.Allows(apikey.ScopeAdmin):
+		writeJSON(w, stdhttp.StatusOK, map[string]any{"keys": s.keys.All()})
+	case id == "" || id == k.ID:
+		writeJSON(w, stdhttp.StatusOK, map[string]any{"keys": []apikey.Usage{s.keys.Usage(k)}})
+	case !k.Allows(apikey.ScopeAdmin):
+		writeJSONError(w, stdhttp.StatusForbidden, "forbidden", "Only admin keys can read usage of other keys")
+	default:
+		other, ok := s.keys.Lookup(id)
+		if !ok {
+			writeJSONError(w, stdhttp.StatusNotFound, "not_found", fmt.Sprintf("API key %q not found", id))
+			return
+		}
+		writeJSON(w, stdhttp.StatusOK, map[string]any{"keys": []apikey.Usage{s.keys.Usage(other)}})
+	}
+}
@@ -0,0 +1,41 @@
+package http
+
+import (
+	stdhttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"resume_backend/internal/apikey"
+)
+
+func TestClientKey(t *testing.T) {
+	s := &Server{}
+
+	tests := []struct {
+		name   string
+		header string
+		key    *apikey.Key
+		want   string
+	}{
+		{"anonymous", "", nil, "ip:192.0.2.1"},
+		// без проверки ключей заголовок ничего не доказывает
+		{"unverified header", "random-1", nil, "ip:192.0.2.1"},
+		{"another unverified header", "random-2", nil, "ip:192.0.2.1"},
+		{"authenticated key", "secret", &apikey.Key{ID: "ci"}, "key:ci"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(stdhttp.MethodPost, "/api/v1/resume/pdf", nil)
+			req.RemoteAddr = "192.0.2.1:4321"
+			if tt.header != "" {
+				req.Header.Set("X-API-Key", tt.header)
+			}
+			if tt.key != nil {
+				req = req.WithContext(apikey.WithKey(req.Context(), tt.key))
+			}
+			if got, _ := s.clientKey(req); got != tt.want {
+				t.Errorf("clientKey = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
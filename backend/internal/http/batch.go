@@ -0,0 +1,246 @@
+package http
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	stdhttp "net/http"
+	"time"
+
+	"resume_backend/internal/batch"
+	"resume_backend/internal/csvimport"
+	"resume_backend/internal/resume"
+)
+
+// batchResult — результат рендера одного элемента пакета.
+type batchResult struct {
+	item batch.ItemResult
+	data []byte
+}
+
+// handleBatch рендерит пакет резюме (JSON-массив, NDJSON или CSV) и отвечает
+// ZIP-архивом: по файлу на успешно отрендеренное резюме и report.json
+// с результатом каждого элемента. Резюме проверяются независимо, ошибка
+// одного не останавливает остальные. Формат и вариант задаются ?format=
+// (по умолчанию pdf) и ?variant= для всего пакета.
+func (s *Server) handleBatch(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
+		return
+	}
+
+	format := resume.FormatPDF
+	if v := r.URL.Query().Get("format"); v != "" {
+		f, err := resume.ParseFormat(v)
+		if err != nil || !s.resumeService.Supports(f) {
+			writeJSONError(w, stdhttp.StatusBadRequest, "invalid_format", fmt.Sprintf("Unsupported format %q", v))
+			return
+		}
+		format = f
+	}
+
+	variant, ok := parseVariant(w, r)
+	if !ok {
+		return
+	}
+
+	var (
+		items []batch.Item
+		err   error
+	)
+	switch mediaType(r) {
+	case csvimport.ContentType:
+		rows, ok := s.readCSV(w, r)
+		if !ok {
+			return
+		}
+		items = csvItems(rows)
+	case batch.ContentTypeNDJSON:
+		items, err = batch.ReadNDJSON(r.Body, s.cfg.BatchMaxItems)
+	default:
+		items, err = batch.ReadJSON(r.Body, s.cfg.BatchMaxItems)
+	}
+	var (
+		tooMany *batch.TooManyItemsError
+		mbe     *stdhttp.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &tooMany):
+		writeJSONError(w, stdhttp.StatusRequestEntityTooLarge, "too_many_items", fmt.Sprintf("Batch may contain at most %d resumes", tooMany.Max))
+		return
+	case errors.As(err, &mbe):
+		writePayloadTooLarge(w, mbe.Limit)
+		return
+	case err != nil:
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_json", fmt.Sprintf("Failed to parse batch: %v", err))
+		return
+	case len(items) == 0:
+		writeJSONError(w, stdhttp.StatusBadRequest, "empty_batch", "Batch contains no resumes")
+		return
+	}
+
+	valid := 0
+	for i := range items {
+		if items[i].Err == nil {
+			items[i].Err = resume.CheckPhotoSize(items[i].Resume, s.cfg.MaxPhotoSize)
+		}
+		if items[i].Err == nil {
+			valid++
+		}
+	}
+
+	ctx := r.Context()
+	// пакет целиком должен поместиться в оставшуюся квоту рендеров ключа
+	if err := reserveRenders(ctx, valid); err != nil {
+		writeQuotaExceeded(w, err)
+		return
+	}
+	results := s.renderBatch(ctx, items, format, variant)
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename=resumes.zip")
+	w.WriteHeader(stdhttp.StatusOK)
+
+	// файлы пишутся по мере готовности, report.json — последним
+	zw := zip.NewWriter(w)
+	report := make([]batch.ItemResult, len(items))
+	now := time.Now()
+	rendered := 0
+	// в учёт API-ключа идут только отрендеренные резюме
+	defer func() { setRenderCount(ctx, rendered) }()
+	for range items {
+		var res batchResult
+		select {
+		case res = <-results:
+		case <-ctx.Done():
+			return
+		}
+		if res.item.Status == batch.StatusOK && !fitsByteQuota(ctx, len(res.data)) {
+			res.item.Status, res.item.File, res.item.Simplified = batch.StatusError, "", false
+			res.item.Error, res.item.Message = "quota_exceeded", "Monthly byte quota of the API key is exhausted"
+		}
+		report[res.item.Index] = res.item
+		if res.item.Status != batch.StatusOK {
+			continue
+		}
+		rendered++
+		if err := writeZipFile(zw, res.item.File, res.data, now, format != resume.FormatPDF); err != nil {
+			s.logger.WarnContext(ctx, "batch: write file failed", "file", res.item.File, "error", err)
+			return
+		}
+	}
+
+	data, err := json.MarshalIndent(batch.NewReport(report), "", "  ")
+	if err == nil {
+		err = writeZipFile(zw, "report.json", data, now, true)
+	}
+	if err == nil {
+		err = zw.Close()
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "batch: write report failed", "error", err)
+	}
+}
+
+// renderBatch рендерит элементы пакета параллельно и отдаёт результаты
+// в порядке готовности. Канал буферизован на весь пакет, поэтому воркеры
+// не блокируются, даже если клиент отключился и результаты никто не читает.
+func (s *Server) renderBatch(ctx context.Context, items []batch.Item, format resume.Format, variant resume.Variant) <-chan batchResult {
+	workers := s.cfg.BatchConcurrency
+	if workers <= 0 && format == resume.FormatPDF {
+		workers = s.resumeService.PDFCapacity(ctx)
+	}
+	if workers <= 0 {
+		workers = defaultBatchConcurrency
+	}
+	workers = min(workers, len(items))
+
+	queue := make(chan batch.Item, len(items))
+	for _, it := range items {
+		queue <- it
+	}
+	close(queue)
+
+	results := make(chan batchResult, len(items))
+	for i := 0; i < workers; i++ {
+		go func() {
+			for it := range queue {
+				results <- s.renderBatchItem(ctx, it, len(items), format, variant)
+			}
+		}()
+	}
+	return results
+}
+
+func (s *Server) renderBatchItem(ctx context.Context, it batch.Item, total int, format resume.Format, variant resume.Variant) batchResult {
+	res := batch.ItemResult{Index: it.Index, Line: it.Line, FullName: it.Resume.FullName, Status: batch.StatusError}
+
+	if it.Err != nil {
+		body := decodeFailure(it.Err)
+		res.Error, res.Message, res.Details = body.Error, body.Message, body.Details
+		return batchResult{item: res}
+	}
+	if err := ctx.Err(); err != nil {
+		res.Error, res.Message = "canceled", "Request was canceled"
+		return batchResult{item: res}
+	}
+
+	out, err := s.resumeService.Render(ctx, it.Resume, format, variant)
+	if err != nil {
+		_, body := s.renderFailure(ctx, format, err)
+		res.Error, res.Message, res.Details = body.Error, body.Message, body.Details
+		return batchResult{item: res}
+	}
+
+	res.Status, res.Simplified = batch.StatusOK, out.Simplified
+	res.File = batch.FileName(it.Index, total, it.Resume.FullName, format.Ext())
+	return batchResult{item: res, data: out.Data}
+}
+
+// csvItems превращает строки таблицы в элементы пакета; строки с ошибками
+// ячеек или валидации попадают в отчёт, не доходя до рендера.
+func csvItems(rows []csvimport.Row) []batch.Item {
+	items := make([]batch.Item, len(rows))
+	for i, row := range rows {
+		items[i] = batch.Item{Index: i, Line: row.Line, Resume: row.Resume}
+		if len(row.Errors) > 0 {
+			items[i].Err = &resume.ValidationError{Errors: row.Errors}
+		}
+	}
+	return items
+}
+
+// decodeFailure — тело ошибки для документа, который не удалось разобрать
+// (то же, что writeDecodeError пишет для одиночного запроса).
+func decodeFailure(err error) apiError {
+	var sve *resume.SchemaVersionError
+	if errors.As(err, &sve) {
+		return apiError{Error: "unsupported_schema_version", Message: sve.Error()}
+	}
+	var ve *resume.ValidationError
+	if errors.As(err, &ve) {
+		return apiError{Error: "validation_error", Message: "Invalid resume data", Details: ve.Errors}
+	}
+	var pte *resume.PhotoTooLargeError
+	if errors.As(err, &pte) {
+		return apiError{Error: "payload_too_large", Message: fmt.Sprintf("Photo exceeds %d bytes", pte.Max)}
+	}
+	return apiError{Error: "invalid_json", Message: fmt.Sprintf("Failed to parse resume: %v", err)}
+}
+
+// writeZipFile добавляет файл в архив. PDF уже сжат внутри, поэтому
+// хранится без повторного сжатия.
+func writeZipFile(zw *zip.Writer, name string, data []byte, modified time.Time, deflate bool) error {
+	method := zip.Store
+	if deflate {
+		method = zip.Deflate
+	}
+	f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: method, Modified: modified})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
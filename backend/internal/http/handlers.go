@@ -1,14 +1,28 @@
 package http
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"math"
+	"mime"
+	"mime/multipart"
 	stdhttp "net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
 	"time"
 
+	"resume_backend/internal/jsonresume"
+	"resume_backend/internal/match"
 	"resume_backend/internal/resume"
+	"resume_backend/internal/resumecodec"
+
+	contract "resume_contract"
+	"resume_contract/requestid"
 )
 
 // handleHealth — простой health-check эндпоинт.
@@ -24,19 +38,19 @@ func (s *Server) handleHealth(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 		"service": "resume-backend",
 		"time":    time.Now().UTC().Format(time.RFC3339),
 	}
+	if st := s.resumeService.RendererStatus(); st != nil {
+		resp["renderer"] = st
+	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-// handleGeneratePDF принимает JSON-данные резюме, вызывает доменный сервис
-// и возвращает PDF-файл, сгенерированный latex-service.
+// handleGeneratePDF принимает данные резюме, вызывает доменный сервис
+// и возвращает документ. По умолчанию это PDF из latex-service; другой формат
+// (HTML, Markdown, plain text, DOCX) выбирается параметром ?format= или
+// заголовком Accept.
 func (s *Server) handleGeneratePDF(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 	if r.Method != stdhttp.MethodPost {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(stdhttp.StatusMethodNotAllowed)
-		_ = json.NewEncoder(w).Encode(map[string]string{
-			"error":   "method_not_allowed",
-			"message": "Only POST is allowed",
-		})
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
 		return
 	}
 
@@ -45,46 +59,578 @@ func (s *Server) handleGeneratePDF(w stdhttp.ResponseWriter, r *stdhttp.Request)
 		ctx = context.Background()
 	}
 
-	var req resume.Resume
+	format, err := s.negotiateFormat(r)
+	if err != nil {
+		writeJSONError(w, stdhttp.StatusNotAcceptable, "not_acceptable", err.Error())
+		return
+	}
+
+	variant, ok := parseVariant(w, r)
+	if !ok {
+		return
+	}
 
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
-	if err := dec.Decode(&req); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(stdhttp.StatusBadRequest)
-		_ = json.NewEncoder(w).Encode(map[string]string{
-			"error":   "invalid_json",
-			"message": fmt.Sprintf("Failed to parse request: %v", err),
+	req, ok := s.decodeResume(w, r)
+	if !ok {
+		return
+	}
+
+	s.render(ctx, w, r, req, format, variant)
+}
+
+// render вызывает доменный сервис и пишет документ или ошибку в ответ.
+func (s *Server) render(ctx context.Context, w stdhttp.ResponseWriter, r *stdhttp.Request, req resume.Resume, format resume.Format, variant resume.Variant) {
+	s.renderAs(ctx, w, r, req, format, variant, "attachment")
+}
+
+// renderAs — render с заданным Content-Disposition: "attachment" для API,
+// "inline" для публичных ссылок, которые открываются прямо в браузере.
+// PDF передаётся клиенту потоком, по мере получения от latex-service.
+// Возвращает true, если документ отдан целиком.
+func (s *Server) renderAs(ctx context.Context, w stdhttp.ResponseWriter, r *stdhttp.Request, req resume.Resume, format resume.Format, variant resume.Variant, disposition string) bool {
+	out, err := s.resumeService.RenderStream(ctx, req, format, variant)
+	if err == nil {
+		defer out.Close()
+		err = writeDocument(w, r, out, disposition)
+	}
+	if err != nil {
+		setRetryAfter(w, err)
+		status, body := s.renderFailure(ctx, format, err)
+		writeAPIError(w, status, body)
+		return false
+	}
+	return true
+}
+
+// apiError — тело ответа об ошибке: {"error", "message"} и, для ошибок
+// валидации, "details".
+type apiError struct {
+	Error   string              `json:"error"`
+	Message string              `json:"message"`
+	Details []resume.FieldError `json:"details,omitempty"`
+	// RequestID — идентификатор запроса для поиска в логах.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// renderFailure сопоставляет ошибку рендера со статусом и телом ответа.
+// Используется и синхронным рендером, и статусом фоновых задач.
+func (s *Server) renderFailure(ctx context.Context, format resume.Format, err error) (int, apiError) {
+	var ve *resume.ValidationError
+	if errors.As(err, &ve) {
+		return stdhttp.StatusBadRequest, apiError{Error: "validation_error", Message: "Invalid resume data", Details: ve.Errors}
+	}
+
+	if errors.Is(err, contract.ErrUnsupportedSchemaVersion) {
+		s.logger.ErrorContext(ctx, "latex-service contract mismatch", "error", err)
+		return stdhttp.StatusBadGateway, apiError{Error: contract.ErrCodeUnsupportedSchemaVersion, Message: "PDF renderer does not support this resume schema version"}
+	}
+
+	if errors.Is(err, resume.ErrRendererUnavailable) {
+		s.logger.WarnContext(ctx, "renderer unavailable", "format", format, "error", err)
+		return stdhttp.StatusServiceUnavailable, apiError{Error: "renderer_unavailable", Message: strings.ToUpper(string(format)) + " renderer is temporarily unavailable, try again later"}
+	}
+
+	var mbe *stdhttp.MaxBytesError
+	if errors.As(err, &mbe) {
+		return stdhttp.StatusRequestEntityTooLarge, apiError{Error: "payload_too_large", Message: fmt.Sprintf("Request body exceeds %d bytes", mbe.Limit)}
+	}
+	if errors.Is(err, resume.ErrRequestTooLarge) {
+		s.logger.WarnContext(ctx, "resume is too large for the renderer", "format", format, "error", err)
+		return stdhttp.StatusRequestEntityTooLarge, apiError{Error: "payload_too_large", Message: "Resume is too large for the " + strings.ToUpper(string(format)) + " renderer"}
+	}
+
+	if errors.Is(err, resume.ErrOutputTooLarge) {
+		s.logger.WarnContext(ctx, "rendered document is too large", "format", format, "error", err)
+		return stdhttp.StatusUnprocessableEntity, apiError{Error: "output_too_large", Message: "Generated " + strings.ToUpper(string(format)) + " exceeds the size limit"}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		s.logger.WarnContext(ctx, "render timed out", "format", format, "error", err)
+		return stdhttp.StatusGatewayTimeout, apiError{Error: "generation_timeout", Message: "Timed out generating " + strings.ToUpper(string(format))}
+	}
+
+	s.logger.ErrorContext(ctx, "render failed", "format", format, "error", err)
+	return stdhttp.StatusInternalServerError, apiError{Error: "generation_failed", Message: "Failed to generate " + strings.ToUpper(string(format))}
+}
+
+// setRetryAfter передаёт клиенту подсказку рендерера, когда повторить
+// запрос (например, сколько ещё разомкнут предохранитель).
+func setRetryAfter(w stdhttp.ResponseWriter, err error) {
+	var ue *resume.UnavailableError
+	if !errors.As(err, &ue) {
+		return
+	}
+	secs := int(math.Ceil(ue.RetryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(secs, 1)))
+}
+
+// writeDocument отдаёт документ с заданным Content-Disposition через
+// http.ServeContent: с Content-Length и поддержкой Range для докачки.
+// PDF запасного рендерера помечается заголовком X-Resume-Layout: simplified,
+// чтобы фронтенд предупредил об упрощённой вёрстке. Ошибка возвращается,
+// только пока ответ ещё не начат.
+func writeDocument(w stdhttp.ResponseWriter, r *stdhttp.Request, out resume.Output, disposition string) error {
+	content, err := documentContent(out, r.Header.Get("Range"))
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", out.Format.ContentType())
+	w.Header().Set("Content-Disposition", disposition+"; filename="+out.Format.Filename())
+	w.Header().Add("Vary", "Accept")
+	if out.Simplified {
+		w.Header().Set("X-Resume-Layout", "simplified")
+	}
+	stdhttp.ServeContent(w, r, out.Format.Filename(), time.Time{}, content)
+	return nil
+}
+
+// documentContent возвращает содержимое документа для http.ServeContent.
+// Поток известной длины не собирается в памяти: одиночный диапазон
+// получается перемоткой вперёд. Поток без длины и запрос нескольких
+// диапазонов (их порядок произволен) требуют документа целиком.
+func documentContent(out resume.Output, rangeHeader string) (io.ReadSeeker, error) {
+	if out.Body == nil {
+		return bytes.NewReader(out.Data), nil
+	}
+	if out.Size >= 0 && !strings.Contains(rangeHeader, ",") {
+		return &forwardSeeker{r: out.Body, size: out.Size}, nil
+	}
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", out.Format, err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// forwardSeeker — io.ReadSeeker поверх потока известной длины, которого
+// достаточно http.ServeContent: Seek(0, io.SeekEnd) только сообщает размер,
+// перемотка вперёд пропускает байты, назад — невозможна.
+type forwardSeeker struct {
+	r    io.Reader
+	size int64
+	pos  int64
+}
+
+func (s *forwardSeeker) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.pos += int64(n)
+	return n, err
+}
+
+func (s *forwardSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekEnd:
+		if offset != 0 {
+			return 0, errors.New("forwardSeeker: only Seek(0, io.SeekEnd) is supported")
+		}
+		return s.size, nil
+	case io.SeekCurrent:
+		offset += s.pos
+	}
+	if offset < s.pos {
+		return 0, errors.New("forwardSeeker: cannot seek backwards")
+	}
+	n, err := io.CopyN(io.Discard, s.r, offset-s.pos)
+	s.pos += n
+	return s.pos, err
+}
+
+// handleCheckATS рендерит PDF и возвращает его вместе с JSON-отчётом о том,
+// какие поля резюме ATS извлечёт из него как обычный текст.
+func (s *Server) handleCheckATS(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
+		return
+	}
+
+	variant, ok := parseVariant(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := s.decodeResume(w, r)
+	if !ok {
+		return
+	}
+
+	report, pdf, err := s.resumeService.CheckATS(r.Context(), req, variant)
+	if errors.Is(err, resume.ErrATSNotSupported) {
+		writeJSONError(w, stdhttp.StatusNotImplemented, "not_implemented", "ATS check is not available")
+		return
+	}
+	if err != nil {
+		setRetryAfter(w, err)
+		status, body := s.renderFailure(r.Context(), resume.FormatPDF, err)
+		writeAPIError(w, status, body)
+		return
+	}
+
+	s.writeATSResponse(w, r, report, pdf)
+}
+
+// writeATSResponse отвечает multipart/mixed так же, как latex-service:
+// первая часть — JSON-отчёт ATS-проверки, вторая — PDF.
+func (s *Server) writeATSResponse(w stdhttp.ResponseWriter, r *stdhttp.Request, report contract.ATSReport, pdf []byte) {
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
+	w.WriteHeader(stdhttp.StatusOK)
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+	if err == nil {
+		err = json.NewEncoder(part).Encode(report)
+	}
+	if err == nil {
+		part, err = mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":        {"application/pdf"},
+			"Content-Disposition": {"attachment; filename=" + resume.FormatPDF.Filename()},
 		})
+	}
+	if err == nil {
+		_, err = part.Write(pdf)
+	}
+	if err == nil {
+		err = mw.Close()
+	}
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "failed to write ATS response", "error", err)
+	}
+}
+
+// maxJobDescriptionLength ограничивает длину текста вакансии в /resume/match.
+const maxJobDescriptionLength = 20000
+
+// handleMatch сопоставляет резюме с текстом вакансии и возвращает найденные
+// и недостающие ключевые слова, покрытие по секциям и навыки, которые стоит
+// вынести в skills.
+func (s *Server) handleMatch(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
+		return
+	}
+
+	var body struct {
+		Resume         json.RawMessage `json:"resume"`
+		JobDescription string          `json:"jobDescription"`
+	}
+	if err := decodeJSONBody(r.Body, &body); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+	if len(body.Resume) == 0 {
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_json", "Field \"resume\" is required")
+		return
+	}
+
+	req, err := resume.DecodeJSON(body.Resume)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	var ve resume.ValidationError
+	if strings.TrimSpace(body.JobDescription) == "" {
+		ve.Add("jobDescription", "Job description is required")
+	}
+	if len([]rune(body.JobDescription)) > maxJobDescriptionLength {
+		ve.Add("jobDescription", fmt.Sprintf("Job description is too long (max %d characters)", maxJobDescriptionLength))
+	}
+	if !ve.Empty() {
+		writeValidationError(w, &ve)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(stdhttp.StatusOK)
+	_ = json.NewEncoder(w).Encode(match.Analyze(req, body.JobDescription))
+}
+
+// negotiateFormat выбирает выходной формат: ?format= имеет приоритет над
+// Accept; без обоих (или при */*) возвращается PDF.
+func (s *Server) negotiateFormat(r *stdhttp.Request) (resume.Format, error) {
+	if q := r.URL.Query().Get("format"); q != "" {
+		f, err := resume.ParseFormat(q)
+		if err != nil || !s.resumeService.Supports(f) {
+			return "", fmt.Errorf("format %q is not supported", q)
+		}
+		return f, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return resume.FormatPDF, nil
+	}
+
+	type candidate struct {
+		format resume.Format
+		q      float64
+	}
+	var best *candidate
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		var f resume.Format
+		switch mt {
+		case "*/*", "application/*":
+			f = resume.FormatPDF
+		default:
+			var ok bool
+			f, ok = resume.FormatForMediaType(mt)
+			if !ok || !s.resumeService.Supports(f) {
+				continue
+			}
+		}
+		if best == nil || q > best.q {
+			best = &candidate{format: f, q: q}
+		}
+	}
+
+	if best == nil {
+		return "", fmt.Errorf("none of the requested media types is supported: %s", accept)
+	}
+	return best.format, nil
+}
+
+// handleMigrate поднимает документ резюме до текущей schemaVersion и
+// возвращает его целиком, чтобы сохранённые файлы можно было обновить.
+func (s *Server) handleMigrate(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
 		return
 	}
 
-	pdf, err := s.resumeService.GeneratePDF(ctx, req)
+	raw, err := io.ReadAll(r.Body)
 	if err != nil {
-		var ve *resume.ValidationError
-		if errors.As(err, &ve) {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(stdhttp.StatusBadRequest)
-			_ = json.NewEncoder(w).Encode(map[string]any{
-				"error":   "validation_error",
-				"message": "Invalid resume data",
-				"details": ve.Errors,
-			})
+		writeReadError(w, err)
+		return
+	}
+
+	upgraded, from, err := resume.Migrate(raw)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	doc, err := resume.DecodeJSON(upgraded)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Resume-Migrated-From", fmt.Sprint(from))
+	w.WriteHeader(stdhttp.StatusOK)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(doc)
+}
+
+// handleConvertJSONResume конвертирует документ между форматом JSON Resume
+// и resume.Resume. Направление определяется Content-Type запроса:
+// application/vnd.jsonresume+json -> resume.Resume, application/json -> JSON Resume.
+func (s *Server) handleConvertJSONResume(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
+		return
+	}
+
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeReadError(w, err)
+		return
+	}
+
+	var resp map[string]any
+
+	if mediaType(r) == jsonresume.ContentType {
+		doc, extra, err := jsonresume.Decode(raw)
+		if err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		res, rep := jsonresume.ToResume(doc, extra)
+		resp = map[string]any{
+			"resume":   res,
+			"unmapped": rep.Unmapped,
+		}
+	} else {
+		res, err := resume.DecodeJSON(raw)
+		if err != nil {
+			writeDecodeError(w, err)
 			return
 		}
+		doc, rep := jsonresume.FromResume(res)
+		resp = map[string]any{
+			"jsonResume": doc,
+			"unmapped":   rep.Unmapped,
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(stdhttp.StatusOK)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// parseVariant читает выражение варианта из параметра ?variant=
+// (см. resume.Variant). При ошибке ответ уже записан и ok == false.
+func parseVariant(w stdhttp.ResponseWriter, r *stdhttp.Request) (resume.Variant, bool) {
+	v, err := resume.ParseVariant(r.URL.Query().Get("variant"))
+	if err != nil {
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_variant", err.Error())
+		return resume.Variant{}, false
+	}
+	return v, true
+}
+
+// decodeResume читает тело запроса и превращает его в resume.Resume с учётом
+// Content-Type: обычный JSON мигрируется до текущей схемы и декодируется строго,
+// YAML и TOML проходят ту же миграцию через resumecodec, JSON Resume
+// конвертируется, а несопоставленные поля перечисляются в заголовке
+// X-Resume-Unmapped-Fields. Фото больше MaxPhotoSize отклоняется с 413.
+// При ошибке ответ уже записан и ok == false.
+func (s *Server) decodeResume(w stdhttp.ResponseWriter, r *stdhttp.Request) (resume.Resume, bool) {
+	req, ok := decodeResumeBody(w, r)
+	if !ok {
+		return resume.Resume{}, false
+	}
+	if err := resume.CheckPhotoSize(req, s.cfg.MaxPhotoSize); err != nil {
+		writeDecodeError(w, err)
+		return resume.Resume{}, false
+	}
+	return req, true
+}
+
+// decodeResumeBody — decodeResume без проверки размера фото.
+func decodeResumeBody(w stdhttp.ResponseWriter, r *stdhttp.Request) (resume.Resume, bool) {
+	raw, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeReadError(w, err)
+		return resume.Resume{}, false
+	}
+
+	if format := resumecodec.FormatForMediaType(mediaType(r)); format != "" {
+		req, err := resumecodec.Decode(format, raw)
+		if err != nil {
+			writeDecodeError(w, err)
+			return resume.Resume{}, false
+		}
+		return req, true
+	}
 
-		s.logger.Printf("GeneratePDF error: %v", err)
+	if mediaType(r) == jsonresume.ContentType {
+		doc, extra, err := jsonresume.Decode(raw)
+		if err != nil {
+			writeDecodeError(w, err)
+			return resume.Resume{}, false
+		}
+		req, rep := jsonresume.ToResume(doc, extra)
+		if len(rep.Unmapped) > 0 {
+			fields := make([]string, 0, len(rep.Unmapped))
+			for _, f := range rep.Unmapped {
+				fields = append(fields, f.Field)
+			}
+			w.Header().Set("X-Resume-Unmapped-Fields", strings.Join(fields, ", "))
+		}
+		return req, true
+	}
+
+	req, err := resume.DecodeJSON(raw)
+	if err != nil {
+		writeDecodeError(w, err)
+		return resume.Resume{}, false
+	}
+	return req, true
+}
+
+// writeDecodeError преобразует ошибку разбора документа в JSON-ответ.
+func writeDecodeError(w stdhttp.ResponseWriter, err error) {
+	var (
+		mbe *stdhttp.MaxBytesError
+		pte *resume.PhotoTooLargeError
+	)
+	if errors.As(err, &mbe) {
+		writePayloadTooLarge(w, mbe.Limit)
+		return
+	}
+	if errors.As(err, &pte) {
+		writeJSONError(w, stdhttp.StatusRequestEntityTooLarge, "payload_too_large", fmt.Sprintf("Photo exceeds %d bytes", pte.Max))
+		return
+	}
+
+	var sve *resume.SchemaVersionError
+	if errors.As(err, &sve) {
+		writeJSONError(w, stdhttp.StatusBadRequest, "unsupported_schema_version", sve.Error())
+		return
+	}
+
+	var de *resumecodec.DocumentError
+	if errors.As(err, &de) {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(stdhttp.StatusInternalServerError)
-		_ = json.NewEncoder(w).Encode(map[string]string{
-			"error":   "generation_failed",
-			"message": "Failed to generate PDF",
+		w.WriteHeader(stdhttp.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"error":   "invalid_" + de.Format,
+			"message": de.Error(),
+			"details": de.Problems,
 		})
 		return
 	}
+	writeJSONError(w, stdhttp.StatusBadRequest, "invalid_json", fmt.Sprintf("Failed to parse request: %v", err))
+}
 
-	w.Header().Set("Content-Type", "application/pdf")
-	w.Header().Set("Content-Disposition", "attachment; filename=resume.pdf")
-	w.WriteHeader(stdhttp.StatusOK)
-	_, _ = w.Write(pdf)
+// writeReadError — ответ на ошибку чтения тела запроса: 413, если тело
+// длиннее лимита маршрута (BodyLimitMiddleware), иначе 400.
+func writeReadError(w stdhttp.ResponseWriter, err error) {
+	var mbe *stdhttp.MaxBytesError
+	if errors.As(err, &mbe) {
+		writePayloadTooLarge(w, mbe.Limit)
+		return
+	}
+	writeJSONError(w, stdhttp.StatusBadRequest, "invalid_json", fmt.Sprintf("Failed to read request: %v", err))
+}
+
+func writePayloadTooLarge(w stdhttp.ResponseWriter, limit int64) {
+	writeJSONError(w, stdhttp.StatusRequestEntityTooLarge, "payload_too_large", fmt.Sprintf("Request body exceeds %d bytes", limit))
+}
+
+// decodeJSONBody строго декодирует JSON-объект запроса в v: неизвестные
+// поля и данные после объекта — ошибка.
+func decodeJSONBody(body io.Reader, v any) error {
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return resume.ErrTrailingData
+	}
+	return nil
+}
+
+func writeValidationError(w stdhttp.ResponseWriter, ve *resume.ValidationError) {
+	writeAPIError(w, stdhttp.StatusBadRequest, apiError{Error: "validation_error", Message: "Invalid resume data", Details: ve.Errors})
+}
+
+func writeJSONError(w stdhttp.ResponseWriter, status int, code, msg string) {
+	writeAPIError(w, status, apiError{Error: code, Message: msg})
+}
+
+// writeAPIError пишет ответ об ошибке с идентификатором запроса из
+// заголовка X-Request-ID ответа (его ставит RequestIDMiddleware).
+func writeAPIError(w stdhttp.ResponseWriter, status int, body apiError) {
+	body.RequestID = w.Header().Get(requestid.Header)
+	writeJSON(w, status, body)
 }
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+)
+
+func TestRenderFailure(t *testing.T) {
+	s := &Server{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"renderer rejected the request", fmt.Errorf("latex-service: %w: Request body exceeds 10 bytes", resume.ErrRequestTooLarge), stdhttp.StatusRequestEntityTooLarge, "payload_too_large"},
+		{"body over the limit", fmt.Errorf("read body: %w", &stdhttp.MaxBytesError{Limit: 10}), stdhttp.StatusRequestEntityTooLarge, "payload_too_large"},
+		{"PDF over the limit", resume.ErrOutputTooLarge, stdhttp.StatusUnprocessableEntity, "output_too_large"},
+		{"timeout", context.DeadlineExceeded, stdhttp.StatusGatewayTimeout, "generation_timeout"},
+		{"other", errors.New("boom"), stdhttp.StatusInternalServerError, "generation_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := s.renderFailure(context.Background(), resume.FormatPDF, tt.err)
+			if status != tt.status || body.Error != tt.code {
+				t.Errorf("renderFailure = %d %s, want %d %s", status, body.Error, tt.status, tt.code)
+			}
+		})
+	}
+}
+
+// atsService — ResumeService, у которого реализована только CheckATS.
+type atsService struct {
+	ResumeService
+	err error
+}
+
+func (s atsService) CheckATS(ctx context.Context, req resume.Resume, variant resume.Variant) (contract.ATSReport, []byte, error) {
+	return contract.ATSReport{}, nil, s.err
+}
+
+func TestHandleCheckATSErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		status     int
+		code       string
+		retryAfter string
+	}{
+		{"not supported", resume.ErrATSNotSupported, stdhttp.StatusNotImplemented, "not_implemented", ""},
+		{"validation", &resume.ValidationError{Errors: []resume.FieldError{{Field: "fullName", Message: "required"}}}, stdhttp.StatusBadRequest, "validation_error", ""},
+		{"timeout", fmt.Errorf("check ATS: %w", context.DeadlineExceeded), stdhttp.StatusGatewayTimeout, "generation_timeout", ""},
+		{"renderer rejected the body", &stdhttp.MaxBytesError{Limit: 10}, stdhttp.StatusRequestEntityTooLarge, "payload_too_large", ""},
+		{"renderer unavailable", &resume.UnavailableError{Err: resume.ErrRendererUnavailable, RetryAfter: 2500 * time.Millisecond}, stdhttp.StatusServiceUnavailable, "renderer_unavailable", "3"},
+		{"schema version", contract.ErrUnsupportedSchemaVersion, stdhttp.StatusBadGateway, contract.ErrCodeUnsupportedSchemaVersion, ""},
+		{"other", errors.New("boom"), stdhttp.StatusInternalServerError, "generation_failed", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{resumeService: atsService{err: tt.err}, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+			req := httptest.NewRequest(stdhttp.MethodPost, "/api/v1/resume/ats", strings.NewReader(`{"fullName": "Ivan"}`))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			s.handleCheckATS(rec, req)
+
+			var body apiError
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != tt.status || body.Error != tt.code {
+				t.Errorf("response = %d %s, want %d %s", rec.Code, body.Error, tt.status, tt.code)
+			}
+			if got := rec.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+		})
+	}
+}
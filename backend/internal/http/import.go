@@ -0,0 +1,129 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	stdhttp "net/http"
+	"strconv"
+
+	"resume_backend/internal/csvimport"
+	"resume_backend/internal/resume"
+)
+
+// Статусы строк в ответе импорта.
+const (
+	importCreated = "created"
+	importValid   = "valid"
+	importError   = "error"
+)
+
+// importRow — результат импорта одной строки таблицы.
+type importRow struct {
+	// Row — номер строки в таблице (заголовок — 1).
+	Row      int    `json:"row"`
+	FullName string `json:"fullName,omitempty"`
+	Status   string `json:"status"`
+	// ID — id созданного резюме.
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Details — ошибки ячеек; Field совпадает с именем колонки.
+	Details []resume.FieldError `json:"details,omitempty"`
+}
+
+// importReport — ответ POST /api/v1/resumes/import.
+type importReport struct {
+	DryRun  bool        `json:"dryRun"`
+	Total   int         `json:"total"`
+	Created int         `json:"created"`
+	Failed  int         `json:"failed"`
+	Rows    []importRow `json:"rows"`
+}
+
+// handleImport создаёт сохранённые резюме из CSV: по резюме на строку
+// таблицы. Строки с ошибками пропускаются и перечисляются в отчёте с
+// ошибками по колонкам; остальные сохраняются. С ?dryRun=true ничего не
+// сохраняется — только проверка таблицы перед настоящим импортом.
+func (s *Server) handleImport(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
+		return
+	}
+
+	dryRun := false
+	if v := r.URL.Query().Get("dryRun"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			writeJSONError(w, stdhttp.StatusBadRequest, "invalid_request", "dryRun must be true or false")
+			return
+		}
+		dryRun = b
+	}
+
+	rows, ok := s.readCSV(w, r)
+	if !ok {
+		return
+	}
+
+	rep := importReport{DryRun: dryRun, Total: len(rows), Rows: make([]importRow, 0, len(rows))}
+	for _, row := range rows {
+		res := importRow{Row: row.Line, FullName: row.Resume.FullName}
+		switch {
+		case len(row.Errors) > 0:
+			res.Status, res.Error, res.Details = importError, "validation_error", row.Errors
+		case dryRun:
+			res.Status = importValid
+		default:
+			doc, err := s.repo.Create(r.Context(), row.Resume)
+			if err != nil {
+				s.logger.ErrorContext(r.Context(), "import row failed", "line", row.Line, "error", err)
+				res.Status, res.Error = importError, "storage_error"
+				break
+			}
+			res.Status, res.ID = importCreated, doc.ID
+			rep.Created++
+		}
+		if res.Status == importError {
+			rep.Failed++
+		}
+		rep.Rows = append(rep.Rows, res)
+	}
+
+	writeJSON(w, stdhttp.StatusOK, rep)
+}
+
+// readCSV разбирает тело с таблицей резюме. Ошибки самого файла (заголовок,
+// синтаксис CSV, число строк, размер тела) отвечают целиком; при них
+// ok == false. Слишком большое фото — ошибка строки.
+func (s *Server) readCSV(w stdhttp.ResponseWriter, r *stdhttp.Request) ([]csvimport.Row, bool) {
+	rows, err := csvimport.Read(r.Body, s.cfg.BatchMaxItems)
+
+	var (
+		he      *csvimport.HeaderError
+		tooMany *csvimport.TooManyRowsError
+		mbe     *stdhttp.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &mbe):
+		writePayloadTooLarge(w, mbe.Limit)
+		return nil, false
+	case errors.As(err, &he):
+		writeAPIError(w, stdhttp.StatusBadRequest, apiError{Error: "invalid_csv", Message: "CSV header does not match resume fields", Details: he.Problems})
+		return nil, false
+	case errors.As(err, &tooMany):
+		writeJSONError(w, stdhttp.StatusRequestEntityTooLarge, "too_many_items", fmt.Sprintf("CSV may contain at most %d rows", tooMany.Max))
+		return nil, false
+	case err != nil:
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_csv", fmt.Sprintf("Failed to parse CSV: %v", err))
+		return nil, false
+	case len(rows) == 0:
+		writeJSONError(w, stdhttp.StatusBadRequest, "empty_batch", "CSV contains no resumes")
+		return nil, false
+	}
+
+	for i := range rows {
+		if err := resume.CheckPhotoSize(rows[i].Resume, s.cfg.MaxPhotoSize); err != nil {
+			rows[i].Errors = append(rows[i].Errors, resume.FieldError{Field: "photo.data", Message: err.Error()})
+		}
+	}
+	return rows, true
+}
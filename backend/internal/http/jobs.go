@@ -0,0 +1,326 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	stdhttp "net/http"
+	"time"
+
+	"resume_backend/internal/jobs"
+	"resume_backend/internal/resume"
+)
+
+// createJobRequest — тело POST /api/v1/jobs. Резюме передаётся целиком
+// (resume) или ссылкой на сохранённое (resumeId); в задачу попадает копия,
+// поэтому последующие правки сохранённого резюме её не меняют.
+type createJobRequest struct {
+	Resume   json.RawMessage `json:"resume"`
+	ResumeID string          `json:"resumeId"`
+	// Format — формат результата, как в ?format= (по умолчанию pdf).
+	Format string `json:"format"`
+	// Variant — выражение варианта, как в ?variant=.
+	Variant string `json:"variant"`
+}
+
+// jobView — задача в ответах API.
+type jobView struct {
+	jobs.Job
+	Error     *apiError `json:"error,omitempty"`
+	ResultURL string    `json:"resultUrl,omitempty"`
+}
+
+// jobError — ошибка рендера, уже сопоставленная с HTTP-ответом в момент
+// сбоя, чтобы опрос статуса не логировал её повторно.
+type jobError struct {
+	status int
+	body   apiError
+}
+
+func (e *jobError) Error() string {
+	return e.body.Error + ": " + e.body.Message
+}
+
+// handleJobs принимает задачу рендера и сразу возвращает 202 с её id.
+func (s *Server) handleJobs(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
+		return
+	}
+
+	var req createJobRequest
+	if err := decodeJSONBody(r.Body, &req); err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	format := resume.FormatPDF
+	if req.Format != "" {
+		f, err := resume.ParseFormat(req.Format)
+		if err != nil || !s.resumeService.Supports(f) {
+			writeJSONError(w, stdhttp.StatusBadRequest, "invalid_format", fmt.Sprintf("Unsupported format %q", req.Format))
+			return
+		}
+		format = f
+	}
+
+	variant, err := resume.ParseVariant(req.Variant)
+	if err != nil {
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_variant", err.Error())
+		return
+	}
+
+	var doc resume.Resume
+	switch {
+	case len(req.Resume) > 0 && req.ResumeID != "":
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_request", `Specify either "resume" or "resumeId", not both`)
+		return
+	case req.ResumeID != "":
+		stored, err := s.repo.Get(r.Context(), req.ResumeID)
+		if err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+		doc = stored.Resume
+	case len(req.Resume) > 0:
+		if doc, err = resume.DecodeJSON(req.Resume); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+		if err := resume.CheckPhotoSize(doc, s.cfg.MaxPhotoSize); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+	default:
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_request", `Either "resume" or "resumeId" is required`)
+		return
+	}
+
+	job, err := s.jobs.Submit(r.Context(), s.jobOwner(r), func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error) {
+		out, err := s.resumeService.RenderProgress(ctx, doc, format, variant, report)
+		if err != nil && ctx.Err() == nil {
+			status, body := s.renderFailure(ctx, format, err)
+			return resume.Output{}, &jobError{status: status, body: body}
+		}
+		return out, err
+	})
+	if err != nil {
+		s.writeJobError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/jobs/"+job.ID)
+	writeJSON(w, stdhttp.StatusAccepted, s.jobView(job))
+}
+
+// handleJob обрабатывает /api/v1/jobs/{id}: GET — состояние и прогресс,
+// DELETE — отмена активной задачи или удаление завершённой вместе с результатом.
+func (s *Server) handleJob(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case stdhttp.MethodGet:
+		job, err := s.getJob(r, id)
+		if err != nil {
+			s.writeJobError(w, r, err)
+			return
+		}
+		if !job.State.Done() {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeJSON(w, stdhttp.StatusOK, s.jobView(job))
+
+	case stdhttp.MethodDelete:
+		job, err := s.getJob(r, id)
+		if err != nil {
+			s.writeJobError(w, r, err)
+			return
+		}
+		if job.State.Done() {
+			if _, err := s.jobs.Delete(id); err != nil {
+				s.writeJobError(w, r, err)
+				return
+			}
+			w.WriteHeader(stdhttp.StatusNoContent)
+			return
+		}
+		job, err = s.jobs.Cancel(id)
+		if err != nil {
+			s.writeJobError(w, r, err)
+			return
+		}
+		writeJSON(w, stdhttp.StatusOK, s.jobView(job))
+
+	default:
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET and DELETE are allowed")
+	}
+}
+
+// handleJobResult отдаёт результат успешной задачи. Для незавершённой —
+// 409 с Retry-After, для упавшей — та же ошибка, что вернул бы синхронный рендер.
+func (s *Server) handleJobResult(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+
+	job, out, err := s.jobs.Result(r.PathValue("id"))
+	if err == nil && job.Owner != s.jobOwner(r) {
+		err = jobs.ErrNotFound
+	}
+	if err != nil {
+		s.writeJobError(w, r, err)
+		return
+	}
+
+	switch job.State {
+	case jobs.StateSucceeded:
+		_ = writeDocument(w, r, out, "attachment")
+	case jobs.StateFailed:
+		status, body := jobFailure(job.Err)
+		writeAPIError(w, status, body)
+	case jobs.StateCanceled:
+		writeJSONError(w, stdhttp.StatusConflict, "job_canceled", "Job was canceled")
+	default:
+		w.Header().Set("Retry-After", "1")
+		writeJSONError(w, stdhttp.StatusConflict, "job_not_finished", fmt.Sprintf("Job is %s", job.State))
+	}
+}
+
+// sseKeepAlive — интервал комментариев-пингов в потоке событий, чтобы
+// прокси не закрывали соединение во время долгой компиляции.
+const sseKeepAlive = 15 * time.Second
+
+// handleJobEvents отдаёт ход задачи как Server-Sent Events: событие progress
+// на каждую смену этапа (validating, template, photo, compiling с номером
+// прохода) и одно конечное — done, failed или canceled. data каждого события —
+// то же JSON-представление задачи, что и в GET /api/v1/jobs/{id}.
+func (s *Server) handleJobEvents(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+
+	id := r.PathValue("id")
+	if _, err := s.getJob(r, id); err != nil {
+		s.writeJobError(w, r, err)
+		return
+	}
+	updates, stop, err := s.jobs.Watch(id)
+	if err != nil {
+		s.writeJobError(w, r, err)
+		return
+	}
+	defer stop()
+
+	rc := stdhttp.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// nginx иначе буферизует ответ целиком
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(stdhttp.StatusOK)
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case job, ok := <-updates:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(s.jobView(job))
+			if err != nil {
+				s.logger.ErrorContext(r.Context(), "encode job event failed", "error", err)
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", jobEventName(job.State), data); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// jobEventName — имя SSE-события для состояния задачи.
+func jobEventName(state jobs.State) string {
+	switch state {
+	case jobs.StateSucceeded:
+		return "done"
+	case jobs.StateFailed:
+		return "failed"
+	case jobs.StateCanceled:
+		return "canceled"
+	default:
+		return "progress"
+	}
+}
+
+// jobOwner — владелец задач запроса: API-ключ, которым он аутентифицирован,
+// иначе адрес клиента (как для лимитов запросов).
+func (s *Server) jobOwner(r *stdhttp.Request) string {
+	owner, _ := s.clientKey(r)
+	return owner
+}
+
+// getJob возвращает задачу id, если её поставил тот же владелец. Чужая
+// задача неотличима от несуществующей.
+func (s *Server) getJob(r *stdhttp.Request, id string) (jobs.Job, error) {
+	job, err := s.jobs.Get(id)
+	if err != nil {
+		return jobs.Job{}, err
+	}
+	if job.Owner != s.jobOwner(r) {
+		return jobs.Job{}, jobs.ErrNotFound
+	}
+	return job, nil
+}
+
+func (s *Server) jobView(job jobs.Job) jobView {
+	v := jobView{Job: job}
+	switch job.State {
+	case jobs.StateSucceeded:
+		v.ResultURL = "/api/v1/jobs/" + job.ID + "/result"
+	case jobs.StateFailed:
+		_, body := jobFailure(job.Err)
+		v.Error = &body
+	}
+	return v
+}
+
+// jobFailure возвращает ответ для ошибки задачи. Ошибки без jobError —
+// таймаут задачи или паника в ней.
+func jobFailure(err error) (int, apiError) {
+	var je *jobError
+	if errors.As(err, &je) {
+		return je.status, je.body
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return stdhttp.StatusGatewayTimeout, apiError{Error: "generation_timeout", Message: "Job exceeded its time limit"}
+	}
+	return stdhttp.StatusInternalServerError, apiError{Error: "generation_failed", Message: "Job failed"}
+}
+
+// writeJobError преобразует ошибку jobs.Manager в JSON-ответ.
+func (s *Server) writeJobError(w stdhttp.ResponseWriter, r *stdhttp.Request, err error) {
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		writeJSONError(w, stdhttp.StatusNotFound, "not_found", "Job not found or its result has expired")
+	case errors.Is(err, jobs.ErrQueueFull):
+		w.Header().Set("Retry-After", "5")
+		writeJSONError(w, stdhttp.StatusServiceUnavailable, "queue_full", "Too many pending jobs, try again later")
+	default:
+		s.logger.ErrorContext(r.Context(), "job queue error", "error", err)
+		writeJSONError(w, stdhttp.StatusServiceUnavailable, "unavailable", "Job queue is not accepting jobs")
+	}
+}
@@ -0,0 +1,292 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	stdhttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"resume_backend/internal/jobs"
+	"resume_backend/internal/resume"
+)
+
+func TestHandleJobsRejectsMalformedBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"unknown field", `{"resumeId": "abc", "fromat": "html"}`},
+		{"trailing data", `{"resumeId": "abc"} {}`},
+		{"not an object", `[]`},
+		{"wrong type", `{"format": 1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(stdhttp.MethodPost, "/api/v1/jobs", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			(&Server{}).handleJobs(rec, req)
+
+			if rec.Code != stdhttp.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rec.Code)
+			}
+			var body apiError
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Error != "invalid_json" {
+				t.Errorf("error = %q, want invalid_json", body.Error)
+			}
+		})
+	}
+}
+
+func TestJobsAreVisibleToTheirOwnerOnly(t *testing.T) {
+	m := jobs.NewManager(jobs.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer m.Close()
+	s := &Server{jobs: m}
+
+	job, err := m.Submit(context.Background(), "ip:192.0.2.1", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+		return resume.Output{Format: resume.FormatPDF, Data: []byte("%PDF")}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if j, _ := m.Get(job.ID); j.State.Done() {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	routes := []struct {
+		method  string
+		path    string
+		handler stdhttp.HandlerFunc
+		status  int // для владельца
+	}{
+		{stdhttp.MethodGet, "/api/v1/jobs/{id}", s.handleJob, stdhttp.StatusOK},
+		{stdhttp.MethodGet, "/api/v1/jobs/{id}/result", s.handleJobResult, stdhttp.StatusOK},
+		{stdhttp.MethodGet, "/api/v1/jobs/{id}/events", s.handleJobEvents, stdhttp.StatusOK},
+		{stdhttp.MethodDelete, "/api/v1/jobs/{id}", s.handleJob, stdhttp.StatusNoContent},
+	}
+	for _, owner := range []bool{false, true} {
+		for _, rt := range routes {
+			req := httptest.NewRequest(rt.method, strings.Replace(rt.path, "{id}", job.ID, 1), nil)
+			req.SetPathValue("id", job.ID)
+			req.RemoteAddr = "198.51.100.7:1234"
+			want := stdhttp.StatusNotFound
+			if owner {
+				req.RemoteAddr = "192.0.2.1:1234"
+				want = rt.status
+			}
+			rec := httptest.NewRecorder()
+			rt.handler(rec, req)
+			if rec.Code != want {
+				t.Errorf("%s %s as owner=%v: status = %d, want %d", rt.method, rt.path, owner, rec.Code, want)
+			}
+		}
+	}
+}
+
+// sseEvent — событие потока text/event-stream.
+type sseEvent struct {
+	name string
+	job  jobView
+}
+
+// readEvent читает следующее событие, пропуская комментарии; io.EOF — поток
+// закрыт сервером.
+func readEvent(t *testing.T, r *bufio.Reader) (sseEvent, error) {
+	t.Helper()
+	var ev sseEvent
+	var data string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			if line != "" {
+				t.Fatalf("incomplete event line %q", line)
+			}
+			return ev, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if ev.name == "" {
+				continue
+			}
+			if err := json.Unmarshal([]byte(data), &ev.job); err != nil {
+				t.Fatalf("event %s data %q: %v", ev.name, data, err)
+			}
+			return ev, nil
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "event: "):
+			ev.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		default:
+			t.Fatalf("unexpected line %q", line)
+		}
+	}
+}
+
+func TestHandleJobEvents(t *testing.T) {
+	tests := []struct {
+		name string
+		// finish — что делает задача после первого события
+		finish func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error)
+		cancel bool
+		events []string
+		last   jobs.State
+	}{
+		{
+			name: "done",
+			finish: func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error) {
+				report(resume.Progress{Stage: resume.StageCompiling, Pass: 1, MaxPasses: 2, Percent: 65})
+				return resume.Output{Format: resume.FormatPDF, Data: []byte("%PDF")}, nil
+			},
+			events: []string{"progress", "progress", "done"},
+			last:   jobs.StateSucceeded,
+		},
+		{
+			name: "failed",
+			finish: func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error) {
+				return resume.Output{}, errors.New("boom")
+			},
+			events: []string{"progress", "failed"},
+			last:   jobs.StateFailed,
+		},
+		{
+			name: "canceled",
+			finish: func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error) {
+				<-ctx.Done()
+				return resume.Output{}, ctx.Err()
+			},
+			cancel: true,
+			events: []string{"progress", "canceled"},
+			last:   jobs.StateCanceled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := jobs.NewManager(jobs.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			defer m.Close()
+			s := &Server{jobs: m}
+			mux := stdhttp.NewServeMux()
+			mux.HandleFunc("/api/v1/jobs/{id}/events", s.handleJobEvents)
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			started, step := make(chan struct{}), make(chan struct{})
+			job, err := m.Submit(context.Background(), "ip:127.0.0.1", func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error) {
+				close(started)
+				<-step
+				return tt.finish(ctx, report)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			<-started
+
+			resp, err := srv.Client().Get(srv.URL + "/api/v1/jobs/" + job.ID + "/events")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			if resp.Header.Get("Cache-Control") != "no-cache" || resp.Header.Get("X-Accel-Buffering") != "no" {
+				t.Errorf("stream is cacheable or buffered: %v", resp.Header)
+			}
+
+			body := bufio.NewReader(resp.Body)
+			first, err := readEvent(t, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if first.job.ID != job.ID || first.job.State != jobs.StateRunning {
+				t.Errorf("first event = %+v, want the running job", first.job)
+			}
+
+			if tt.cancel {
+				if _, err := m.Cancel(job.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+			close(step)
+
+			names := []string{first.name}
+			var last sseEvent
+			for {
+				ev, err := readEvent(t, body)
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				names = append(names, ev.name)
+				last = ev
+				if ev.name == "progress" && ev.job.Progress.Stage == resume.StageCompiling && ev.job.Progress.Pass != 1 {
+					t.Errorf("compiling event = %+v", ev.job.Progress)
+				}
+			}
+			if strings.Join(names, ",") != strings.Join(tt.events, ",") {
+				t.Errorf("events = %v, want %v", names, tt.events)
+			}
+			if last.job.State != tt.last {
+				t.Errorf("last event state = %s, want %s", last.job.State, tt.last)
+			}
+			switch tt.last {
+			case jobs.StateSucceeded:
+				if last.job.ResultURL != "/api/v1/jobs/"+job.ID+"/result" {
+					t.Errorf("done event resultUrl = %q", last.job.ResultURL)
+				}
+			case jobs.StateFailed:
+				if last.job.Error == nil || last.job.Error.Error != "generation_failed" {
+					t.Errorf("failed event error = %+v", last.job.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleJobEventsFinishedJob(t *testing.T) {
+	m := jobs.NewManager(jobs.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	defer m.Close()
+	s := &Server{jobs: m}
+
+	job, err := m.Submit(context.Background(), "ip:192.0.2.1", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+		return resume.Output{Format: resume.FormatPDF, Data: []byte("%PDF")}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for {
+		if j, _ := m.Get(job.ID); j.State.Done() {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// завершённая задача: одно конечное событие, и поток закрывается
+	req := httptest.NewRequest(stdhttp.MethodGet, "/api/v1/jobs/"+job.ID+"/events", nil)
+	req.SetPathValue("id", job.ID)
+	req.RemoteAddr = "192.0.2.1:1234"
+	rec := httptest.NewRecorder()
+	s.handleJobEvents(rec, req)
+
+	if !strings.HasPrefix(rec.Body.String(), "event: done\ndata: {") || strings.Count(rec.Body.String(), "event:") != 1 {
+		t.Errorf("body = %q, want a single done event", rec.Body.String())
+	}
+	if !strings.HasSuffix(rec.Body.String(), "}\n\n") {
+		t.Errorf("event is not terminated by a blank line: %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	stdhttp "net/http"
+	"strconv"
+	"time"
+
+	"resume_contract/metrics"
+)
+
+var (
+	httpRequests = metrics.Default.NewCounter(
+		"http_requests_total",
+		"HTTP requests by route pattern, method and status.",
+		"route", "method", "status",
+	)
+	httpDuration = metrics.Default.NewHistogram(
+		"http_request_duration_seconds",
+		"HTTP request latency by route pattern, method and status.",
+		metrics.DurationBuckets,
+		"route", "method", "status",
+	)
+)
+
+// observeRequest записывает запрос в метрики HTTP. route — шаблон
+// маршрута, а не путь: иначе каждый id резюме и токен ссылки давал бы
+// отдельный ряд.
+func observeRequest(route string, r *stdhttp.Request, status int, d time.Duration) {
+	code, method := strconv.Itoa(status), metrics.Method(r.Method)
+	httpRequests.Inc(route, method, code)
+	httpDuration.ObserveDuration(d, route, method, code)
+}
+
+// route возвращает шаблон маршрута, которым mux обрабатывает запрос.
+func (s *Server) route(r *stdhttp.Request) string {
+	_, pattern := s.mux.Handler(r)
+	return pattern
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"resume_contract/metrics"
+)
+
+func TestObserveRequestMethod(t *testing.T) {
+	for _, method := range []string{"GET", "BREW", "get", "X-" + strings.Repeat("A", 100)} {
+		observeRequest("/test/method", httptest.NewRequest(method, "/", nil), 200, time.Millisecond)
+	}
+
+	var out strings.Builder
+	if _, err := metrics.Default.WriteTo(&out); err != nil {
+		t.Fatal(err)
+	}
+	var series []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if strings.HasPrefix(line, `http_requests_total{route="/test/method"`) {
+			series = append(series, line)
+		}
+	}
+	want := []string{
+		`http_requests_total{route="/test/method",method="GET",status="200"} 1`,
+		`http_requests_total{route="/test/method",method="other",status="200"} 3`,
+	}
+	if strings.Join(series, "\n") != strings.Join(want, "\n") {
+		t.Errorf("series =\n%s\nwant\n%s", strings.Join(series, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -1,10 +1,19 @@
 package http
 
 import (
-	"log"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"mime"
 	stdhttp "net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"resume_backend/internal/ratelimit"
+
+	"resume_contract/requestid"
 )
 
 // Middleware описывает функцию-обёртку над http.Handler.
@@ -22,10 +31,25 @@ func (s *Server) applyMiddleware(h stdhttp.Handler, mws ...Middleware) stdhttp.H
 	return h
 }
 
-// LoggingMiddleware логирует входящие запросы и статус-ответ.
-func LoggingMiddleware(logger *log.Logger) Middleware {
+// RequestIDMiddleware принимает идентификатор запроса из заголовка
+// X-Request-ID или создаёт новый, кладёт его в контекст запроса (оттуда
+// его берут логи и клиент latex-service) и возвращает в том же заголовке
+// ответа.
+func RequestIDMiddleware() Middleware {
+	return func(next stdhttp.Handler) stdhttp.Handler {
+		return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+			id := requestid.FromRequest(r)
+			w.Header().Set(requestid.Header, id)
+			next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+		})
+	}
+}
+
+// LoggingMiddleware логирует входящие запросы и статус-ответ и записывает
+// их в метрики HTTP с маршрутом, который возвращает route.
+func LoggingMiddleware(logger *slog.Logger, route func(*stdhttp.Request) string) Middleware {
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
 	}
 
 	return func(next stdhttp.Handler) stdhttp.Handler {
@@ -37,26 +61,30 @@ func LoggingMiddleware(logger *log.Logger) Middleware {
 
 			next.ServeHTTP(ww, r)
 
-			duration := time.Since(start)
-			logger.Printf("%s %s %d %s", r.Method, r.URL.Path, ww.status, duration)
+			elapsed := time.Since(start)
+			observeRequest(route(r), r, ww.status, elapsed)
+			logger.InfoContext(r.Context(), "request",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", ww.status,
+				"duration_ms", float64(elapsed.Microseconds())/1000,
+			)
 		})
 	}
 }
 
 // RecoverMiddleware перехватывает паники и возвращает 500 вместо падения процесса.
-func RecoverMiddleware(logger *log.Logger) Middleware {
+func RecoverMiddleware(logger *slog.Logger) Middleware {
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
 	}
 
 	return func(next stdhttp.Handler) stdhttp.Handler {
 		return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 			defer func() {
 				if rec := recover(); rec != nil {
-					logger.Printf("panic recovered: %v", rec)
-					w.Header().Set("Content-Type", "application/json")
-					w.WriteHeader(stdhttp.StatusInternalServerError)
-					_, _ = w.Write([]byte(`{"error":"internal_error","message":"Internal server error"}`))
+					logger.ErrorContext(r.Context(), "panic recovered", "panic", rec)
+					writeJSONError(w, stdhttp.StatusInternalServerError, "internal_error", "Internal server error")
 				}
 			}()
 			next.ServeHTTP(w, r)
@@ -64,16 +92,90 @@ func RecoverMiddleware(logger *log.Logger) Middleware {
 	}
 }
 
-// JSONOnlyMiddleware гарантирует, что запросы имеют Content-Type application/json.
+// RateLimitMiddleware ограничивает частоту запросов одного клиента;
+// client возвращает ключ клиента и его собственный лимит (нулевой — общий
+// лимит limiter). Ответ получает заголовки RateLimit-Limit,
+// RateLimit-Remaining и RateLimit-Reset, сверх лимита — 429 с Retry-After.
+// nil limiter — без ограничения.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, client func(*stdhttp.Request) (string, ratelimit.Limit)) Middleware {
+	return func(next stdhttp.Handler) stdhttp.Handler {
+		if limiter == nil {
+			return next
+		}
+		return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+			k, limit := client(r)
+			d := limiter.Acquire(k, limit)
+			if d.Limit == 0 {
+				// лимита для клиента нет
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			h := w.Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(d.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(d.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(d.Reset)))
+			if !d.Allowed {
+				retry := max(ceilSeconds(d.RetryAfter), 1)
+				h.Set("Retry-After", strconv.Itoa(retry))
+				msg := fmt.Sprintf("Too many requests, retry in %d s", retry)
+				if d.Concurrency {
+					msg = "Too many concurrent requests, wait for the previous ones to finish"
+				}
+				writeJSONError(w, stdhttp.StatusTooManyRequests, "rate_limited", msg)
+				return
+			}
+			defer limiter.Release(k)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ceilSeconds округляет длительность вверх до целых секунд.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// BodyLimitMiddleware ограничивает тело запроса limit байтами. Запрос
+// с заведомо большим Content-Length отклоняется, не читая тело; иначе
+// чтение сверх лимита возвращает обработчику *http.MaxBytesError, и тот
+// отвечает 413 (см. writeReadError).
+func BodyLimitMiddleware(limit int64) Middleware {
+	return func(next stdhttp.Handler) stdhttp.Handler {
+		return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+			if r.ContentLength > limit {
+				writePayloadTooLarge(w, limit)
+				return
+			}
+			r.Body = stdhttp.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// JSONOnlyMiddleware гарантирует, что запросы имеют Content-Type application/json
+// или JSON-тип с суффиксом +json (например, application/vnd.jsonresume+json).
 func JSONOnlyMiddleware() Middleware {
+	return ContentTypeMiddleware("application/json", "application/*+json")
+}
+
+// ContentTypeMiddleware пропускает запросы с телом (POST/PUT/PATCH), только если
+// их Content-Type входит в список allowed. Шаблон "application/*+json"
+// соответствует любому типу с суффиксом +json.
+func ContentTypeMiddleware(allowed ...string) Middleware {
+	msg := "Content-Type must be " + strings.Join(allowed, ", ")
+	body, _ := json.Marshal(map[string]string{
+		"error":   "unsupported_media_type",
+		"message": msg,
+	})
+
 	return func(next stdhttp.Handler) stdhttp.Handler {
 		return stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 			if r.Method == stdhttp.MethodPost || r.Method == stdhttp.MethodPut || r.Method == stdhttp.MethodPatch {
-				ct := r.Header.Get("Content-Type")
-				if ct == "" || !strings.HasPrefix(strings.ToLower(ct), "application/json") {
+				if !mediaTypeAllowed(mediaType(r), allowed) {
 					w.Header().Set("Content-Type", "application/json")
 					w.WriteHeader(stdhttp.StatusUnsupportedMediaType)
-					_, _ = w.Write([]byte(`{"error":"unsupported_media_type","message":"Content-Type must be application/json"}`))
+					_, _ = w.Write(body)
 					return
 				}
 			}
@@ -82,6 +184,38 @@ func JSONOnlyMiddleware() Middleware {
 	}
 }
 
+func mediaTypeAllowed(mt string, allowed []string) bool {
+	if mt == "" {
+		return false
+	}
+	for _, a := range allowed {
+		if prefix, suffix, ok := strings.Cut(a, "*"); ok {
+			if strings.HasPrefix(mt, prefix) && strings.HasSuffix(mt, suffix) && len(mt) > len(prefix)+len(suffix) {
+				return true
+			}
+			continue
+		}
+		if mt == a {
+			return true
+		}
+	}
+	return false
+}
+
+// mediaType возвращает MIME-тип из Content-Type запроса в нижнем регистре
+// без параметров (charset и т.п.).
+func mediaType(r *stdhttp.Request) string {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(mt)
+}
+
 // responseWriterWrapper используется для захвата статуса ответа.
 type responseWriterWrapper struct {
 	stdhttp.ResponseWriter
@@ -92,3 +226,9 @@ func (w *responseWriterWrapper) WriteHeader(statusCode int) {
 	w.status = statusCode
 	w.ResponseWriter.WriteHeader(statusCode)
 }
+
+// Unwrap даёт http.ResponseController доступ к исходному ResponseWriter
+// (Flush для потоковых ответов).
+func (w *responseWriterWrapper) Unwrap() stdhttp.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,308 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+
+	"resume_backend/internal/resume"
+	"resume_backend/internal/resumediff"
+
+	contract "resume_contract"
+)
+
+// handleResumes обрабатывает /api/v1/resumes: GET — список сохранённых
+// резюме, POST — сохранение нового.
+func (s *Server) handleResumes(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	switch r.Method {
+	case stdhttp.MethodGet:
+		list, err := s.repo.List(r.Context())
+		if err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+		writeJSON(w, stdhttp.StatusOK, map[string]any{"resumes": list})
+
+	case stdhttp.MethodPost:
+		req, ok := s.decodeResume(w, r)
+		if !ok {
+			return
+		}
+		doc, err := s.repo.Create(r.Context(), req)
+		if err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+		w.Header().Set("Location", "/api/v1/resumes/"+doc.ID)
+		setETag(w, doc.Version)
+		writeJSON(w, stdhttp.StatusCreated, doc)
+
+	default:
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET and POST are allowed")
+	}
+}
+
+// handleResume обрабатывает /api/v1/resumes/{id}. PUT и DELETE требуют
+// заголовок If-Match с версией из ETag (оптимистичная блокировка).
+func (s *Server) handleResume(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case stdhttp.MethodGet:
+		doc, err := s.repo.Get(r.Context(), id)
+		if err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+		setETag(w, doc.Version)
+		writeJSON(w, stdhttp.StatusOK, doc)
+
+	case stdhttp.MethodPut:
+		version, ok := ifMatchVersion(w, r)
+		if !ok {
+			return
+		}
+		req, ok := s.decodeResume(w, r)
+		if !ok {
+			return
+		}
+		doc, err := s.repo.Update(r.Context(), id, version, req)
+		if err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+		setETag(w, doc.Version)
+		writeJSON(w, stdhttp.StatusOK, doc)
+
+	case stdhttp.MethodDelete:
+		version, ok := ifMatchVersion(w, r)
+		if !ok {
+			return
+		}
+		if err := s.repo.Delete(r.Context(), id, version); err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+		// ссылки на удалённое резюме всё равно отдали бы 404
+		if err := s.shares.DeleteResume(id); err != nil {
+			s.logger.ErrorContext(r.Context(), "delete share links failed", "resume_id", id, "error", err)
+		}
+		w.WriteHeader(stdhttp.StatusNoContent)
+
+	default:
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET, PUT and DELETE are allowed")
+	}
+}
+
+// handleStoredPDF рендерит сохранённое резюме. Формат и вариант выбираются
+// так же, как в /api/v1/resume/pdf (?format=, Accept, ?variant=).
+func (s *Server) handleStoredPDF(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+
+	format, err := s.negotiateFormat(r)
+	if err != nil {
+		writeJSONError(w, stdhttp.StatusNotAcceptable, "not_acceptable", err.Error())
+		return
+	}
+
+	variant, ok := parseVariant(w, r)
+	if !ok {
+		return
+	}
+
+	doc, err := s.repo.Get(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.writeStorageError(w, r, err)
+		return
+	}
+
+	setETag(w, doc.Version)
+	s.render(r.Context(), w, r, doc.Resume, format, variant)
+}
+
+// handleRevisions возвращает список неизменяемых ревизий резюме.
+func (s *Server) handleRevisions(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+
+	revs, err := s.repo.Revisions(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.writeStorageError(w, r, err)
+		return
+	}
+	writeJSON(w, stdhttp.StatusOK, map[string]any{"revisions": revs})
+}
+
+// handleRevision возвращает резюме в том виде, в каком оно было в ревизии.
+func (s *Server) handleRevision(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+
+	rev, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil || rev < 1 {
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_revision", "Revision must be a positive integer")
+		return
+	}
+
+	doc, err := s.repo.Revision(r.Context(), r.PathValue("id"), rev)
+	if err != nil {
+		s.writeStorageError(w, r, err)
+		return
+	}
+	setETag(w, doc.Version)
+	writeJSON(w, stdhttp.StatusOK, doc)
+}
+
+// handleDiff сравнивает ревизии ?from= и ?to= (по умолчанию — текущую
+// версию с предыдущей). Ответ — список изменений в JSON либо PDF
+// с подсветкой, если запрошен ?format=pdf или Accept: application/pdf.
+func (s *Server) handleDiff(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+
+	id := r.PathValue("id")
+	current, err := s.repo.Get(r.Context(), id)
+	if err != nil {
+		s.writeStorageError(w, r, err)
+		return
+	}
+
+	q := r.URL.Query()
+	to, ok := revisionParam(w, q.Get("to"), "to", current.Version)
+	if !ok {
+		return
+	}
+	from, ok := revisionParam(w, q.Get("from"), "from", max(to-1, 1))
+	if !ok {
+		return
+	}
+
+	a, err := s.repo.Revision(r.Context(), id, from)
+	if err != nil {
+		s.writeStorageError(w, r, err)
+		return
+	}
+	b, err := s.repo.Revision(r.Context(), id, to)
+	if err != nil {
+		s.writeStorageError(w, r, err)
+		return
+	}
+
+	res := resumediff.Compare(a.Resume, b.Resume)
+
+	wantPDF := q.Get("format") == "pdf" ||
+		(q.Get("format") == "" && strings.Contains(r.Header.Get("Accept"), "application/pdf"))
+	if !wantPDF {
+		// JSON-сравнение не рендерит документ
+		setRenderCount(r.Context(), 0)
+		writeJSON(w, stdhttp.StatusOK, map[string]any{
+			"id":      id,
+			"from":    from,
+			"to":      to,
+			"changes": res.Changes,
+		})
+		return
+	}
+
+	pdf, err := s.resumeService.RenderDiff(r.Context(), contract.DiffDocument{
+		Title:    b.Resume.FullName,
+		Subtitle: fmt.Sprintf("Changes from revision %d to revision %d", from, to),
+		Sections: res.Sections,
+	})
+	if err != nil {
+		if errors.Is(err, resume.ErrDiffNotSupported) {
+			writeJSONError(w, stdhttp.StatusNotImplemented, "not_implemented", "Diff PDF is not available")
+			return
+		}
+		if errors.Is(err, resume.ErrRendererUnavailable) {
+			s.logger.WarnContext(r.Context(), "renderer unavailable", "format", "diff", "error", err)
+			setRetryAfter(w, err)
+			writeJSONError(w, stdhttp.StatusServiceUnavailable, "renderer_unavailable", "PDF renderer is temporarily unavailable, try again later")
+			return
+		}
+		if errors.Is(err, resume.ErrRequestTooLarge) {
+			s.logger.WarnContext(r.Context(), "resume is too large for the renderer", "format", "diff", "error", err)
+			writeJSONError(w, stdhttp.StatusRequestEntityTooLarge, "payload_too_large", "Diff is too large for the PDF renderer")
+			return
+		}
+		s.logger.ErrorContext(r.Context(), "render diff PDF failed", "error", err)
+		writeJSONError(w, stdhttp.StatusInternalServerError, "generation_failed", "Failed to generate diff PDF")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=resume-diff-%d-%d.pdf", from, to))
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(stdhttp.StatusOK)
+	_, _ = w.Write(pdf)
+}
+
+// revisionParam разбирает номер ревизии из параметра запроса; пустой
+// параметр даёт def.
+func revisionParam(w stdhttp.ResponseWriter, v, name string, def int) (int, bool) {
+	if v == "" {
+		return def, true
+	}
+	rev, err := strconv.Atoi(v)
+	if err != nil || rev < 1 {
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_revision", fmt.Sprintf("Parameter %q must be a positive integer", name))
+		return 0, false
+	}
+	return rev, true
+}
+
+// ifMatchVersion читает версию из If-Match. Без заголовка изменение
+// отклоняется с 428, чтобы клиент не перезаписал чужие правки вслепую.
+func ifMatchVersion(w stdhttp.ResponseWriter, r *stdhttp.Request) (int, bool) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" {
+		writeJSONError(w, stdhttp.StatusPreconditionRequired, "precondition_required", "If-Match header with the resume version is required")
+		return 0, false
+	}
+
+	v, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(h, "W/"), `"`))
+	if err != nil || v < 1 {
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_version", fmt.Sprintf("If-Match must contain a resume version, got %q", h))
+		return 0, false
+	}
+	return v, true
+}
+
+func setETag(w stdhttp.ResponseWriter, version int) {
+	w.Header().Set("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// writeStorageError преобразует ошибку репозитория в JSON-ответ.
+func (s *Server) writeStorageError(w stdhttp.ResponseWriter, r *stdhttp.Request, err error) {
+	var vc *resume.VersionConflictError
+	switch {
+	case errors.Is(err, resume.ErrNotFound):
+		writeJSONError(w, stdhttp.StatusNotFound, "not_found", "Resume not found")
+	case errors.Is(err, resume.ErrRevisionNotFound):
+		writeJSONError(w, stdhttp.StatusNotFound, "not_found", "Resume revision not found")
+	case errors.As(err, &vc):
+		setETag(w, vc.Current)
+		writeJSONError(w, stdhttp.StatusPreconditionFailed, "version_conflict", vc.Error())
+	default:
+		s.logger.ErrorContext(r.Context(), "storage error", "error", err)
+		writeJSONError(w, stdhttp.StatusInternalServerError, "storage_error", "Failed to access resume storage")
+	}
+}
+
+func writeJSON(w stdhttp.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -2,37 +2,132 @@ package http
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	stdhttp "net/http"
 
+	"resume_backend/internal/apikey"
+	"resume_backend/internal/batch"
+	"resume_backend/internal/csvimport"
+	"resume_backend/internal/jobs"
+	"resume_backend/internal/ratelimit"
 	"resume_backend/internal/resume"
+	"resume_backend/internal/share"
+
+	contract "resume_contract"
+	"resume_contract/metrics"
 )
 
 // ResumeService — интерфейс доменного сервиса, который знает,
-// как из модели Resume сделать PDF.
+// как из модели Resume сделать PDF и другие форматы.
 type ResumeService interface {
 	GeneratePDF(ctx context.Context, req resume.Resume) ([]byte, error)
+	Render(ctx context.Context, req resume.Resume, format resume.Format, variant resume.Variant) (resume.Output, error)
+	RenderStream(ctx context.Context, req resume.Resume, format resume.Format, variant resume.Variant) (resume.Output, error)
+	RenderProgress(ctx context.Context, req resume.Resume, format resume.Format, variant resume.Variant, report resume.ProgressFunc) (resume.Output, error)
+	Supports(format resume.Format) bool
+	PDFCapacity(ctx context.Context) int
+	RendererStatus() any
+	CheckATS(ctx context.Context, req resume.Resume, variant resume.Variant) (contract.ATSReport, []byte, error)
+	RenderDiff(ctx context.Context, doc contract.DiffDocument) ([]byte, error)
+}
+
+// resumeInputTypes — форматы тела запроса, из которых читается резюме.
+var resumeInputTypes = []string{
+	"application/json",
+	"application/*+json",
+	"application/yaml",
+	"application/x-yaml",
+	"text/yaml",
+	"application/toml",
+}
+
+// Config — настройки HTTP-слоя; нулевые значения заменяются дефолтами.
+type Config struct {
+	// BatchMaxItems — предельное число резюме в одном пакетном запросе.
+	BatchMaxItems int
+	// BatchConcurrency — сколько резюме пакета рендерится одновременно;
+	// 0 — по размеру пула latex-service.
+	BatchConcurrency int
+	// MaxBodySize — предельный размер тела запроса в байтах для маршрутов
+	// с одним документом.
+	MaxBodySize int64
+	// MaxBatchBodySize — то же для пакетного рендера и импорта CSV.
+	MaxBatchBodySize int64
+	// MaxPhotoSize — предельный размер фото после декодирования base64.
+	MaxPhotoSize int64
+	// RateLimitPDF — лимит одного клиента на маршруты рендера,
+	// RateLimitValidate — на остальные маршруты API (разбор, проверка и
+	// хранение документов без рендера). Нулевой лимит — без ограничения.
+	RateLimitPDF      ratelimit.Limit
+	RateLimitValidate ratelimit.Limit
+	// TrustedProxies — прокси, чьему X-Forwarded-For верится при
+	// определении адреса клиента.
+	TrustedProxies ratelimit.Proxies
+	// RequireAPIKey — запросы к API без ключа отклоняются с 401; иначе
+	// проходят анонимно (ключ, если передан, всё равно проверяется).
+	// Действует, только если ключи настроены.
+	RequireAPIKey bool
 }
 
+const (
+	defaultBatchMaxItems    = 200
+	defaultMaxBodySize      = contract.DefaultMaxRequestSize
+	defaultMaxBatchBodySize = 64 << 20
+	defaultMaxPhotoSize     = contract.DefaultMaxRequestSize / 2
+	// defaultBatchConcurrency используется, если latex-service не сообщил
+	// размер пула.
+	defaultBatchConcurrency = 2
+)
+
 // Server инкапсулирует HTTP-маршрутизацию backend API.
 type Server struct {
+	cfg           Config
 	mux           *stdhttp.ServeMux
 	resumeService ResumeService
-	logger        *log.Logger
+	repo          resume.Repository
+	shares        *share.Store
+	jobs          *jobs.Manager
+	keys          *apikey.Store
+	logger        *slog.Logger
+
+	// лимиты клиентов
+	pdfLimit      *ratelimit.Limiter
+	validateLimit *ratelimit.Limiter
 }
 
-// NewServer создаёт новый экземпляр HTTP-сервера.
-func NewServer(resumeService ResumeService, logger *log.Logger) *Server {
+// NewServer создаёт новый экземпляр HTTP-сервера. keys == nil —
+// API-ключи не настроены, запросы не аутентифицируются.
+func NewServer(resumeService ResumeService, repo resume.Repository, shares *share.Store, jobQueue *jobs.Manager, keys *apikey.Store, cfg Config, logger *slog.Logger) *Server {
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
+	}
+	if cfg.BatchMaxItems <= 0 {
+		cfg.BatchMaxItems = defaultBatchMaxItems
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultMaxBodySize
+	}
+	if cfg.MaxBatchBodySize <= 0 {
+		cfg.MaxBatchBodySize = defaultMaxBatchBodySize
+	}
+	if cfg.MaxPhotoSize <= 0 {
+		cfg.MaxPhotoSize = defaultMaxPhotoSize
 	}
 
 	mux := stdhttp.NewServeMux()
 
 	s := &Server{
+		cfg:           cfg,
 		mux:           mux,
 		resumeService: resumeService,
+		repo:          repo,
+		shares:        shares,
+		jobs:          jobQueue,
+		keys:          keys,
 		logger:        logger,
+		// даже с нулевым общим лимитом у API-ключа может быть свой
+		pdfLimit:      ratelimit.New(cfg.RateLimitPDF),
+		validateLimit: ratelimit.New(cfg.RateLimitValidate),
 	}
 
 	s.registerRoutes()
@@ -42,12 +137,16 @@ func NewServer(resumeService ResumeService, logger *log.Logger) *Server {
 
 // registerRoutes регистрирует маршруты и навешивает middleware.
 func (s *Server) registerRoutes() {
+	// метрики Prometheus; наружу через gateway не публикуются
+	s.mux.Handle("/metrics", metrics.Default.Handler())
+
 	// health-check
 	s.mux.Handle(
 		"/healthz",
 		s.applyMiddleware(
 			stdhttp.HandlerFunc(s.handleHealth),
-			LoggingMiddleware(s.logger),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
 			RecoverMiddleware(s.logger),
 		),
 	)
@@ -57,8 +156,288 @@ func (s *Server) registerRoutes() {
 		"/api/v1/resume/pdf",
 		s.applyMiddleware(
 			stdhttp.HandlerFunc(s.handleGeneratePDF),
-			LoggingMiddleware(s.logger),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, apikey.ScopeRender),
+			RateLimitMiddleware(s.pdfLimit, s.renderClient),
+			UsageMiddleware(s.keys, true),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			ContentTypeMiddleware(resumeInputTypes...),
+		),
+	)
+
+	// пакетный рендер: массив, NDJSON или CSV резюме → ZIP с PDF и report.json
+	s.mux.Handle(
+		"/api/v1/resume/batch",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleBatch),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, apikey.ScopeBatch),
+			RateLimitMiddleware(s.pdfLimit, s.renderClient),
+			UsageMiddleware(s.keys, true),
+			BodyLimitMiddleware(s.cfg.MaxBatchBodySize),
+			ContentTypeMiddleware("application/json", batch.ContentTypeNDJSON, csvimport.ContentType),
+		),
+	)
+
+	// проверка извлекаемости текста из PDF (ATS)
+	s.mux.Handle(
+		"/api/v1/resume/ats",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleCheckATS),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, apikey.ScopeRender),
+			RateLimitMiddleware(s.pdfLimit, s.renderClient),
+			UsageMiddleware(s.keys, true),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			ContentTypeMiddleware(resumeInputTypes...),
+		),
+	)
+
+	// сохранённые резюме: список и создание
+	s.mux.Handle(
+		"/api/v1/resumes",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleResumes),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			ContentTypeMiddleware(resumeInputTypes...),
+		),
+	)
+
+	// импорт резюме из CSV-таблицы
+	s.mux.Handle(
+		"/api/v1/resumes/import",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleImport),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, apikey.ScopeBatch),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			BodyLimitMiddleware(s.cfg.MaxBatchBodySize),
+			ContentTypeMiddleware(csvimport.ContentType),
+		),
+	)
+
+	// сохранённое резюме: чтение, замена, удаление
+	s.mux.Handle(
+		"/api/v1/resumes/{id}",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleResume),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			ContentTypeMiddleware(resumeInputTypes...),
+		),
+	)
+
+	// рендер сохранённого резюме (формат и вариант — как у /api/v1/resume/pdf)
+	s.mux.Handle(
+		"/api/v1/resumes/{id}/pdf",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleStoredPDF),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, apikey.ScopeRender),
+			RateLimitMiddleware(s.pdfLimit, s.renderClient),
+			UsageMiddleware(s.keys, true),
+		),
+	)
+
+	// история ревизий сохранённого резюме
+	s.mux.Handle(
+		"/api/v1/resumes/{id}/revisions",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleRevisions),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+		),
+	)
+	s.mux.Handle(
+		"/api/v1/resumes/{id}/revisions/{rev}",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleRevision),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+		),
+	)
+
+	// сравнение двух ревизий: JSON или PDF с подсветкой
+	s.mux.Handle(
+		"/api/v1/resumes/{id}/diff",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleDiff),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, apikey.ScopeRender),
+			RateLimitMiddleware(s.pdfLimit, s.renderClient),
+			UsageMiddleware(s.keys, true),
+		),
+	)
+
+	// публичные ссылки на сохранённое резюме: выдача, список, отзыв
+	s.mux.Handle(
+		"/api/v1/resumes/{id}/shares",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleShares),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			JSONOnlyMiddleware(),
+		),
+	)
+	s.mux.Handle(
+		"/api/v1/resumes/{id}/shares/{share}",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleShare),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+		),
+	)
+
+	// открытие документа по публичной ссылке
+	s.mux.Handle(
+		"/s/{token}",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleSharedDocument),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			RateLimitMiddleware(s.pdfLimit, s.clientKey),
+		),
+	)
+
+	// фоновые задачи рендера: постановка, статус, поток событий (SSE), отмена, результат
+	s.mux.Handle(
+		"/api/v1/jobs",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleJobs),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, apikey.ScopeRender),
+			RateLimitMiddleware(s.pdfLimit, s.renderClient),
+			UsageMiddleware(s.keys, true),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			JSONOnlyMiddleware(),
+		),
+	)
+	s.mux.Handle(
+		"/api/v1/jobs/{id}",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleJob),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+		),
+	)
+	s.mux.Handle(
+		"/api/v1/jobs/{id}/events",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleJobEvents),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+		),
+	)
+	s.mux.Handle(
+		"/api/v1/jobs/{id}/result",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleJobResult),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			UsageMiddleware(s.keys, false),
+		),
+	)
+
+	// сопоставление резюме с текстом вакансии
+	s.mux.Handle(
+		"/api/v1/resume/match",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleMatch),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			JSONOnlyMiddleware(),
+		),
+	)
+
+	// миграция документа резюме до текущей schemaVersion
+	s.mux.Handle(
+		"/api/v1/resume/migrate",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleMigrate),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
+			JSONOnlyMiddleware(),
+		),
+	)
+
+	// использование API-ключа: рендеры и байты по месяцам
+	s.mux.Handle(
+		"/api/v1/usage",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleUsage),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
+			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+		),
+	)
+
+	// конвертация между resume.Resume и JSON Resume (jsonresume.org)
+	s.mux.Handle(
+		"/api/v1/convert/jsonresume",
+		s.applyMiddleware(
+			stdhttp.HandlerFunc(s.handleConvertJSONResume),
+			RequestIDMiddleware(),
+			LoggingMiddleware(s.logger, s.route),
 			RecoverMiddleware(s.logger),
+			AuthMiddleware(s.keys, s.cfg.RequireAPIKey, ""),
+			RateLimitMiddleware(s.validateLimit, s.clientKey),
+			BodyLimitMiddleware(s.cfg.MaxBodySize),
 			JSONOnlyMiddleware(),
 		),
 	)
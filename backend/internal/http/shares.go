@@ -0,0 +1,230 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	stdhttp "net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"resume_backend/internal/resume"
+	"resume_backend/internal/share"
+)
+
+// maxSharePasswordLength ограничивает пароль ссылки: PBKDF2 хэширует его
+// целиком на каждую попытку входа.
+const maxSharePasswordLength = 128
+
+// createShareRequest — тело POST /api/v1/resumes/{id}/shares.
+type createShareRequest struct {
+	// ExpiresIn — срок действия: "72h", "30m" или "7d". Пусто — share.DefaultTTL.
+	ExpiresIn string `json:"expiresIn"`
+	// Password — необязательный пароль, который получатель вводит в браузере.
+	Password string `json:"password"`
+	// Format — pdf (по умолчанию) или html.
+	Format string `json:"format"`
+	// Variant — выражение варианта, как в ?variant=.
+	Variant string `json:"variant"`
+}
+
+// handleShares обрабатывает /api/v1/resumes/{id}/shares: GET — ссылки резюме
+// со счётчиками обращений, POST — выдача новой ссылки.
+func (s *Server) handleShares(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	id := r.PathValue("id")
+
+	switch r.Method {
+	case stdhttp.MethodGet:
+		if _, err := s.repo.Get(r.Context(), id); err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+		writeJSON(w, stdhttp.StatusOK, map[string]any{"shares": s.shares.List(id)})
+
+	case stdhttp.MethodPost:
+		var req createShareRequest
+		if err := decodeJSONBody(r.Body, &req); err != nil {
+			writeDecodeError(w, err)
+			return
+		}
+
+		opts, errs := s.shareOptions(req)
+		if len(errs) > 0 {
+			writeAPIError(w, stdhttp.StatusBadRequest, apiError{Error: "validation_error", Message: "Invalid share link parameters", Details: errs})
+			return
+		}
+		opts.ResumeID = id
+
+		if _, err := s.repo.Get(r.Context(), id); err != nil {
+			s.writeStorageError(w, r, err)
+			return
+		}
+
+		info, err := s.shares.Create(opts)
+		if err != nil {
+			s.writeShareError(w, r, err)
+			return
+		}
+		w.Header().Set("Location", "/api/v1/resumes/"+id+"/shares/"+info.ID)
+		writeJSON(w, stdhttp.StatusCreated, info)
+
+	default:
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET and POST are allowed")
+	}
+}
+
+// shareOptions проверяет тело запроса и собирает share.Options. Ошибки
+// возвращаются в формате FieldError, как у валидации резюме.
+func (s *Server) shareOptions(req createShareRequest) (share.Options, []resume.FieldError) {
+	var (
+		opts share.Options
+		errs []resume.FieldError
+	)
+
+	if req.ExpiresIn != "" {
+		ttl, err := parseTTL(req.ExpiresIn)
+		switch {
+		case err != nil:
+			errs = append(errs, resume.FieldError{Field: "expiresIn", Message: `Must be a duration such as "72h" or "7d"`})
+		case ttl < time.Minute || ttl > share.MaxTTL:
+			errs = append(errs, resume.FieldError{Field: "expiresIn", Message: fmt.Sprintf("Must be between 1m and %dd", int(share.MaxTTL.Hours()/24))})
+		default:
+			opts.TTL = ttl
+		}
+	}
+
+	if len(req.Password) > maxSharePasswordLength {
+		errs = append(errs, resume.FieldError{Field: "password", Message: fmt.Sprintf("Must be at most %d characters", maxSharePasswordLength)})
+	}
+	opts.Password = req.Password
+
+	opts.Format = resume.FormatPDF
+	if req.Format != "" {
+		f, err := resume.ParseFormat(req.Format)
+		if err != nil || (f != resume.FormatPDF && f != resume.FormatHTML) || !s.resumeService.Supports(f) {
+			errs = append(errs, resume.FieldError{Field: "format", Message: "Must be pdf or html"})
+		} else {
+			opts.Format = f
+		}
+	}
+
+	v, err := resume.ParseVariant(req.Variant)
+	if err != nil {
+		errs = append(errs, resume.FieldError{Field: "variant", Message: err.Error()})
+	}
+	opts.Variant = v
+
+	return opts, errs
+}
+
+// parseTTL разбирает длительность в формате time.ParseDuration и,
+// дополнительно, целое число дней с суффиксом "d".
+func parseTTL(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(v)
+}
+
+// handleShare обрабатывает /api/v1/resumes/{id}/shares/{share}: GET — ссылка
+// со счётчиком, DELETE — отзыв (ссылка остаётся в списке со статусом revoked).
+func (s *Server) handleShare(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	id, shareID := r.PathValue("id"), r.PathValue("share")
+
+	var (
+		info share.Info
+		err  error
+	)
+	switch r.Method {
+	case stdhttp.MethodGet:
+		info, err = s.shares.Get(id, shareID)
+	case stdhttp.MethodDelete:
+		info, err = s.shares.Revoke(id, shareID)
+	default:
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET and DELETE are allowed")
+		return
+	}
+	if err != nil {
+		s.writeShareError(w, r, err)
+		return
+	}
+	writeJSON(w, stdhttp.StatusOK, info)
+}
+
+// handleSharedDocument отдаёт резюме по публичной ссылке /s/{token} в формате
+// ссылки (или ?format=pdf|html). Пароль принимается через HTTP Basic
+// (имя пользователя игнорируется), поэтому браузер сам покажет форму входа.
+func (s *Server) handleSharedDocument(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodGet {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only GET is allowed")
+		return
+	}
+
+	// токен в URL не должен утекать в Referer, кэши и поисковики
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("X-Robots-Tag", "noindex, nofollow")
+
+	_, password, _ := r.BasicAuth()
+	link, err := s.shares.Authorize(r.PathValue("token"), password)
+	if err != nil {
+		s.writeShareError(w, r, err)
+		return
+	}
+
+	format := link.Format
+	if v := r.URL.Query().Get("format"); v != "" {
+		f, err := resume.ParseFormat(v)
+		if err != nil || (f != resume.FormatPDF && f != resume.FormatHTML) {
+			writeJSONError(w, stdhttp.StatusNotAcceptable, "not_acceptable", "Shared resumes are available as pdf or html")
+			return
+		}
+		format = f
+	}
+
+	variant, err := resume.ParseVariant(link.Variant)
+	if err != nil {
+		// выражение проверено при выдаче ссылки
+		s.logger.ErrorContext(r.Context(), "share link has a damaged variant", "share_id", link.ID, "variant", link.Variant, "error", err)
+		writeJSONError(w, stdhttp.StatusInternalServerError, "internal_error", "Share link is damaged")
+		return
+	}
+
+	doc, err := s.repo.Get(r.Context(), link.ResumeID)
+	if err != nil {
+		s.writeStorageError(w, r, err)
+		return
+	}
+
+	// обращение считается, только если документ действительно отдан
+	if !s.renderAs(r.Context(), w, r, doc.Resume, format, variant, "inline") {
+		return
+	}
+	s.shares.RecordAccess(link.ID)
+}
+
+// writeShareError преобразует ошибку share.Store в JSON-ответ.
+func (s *Server) writeShareError(w stdhttp.ResponseWriter, r *stdhttp.Request, err error) {
+	switch {
+	case errors.Is(err, share.ErrNotFound), errors.Is(err, share.ErrInvalidToken):
+		writeJSONError(w, stdhttp.StatusNotFound, "not_found", "Share link not found")
+	case errors.Is(err, share.ErrExpired):
+		writeJSONError(w, stdhttp.StatusGone, "share_expired", "Share link has expired")
+	case errors.Is(err, share.ErrRevoked):
+		writeJSONError(w, stdhttp.StatusGone, "share_revoked", "Share link has been revoked")
+	case errors.Is(err, share.ErrPasswordRequired):
+		w.Header().Set("WWW-Authenticate", `Basic realm="Shared resume", charset="UTF-8"`)
+		writeJSONError(w, stdhttp.StatusUnauthorized, "password_required", "Share link is password protected")
+	case errors.Is(err, share.ErrWrongPassword):
+		w.Header().Set("WWW-Authenticate", `Basic realm="Shared resume", charset="UTF-8"`)
+		writeJSONError(w, stdhttp.StatusUnauthorized, "invalid_password", "Wrong share link password")
+	default:
+		s.logger.ErrorContext(r.Context(), "share storage error", "error", err)
+		writeJSONError(w, stdhttp.StatusInternalServerError, "storage_error", "Failed to access share links")
+	}
+}
@@ -0,0 +1,423 @@
+// Package jobs — очередь фоновых задач внутри процесса backend. Задача
+// выполняется одним из воркеров, её состояние и результат доступны по id до
+// истечения TTL после завершения. Очередь ограничена: при переполнении
+// Submit возвращает ErrQueueFull, а не копит задачи в памяти.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+// State — состояние задачи.
+type State string
+
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCanceled  State = "canceled"
+)
+
+// Done сообщает, завершилась ли задача (успешно или нет).
+func (s State) Done() bool {
+	return s == StateSucceeded || s == StateFailed || s == StateCanceled
+}
+
+var (
+	// ErrNotFound — задачи нет или её результат уже удалён по TTL.
+	ErrNotFound = errors.New("job not found")
+	// ErrQueueFull — в очереди нет места.
+	ErrQueueFull = errors.New("job queue is full")
+	// ErrClosed — менеджер остановлен.
+	ErrClosed = errors.New("job manager is closed")
+)
+
+// Func — работа задачи. ctx отменяется при Cancel, Close и по таймауту;
+// через report задача сообщает о своих этапах.
+type Func func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error)
+
+// Job — снимок состояния задачи.
+type Job struct {
+	ID         string          `json:"id"`
+	State      State           `json:"state"`
+	Progress   resume.Progress `json:"progress"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	StartedAt  *time.Time      `json:"startedAt,omitempty"`
+	FinishedAt *time.Time      `json:"finishedAt,omitempty"`
+	// ExpiresAt — когда завершённая задача будет удалена вместе с результатом.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// Owner — кто поставил задачу (API-ключ или адрес клиента); HTTP-слой
+	// показывает задачу только ему.
+	Owner string `json:"-"`
+	// Err — ошибка задачи в состоянии failed; в JSON её выводит HTTP-слой.
+	Err error `json:"-"`
+}
+
+// Config — параметры менеджера; нулевые значения заменяются дефолтами.
+type Config struct {
+	// Workers — число задач, выполняемых одновременно.
+	Workers int
+	// QueueSize — сколько задач может ждать воркера.
+	QueueSize int
+	// TTL — сколько хранится завершённая задача и её результат.
+	TTL time.Duration
+	// Timeout — максимальная длительность выполнения одной задачи.
+	Timeout time.Duration
+}
+
+const (
+	defaultWorkers   = 2
+	defaultQueueSize = 64
+	defaultTTL       = 15 * time.Minute
+	defaultTimeout   = 5 * time.Minute
+)
+
+type entry struct {
+	job    Job
+	fn     Func
+	result resume.Output
+	ctx    context.Context
+	cancel context.CancelFunc
+	// watchers получают снимки задачи при каждом изменении, см. Watch.
+	watchers map[chan Job]struct{}
+}
+
+// notify рассылает текущий снимок наблюдателям. Медленный наблюдатель
+// теряет промежуточные снимки, но не последний: вместо него выбрасывается
+// самый старый. Вызывается под Manager.mu.
+func (e *entry) notify() {
+	for ch := range e.watchers {
+		select {
+		case ch <- e.job:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- e.job
+		}
+		if e.job.State.Done() {
+			close(ch)
+			delete(e.watchers, ch)
+		}
+	}
+}
+
+// Manager управляет очередью и воркерами.
+type Manager struct {
+	cfg    Config
+	logger *slog.Logger
+	now    func() time.Time
+
+	queue chan *entry
+	stop  chan struct{}
+	wg    sync.WaitGroup
+
+	mu     sync.Mutex
+	jobs   map[string]*entry
+	closed bool
+}
+
+// NewManager запускает воркеры и фоновую очистку устаревших задач.
+func NewManager(cfg Config, logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultQueueSize
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = defaultTTL
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	m := &Manager{
+		cfg:    cfg,
+		logger: logger,
+		now:    time.Now,
+		queue:  make(chan *entry, cfg.QueueSize),
+		stop:   make(chan struct{}),
+		jobs:   make(map[string]*entry),
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+	m.wg.Add(1)
+	go m.janitor()
+
+	return m
+}
+
+// Submit ставит задачу владельца owner в очередь и возвращает её снимок.
+// Задача наследует значения ctx (например, идентификатор запроса), но не
+// его отмену: она продолжается после ответа на запрос, который её поставил.
+func (m *Manager) Submit(ctx context.Context, owner string, fn Func) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return Job{}, ErrClosed
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e := &entry{
+		job: Job{
+			ID:        resume.NewID(),
+			State:     StateQueued,
+			Progress:  resume.Progress{Stage: string(StateQueued)},
+			CreatedAt: m.now().UTC(),
+			Owner:     owner,
+		},
+		fn:     fn,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	select {
+	case m.queue <- e:
+	default:
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+
+	m.jobs[e.job.ID] = e
+	return e.job, nil
+}
+
+// Get возвращает снимок задачи.
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return e.job, nil
+}
+
+// Result возвращает снимок задачи и, если она завершилась успешно, результат.
+func (m *Manager) Result(id string) (Job, resume.Output, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, resume.Output{}, ErrNotFound
+	}
+	return e.job, e.result, nil
+}
+
+// Watch подписывается на изменения задачи. Канал сразу получает текущий
+// снимок, затем — каждое изменение состояния и этапа; после конечного
+// состояния он закрывается. stop отписывает наблюдателя раньше (например,
+// когда клиент SSE отключился); его можно вызывать повторно.
+func (m *Manager) Watch(id string) (<-chan Job, func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+
+	ch := make(chan Job, 8)
+	ch <- e.job
+	if e.job.State.Done() {
+		close(ch)
+		return ch, func() {}, nil
+	}
+
+	if e.watchers == nil {
+		e.watchers = make(map[chan Job]struct{})
+	}
+	e.watchers[ch] = struct{}{}
+
+	stop := func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if _, ok := e.watchers[ch]; ok {
+			delete(e.watchers, ch)
+			close(ch)
+		}
+	}
+	return ch, stop, nil
+}
+
+// Cancel отменяет задачу. Ожидающая задача сразу становится canceled,
+// у выполняющейся отменяется контекст, и она завершится, как только
+// работа это заметит. Завершённые задачи не меняются.
+func (m *Manager) Cancel(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	switch e.job.State {
+	case StateQueued:
+		// воркер пропустит задачу, увидев отменённый контекст
+		m.finish(e, StateCanceled, resume.Output{}, context.Canceled)
+	case StateRunning:
+		e.cancel()
+	}
+	return e.job, nil
+}
+
+// Delete удаляет завершённую задачу вместе с результатом, не дожидаясь TTL.
+// Активную задачу нужно сначала отменить.
+func (m *Manager) Delete(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	if e.job.State.Done() {
+		delete(m.jobs, id)
+	}
+	return e.job, nil
+}
+
+// Close отменяет все задачи и ждёт остановки воркеров.
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	for _, e := range m.jobs {
+		e.cancel()
+	}
+	m.mu.Unlock()
+
+	close(m.stop)
+	m.wg.Wait()
+}
+
+func (m *Manager) worker() {
+	defer m.wg.Done()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case e := <-m.queue:
+			m.run(e)
+		}
+	}
+}
+
+func (m *Manager) run(e *entry) {
+	m.mu.Lock()
+	if e.job.State != StateQueued {
+		// отменена, пока ждала в очереди
+		m.mu.Unlock()
+		return
+	}
+	now := m.now().UTC()
+	e.job.State = StateRunning
+	e.job.StartedAt = &now
+	e.job.Progress = resume.Progress{Stage: string(StateRunning)}
+	e.notify()
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(e.ctx, m.cfg.Timeout)
+	defer cancel()
+
+	report := func(p resume.Progress) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if e.job.State == StateRunning {
+			e.job.Progress = p
+			e.notify()
+		}
+	}
+
+	out, err := m.call(ctx, e.fn, report)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case err == nil:
+		m.finish(e, StateSucceeded, out, nil)
+	case errors.Is(e.ctx.Err(), context.Canceled):
+		m.finish(e, StateCanceled, resume.Output{}, context.Canceled)
+	default:
+		m.finish(e, StateFailed, resume.Output{}, err)
+	}
+}
+
+// call выполняет работу задачи; паника в ней завершает задачу с ошибкой,
+// а не роняет воркер.
+func (m *Manager) call(ctx context.Context, fn Func, report resume.ProgressFunc) (out resume.Output, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			m.logger.ErrorContext(ctx, "job panic recovered", "panic", rec)
+			err = errors.New("job panicked")
+		}
+	}()
+	return fn(ctx, report)
+}
+
+// finish переводит задачу в конечное состояние. Вызывается под m.mu.
+func (m *Manager) finish(e *entry, state State, out resume.Output, err error) {
+	now := m.now().UTC()
+	expires := now.Add(m.cfg.TTL)
+
+	e.job.State = state
+	e.job.FinishedAt = &now
+	e.job.ExpiresAt = &expires
+	e.job.Progress = resume.Progress{Stage: string(state), Percent: e.job.Progress.Percent}
+	if state == StateSucceeded {
+		e.job.Progress.Percent = 100
+	}
+	e.job.Err = err
+	e.result = out
+	e.fn = nil
+	e.cancel()
+	e.notify()
+}
+
+// janitor удаляет завершённые задачи, у которых истёк TTL.
+func (m *Manager) janitor() {
+	defer m.wg.Done()
+
+	interval := min(m.cfg.TTL/2, time.Minute)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			now := m.now()
+			m.mu.Lock()
+			for id, e := range m.jobs {
+				if e.job.ExpiresAt != nil && now.After(*e.job.ExpiresAt) {
+					delete(m.jobs, id)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+func newManager(t *testing.T, cfg Config) *Manager {
+	t.Helper()
+	m := NewManager(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	t.Cleanup(m.Close)
+	return m
+}
+
+// blocking — работа, которая ждёт release или отмены контекста.
+func blocking(started chan<- struct{}, release <-chan struct{}) Func {
+	return func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error) {
+		if started != nil {
+			close(started)
+		}
+		select {
+		case <-release:
+			return resume.Output{Data: []byte("%PDF")}, nil
+		case <-ctx.Done():
+			return resume.Output{}, ctx.Err()
+		}
+	}
+}
+
+func waitState(t *testing.T, m *Manager, id string, want State) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, err := m.Get(id)
+		if err != nil {
+			t.Fatalf("Get %s: %v", id, err)
+		}
+		if job.State == want {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s is %s, want %s", id, job.State, want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    Func
+		state State
+	}{
+		{"success", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+			return resume.Output{Data: []byte("%PDF")}, nil
+		}, StateSucceeded},
+		{"error", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+			return resume.Output{}, errors.New("boom")
+		}, StateFailed},
+		{"panic", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+			panic("boom")
+		}, StateFailed},
+		{"timeout", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+			<-ctx.Done()
+			return resume.Output{}, ctx.Err()
+		}, StateFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newManager(t, Config{Timeout: 50 * time.Millisecond})
+			job, err := m.Submit(context.Background(), "ip:192.0.2.1", tt.fn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if job.Owner != "ip:192.0.2.1" {
+				t.Errorf("Owner = %q", job.Owner)
+			}
+
+			job = waitState(t, m, job.ID, tt.state)
+			if job.FinishedAt == nil || job.ExpiresAt == nil {
+				t.Errorf("finished job without FinishedAt/ExpiresAt: %+v", job)
+			}
+			_, out, err := m.Result(job.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(out.Data); (tt.state == StateSucceeded) != (got == "%PDF") {
+				t.Errorf("result = %q in state %s", got, tt.state)
+			}
+		})
+	}
+}
+
+func TestCancelQueued(t *testing.T) {
+	m := newManager(t, Config{Workers: 1})
+	started, release := make(chan struct{}), make(chan struct{})
+	first, err := m.Submit(context.Background(), "", blocking(started, release))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	called := make(chan struct{}, 1)
+	queued, err := m.Submit(context.Background(), "", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+		called <- struct{}{}
+		return resume.Output{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job, err := m.Cancel(queued.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State != StateCanceled {
+		t.Fatalf("queued job after Cancel is %s, want canceled", job.State)
+	}
+
+	close(release)
+	waitState(t, m, first.ID, StateSucceeded)
+	// воркер освободился и дошёл до отменённой задачи в очереди
+	after, err := m.Submit(context.Background(), "", blocking(nil, release))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitState(t, m, after.ID, StateSucceeded)
+	select {
+	case <-called:
+		t.Error("canceled queued job was run")
+	default:
+	}
+}
+
+func TestCancelRunning(t *testing.T) {
+	m := newManager(t, Config{})
+	started := make(chan struct{})
+	job, err := m.Submit(context.Background(), "", blocking(started, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	if job, err = m.Cancel(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job.State != StateRunning {
+		t.Errorf("running job right after Cancel is %s, want running until the work stops", job.State)
+	}
+	waitState(t, m, job.ID, StateCanceled)
+}
+
+func TestDelete(t *testing.T) {
+	m := newManager(t, Config{})
+	started, release := make(chan struct{}), make(chan struct{})
+	job, err := m.Submit(context.Background(), "", blocking(started, release))
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	// активную задачу Delete не трогает
+	if job, err = m.Delete(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if job.State != StateRunning {
+		t.Errorf("Delete of a running job returned state %s", job.State)
+	}
+	close(release)
+	waitState(t, m, job.ID, StateSucceeded)
+
+	if _, err := m.Delete(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Get(job.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+	if _, err := m.Delete(job.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestQueueFull(t *testing.T) {
+	m := newManager(t, Config{Workers: 1, QueueSize: 1})
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	if _, err := m.Submit(context.Background(), "", blocking(started, release)); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+	if _, err := m.Submit(context.Background(), "", blocking(nil, release)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Submit(context.Background(), "", blocking(nil, release)); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("err = %v, want ErrQueueFull", err)
+	}
+}
+
+func TestJanitorRemovesExpired(t *testing.T) {
+	m := newManager(t, Config{TTL: 20 * time.Millisecond})
+	job, err := m.Submit(context.Background(), "", func(ctx context.Context, _ resume.ProgressFunc) (resume.Output, error) {
+		return resume.Output{Data: []byte("%PDF")}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := m.Get(job.ID)
+		if errors.Is(err, ErrNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expired job was not removed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, _, err := m.Result(job.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Result after TTL: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	m := newManager(t, Config{})
+	started, release := make(chan struct{}), make(chan struct{})
+	progress := make(chan struct{})
+	job, err := m.Submit(context.Background(), "", func(ctx context.Context, report resume.ProgressFunc) (resume.Output, error) {
+		close(started)
+		<-progress
+		report(resume.Progress{Stage: "compiling", Pass: 1, Percent: 50})
+		<-release
+		return resume.Output{Data: []byte("%PDF")}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	a, stopA, err := m.Watch(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, stopB, err := m.Watch(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	left, stopLeft, err := m.Watch(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stopA()
+	defer stopB()
+
+	// отписавшийся наблюдатель получает закрытый канал, остальные — всё
+	stopLeft()
+	stopLeft()
+	close(progress)
+	close(release)
+
+	for name, ch := range map[string]<-chan Job{"a": a, "b": b} {
+		var stages []string
+		for job := range ch {
+			stages = append(stages, job.Progress.Stage)
+		}
+		want := []string{"running", "compiling", "succeeded"}
+		if len(stages) != len(want) {
+			t.Errorf("watcher %s stages = %v, want %v", name, stages, want)
+			continue
+		}
+		for i := range want {
+			if stages[i] != want[i] {
+				t.Errorf("watcher %s stages = %v, want %v", name, stages, want)
+				break
+			}
+		}
+	}
+
+	var rest []Job
+	for job := range left {
+		rest = append(rest, job)
+	}
+	if len(rest) != 1 || rest[0].State != StateRunning {
+		t.Errorf("stopped watcher got %+v, want only the initial snapshot", rest)
+	}
+
+	// завершённая задача: один снимок и сразу закрытый канал
+	done, _, err := m.Watch(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first, ok := <-done; !ok || first.State != StateSucceeded {
+		t.Errorf("Watch of a finished job: %+v, %v", first, ok)
+	}
+	if _, ok := <-done; ok {
+		t.Error("Watch of a finished job did not close the channel")
+	}
+}
@@ -0,0 +1,354 @@
+package jsonresume
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+const (
+	projectsSectionTitle  = "Projects"
+	languagesSectionTitle = "Languages"
+	websiteLinkLabel      = "Website"
+)
+
+// Report перечисляет поля, которые не удалось перенести при конвертации.
+// Формат элементов совпадает с resume.FieldError.
+type Report struct {
+	Unmapped []resume.FieldError `json:"unmapped"`
+}
+
+func (r *Report) add(field, msg string) {
+	r.Unmapped = append(r.Unmapped, resume.FieldError{
+		Field:   field,
+		Message: msg,
+	})
+}
+
+// addTags отмечает теги вариантов: в JSON Resume им нет соответствия.
+func (r *Report) addTags(field string, tags []string) {
+	if len(tags) > 0 {
+		r.add(field+".tags", "Variant tags have no JSON Resume equivalent")
+	}
+}
+
+// ToResume переводит документ JSON Resume в resume.Resume.
+// extra — пути неизвестных полей, которые вернул Decode.
+func ToResume(doc Document, extra []string) (resume.Resume, Report) {
+	rep := Report{Unmapped: []resume.FieldError{}}
+
+	r := resume.Resume{
+		SchemaVersion: resume.CurrentSchemaVersion,
+		FullName:      doc.Basics.Name,
+		Position:      doc.Basics.Label,
+		Summary:       doc.Basics.Summary,
+		Contacts: resume.Contacts{
+			Email: doc.Basics.Email,
+			Phone: doc.Basics.Phone,
+		},
+	}
+
+	if doc.Basics.Image != "" {
+		rep.add("basics.image", "Photo must be uploaded as base64 data, image URLs are not fetched")
+	}
+
+	if loc := doc.Basics.Location; loc != nil {
+		r.Contacts.Location = joinNonEmpty(", ", loc.City, loc.Region, loc.CountryCode)
+		if loc.Address != "" {
+			rep.add("basics.location.address", "Street address is not part of the resume model")
+		}
+		if loc.PostalCode != "" {
+			rep.add("basics.location.postalCode", "Postal code is not part of the resume model")
+		}
+	}
+
+	if doc.Basics.URL != "" {
+		r.Contacts.Links = append(r.Contacts.Links, resume.Link{Label: websiteLinkLabel, URL: doc.Basics.URL})
+	}
+	for i, p := range doc.Basics.Profiles {
+		if p.URL == "" {
+			rep.add(fmt.Sprintf("basics.profiles[%d]", i), "Profile without url cannot be converted to a link")
+			continue
+		}
+		label := p.Network
+		if label == "" {
+			label = p.Username
+		}
+		r.Contacts.Links = append(r.Contacts.Links, resume.Link{Label: label, URL: p.URL})
+	}
+
+	for i, w := range doc.Work {
+		prefix := fmt.Sprintf("work[%d]", i)
+		r.Experience = append(r.Experience, resume.ExperienceItem{
+			Company:     w.Name,
+			Position:    w.Position,
+			Location:    w.Location,
+			StartDate:   toMonth(w.StartDate),
+			EndDate:     toMonth(w.EndDate),
+			Description: w.Summary,
+			Bullets:     resume.Untagged(w.Highlights),
+		})
+		if w.Description != "" {
+			rep.add(prefix+".description", "Company description is not part of the resume model")
+		}
+		if w.URL != "" {
+			rep.add(prefix+".url", "Company URL is not part of the resume model")
+		}
+	}
+
+	for i, e := range doc.Education {
+		prefix := fmt.Sprintf("education[%d]", i)
+		var details []string
+		if e.Score != "" {
+			details = append(details, "Score: "+e.Score)
+		}
+		if len(e.Courses) > 0 {
+			details = append(details, "Courses: "+strings.Join(e.Courses, ", "))
+		}
+		r.Education = append(r.Education, resume.EducationItem{
+			Institution: e.Institution,
+			Degree:      joinNonEmpty(", ", e.StudyType, e.Area),
+			StartDate:   toMonth(e.StartDate),
+			EndDate:     toMonth(e.EndDate),
+			Details:     strings.Join(details, "; "),
+		})
+		if e.URL != "" {
+			rep.add(prefix+".url", "Institution URL is not part of the resume model")
+		}
+	}
+
+	// навыки без ключевых слов собираются в группу без названия, навык
+	// с ключевыми словами становится группой с его названием
+	var loose resume.SkillGroup
+	var groups []resume.SkillGroup
+	for i, s := range doc.Skills {
+		prefix := fmt.Sprintf("skills[%d]", i)
+		if len(s.Keywords) == 0 {
+			loose.Items = appendSkill(loose.Items, s.Name)
+		} else {
+			g := resume.SkillGroup{Name: strings.TrimSpace(s.Name)}
+			for _, kw := range s.Keywords {
+				g.Items = appendSkill(g.Items, kw)
+			}
+			groups = append(groups, g)
+		}
+		if s.Level != "" {
+			rep.add(prefix+".level", "Skill level is not part of the resume model")
+		}
+	}
+	if len(loose.Items) > 0 {
+		r.Skills = append(r.Skills, loose)
+	}
+	r.Skills = append(r.Skills, groups...)
+
+	if len(doc.Projects) > 0 {
+		section := resume.CustomSection{Title: projectsSectionTitle, Type: resume.SectionProjects}
+		for i, p := range doc.Projects {
+			prefix := fmt.Sprintf("projects[%d]", i)
+			section.Items = append(section.Items, resume.TaggedText{Text: joinNonEmpty(" — ", p.Name, p.Description)})
+			if len(p.Highlights) > 0 {
+				rep.add(prefix+".highlights", "Project highlights are not supported in custom sections")
+			}
+			if len(p.Keywords) > 0 {
+				rep.add(prefix+".keywords", "Project keywords are not supported in custom sections")
+			}
+			if p.URL != "" {
+				rep.add(prefix+".url", "Project URL is not supported in custom sections")
+			}
+			if p.StartDate != "" {
+				rep.add(prefix+".startDate", "Project dates are not supported in custom sections")
+			}
+			if p.EndDate != "" {
+				rep.add(prefix+".endDate", "Project dates are not supported in custom sections")
+			}
+			if len(p.Roles) > 0 {
+				rep.add(prefix+".roles", "Project roles are not supported in custom sections")
+			}
+			if p.Entity != "" {
+				rep.add(prefix+".entity", "Project entity is not supported in custom sections")
+			}
+			if p.Type != "" {
+				rep.add(prefix+".type", "Project type is not supported in custom sections")
+			}
+		}
+		r.CustomSections = append(r.CustomSections, section)
+	}
+
+	if len(doc.Languages) > 0 {
+		section := resume.CustomSection{Title: languagesSectionTitle, Type: resume.SectionLanguages}
+		for _, l := range doc.Languages {
+			item := l.Language
+			if l.Fluency != "" {
+				item += " (" + l.Fluency + ")"
+			}
+			section.Items = append(section.Items, resume.TaggedText{Text: item})
+		}
+		r.CustomSections = append(r.CustomSections, section)
+	}
+
+	sort.Strings(extra)
+	for _, path := range extra {
+		if strings.ContainsAny(path, ".[") {
+			rep.add(path, "Field is not part of the resume model")
+		} else {
+			rep.add(path, "Section is not supported by the resume model")
+		}
+	}
+
+	return r, rep
+}
+
+// FromResume переводит resume.Resume в документ JSON Resume.
+func FromResume(r resume.Resume) (Document, Report) {
+	rep := Report{Unmapped: []resume.FieldError{}}
+
+	doc := Document{
+		Schema: "https://raw.githubusercontent.com/jsonresume/resume-schema/v1.0.0/schema.json",
+		Basics: Basics{
+			Name:    r.FullName,
+			Label:   r.Position,
+			Summary: r.Summary,
+			Email:   r.Contacts.Email,
+			Phone:   r.Contacts.Phone,
+		},
+	}
+
+	if r.Contacts.Location != "" {
+		doc.Basics.Location = &Location{City: r.Contacts.Location}
+	}
+
+	for _, l := range r.Contacts.Links {
+		if strings.EqualFold(l.Label, websiteLinkLabel) && doc.Basics.URL == "" {
+			doc.Basics.URL = l.URL
+			continue
+		}
+		doc.Basics.Profiles = append(doc.Basics.Profiles, Profile{Network: l.Label, URL: l.URL})
+	}
+
+	if r.Photo != nil && r.Photo.Data != "" {
+		rep.add("photo", "JSON Resume expects an image URL, embedded photo data is dropped")
+	}
+
+	for i, e := range r.Experience {
+		prefix := fmt.Sprintf("experience[%d]", i)
+		doc.Work = append(doc.Work, Work{
+			Name:       e.Company,
+			Position:   e.Position,
+			Location:   e.Location,
+			StartDate:  e.StartDate,
+			EndDate:    e.EndDate,
+			Summary:    e.Description,
+			Highlights: resume.Texts(e.Bullets),
+		})
+		rep.addTags(prefix, e.Tags)
+		for j, b := range e.Bullets {
+			rep.addTags(fmt.Sprintf("%s.bullets[%d]", prefix, j), b.Tags)
+		}
+	}
+
+	for i, e := range r.Education {
+		prefix := fmt.Sprintf("education[%d]", i)
+		doc.Education = append(doc.Education, Education{
+			Institution: e.Institution,
+			StudyType:   e.Degree,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+		})
+		if e.Location != "" {
+			rep.add(prefix+".location", "JSON Resume education has no location")
+		}
+		if e.Details != "" {
+			rep.add(prefix+".details", "JSON Resume education has no free-form details")
+		}
+	}
+
+	for i, g := range r.Skills {
+		for j, s := range g.Items {
+			rep.addTags(fmt.Sprintf("skills[%d].items[%d]", i, j), s.Tags)
+		}
+		if strings.TrimSpace(g.Name) == "" {
+			for _, s := range g.Items {
+				doc.Skills = append(doc.Skills, Skill{Name: s.Text})
+			}
+			continue
+		}
+		doc.Skills = append(doc.Skills, Skill{Name: g.Name, Keywords: resume.Texts(g.Items)})
+	}
+
+	for i, cs := range r.CustomSections {
+		switch resume.NormalizeSectionType(cs.Type) {
+		case resume.SectionProjects:
+			for j, item := range cs.Items {
+				rep.addTags(fmt.Sprintf("customSections[%d].items[%d]", i, j), item.Tags)
+				name, desc, _ := strings.Cut(item.Text, " — ")
+				doc.Projects = append(doc.Projects, Project{Name: name, Description: desc})
+			}
+		case resume.SectionLanguages:
+			for j, item := range cs.Items {
+				rep.addTags(fmt.Sprintf("customSections[%d].items[%d]", i, j), item.Tags)
+				doc.Languages = append(doc.Languages, parseLanguage(item.Text))
+			}
+		default:
+			rep.add(fmt.Sprintf("customSections[%d]", i), fmt.Sprintf("Custom section %q has no JSON Resume equivalent", cs.Title))
+		}
+	}
+
+	return doc, rep
+}
+
+// appendSkill добавляет навык в группу, пропуская пустые и повторы без
+// учёта регистра.
+func appendSkill(items []resume.TaggedText, s string) []resume.TaggedText {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return items
+	}
+	for _, it := range items {
+		if strings.EqualFold(it.Text, s) {
+			return items
+		}
+	}
+	return append(items, resume.TaggedText{Text: s})
+}
+
+var languageRe = regexp.MustCompile(`^\s*(.+?)\s*\(([^)]*)\)\s*$`)
+
+// parseLanguage разбирает строку вида "English (Native)".
+func parseLanguage(item string) Language {
+	if m := languageRe.FindStringSubmatch(item); m != nil {
+		return Language{Language: m[1], Fluency: m[2]}
+	}
+	return Language{Language: strings.TrimSpace(item)}
+}
+
+var (
+	isoDateRe = regexp.MustCompile(`^(\d{4}-\d{2})(-\d{2})?$`)
+	isoYearRe = regexp.MustCompile(`^\d{4}$`)
+)
+
+// toMonth приводит дату ISO 8601 (YYYY-MM-DD, YYYY-MM, YYYY) к формату YYYY-MM,
+// который использует модель резюме. Для одного года берётся январь.
+// Нераспознанные значения возвращаются как есть.
+func toMonth(date string) string {
+	date = strings.TrimSpace(date)
+	if m := isoDateRe.FindStringSubmatch(date); m != nil {
+		return m[1]
+	}
+	if isoYearRe.MatchString(date) {
+		return date + "-01"
+	}
+	return date
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
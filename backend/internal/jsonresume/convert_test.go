@@ -0,0 +1,132 @@
+package jsonresume
+
+import (
+	"reflect"
+	"testing"
+
+	"resume_backend/internal/resume"
+)
+
+func TestToMonth(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"2021-03-15", "2021-03"},
+		{"2021-03", "2021-03"},
+		{"2021", "2021-01"},
+		{" 2021 ", "2021-01"},
+		{"", ""},
+		{"present", "present"},
+		{"20210", "20210"},
+		{"2020-01garbage", "2020-01garbage"},
+		{"2020-01-15T10:00:00Z", "2020-01-15T10:00:00Z"},
+	}
+	for _, tt := range tests {
+		if got := toMonth(tt.in); got != tt.want {
+			t.Errorf("toMonth(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestToResumeDates(t *testing.T) {
+	doc := Document{
+		Work:      []Work{{Name: "Example", StartDate: "2019", EndDate: "2021-06-30"}},
+		Education: []Education{{Institution: "MIT", StartDate: "2012", EndDate: "2016"}},
+	}
+	r, _ := ToResume(doc, nil)
+
+	if got := r.Experience[0]; got.StartDate != "2019-01" || got.EndDate != "2021-06" {
+		t.Errorf("experience dates = %q..%q, want 2019-01..2021-06", got.StartDate, got.EndDate)
+	}
+	if got := r.Education[0]; got.StartDate != "2012-01" || got.EndDate != "2016-01" {
+		t.Errorf("education dates = %q..%q, want 2012-01..2016-01", got.StartDate, got.EndDate)
+	}
+}
+
+func TestToResumeReportsDroppedFields(t *testing.T) {
+	raw := `{
+		"basics": {"name": "Ivan", "location": {"city": "Moscow", "countryCode": ""}, "profiles": [{"url": "https://x", "extra": 1}]},
+		"work": [{"name": "A"}, {"name": "B", "isCurrent": true}],
+		"projects": [{
+			"name": "gopdf",
+			"keywords": ["Go"],
+			"endDate": "2021",
+			"roles": ["Maintainer"],
+			"entity": "OSS",
+			"type": "library"
+		}],
+		"awards": [{"title": "Best"}],
+		"volunteer": []
+	}`
+	doc, extra, err := Decode([]byte(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, rep := ToResume(doc, extra)
+
+	var got []string
+	for _, f := range rep.Unmapped {
+		got = append(got, f.Field)
+	}
+	want := []string{
+		"projects[0].keywords",
+		"projects[0].endDate",
+		"projects[0].roles",
+		"projects[0].entity",
+		"projects[0].type",
+		"awards",
+		"basics.profiles[0].extra",
+		"work[1].isCurrent",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmapped = %q, want %q", got, want)
+	}
+}
+
+func TestSkillsRoundTrip(t *testing.T) {
+	doc := Document{Skills: []Skill{
+		{Name: "Go"},
+		{Name: "Cloud", Keywords: []string{"AWS", "aws", "GCP"}},
+		{Name: "go", Level: "Expert"},
+	}}
+
+	r, rep := ToResume(doc, nil)
+	want := []resume.SkillGroup{
+		{Items: resume.Untagged([]string{"Go"})},
+		{Name: "Cloud", Items: resume.Untagged([]string{"AWS", "GCP"})},
+	}
+	if !reflect.DeepEqual(r.Skills, want) {
+		t.Errorf("Skills = %+v, want %+v", r.Skills, want)
+	}
+	if len(rep.Unmapped) != 1 || rep.Unmapped[0].Field != "skills[2].level" {
+		t.Errorf("Unmapped = %+v, want skills[2].level", rep.Unmapped)
+	}
+
+	back, _ := FromResume(r)
+	wantSkills := []Skill{
+		{Name: "Go"},
+		{Name: "Cloud", Keywords: []string{"AWS", "GCP"}},
+	}
+	if !reflect.DeepEqual(back.Skills, wantSkills) {
+		t.Errorf("FromResume skills = %+v, want %+v", back.Skills, wantSkills)
+	}
+}
+
+func TestFromResumeSectionTypes(t *testing.T) {
+	r := resume.Resume{CustomSections: []resume.CustomSection{
+		{Title: "Side work", Type: resume.SectionProjects, Items: resume.Untagged([]string{"gopdf — PDF library"})},
+		{Title: "Языки", Type: resume.SectionLanguages, Items: resume.Untagged([]string{"English (C1)"})},
+		{Title: "Homelab", Items: resume.Untagged([]string{"k3s"})},
+	}}
+
+	doc, rep := FromResume(r)
+	if want := []Project{{Name: "gopdf", Description: "PDF library"}}; !reflect.DeepEqual(doc.Projects, want) {
+		t.Errorf("Projects = %+v, want %+v", doc.Projects, want)
+	}
+	if want := []Language{{Language: "English", Fluency: "C1"}}; !reflect.DeepEqual(doc.Languages, want) {
+		t.Errorf("Languages = %+v, want %+v", doc.Languages, want)
+	}
+	if len(rep.Unmapped) != 1 || rep.Unmapped[0].Field != "customSections[2]" {
+		t.Errorf("Unmapped = %+v, want customSections[2]", rep.Unmapped)
+	}
+}
@@ -0,0 +1,187 @@
+package jsonresume
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ContentType — MIME-тип документа в формате JSON Resume.
+const ContentType = "application/vnd.jsonresume+json"
+
+// Document — подмножество схемы JSON Resume (https://jsonresume.org/schema),
+// которое конвертер умеет переводить в resume.Resume и обратно.
+type Document struct {
+	Schema    string      `json:"$schema,omitempty"`
+	Basics    Basics      `json:"basics"`
+	Work      []Work      `json:"work,omitempty"`
+	Education []Education `json:"education,omitempty"`
+	Skills    []Skill     `json:"skills,omitempty"`
+	Projects  []Project   `json:"projects,omitempty"`
+	Languages []Language  `json:"languages,omitempty"`
+}
+
+// Basics — раздел basics.
+type Basics struct {
+	Name     string    `json:"name,omitempty"`
+	Label    string    `json:"label,omitempty"`
+	Image    string    `json:"image,omitempty"`
+	Email    string    `json:"email,omitempty"`
+	Phone    string    `json:"phone,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	Summary  string    `json:"summary,omitempty"`
+	Location *Location `json:"location,omitempty"`
+	Profiles []Profile `json:"profiles,omitempty"`
+}
+
+// Location — basics.location.
+type Location struct {
+	Address     string `json:"address,omitempty"`
+	PostalCode  string `json:"postalCode,omitempty"`
+	City        string `json:"city,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+	Region      string `json:"region,omitempty"`
+}
+
+// Profile — ссылка на профиль в соцсети (basics.profiles[]).
+type Profile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+// Work — запись work[].
+type Work struct {
+	Name        string   `json:"name,omitempty"`
+	Position    string   `json:"position,omitempty"`
+	Location    string   `json:"location,omitempty"`
+	Description string   `json:"description,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Summary     string   `json:"summary,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+}
+
+// Education — запись education[].
+type Education struct {
+	Institution string   `json:"institution,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Area        string   `json:"area,omitempty"`
+	StudyType   string   `json:"studyType,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	Score       string   `json:"score,omitempty"`
+	Courses     []string `json:"courses,omitempty"`
+}
+
+// Skill — запись skills[].
+type Skill struct {
+	Name     string   `json:"name,omitempty"`
+	Level    string   `json:"level,omitempty"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+// Project — запись projects[].
+type Project struct {
+	Name        string   `json:"name,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	StartDate   string   `json:"startDate,omitempty"`
+	EndDate     string   `json:"endDate,omitempty"`
+	URL         string   `json:"url,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Entity      string   `json:"entity,omitempty"`
+	Type        string   `json:"type,omitempty"`
+}
+
+// Language — запись languages[].
+type Language struct {
+	Language string `json:"language,omitempty"`
+	Fluency  string `json:"fluency,omitempty"`
+}
+
+// fields описывает известные ключи объекта: значение — набор полей
+// вложенного объекта (или элементов массива объектов), nil — скалярное поле
+// или массив строк.
+type fields map[string]fields
+
+// documentFields — поля схемы, которые описаны в Document. Остальные
+// (volunteer, awards, расширения схемы, ...) попадают в отчёт как
+// несопоставленные.
+var documentFields = fields{
+	"$schema": nil,
+	"basics": {
+		"name": nil, "label": nil, "image": nil, "email": nil, "phone": nil, "url": nil, "summary": nil,
+		"location": {"address": nil, "postalCode": nil, "city": nil, "countryCode": nil, "region": nil},
+		"profiles": {"network": nil, "username": nil, "url": nil},
+	},
+	"work": {
+		"name": nil, "position": nil, "location": nil, "description": nil, "url": nil,
+		"startDate": nil, "endDate": nil, "summary": nil, "highlights": nil,
+	},
+	"education": {
+		"institution": nil, "url": nil, "area": nil, "studyType": nil,
+		"startDate": nil, "endDate": nil, "score": nil, "courses": nil,
+	},
+	"skills": {"name": nil, "level": nil, "keywords": nil},
+	"projects": {
+		"name": nil, "description": nil, "highlights": nil, "keywords": nil,
+		"startDate": nil, "endDate": nil, "url": nil, "roles": nil, "entity": nil, "type": nil,
+	},
+	"languages": {"language": nil, "fluency": nil},
+}
+
+// Decode разбирает документ JSON Resume. Неизвестные поля не считаются
+// ошибкой и возвращаются списком путей (work[0].isCurrent, volunteer), чтобы
+// попасть в отчёт.
+func Decode(raw []byte) (Document, []string, error) {
+	var doc Document
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return Document{}, nil, err
+	}
+
+	var extra []string
+	unknownFields(json.RawMessage(raw), "", documentFields, &extra)
+	return doc, extra, nil
+}
+
+// unknownFields добавляет в extra пути непустых полей объекта v, которых нет
+// в known. Массивы объектов обходятся поэлементно.
+func unknownFields(v json.RawMessage, path string, known fields, extra *[]string) {
+	var list []json.RawMessage
+	if json.Unmarshal(v, &list) == nil {
+		for i, item := range list {
+			unknownFields(item, fmt.Sprintf("%s[%d]", path, i), known, extra)
+		}
+		return
+	}
+
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(v, &obj) != nil {
+		return
+	}
+	for key, val := range obj {
+		sub, ok := known[key]
+		p := key
+		if path != "" {
+			p = path + "." + key
+		}
+		switch {
+		case !ok:
+			if !isEmptyJSON(val) {
+				*extra = append(*extra, p)
+			}
+		case sub != nil:
+			unknownFields(val, p, sub, extra)
+		}
+	}
+}
+
+func isEmptyJSON(v json.RawMessage) bool {
+	switch string(v) {
+	case "", "null", "[]", "{}", `""`:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,334 @@
+package latexclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+// DNSScheme — префикс адреса, который раскрывается в узлы по A-записям
+// имени: "dns+http://latex-service:8081" даёт по узлу на каждую реплику
+// (так их отдаёт DNS Docker Compose при --scale).
+const DNSScheme = "dns+"
+
+const (
+	defaultHealthInterval = 5 * time.Second
+	healthTimeout         = 2 * time.Second
+	// ejectAfter — сколько запросов подряд должно упасть с недоступностью,
+	// чтобы узел был исключён до следующей успешной проверки /healthz.
+	ejectAfter = 3
+)
+
+// errNoNodes — ни одного здорового узла: все исключены или DNS не вернул адресов.
+var errNoNodes = errors.New("no healthy latex-service nodes")
+
+// NodeStats — состояние узла latex-service, его отдаёт /healthz backend.
+type NodeStats struct {
+	URL     string `json:"url"`
+	Healthy bool   `json:"healthy"`
+	// InFlight — запросы, которые backend отправил узлу и ждёт ответа.
+	InFlight int `json:"inFlight"`
+	// Workers и Busy — размер пула latexmk и занятые слоты по последней
+	// проверке /healthz.
+	Workers   int        `json:"workers"`
+	Busy      int        `json:"busy"`
+	Requests  uint64     `json:"requests"`
+	Failures  uint64     `json:"failures"`
+	LastError string     `json:"lastError,omitempty"`
+	LastCheck *time.Time `json:"lastCheck,omitempty"`
+}
+
+type node struct {
+	url string
+	// поля ниже защищены pool.mu
+	stats NodeStats
+	// failures — отказов подряд, см. ejectAfter.
+	failures int
+}
+
+// pool — узлы latex-service с балансировкой по наименьшему числу запросов
+// в работе. Узлы проверяются через /healthz; узел, на котором подряд
+// падают запросы, исключается сразу, не дожидаясь проверки, и
+// возвращается, когда /healthz снова ответит.
+type pool struct {
+	targets    []string
+	httpClient *http.Client
+	interval   time.Duration
+	logger     *slog.Logger
+	lookup     func(ctx context.Context, host string) ([]string, error)
+	// resolved — последние адреса DNS-целей; используется только из resolve,
+	// который не вызывается параллельно.
+	resolved map[string][]string
+
+	mu    sync.Mutex
+	nodes []*node
+	next  int // сдвиг для равных кандидатов, чтобы не грузить всегда первый
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newPool(targets []string, httpClient *http.Client, interval time.Duration, logger *slog.Logger) *pool {
+	p := &pool{
+		targets:    targets,
+		httpClient: httpClient,
+		interval:   interval,
+		logger:     logger,
+		lookup:     net.DefaultResolver.LookupHost,
+		resolved:   make(map[string][]string),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	p.resolve(ctx)
+	cancel()
+
+	go p.loop()
+	return p
+}
+
+// resolve пересобирает список узлов из targets. Узлы, которые остались,
+// сохраняют счётчики; новые считаются здоровыми до первой проверки.
+func (p *pool) resolve(ctx context.Context) {
+	var urls []string
+	for _, t := range p.targets {
+		raw, ok := strings.CutPrefix(t, DNSScheme)
+		if !ok {
+			urls = append(urls, t)
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil {
+			p.logger.Warn("invalid latex-service target", "target", t, "error", err)
+			continue
+		}
+		addrs, err := p.lookup(ctx, u.Hostname())
+		if err != nil {
+			p.logger.Warn("resolve latex-service failed", "host", u.Hostname(), "error", err)
+			// при сбое DNS оставляем известные узлы этого имени
+			urls = append(urls, p.resolved[t]...)
+			continue
+		}
+		sort.Strings(addrs)
+		var resolved []string
+		for _, a := range addrs {
+			nu := *u
+			nu.Host = net.JoinHostPort(a, u.Port())
+			if u.Port() == "" {
+				nu.Host = a
+			}
+			resolved = append(resolved, nu.String())
+		}
+		p.resolved[t] = resolved
+		urls = append(urls, resolved...)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	old := make(map[string]*node, len(p.nodes))
+	for _, n := range p.nodes {
+		old[n.url] = n
+	}
+	nodes := make([]*node, 0, len(urls))
+	seen := make(map[string]bool, len(urls))
+	for _, u := range urls {
+		if seen[u] {
+			continue
+		}
+		seen[u] = true
+		if n, ok := old[u]; ok {
+			nodes = append(nodes, n)
+			delete(old, u)
+			continue
+		}
+		p.logger.Info("latex-service node added", "node", u)
+		nodes = append(nodes, &node{url: u, stats: NodeStats{URL: u, Healthy: true}})
+	}
+	for u := range old {
+		// запросы в работе держат ссылку на узел и завершатся штатно
+		p.logger.Info("latex-service node removed", "node", u)
+	}
+	p.nodes = nodes
+}
+
+// acquire выбирает здоровый узел с наименьшим числом запросов в работе,
+// предпочитая те, что этот запрос ещё не пробовал (tried), и отмечает его
+// в tried. Узел нужно вернуть через release.
+func (p *pool) acquire(tried map[*node]bool) (*node, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *node
+	bestTried := true
+	count := len(p.nodes)
+	for i := 0; i < count; i++ {
+		n := p.nodes[(p.next+i)%count]
+		if !n.stats.Healthy {
+			continue
+		}
+		t := tried[n]
+		switch {
+		case best == nil,
+			bestTried && !t,
+			bestTried == t && n.stats.InFlight < best.stats.InFlight:
+			best, bestTried = n, t
+		}
+	}
+	if best == nil {
+		return nil, &resume.UnavailableError{Err: errNoNodes, RetryAfter: p.interval}
+	}
+
+	p.next++
+	tried[best] = true
+	best.stats.InFlight++
+	best.stats.Requests++
+	return best, nil
+}
+
+// release учитывает исход запроса к узлу.
+func (p *pool) release(ctx context.Context, n *node, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n.stats.InFlight--
+	switch {
+	case errors.Is(err, resume.ErrRendererUnavailable):
+		n.stats.Failures++
+		n.stats.LastError = err.Error()
+		n.failures++
+		if n.failures >= ejectAfter && n.stats.Healthy {
+			n.stats.Healthy = false
+			p.logger.WarnContext(ctx, "latex-service node ejected", "node", n.url, "failures", n.failures, "error", err)
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		// отмена запроса ничего не говорит об узле
+	default:
+		n.failures = 0
+	}
+}
+
+func (p *pool) loop() {
+	defer close(p.done)
+
+	p.checkAll()
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+			p.resolve(ctx)
+			cancel()
+			p.checkAll()
+		}
+	}
+}
+
+// checkAll проверяет все узлы параллельно.
+func (p *pool) checkAll() {
+	p.mu.Lock()
+	nodes := append([]*node(nil), p.nodes...)
+	p.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, n := range nodes {
+		wg.Add(1)
+		go func(n *node) {
+			defer wg.Done()
+			p.check(n)
+		}(n)
+	}
+	wg.Wait()
+}
+
+// check опрашивает /healthz узла и обновляет его состояние.
+func (p *pool) check(n *node) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthTimeout)
+	defer cancel()
+
+	var health struct {
+		Workers int `json:"workers"`
+		Busy    int `json:"busy"`
+	}
+	err := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.url+"/healthz", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := p.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("health returned status %d", resp.StatusCode)
+		}
+		return json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&health)
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now().UTC()
+	n.stats.LastCheck = &now
+	if err != nil {
+		n.stats.LastError = err.Error()
+		if n.stats.Healthy {
+			n.stats.Healthy = false
+			p.logger.Warn("latex-service node ejected: health check failed", "node", n.url, "error", err)
+		}
+		return
+	}
+	n.stats.Workers, n.stats.Busy = health.Workers, health.Busy
+	n.failures = 0
+	if !n.stats.Healthy {
+		n.stats.Healthy = true
+		p.logger.Info("latex-service node restored", "node", n.url)
+	}
+}
+
+// capacity — суммарный пул latexmk здоровых узлов.
+func (p *pool) capacity() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	total := 0
+	for _, n := range p.nodes {
+		if n.stats.Healthy {
+			total += n.stats.Workers
+		}
+	}
+	return total
+}
+
+func (p *pool) stats() []NodeStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]NodeStats, len(p.nodes))
+	for i, n := range p.nodes {
+		out[i] = n.stats
+	}
+	return out
+}
+
+func (p *pool) close() {
+	close(p.stop)
+	<-p.done
+}
@@ -0,0 +1,281 @@
+package latexclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+// testPool собирает pool без фоновой проверки узлов.
+func testPool(targets []string, lookup func(ctx context.Context, host string) ([]string, error)) *pool {
+	p := &pool{
+		targets:    targets,
+		httpClient: http.DefaultClient,
+		interval:   time.Second,
+		logger:     discard,
+		lookup:     lookup,
+		resolved:   make(map[string][]string),
+	}
+	p.resolve(context.Background())
+	return p
+}
+
+func (p *pool) urls() []string {
+	var out []string
+	for _, s := range p.stats() {
+		out = append(out, s.URL)
+	}
+	return out
+}
+
+// busy добавляет узлу url n запросов в работе.
+func busy(t *testing.T, p *pool, url string, n int) {
+	t.Helper()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, nd := range p.nodes {
+		if nd.url == url {
+			nd.stats.InFlight += n
+			return
+		}
+	}
+	t.Fatalf("no node %s", url)
+}
+
+func TestPoolAcquireLeastInFlight(t *testing.T) {
+	p := testPool([]string{"http://a", "http://b", "http://c"}, nil)
+
+	// равные кандидаты обходятся по кругу
+	var got []string
+	var nodes []*node
+	for i := 0; i < 3; i++ {
+		n, err := p.acquire(map[*node]bool{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, n.url)
+		nodes = append(nodes, n)
+	}
+	if want := []string{"http://a", "http://b", "http://c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("acquired %v, want %v", got, want)
+	}
+
+	// освободившийся узел выбирается первым
+	p.release(context.Background(), nodes[1], nil)
+	n, err := p.acquire(map[*node]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.url != "http://b" {
+		t.Errorf("acquired %s, want the least loaded http://b", n.url)
+	}
+
+	stats := p.stats()
+	if stats[1].InFlight != 1 || stats[1].Requests != 2 {
+		t.Errorf("b stats = %+v, want 1 in flight and 2 requests", stats[1])
+	}
+}
+
+func TestPoolAcquirePrefersUntried(t *testing.T) {
+	tests := []struct {
+		name    string
+		release bool // первая попытка завершилась (повтор), иначе ещё идёт (хедж)
+	}{
+		{"retry", true},
+		{"hedge", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := testPool([]string{"http://a", "http://b"}, nil)
+			tried := make(map[*node]bool)
+			first, err := p.acquire(tried)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.release {
+				p.release(context.Background(), first, &resume.UnavailableError{Err: errors.New("refused")})
+			}
+			// второй узел загружен сильнее, но этот запрос его ещё не пробовал
+			other := "http://b"
+			if first.url == other {
+				other = "http://a"
+			}
+			busy(t, p, other, 3)
+
+			second, err := p.acquire(tried)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if second.url != other {
+				t.Errorf("second attempt went to %s, want untried %s", second.url, other)
+			}
+
+			// все узлы пробовали — снова решает число запросов в работе
+			third, err := p.acquire(tried)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if third.url != first.url {
+				t.Errorf("third attempt went to %s, want the less loaded %s", third.url, first.url)
+			}
+		})
+	}
+}
+
+func TestPoolEjectsFailingNode(t *testing.T) {
+	p := testPool([]string{"http://a"}, nil)
+	ctx := context.Background()
+	unavailable := &resume.UnavailableError{Err: errors.New("connection refused")}
+
+	fail := func(err error) {
+		t.Helper()
+		n, aerr := p.acquire(map[*node]bool{})
+		if aerr != nil {
+			t.Fatalf("acquire: %v", aerr)
+		}
+		p.release(ctx, n, err)
+	}
+
+	for i := 0; i < ejectAfter-1; i++ {
+		fail(unavailable)
+	}
+	// успешный ответ и ошибка рендера сбрасывают счётчик, отмена — нет
+	fail(nil)
+	fail(&StatusError{Status: http.StatusBadRequest})
+	for i := 0; i < ejectAfter-1; i++ {
+		fail(unavailable)
+	}
+	fail(context.Canceled)
+	if !p.stats()[0].Healthy {
+		t.Fatal("node ejected before ejectAfter consecutive failures")
+	}
+
+	fail(unavailable)
+	s := p.stats()[0]
+	if s.Healthy {
+		t.Fatalf("node is healthy after %d consecutive failures", ejectAfter)
+	}
+	if s.Failures != 2*(ejectAfter-1)+1 || s.LastError != unavailable.Error() {
+		t.Errorf("stats = %+v", s)
+	}
+
+	_, err := p.acquire(map[*node]bool{})
+	var ue *resume.UnavailableError
+	if !errors.As(err, &ue) || !errors.Is(err, errNoNodes) || ue.RetryAfter != p.interval {
+		t.Errorf("acquire without healthy nodes: err = %v, want errNoNodes with Retry-After", err)
+	}
+}
+
+func TestPoolCheck(t *testing.T) {
+	var status atomic.Int32
+	status.Store(http.StatusOK)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+		w.WriteHeader(int(status.Load()))
+		fmt.Fprint(w, `{"status":"ok","workers":4,"busy":1}`)
+	}))
+	defer srv.Close()
+
+	p := testPool([]string{srv.URL, "http://127.0.0.1:1"}, nil)
+	ctx := context.Background()
+	for i := 0; i < ejectAfter; i++ {
+		n, _ := p.acquire(map[*node]bool{p.nodes[1]: true})
+		p.release(ctx, n, &resume.UnavailableError{Err: errors.New("refused")})
+	}
+	if p.stats()[0].Healthy {
+		t.Fatal("node was not ejected")
+	}
+
+	// успешная проверка возвращает узел и обновляет пул latexmk
+	p.checkAll()
+	s := p.stats()
+	if !s[0].Healthy || s[0].Workers != 4 || s[0].Busy != 1 || s[0].LastCheck == nil {
+		t.Errorf("after a successful check: %+v", s[0])
+	}
+	if p.nodes[0].failures != 0 {
+		t.Errorf("failures = %d after a successful check, want 0", p.nodes[0].failures)
+	}
+	// недоступный узел исключается проверкой
+	if s[1].Healthy || s[1].LastError == "" {
+		t.Errorf("unreachable node after check: %+v", s[1])
+	}
+	if got := p.capacity(); got != 4 {
+		t.Errorf("capacity = %d, want 4 (only healthy nodes)", got)
+	}
+
+	status.Store(http.StatusServiceUnavailable)
+	p.check(p.nodes[0])
+	if s := p.stats()[0]; s.Healthy || s.LastError != "health returned status 503" {
+		t.Errorf("after a failed check: %+v", s)
+	}
+}
+
+func TestPoolResolve(t *testing.T) {
+	var addrs []string
+	var lookupErr error
+	lookup := func(ctx context.Context, host string) ([]string, error) {
+		if host != "latex" {
+			t.Errorf("lookup(%q)", host)
+		}
+		return addrs, lookupErr
+	}
+
+	addrs = []string{"10.0.0.2", "10.0.0.1"}
+	p := testPool([]string{"dns+http://latex:8081", "http://static:8081", "dns+http://latex:8081"}, lookup)
+	want := []string{"http://10.0.0.1:8081", "http://10.0.0.2:8081", "http://static:8081"}
+	if got := p.urls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("nodes = %v, want %v", got, want)
+	}
+
+	// оставшиеся узлы сохраняют счётчики
+	kept, err := p.acquire(map[*node]bool{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept.url != "http://10.0.0.1:8081" {
+		t.Fatalf("acquired %s, want the first node", kept.url)
+	}
+	addrs = []string{"10.0.0.3", "10.0.0.1"}
+	p.resolve(context.Background())
+	want = []string{"http://10.0.0.1:8081", "http://10.0.0.3:8081", "http://static:8081"}
+	if got := p.urls(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("nodes after DNS change = %v, want %v", got, want)
+	}
+	if s := p.stats()[0]; s.Requests != 1 || s.InFlight != 1 {
+		t.Errorf("kept node lost its stats: %+v", s)
+	}
+
+	// при сбое DNS известные узлы остаются
+	lookupErr = errors.New("server misbehaving")
+	addrs = nil
+	p.resolve(context.Background())
+	if got := p.urls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("nodes after a DNS failure = %v, want %v", got, want)
+	}
+
+	// пустой ответ DNS без ошибки убирает узлы имени
+	lookupErr = nil
+	p.resolve(context.Background())
+	if got := p.urls(); !reflect.DeepEqual(got, []string{"http://static:8081"}) {
+		t.Errorf("nodes after an empty DNS answer = %v", got)
+	}
+}
+
+func TestPoolResolveWithoutPort(t *testing.T) {
+	p := testPool([]string{"dns+http://latex"}, func(ctx context.Context, host string) ([]string, error) {
+		return []string{"10.0.0.1"}, nil
+	})
+	if got := p.urls(); !reflect.DeepEqual(got, []string{"http://10.0.0.1"}) {
+		t.Errorf("nodes = %v", got)
+	}
+}
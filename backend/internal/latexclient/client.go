@@ -6,68 +6,358 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"time"
 
 	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+	"resume_contract/requestid"
 )
 
-// Client реализует вызов LaTeX-сервиса по HTTP.
+// DefaultTimeout ограничивает вызов latex-service, если у контекста нет
+// своего дедлайна. Фоновые задачи передают контекст с более долгим сроком.
+const DefaultTimeout = 30 * time.Second
+
+// Client реализует вызов LaTeX-сервиса по HTTP. Запросы распределяются
+// между узлами latex-service, см. pool.
 type Client struct {
-	baseURL    string
+	pool       *pool
 	httpClient *http.Client
-	logger     *log.Logger
+	timeout    time.Duration
+	cfg        Config
+	breaker    *breaker
+	logger     *slog.Logger
 }
 
-func NewClient(baseURL string, logger *log.Logger) *Client {
+// NewClient создаёт клиент для узлов latex-service. targets — базовые URL
+// узлов; адрес с префиксом DNSScheme раскрывается в узел на каждую
+// A-запись. cfg задаёт повторы, предохранитель, дублирование запросов и
+// интервал проверки узлов (нулевые поля — дефолты, см. Config).
+func NewClient(targets []string, cfg Config, logger *slog.Logger) *Client {
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
 	}
-	if baseURL == "" {
-		baseURL = "http://latex-service:8081"
+	if len(targets) == 0 {
+		targets = []string{"http://latex-service:8081"}
 	}
+	cfg = cfg.withDefaults()
+	httpClient := &http.Client{}
 
 	return &Client{
-		baseURL: baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		logger: logger,
+		pool:       newPool(targets, httpClient, cfg.HealthInterval, logger),
+		httpClient: httpClient,
+		timeout:    DefaultTimeout,
+		cfg:        cfg,
+		breaker:    newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown, logger),
+		logger:     logger,
 	}
 }
 
 // RenderResume отправляет JSON с резюме в LaTeX-сервис и возвращает PDF.
-func (c *Client) RenderResume(ctx context.Context, r resume.Resume) ([]byte, error) {
-	url := fmt.Sprintf("%s/internal/v1/render", c.baseURL)
+func (c *Client) RenderResume(ctx context.Context, r resume.Resume) (_ []byte, err error) {
+	defer func() { observeError(err) }()
+
+	resp, err := c.render(ctx, r, "")
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.limitBody(resp, c.cfg.MaxOutputSize)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return readPDF(body)
+}
+
+// StreamResume отправляет резюме в LaTeX-сервис и возвращает тело ответа
+// с PDF, не читая его: документ можно передавать клиенту по мере
+// получения. Размер ограничен Config.MaxOutputSize — и заранее, по
+// Content-Length, и при чтении.
+func (c *Client) StreamResume(ctx context.Context, r resume.Resume) (io.ReadCloser, int64, error) {
+	resp, err := c.render(ctx, r, "")
+	if err != nil {
+		return nil, 0, observeError(err)
+	}
+	body, err := c.limitBody(resp, c.cfg.MaxOutputSize)
+	if err != nil {
+		return nil, 0, observeError(err)
+	}
+	return &observedBody{ReadCloser: body}, resp.ContentLength, nil
+}
+
+// RenderResumeProgress рендерит PDF, получая от latex-service поток событий
+// (contract.RenderEvent): промежуточные передаются в report, итоговое
+// событие done содержит PDF, error — ошибку рендера.
+func (c *Client) RenderResumeProgress(ctx context.Context, r resume.Resume, report func(contract.RenderEvent)) (_ []byte, err error) {
+	defer func() { observeError(err) }()
 
-	payload, err := json.Marshal(r)
+	resp, err := c.render(ctx, r, "progress=1")
 	if err != nil {
-		return nil, fmt.Errorf("marshal resume: %w", err)
+		return nil, err
 	}
+	defer resp.Body.Close()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != contract.ProgressContentType {
+		return nil, fmt.Errorf("latex-service returned unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	// PDF приходит в base64 внутри JSON
+	body, err := c.limitBody(resp, base64Len(c.cfg.MaxOutputSize)+maxEnvelopeSize)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, err
+	}
+	defer body.Close()
+
+	dec := json.NewDecoder(body)
+	for {
+		var ev contract.RenderEvent
+		if err := dec.Decode(&ev); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("latex-service progress stream ended without a result")
+			}
+			return nil, fmt.Errorf("read progress stream: %w", err)
+		}
+
+		switch ev.Stage {
+		case contract.StageDone:
+			if len(ev.PDF) == 0 {
+				return nil, fmt.Errorf("latex-service returned an empty PDF")
+			}
+			if int64(len(ev.PDF)) > c.cfg.MaxOutputSize {
+				return nil, fmt.Errorf("latex-service PDF of %d bytes: %w (limit %d)", len(ev.PDF), resume.ErrOutputTooLarge, c.cfg.MaxOutputSize)
+			}
+			return ev.PDF, nil
+		case contract.StageError:
+			c.logger.WarnContext(ctx, "latex-service render error", "code", ev.Error, "message", ev.Message)
+			if ev.Error == codeOutputTooLarge {
+				return nil, fmt.Errorf("latex-service: %w: %s", resume.ErrOutputTooLarge, ev.Message)
+			}
+			return nil, fmt.Errorf("latex-service: %s: %s", ev.Error, ev.Message)
+		default:
+			report(ev)
+		}
 	}
-	req.Header.Set("Content-Type", "application/json")
+}
+
+// CheckATS рендерит резюме в режиме ATS-проверки: LaTeX-сервис возвращает
+// multipart/mixed с JSON-отчётом и PDF.
+func (c *Client) CheckATS(ctx context.Context, r resume.Resume) (report contract.ATSReport, _ []byte, err error) {
+	defer func() { observeError(err) }()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.render(ctx, r, "check=ats")
 	if err != nil {
-		return nil, fmt.Errorf("call latex-service: %w", err)
+		return report, nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
-		c.logger.Printf("latex-service returned %d: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("latex-service returned status %d", resp.StatusCode)
+	mt, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mt != "multipart/mixed" {
+		return report, nil, fmt.Errorf("latex-service returned unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	body, err := c.limitBody(resp, c.cfg.MaxOutputSize+maxEnvelopeSize)
+	if err != nil {
+		return report, nil, err
 	}
+	defer body.Close()
+
+	var pdf []byte
+	gotReport := false
+	mr := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, nil, fmt.Errorf("read multipart response: %w", err)
+		}
+
+		switch part.Header.Get("Content-Type") {
+		case "application/json":
+			if err := json.NewDecoder(part).Decode(&report); err != nil {
+				return report, nil, fmt.Errorf("decode ats report: %w", err)
+			}
+			gotReport = true
+		case "application/pdf":
+			if pdf, err = readPDF(part); err != nil {
+				return report, nil, err
+			}
+		}
+	}
+
+	if !gotReport || pdf == nil {
+		return report, nil, fmt.Errorf("latex-service response is missing the report or PDF part")
+	}
+
+	return report, pdf, nil
+}
+
+// RenderDiff отправляет визуальный diff двух версий резюме и возвращает PDF.
+func (c *Client) RenderDiff(ctx context.Context, doc contract.DiffDocument) (_ []byte, err error) {
+	defer func() { observeError(err) }()
+
+	doc.SchemaVersion = contract.SchemaVersion
 
-	pdf, err := io.ReadAll(resp.Body)
+	resp, err := c.post(ctx, "/internal/v1/render/diff", doc)
 	if err != nil {
-		return nil, fmt.Errorf("read pdf body: %w", err)
+		return nil, err
 	}
+	body, err := c.limitBody(resp, c.cfg.MaxOutputSize)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return readPDF(body)
+}
+
+// Capacity возвращает суммарный пул latexmk здоровых узлов по последним
+// проверкам /healthz: столько рендеров выполняется одновременно, остальные
+// ждут в очередях узлов.
+func (c *Client) Capacity(ctx context.Context) (int, error) {
+	n := c.pool.capacity()
+	if n < 1 {
+		return 0, fmt.Errorf("latex-service nodes do not report their worker pools")
+	}
+	return n, nil
+}
+
+// Status — состояние клиента для /healthz backend.
+type Status struct {
+	// Breaker — состояние предохранителя: closed, open или half-open.
+	Breaker string      `json:"breaker"`
+	Nodes   []NodeStats `json:"nodes"`
+}
+
+// RendererStatus возвращает Status (реализует resume.StatusReporter).
+func (c *Client) RendererStatus() any {
+	return Status{Breaker: c.breaker.current().String(), Nodes: c.pool.stats()}
+}
+
+// Close останавливает проверку узлов.
+func (c *Client) Close() {
+	c.pool.close()
+}
+
+// render вызывает /internal/v1/render для резюме.
+func (c *Client) render(ctx context.Context, r resume.Resume, query string) (*http.Response, error) {
+	path := "/internal/v1/render"
+	if query != "" {
+		path += "?" + query
+	}
+	return c.post(ctx, path, toContract(r))
+}
+
+// post отправляет body в latex-service и возвращает успешный ответ;
+// ошибочные статусы преобразуются в ошибки. Запрос проходит через
+// предохранитель и повторяется при временной недоступности сервиса.
+func (c *Client) post(ctx context.Context, path string, body any) (*http.Response, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+	if int64(len(payload)) > c.cfg.MaxRequestSize {
+		return nil, fmt.Errorf("latex-service request of %d bytes: %w (limit %d)", len(payload), resume.ErrRequestTooLarge, c.cfg.MaxRequestSize)
+	}
+
+	probe, err := c.breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if _, ok := ctx.Deadline(); !ok {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	resp, err := c.send(ctx, func(ctx context.Context, base string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+path, bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	})
+	c.breaker.done(ctx, probe, err)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// тело читает вызывающий код, поэтому таймаут снимается при его закрытии
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// do выполняет одну попытку запроса на узле, выбранном балансировщиком.
+func (c *Client) do(ctx context.Context, newRequest requestFunc, tried map[*node]bool) (resp *http.Response, err error) {
+	n, err := c.pool.acquire(tried)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { c.pool.release(ctx, n, err) }()
+
+	req, err := newRequest(ctx, n.url)
+	if err != nil {
+		return nil, err
+	}
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+
+	resp, err = c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("call latex-service: %w", err)
+		}
+		return nil, &resume.UnavailableError{Err: fmt.Errorf("call latex-service: %w", err)}
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	c.logger.WarnContext(ctx, "latex-service returned an error", "node", n.url, "status", resp.StatusCode, "body", string(body))
+
+	var envelope struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	_ = json.Unmarshal(body, &envelope)
+	switch envelope.Error {
+	case contract.ErrCodeUnsupportedSchemaVersion:
+		return nil, fmt.Errorf("latex-service: %w: %s", contract.ErrUnsupportedSchemaVersion, envelope.Message)
+	case codeOutputTooLarge:
+		return nil, fmt.Errorf("latex-service: %w: %s", resume.ErrOutputTooLarge, envelope.Message)
+	case contract.ErrCodePayloadTooLarge:
+		return nil, fmt.Errorf("latex-service: %w: %s", resume.ErrRequestTooLarge, envelope.Message)
+	}
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		// 413 без JSON-конверта, например от прокси перед latex-service
+		return nil, fmt.Errorf("latex-service: %w: status %d", resume.ErrRequestTooLarge, resp.StatusCode)
+	}
+
+	se := &StatusError{Status: resp.StatusCode, Code: envelope.Error, Message: envelope.Message}
+	if retryable(se) {
+		return nil, &resume.UnavailableError{Err: se, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+	}
+	return nil, se
+}
+
+// cancelBody отменяет контекст запроса при закрытии тела ответа.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
 
-	return pdf, nil
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
 }
@@ -0,0 +1,56 @@
+package latexclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"resume_backend/internal/resume"
+)
+
+func TestClientRequestTooLarge(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		maxSize   int64
+		wantCalls int32
+	}{
+		{"latex-service envelope", http.StatusRequestEntityTooLarge, `{"error":"payload_too_large","message":"Request body exceeds 10 bytes"}`, 0, 1},
+		{"proxy without envelope", http.StatusRequestEntityTooLarge, "<html>413 Request Entity Too Large</html>", 0, 1},
+		{"over the client limit", http.StatusOK, "%PDF", 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/healthz" {
+					io.WriteString(w, `{"status":"ok","workers":2}`)
+					return
+				}
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+			c := NewClient([]string{srv.URL}, Config{MaxRequestSize: tt.maxSize}, logger)
+			defer c.Close()
+
+			_, err := c.RenderResume(context.Background(), resume.Resume{FullName: strings.Repeat("x", 100)})
+			if !errors.Is(err, resume.ErrRequestTooLarge) {
+				t.Fatalf("err = %v, want ErrRequestTooLarge", err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("render calls = %d, want %d (413 is not retried)", got, tt.wantCalls)
+			}
+		})
+	}
+}
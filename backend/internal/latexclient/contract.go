@@ -0,0 +1,72 @@
+package latexclient
+
+import (
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+)
+
+// toContract переводит доменную модель backend в wire-контракт latex-service.
+// Доменная модель может расширяться независимо: в latex-service уходят
+// только поля, описанные в текущей версии контракта.
+func toContract(r resume.Resume) contract.Resume {
+	out := contract.Resume{
+		SchemaVersion: contract.SchemaVersion,
+		FullName:      r.FullName,
+		Position:      r.Position,
+		Summary:       r.Summary,
+		Contacts: contract.Contacts{
+			Email:    r.Contacts.Email,
+			Phone:    r.Contacts.Phone,
+			Location: r.Contacts.Location,
+		},
+		Skills: resume.SkillLines(r.Skills),
+	}
+
+	for _, l := range r.Contacts.Links {
+		out.Contacts.Links = append(out.Contacts.Links, contract.Link{
+			Label: l.Label,
+			URL:   l.URL,
+		})
+	}
+
+	for _, e := range r.Experience {
+		out.Experience = append(out.Experience, contract.ExperienceEntry{
+			Company:     e.Company,
+			Position:    e.Position,
+			Location:    e.Location,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+			Description: e.Description,
+			Bullets:     resume.Texts(e.Bullets),
+		})
+	}
+
+	for _, e := range r.Education {
+		out.Education = append(out.Education, contract.EducationEntry{
+			Institution: e.Institution,
+			Degree:      e.Degree,
+			Location:    e.Location,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+			Details:     e.Details,
+		})
+	}
+
+	for _, cs := range r.CustomSections {
+		out.CustomSections = append(out.CustomSections, contract.CustomSection{
+			Title:        cs.Title,
+			BulletSymbol: cs.BulletSymbol,
+			Items:        resume.Texts(cs.Items),
+		})
+	}
+
+	if r.Photo != nil {
+		out.Photo = &contract.Photo{
+			MimeType: r.Photo.MimeType,
+			Data:     r.Photo.Data,
+		}
+	}
+
+	return out
+}
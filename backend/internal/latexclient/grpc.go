@@ -0,0 +1,469 @@
+package latexclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health" // проверка узлов через grpc.health.v1
+	"google.golang.org/grpc/status"
+
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+	"resume_contract/renderpb"
+	"resume_contract/requestid"
+)
+
+// DefaultGRPCTarget — адрес gRPC-API latex-service по умолчанию: резолвер
+// dns возвращает все реплики, и запросы распределяются между ними.
+const DefaultGRPCTarget = "dns:///latex-service:9090"
+
+// grpcMaxAttempts — предел числа попыток в gRPC: большее значение
+// grpc-go молча урезает до него.
+const grpcMaxAttempts = 5
+
+// GRPCClient вызывает latex-service по gRPC (renderpb.RenderService) —
+// альтернатива Client по HTTP. Резюме передаётся protobuf с фото в байтах,
+// PDF приходит частями и отдаётся потоком (StreamResume). Дедлайн
+// контекста передаётся в latex-service и ограничивает latexmk, метаданные
+// исходящего контекста (metadata.NewOutgoingContext) уходят вместе с вызовом.
+//
+// Балансировку (round_robin по адресам резолвера), проверку узлов
+// (grpc.health.v1) и повторы при UNAVAILABLE выполняет сам grpc-go по
+// service config; предохранитель общий с Client. Дублирования запросов
+// (HedgeDelay) нет: grpc-go не поддерживает hedgingPolicy.
+type GRPCClient struct {
+	conn    *grpc.ClientConn
+	rpc     renderpb.RenderServiceClient
+	target  string
+	timeout time.Duration
+	// maxOutput — предельный размер PDF, см. Config.MaxOutputSize.
+	maxOutput int64
+	breaker   *breaker
+	logger    *slog.Logger
+}
+
+// NewGRPCClient создаёт клиент для target в синтаксисе gRPC, например
+// "dns:///latex-service:9090". Соединение устанавливается лениво, при
+// первом вызове.
+func NewGRPCClient(target string, cfg Config, logger *slog.Logger) (*GRPCClient, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if target == "" {
+		target = DefaultGRPCTarget
+	}
+	cfg = cfg.withDefaults()
+	if cfg.HedgeDelay > 0 {
+		logger.Warn("latex-service gRPC client does not support hedging, HedgeDelay is ignored", "hedge_delay", cfg.HedgeDelay.String())
+	}
+
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(serviceConfig(cfg)),
+		grpc.WithChainUnaryInterceptor(requestid.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(requestid.StreamClientInterceptor()),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(int(cfg.MaxRequestSize))),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create gRPC client for %s: %w", target, err)
+	}
+
+	return &GRPCClient{
+		conn:      conn,
+		rpc:       renderpb.NewRenderServiceClient(conn),
+		target:    target,
+		timeout:   DefaultTimeout,
+		maxOutput: cfg.MaxOutputSize,
+		breaker:   newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown, logger),
+		logger:    logger,
+	}, nil
+}
+
+// serviceConfig собирает service config gRPC: балансировка round_robin,
+// проверка здоровья и политика повторов из cfg.
+func serviceConfig(cfg Config) string {
+	service := renderpb.RenderService_ServiceDesc.ServiceName
+	sc := map[string]any{
+		"loadBalancingConfig": []any{map[string]any{"round_robin": map[string]any{}}},
+		"healthCheckConfig":   map[string]any{"serviceName": service},
+	}
+	if attempts := min(cfg.MaxAttempts, grpcMaxAttempts); attempts > 1 {
+		sc["methodConfig"] = []any{map[string]any{
+			"name": []any{map[string]any{"service": service}},
+			"retryPolicy": map[string]any{
+				"maxAttempts":          attempts,
+				"initialBackoff":       fmt.Sprintf("%gs", cfg.BaseBackoff.Seconds()),
+				"maxBackoff":           fmt.Sprintf("%gs", cfg.MaxBackoff.Seconds()),
+				"backoffMultiplier":    2,
+				"retryableStatusCodes": []string{"UNAVAILABLE"},
+			},
+		}}
+	}
+	b, _ := json.Marshal(sc)
+	return string(b)
+}
+
+// RenderResume рендерит резюме и возвращает PDF.
+func (c *GRPCClient) RenderResume(ctx context.Context, r resume.Resume) ([]byte, error) {
+	_, pdf, err := c.render(ctx, &renderpb.RenderRequest{Resume: renderpb.ResumeFromContract(toContract(r))}, nil)
+	return pdf, err
+}
+
+// StreamResume рендерит резюме и возвращает PDF потоком, по мере прихода
+// частей от latex-service, не собирая документ в памяти (реализует
+// resume.PDFStreamer). Размер берётся из первой части; Config.MaxOutputSize
+// проверяется и по нему, и при чтении. Дедлайн вызова действует до
+// закрытия тела.
+func (c *GRPCClient) StreamResume(ctx context.Context, r resume.Resume) (io.ReadCloser, int64, error) {
+	probe, err := c.breaker.allow()
+	if err != nil {
+		return nil, 0, observeError(err)
+	}
+
+	// поток нужно отменить при закрытии тела, даже если он не дочитан
+	var cancel context.CancelFunc
+	if _, ok := ctx.Deadline(); ok {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+
+	req := &renderpb.RenderRequest{Resume: renderpb.ResumeFromContract(toContract(r))}
+	var pr *pdfReader
+	stream, err := c.rpc.Render(ctx, req)
+	if err == nil {
+		pr = newPDFReader(stream, nil, c.maxOutput)
+		err = pr.start()
+	}
+	err = c.convertError(ctx, err)
+	c.breaker.done(ctx, probe, err)
+	if err != nil {
+		cancel()
+		return nil, 0, observeError(err)
+	}
+
+	body := &streamBody{client: c, ctx: ctx, pdf: pr, cancel: cancel}
+	return &observedBody{ReadCloser: body}, pr.size, nil
+}
+
+// RenderResumeProgress рендерит PDF, передавая этапы работы в report.
+func (c *GRPCClient) RenderResumeProgress(ctx context.Context, r resume.Resume, report func(contract.RenderEvent)) ([]byte, error) {
+	req := &renderpb.RenderRequest{Resume: renderpb.ResumeFromContract(toContract(r)), Progress: true}
+	_, pdf, err := c.render(ctx, req, report)
+	return pdf, err
+}
+
+// CheckATS рендерит резюме и возвращает отчёт ATS-проверки вместе с PDF.
+func (c *GRPCClient) CheckATS(ctx context.Context, r resume.Resume) (contract.ATSReport, []byte, error) {
+	req := &renderpb.RenderRequest{Resume: renderpb.ResumeFromContract(toContract(r)), CheckAts: true}
+	report, pdf, err := c.render(ctx, req, nil)
+	if err != nil {
+		return contract.ATSReport{}, nil, err
+	}
+	if report == nil {
+		return contract.ATSReport{}, nil, fmt.Errorf("latex-service response is missing the ATS report")
+	}
+	return renderpb.ATSReportToContract(report), pdf, nil
+}
+
+// RenderDiff рендерит визуальный diff двух версий резюме.
+func (c *GRPCClient) RenderDiff(ctx context.Context, doc contract.DiffDocument) ([]byte, error) {
+	doc.SchemaVersion = contract.SchemaVersion
+	req := &renderpb.RenderDiffRequest{Document: renderpb.DiffFromContract(doc)}
+
+	var pdf []byte
+	err := c.call(ctx, func(ctx context.Context) error {
+		stream, err := c.rpc.RenderDiff(ctx, req)
+		if err != nil {
+			return err
+		}
+		_, pdf, err = c.receive(stream, nil)
+		return err
+	})
+	return pdf, err
+}
+
+// Capacity возвращает размер пула latexmk узла, ответившего на Status.
+// Узлы за одним адресом gRPC неразличимы, поэтому это оценка снизу для
+// нескольких реплик.
+func (c *GRPCClient) Capacity(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, healthTimeout)
+	defer cancel()
+
+	resp, err := c.rpc.Status(ctx, &renderpb.StatusRequest{})
+	if err != nil {
+		return 0, fmt.Errorf("latex-service status: %w", err)
+	}
+	if resp.GetWorkers() < 1 {
+		return 0, fmt.Errorf("latex-service does not report its worker pool")
+	}
+	return int(resp.GetWorkers()), nil
+}
+
+// GRPCStatus — состояние gRPC-клиента для /healthz backend.
+type GRPCStatus struct {
+	Transport string `json:"transport"`
+	Target    string `json:"target"`
+	// State — состояние соединения: IDLE, CONNECTING, READY,
+	// TRANSIENT_FAILURE или SHUTDOWN.
+	State   string `json:"state"`
+	Breaker string `json:"breaker"`
+}
+
+// RendererStatus возвращает GRPCStatus (реализует resume.StatusReporter).
+func (c *GRPCClient) RendererStatus() any {
+	return GRPCStatus{
+		Transport: "grpc",
+		Target:    c.target,
+		State:     c.conn.GetState().String(),
+		Breaker:   c.breaker.current().String(),
+	}
+}
+
+// Close закрывает соединение.
+func (c *GRPCClient) Close() {
+	if err := c.conn.Close(); err != nil {
+		c.logger.Warn("close latex-service gRPC connection failed", "error", err)
+	}
+}
+
+// render вызывает Render и собирает ответ.
+func (c *GRPCClient) render(ctx context.Context, req *renderpb.RenderRequest, report func(contract.RenderEvent)) (ats *renderpb.AtsReport, pdf []byte, err error) {
+	err = c.call(ctx, func(ctx context.Context) error {
+		stream, err := c.rpc.Render(ctx, req)
+		if err != nil {
+			return err
+		}
+		ats, pdf, err = c.receive(stream, report)
+		return err
+	})
+	return ats, pdf, err
+}
+
+// call выполняет вызов через предохранитель, с таймаутом по умолчанию,
+// если у контекста нет дедлайна, и переводит статус gRPC в ошибки
+// backend.
+func (c *GRPCClient) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	probe, err := c.breaker.allow()
+	if err != nil {
+		return observeError(err)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	err = c.convertError(ctx, fn(ctx))
+	c.breaker.done(ctx, probe, err)
+	return observeError(err)
+}
+
+// convertError переводит ошибку gRPC в ошибки, которые понимает сервис
+// резюме: UNAVAILABLE — resume.UnavailableError, истёкший дедлайн и
+// отмена — ошибки контекста, несовместимая версия контракта —
+// contract.ErrUnsupportedSchemaVersion.
+func (c *GRPCClient) convertError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("call latex-service: %w", ctx.Err())
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		// ошибка самого клиента, например превышение MaxOutputSize
+		return err
+	}
+	switch st.Code() {
+	case codes.Unavailable:
+		return &resume.UnavailableError{Err: fmt.Errorf("call latex-service: %w", err)}
+	case codes.DeadlineExceeded:
+		return fmt.Errorf("call latex-service: %w", context.DeadlineExceeded)
+	case codes.Canceled:
+		return fmt.Errorf("call latex-service: %w", context.Canceled)
+	}
+
+	c.logger.WarnContext(ctx, "latex-service returned an error", "target", c.target, "code", st.Code().String(), "message", st.Message())
+	switch renderpb.Reason(err) {
+	case contract.ErrCodeUnsupportedSchemaVersion:
+		return fmt.Errorf("latex-service: %w: %s", contract.ErrUnsupportedSchemaVersion, st.Message())
+	case codeOutputTooLarge:
+		return fmt.Errorf("latex-service: %w: %s", resume.ErrOutputTooLarge, st.Message())
+	}
+	if st.Code() == codes.ResourceExhausted {
+		// сообщение больше MaxCallSendMsgSize клиента или MaxRecvMsgSize
+		// сервера; grpc-go отклоняет его без причины в деталях
+		return fmt.Errorf("latex-service: %w: %s", resume.ErrRequestTooLarge, st.Message())
+	}
+	return fmt.Errorf("latex-service: %w", err)
+}
+
+// responseStream — общая часть потоков ответа Render и RenderDiff.
+type responseStream interface {
+	Recv() (*renderpb.RenderResponse, error)
+}
+
+// receive читает поток ответа целиком: события этапов передаются
+// в report, отчёт ATS запоминается, PDF читается через pdfReader.
+func (c *GRPCClient) receive(stream responseStream, report func(contract.RenderEvent)) (*renderpb.AtsReport, []byte, error) {
+	pr := newPDFReader(stream, report, c.maxOutput)
+	if err := pr.start(); err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	if pr.size > 0 {
+		buf.Grow(int(pr.size))
+	}
+	if _, err := buf.ReadFrom(pr); err != nil {
+		return nil, nil, err
+	}
+	return pr.ats, buf.Bytes(), nil
+}
+
+// pdfReader отдаёт PDF из частей потока ответа по мере их прихода, не
+// собирая документ в памяти. Сообщения до первой части (события этапов,
+// отчёт ATS) разбирает start. Размер больше max — и объявленный в первой
+// части, и фактически пришедший — даёт resume.ErrOutputTooLarge.
+type pdfReader struct {
+	stream responseStream
+	report func(contract.RenderEvent)
+	max    int64
+
+	// ats — отчёт ATS, если он пришёл перед PDF.
+	ats *renderpb.AtsReport
+	// size — полный размер PDF из первой части, -1 — неизвестен.
+	size int64
+
+	buf  []byte
+	read int64
+	last bool
+	err  error
+}
+
+func newPDFReader(stream responseStream, report func(contract.RenderEvent), max int64) *pdfReader {
+	return &pdfReader{stream: stream, report: report, max: max, size: -1}
+}
+
+// start читает поток до первой части PDF.
+func (p *pdfReader) start() error {
+	chunk, err := p.next()
+	if err != nil {
+		return err
+	}
+	if size := chunk.GetSize(); size > 0 {
+		if size > p.max {
+			return fmt.Errorf("latex-service PDF of %d bytes: %w (limit %d)", size, resume.ErrOutputTooLarge, p.max)
+		}
+		p.size = size
+	}
+	return p.accept(chunk)
+}
+
+// next возвращает следующую часть PDF, разбирая остальные сообщения.
+func (p *pdfReader) next() (*renderpb.PdfChunk, error) {
+	for {
+		msg, err := p.stream.Recv()
+		if err == io.EOF {
+			return nil, errors.New("latex-service stream ended without the complete PDF")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch m := msg.GetPayload().(type) {
+		case *renderpb.RenderResponse_Progress:
+			if p.report != nil {
+				p.report(contract.RenderEvent{
+					Stage:     m.Progress.GetStage(),
+					Pass:      int(m.Progress.GetPass()),
+					MaxPasses: int(m.Progress.GetMaxPasses()),
+				})
+			}
+		case *renderpb.RenderResponse_AtsReport:
+			p.ats = m.AtsReport
+		case *renderpb.RenderResponse_Chunk:
+			return m.Chunk, nil
+		}
+	}
+}
+
+// accept проверяет предел размера и делает chunk текущей частью.
+func (p *pdfReader) accept(chunk *renderpb.PdfChunk) error {
+	data := chunk.GetData()
+	if total := p.read + int64(len(data)); total > p.max {
+		return fmt.Errorf("latex-service PDF of at least %d bytes: %w (limit %d)", total, resume.ErrOutputTooLarge, p.max)
+	}
+	p.read += int64(len(data))
+	p.buf = data
+	p.last = chunk.GetLast()
+	if p.last && p.read == 0 {
+		return errors.New("latex-service returned an empty PDF")
+	}
+	if p.last && p.size >= 0 && p.read != p.size {
+		return fmt.Errorf("latex-service PDF is %d bytes instead of %d", p.read, p.size)
+	}
+	return nil
+}
+
+func (p *pdfReader) Read(b []byte) (int, error) {
+	for len(p.buf) == 0 {
+		if p.err != nil {
+			return 0, p.err
+		}
+		if p.last {
+			return 0, io.EOF
+		}
+		chunk, err := p.next()
+		if err == nil {
+			err = p.accept(chunk)
+		}
+		if err != nil {
+			p.err = err
+			return 0, err
+		}
+	}
+	n := copy(b, p.buf)
+	p.buf = p.buf[n:]
+	return n, nil
+}
+
+// streamBody — тело StreamResume: ошибки потока переводятся в ошибки
+// backend так же, как ошибки вызова, Close отменяет поток.
+type streamBody struct {
+	client *GRPCClient
+	ctx    context.Context
+	pdf    *pdfReader
+	cancel context.CancelFunc
+	err    error
+}
+
+func (b *streamBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	n, err := b.pdf.Read(p)
+	if err != nil && err != io.EOF {
+		b.err = b.client.convertError(b.ctx, err)
+		err = b.err
+	}
+	return n, err
+}
+
+func (b *streamBody) Close() error {
+	b.cancel()
+	return nil
+}
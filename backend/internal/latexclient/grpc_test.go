@@ -0,0 +1,241 @@
+package latexclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+	"resume_contract/renderpb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStream отдаёт заранее заданные сообщения, затем err (по умолчанию io.EOF).
+type fakeStream struct {
+	msgs []*renderpb.RenderResponse
+	err  error
+}
+
+func (s *fakeStream) Recv() (*renderpb.RenderResponse, error) {
+	if len(s.msgs) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	m := s.msgs[0]
+	s.msgs = s.msgs[1:]
+	return m, nil
+}
+
+func chunk(data string, size int64, last bool) *renderpb.RenderResponse {
+	return &renderpb.RenderResponse{Payload: &renderpb.RenderResponse_Chunk{
+		Chunk: &renderpb.PdfChunk{Data: []byte(data), Size: size, Last: last},
+	}}
+}
+
+func TestPDFReader(t *testing.T) {
+	broken := errors.New("connection reset")
+
+	tests := []struct {
+		name     string
+		stream   *fakeStream
+		max      int64
+		want     string
+		startErr error
+		readErr  error
+	}{
+		{
+			name:   "single chunk",
+			stream: &fakeStream{msgs: []*renderpb.RenderResponse{chunk("%PDF-1.5", 8, true)}},
+			max:    100,
+			want:   "%PDF-1.5",
+		},
+		{
+			name: "progress and several chunks",
+			stream: &fakeStream{msgs: []*renderpb.RenderResponse{
+				{Payload: &renderpb.RenderResponse_Progress{Progress: &renderpb.Progress{Stage: contract.StageCompile}}},
+				chunk("%PDF", 8, false),
+				chunk("-1.5", 0, true),
+			}},
+			max:  100,
+			want: "%PDF-1.5",
+		},
+		{
+			name:     "declared size over the limit",
+			stream:   &fakeStream{msgs: []*renderpb.RenderResponse{chunk("%PDF", 1000, false)}},
+			max:      100,
+			startErr: resume.ErrOutputTooLarge,
+		},
+		{
+			name: "received data over the limit",
+			stream: &fakeStream{msgs: []*renderpb.RenderResponse{
+				chunk("0123456789", 0, false),
+				chunk("0123456789", 0, true),
+			}},
+			max:     15,
+			readErr: resume.ErrOutputTooLarge,
+		},
+		{
+			name:     "stream without chunks",
+			stream:   &fakeStream{},
+			max:      100,
+			startErr: errors.New("latex-service stream ended without the complete PDF"),
+		},
+		{
+			name:    "stream breaks midway",
+			stream:  &fakeStream{msgs: []*renderpb.RenderResponse{chunk("%PDF", 8, false)}, err: broken},
+			max:     100,
+			readErr: broken,
+		},
+		{
+			name:    "stream ends before the last chunk",
+			stream:  &fakeStream{msgs: []*renderpb.RenderResponse{chunk("%PDF", 8, false)}},
+			max:     100,
+			readErr: errors.New("latex-service stream ended without the complete PDF"),
+		},
+		{
+			name:     "empty PDF",
+			stream:   &fakeStream{msgs: []*renderpb.RenderResponse{chunk("", 0, true)}},
+			max:      100,
+			startErr: errors.New("latex-service returned an empty PDF"),
+		},
+		{
+			name:   "size mismatch",
+			stream: &fakeStream{msgs: []*renderpb.RenderResponse{chunk("%PDF", 8, true)}},
+			max:    100,
+			// единственная часть и последняя, поэтому проверяется уже в start
+			startErr: errors.New("latex-service PDF is 4 bytes instead of 8"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pr := newPDFReader(tt.stream, nil, tt.max)
+			err := pr.start()
+			if !matchErr(err, tt.startErr) {
+				t.Fatalf("start: err = %v, want %v", err, tt.startErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got, err := io.ReadAll(oneByte(pr))
+			if !matchErr(err, tt.readErr) {
+				t.Fatalf("read: err = %v, want %v", err, tt.readErr)
+			}
+			if err == nil && string(got) != tt.want {
+				t.Errorf("PDF = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPDFReaderReportsProgressAndATS(t *testing.T) {
+	stream := &fakeStream{msgs: []*renderpb.RenderResponse{
+		{Payload: &renderpb.RenderResponse_Progress{Progress: &renderpb.Progress{Stage: contract.StageCompile, Pass: 1, MaxPasses: 3}}},
+		{Payload: &renderpb.RenderResponse_AtsReport{AtsReport: &renderpb.AtsReport{Score: 90}}},
+		chunk("%PDF", 4, true),
+	}}
+
+	var events []contract.RenderEvent
+	c := &GRPCClient{maxOutput: 100}
+	ats, pdf, err := c.receive(stream, func(ev contract.RenderEvent) { events = append(events, ev) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(pdf, []byte("%PDF")) {
+		t.Errorf("PDF = %q", pdf)
+	}
+	if ats.GetScore() != 90 {
+		t.Errorf("ATS score = %d, want 90", ats.GetScore())
+	}
+	if len(events) != 1 || events[0].Stage != contract.StageCompile || events[0].Pass != 1 || events[0].MaxPasses != 3 {
+		t.Errorf("events = %+v", events)
+	}
+}
+
+func TestStreamBody(t *testing.T) {
+	stream := &fakeStream{
+		msgs: []*renderpb.RenderResponse{chunk("%PDF", 8, false)},
+		err:  status.Error(codes.Unavailable, "connection reset"),
+	}
+	pr := newPDFReader(stream, nil, 100)
+	if err := pr.start(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	body := &streamBody{client: &GRPCClient{}, ctx: ctx, pdf: pr, cancel: cancel}
+	got, err := io.ReadAll(body)
+	if string(got) != "%PDF" {
+		t.Errorf("PDF = %q, want the part received before the break", got)
+	}
+	var unavailable *resume.UnavailableError
+	if !errors.As(err, &unavailable) {
+		t.Fatalf("err = %v, want *resume.UnavailableError", err)
+	}
+	if _, again := body.Read(make([]byte, 1)); again != err {
+		t.Errorf("second Read: err = %v, want the same error", again)
+	}
+
+	if err := body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Error("Close did not cancel the stream context")
+	}
+}
+
+func TestConvertError(t *testing.T) {
+	c := &GRPCClient{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"request over the server limit", status.Error(codes.ResourceExhausted, "grpc: received message larger than max (300 vs. 200)"), resume.ErrRequestTooLarge},
+		{"request over the client limit", status.Error(codes.ResourceExhausted, "grpc: trying to send message larger than max (300 vs. 200)"), resume.ErrRequestTooLarge},
+		{"PDF over the limit", renderpb.Error(codes.ResourceExhausted, "output_too_large", "Generated PDF exceeds the size limit"), resume.ErrOutputTooLarge},
+		{"unavailable", status.Error(codes.Unavailable, "connection refused"), resume.ErrRendererUnavailable},
+		{"schema version", renderpb.Error(codes.InvalidArgument, contract.ErrCodeUnsupportedSchemaVersion, "schemaVersion 9"), contract.ErrUnsupportedSchemaVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := c.convertError(context.Background(), tt.err); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// oneByte читает по одному байту, чтобы части PDF дробились между вызовами Read.
+func oneByte(r io.Reader) io.Reader {
+	return readerFunc(func(p []byte) (int, error) {
+		if len(p) > 1 {
+			p = p[:1]
+		}
+		return r.Read(p)
+	})
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
+
+// matchErr сравнивает ошибки через errors.Is, а ошибки без
+// сигнальных значений — по тексту.
+func matchErr(got, want error) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+	return errors.Is(got, want) || got.Error() == want.Error()
+}
@@ -0,0 +1,70 @@
+package latexclient
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"resume_backend/internal/resume"
+)
+
+// codeOutputTooLarge — код ошибки latex-service, если PDF больше его
+// MAX_PDF_SIZE.
+const codeOutputTooLarge = "output_too_large"
+
+// maxEnvelopeSize — запас на всё, кроме PDF, в ответах, где документ
+// вложен в поток событий или multipart вместе с ATS-отчётом.
+const maxEnvelopeSize = 4 << 20
+
+// base64Len — длина n байт в base64.
+func base64Len(n int64) int64 {
+	return (n + 2) / 3 * 4
+}
+
+// limitBody проверяет Content-Length ответа и ограничивает чтение тела
+// limit байтами: тело длиннее даёт resume.ErrOutputTooLarge на чтении.
+// При ошибке тело закрывается.
+func (c *Client) limitBody(resp *http.Response, limit int64) (io.ReadCloser, error) {
+	if resp.ContentLength > limit {
+		resp.Body.Close()
+		return nil, fmt.Errorf("latex-service response of %d bytes: %w (limit %d)", resp.ContentLength, resume.ErrOutputTooLarge, limit)
+	}
+	return &limitedBody{ReadCloser: resp.Body, left: limit, limit: limit}, nil
+}
+
+// limitedBody — тело ответа, которое отказывается читать больше limit байт.
+type limitedBody struct {
+	io.ReadCloser
+	left  int64
+	limit int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.left <= 0 {
+		// лишний байт отличает тело ровно в limit от более длинного
+		var one [1]byte
+		n, err := b.ReadCloser.Read(one[:])
+		if n > 0 {
+			return 0, fmt.Errorf("latex-service response: %w (limit %d)", resume.ErrOutputTooLarge, b.limit)
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.left {
+		p = p[:b.left]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.left -= int64(n)
+	return n, err
+}
+
+// readPDF читает PDF из ограниченного тела.
+func readPDF(r io.Reader) ([]byte, error) {
+	pdf, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read pdf body: %w", err)
+	}
+	if len(pdf) == 0 {
+		return nil, fmt.Errorf("latex-service returned an empty PDF")
+	}
+	return pdf, nil
+}
@@ -0,0 +1,73 @@
+package latexclient
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc/status"
+
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+	"resume_contract/metrics"
+)
+
+var clientErrors = metrics.Default.NewCounter(
+	"latex_client_errors_total",
+	"Failed calls to latex-service by error type.",
+	"type",
+)
+
+// observeError считает неудачный вызов latex-service по типу ошибки и
+// возвращает err как есть. Каждый вызов считается один раз, повторные
+// попытки внутри него — нет.
+func observeError(err error) error {
+	if err != nil {
+		clientErrors.Inc(errorType(err))
+	}
+	return err
+}
+
+// errorType — тип ошибки для метки type.
+func errorType(err error) string {
+	var se *StatusError
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, resume.ErrRendererUnavailable):
+		return "unavailable"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, resume.ErrOutputTooLarge):
+		return "output_too_large"
+	case errors.Is(err, resume.ErrRequestTooLarge):
+		return "payload_too_large"
+	case errors.Is(err, contract.ErrUnsupportedSchemaVersion):
+		return "unsupported_schema_version"
+	case errors.As(err, &se):
+		return "render_failed"
+	}
+	if _, ok := status.FromError(err); ok {
+		return "render_failed"
+	}
+	return "other"
+}
+
+// observedBody считает ошибку чтения потокового тела ответа: она
+// происходит уже после того, как StreamResume вернул управление.
+type observedBody struct {
+	io.ReadCloser
+	failed bool
+}
+
+func (b *observedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF && !b.failed {
+		b.failed = true
+		observeError(err)
+	}
+	return n, err
+}
@@ -0,0 +1,354 @@
+package latexclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+)
+
+// Config — параметры устойчивости клиента; нулевые значения заменяются
+// дефолтами.
+type Config struct {
+	// MaxAttempts — сколько раз выполняется запрос, включая первый; 1 —
+	// без повторов.
+	MaxAttempts int
+	// BaseBackoff и MaxBackoff задают экспоненциальную паузу между
+	// попытками; фактическая пауза случайна в [0, backoff) (full jitter).
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// BreakerThreshold — сколько запросов подряд должно упасть с
+	// недоступностью, чтобы предохранитель разомкнулся.
+	BreakerThreshold int
+	// BreakerCooldown — сколько предохранитель разомкнут; после этого
+	// пропускается один пробный запрос.
+	BreakerCooldown time.Duration
+	// HedgeDelay — через сколько без ответа отправить дублирующий запрос
+	// и взять тот, что ответит первым; 0 — без дублирования.
+	HedgeDelay time.Duration
+	// HealthInterval — как часто проверяются узлы и перечитывается DNS.
+	HealthInterval time.Duration
+	// MaxOutputSize — предельный размер PDF в байтах; ответ больше
+	// прерывается с resume.ErrOutputTooLarge.
+	MaxOutputSize int64
+	// MaxRequestSize — предельный размер запроса, который принимает
+	// latex-service; больший запрос не отправляется и завершается
+	// resume.ErrRequestTooLarge.
+	MaxRequestSize int64
+}
+
+const (
+	defaultMaxAttempts      = 3
+	defaultBaseBackoff      = 200 * time.Millisecond
+	defaultMaxBackoff       = 2 * time.Second
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = 30 * time.Second
+	defaultMaxOutputSize    = 20 << 20
+)
+
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = defaultBaseBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultMaxBackoff
+	}
+	if c.BreakerThreshold <= 0 {
+		c.BreakerThreshold = defaultBreakerThreshold
+	}
+	if c.BreakerCooldown <= 0 {
+		c.BreakerCooldown = defaultBreakerCooldown
+	}
+	if c.HealthInterval <= 0 {
+		c.HealthInterval = defaultHealthInterval
+	}
+	if c.MaxOutputSize <= 0 {
+		c.MaxOutputSize = defaultMaxOutputSize
+	}
+	if c.MaxRequestSize <= 0 {
+		c.MaxRequestSize = contract.DefaultMaxRequestSize
+	}
+	return c
+}
+
+// ErrCircuitOpen — запрос не отправлялся: предохранитель разомкнут после
+// серии отказов latex-service. Приходит внутри resume.UnavailableError;
+// это то же значение, что resume.ErrCircuitOpen, по которому сервис резюме
+// переходит на запасной рендерер.
+var ErrCircuitOpen = resume.ErrCircuitOpen
+
+// StatusError — latex-service ответил ошибкой. Code и Message — из его
+// JSON-тела, если оно было.
+type StatusError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("latex-service returned status %d: %s: %s", e.Status, e.Code, e.Message)
+	}
+	return fmt.Sprintf("latex-service returned status %d", e.Status)
+}
+
+// retryable сообщает, можно ли повторить запрос: он либо не дошёл до
+// latex-service (соединение отвергнуто, имя не разрешилось), либо тот
+// явно ответил, что сейчас не может его обработать (502, 503). Рендер без
+// побочных эффектов, но повторять запрос, который мог уже выполняться,
+// незачем — это только удвоит нагрузку на перегруженный сервис.
+func retryable(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Status == http.StatusBadGateway || se.Status == http.StatusServiceUnavailable
+	}
+	var dnsErr *net.DNSError
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.As(err, &dnsErr)
+}
+
+// parseRetryAfter читает Retry-After в секундах или как HTTP-дату.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if n, err := strconv.Atoi(v); err == nil && n > 0 {
+		return time.Duration(n) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
+
+// backoff — пауза перед попыткой attempt+1.
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.cfg.BaseBackoff << (attempt - 1)
+	if d <= 0 || d > c.cfg.MaxBackoff {
+		d = c.cfg.MaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(d)) + 1)
+}
+
+// requestFunc строит запрос к узлу с базовым URL base.
+type requestFunc func(ctx context.Context, base string) (*http.Request, error)
+
+// send выполняет запрос с повторами. Ответ — всегда 200; остальные статусы
+// преобразуются в ошибки, недоступность — в resume.UnavailableError.
+// Повторы и дублирующие запросы уходят в первую очередь на узлы, которые
+// этот запрос ещё не пробовал.
+func (c *Client) send(ctx context.Context, newRequest requestFunc) (*http.Response, error) {
+	tried := make(map[*node]bool)
+	for attempt := 1; ; attempt++ {
+		resp, err := c.hedged(ctx, newRequest, tried)
+		if err == nil {
+			return resp, nil
+		}
+		if !retryable(err) || attempt == c.cfg.MaxAttempts {
+			return nil, err
+		}
+
+		wait := c.backoff(attempt)
+		var ue *resume.UnavailableError
+		if errors.As(err, &ue) && ue.RetryAfter > wait {
+			wait = ue.RetryAfter
+		}
+		// если до дедлайна не дождаться, повтор бессмыслен
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return nil, err
+		}
+
+		c.logger.WarnContext(ctx, "latex-service attempt failed, retrying", "attempt", attempt, "max_attempts", c.cfg.MaxAttempts, "retry_in", wait.Round(time.Millisecond).String(), "error", err)
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, fmt.Errorf("call latex-service: %w", ctx.Err())
+		case <-t.C:
+		}
+	}
+}
+
+// hedged выполняет одну попытку. С HedgeDelay, если ответа нет дольше
+// этого срока, отправляется второй такой же запрос, и побеждает первый
+// успешный; проигравший отменяется. Дублирование полезно, когда у
+// latex-service несколько реплик или свободные воркеры: хвост задержек
+// (медленный узел, долгий GC) срезается ценой лишнего рендера.
+func (c *Client) hedged(ctx context.Context, newRequest requestFunc, tried map[*node]bool) (*http.Response, error) {
+	if c.cfg.HedgeDelay <= 0 {
+		return c.do(ctx, newRequest, tried)
+	}
+
+	type result struct {
+		resp *http.Response
+		err  error
+		idx  int // индекс в cancels
+	}
+	results := make(chan result, 2)
+	var cancels []context.CancelFunc
+	launch := func() {
+		actx, cancel := context.WithCancel(ctx)
+		idx := len(cancels)
+		cancels = append(cancels, cancel)
+		go func() {
+			resp, err := c.do(actx, newRequest, tried)
+			results <- result{resp: resp, err: err, idx: idx}
+		}()
+	}
+
+	launch()
+	inFlight, hedgedOnce := 1, false
+	timer := time.NewTimer(c.cfg.HedgeDelay)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case <-timer.C:
+			if !hedgedOnce {
+				hedgedOnce = true
+				inFlight++
+				launch()
+			}
+		case res := <-results:
+			inFlight--
+			if res.err == nil {
+				// проигравшего отменяем сразу, а его ответ закрываем, чтобы
+				// не текли соединения
+				for i, cancel := range cancels {
+					if i != res.idx {
+						cancel()
+					}
+				}
+				go func(n int) {
+					for i := 0; i < n; i++ {
+						if r := <-results; r.err == nil {
+							r.resp.Body.Close()
+						}
+					}
+				}(inFlight)
+				res.resp.Body = &cancelBody{ReadCloser: res.resp.Body, cancel: cancels[res.idx]}
+				return res.resp, nil
+			}
+			cancels[res.idx]()
+			lastErr = res.err
+			if inFlight == 0 {
+				return nil, lastErr
+			}
+		}
+	}
+}
+
+// breakerState — состояние предохранителя.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerOpen:
+		return "open"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// breaker — предохранитель: после threshold отказов подряд он размыкается
+// на cooldown, и запросы сразу получают ErrCircuitOpen, не ожидая таймаутов.
+// Затем пропускается один пробный запрос: успех замыкает цепь, отказ
+// размыкает её снова.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func newBreaker(threshold int, cooldown time.Duration, logger *slog.Logger) *breaker {
+	return &breaker{threshold: threshold, cooldown: cooldown, logger: logger, now: time.Now}
+}
+
+func (b *breaker) current() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// allow решает, можно ли отправить запрос. probe == true — это пробный
+// запрос после cooldown, о его исходе нужно сообщить в done.
+func (b *breaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return false, nil
+	case breakerOpen:
+		if left := b.openedAt.Add(b.cooldown).Sub(b.now()); left > 0 {
+			return false, &resume.UnavailableError{Err: ErrCircuitOpen, RetryAfter: left}
+		}
+		b.state = breakerHalfOpen
+		return true, nil
+	default:
+		// пробный запрос уже выполняется
+		return false, &resume.UnavailableError{Err: ErrCircuitOpen, RetryAfter: time.Second}
+	}
+}
+
+// done учитывает исход запроса. Отказом считается только недоступность
+// latex-service; ошибка рендера показывает, что сервис жив, а отмена
+// и таймаут ничего о нём не говорят.
+func (b *breaker) done(ctx context.Context, probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	failed := errors.Is(err, resume.ErrRendererUnavailable)
+	switch {
+	case failed:
+		b.failures++
+		if probe || (b.state == breakerClosed && b.failures >= b.threshold) {
+			if b.state == breakerClosed {
+				b.logger.WarnContext(ctx, "latex-service circuit breaker opened", "failures", b.failures, "error", err)
+			}
+			b.state = breakerOpen
+			b.openedAt = b.now()
+		}
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		if probe {
+			// проба ничего не показала — следующий запрос попробует снова
+			b.state = breakerOpen
+		}
+	default:
+		if b.state != breakerClosed && probe {
+			b.logger.InfoContext(ctx, "latex-service circuit breaker closed")
+		}
+		if probe || b.state == breakerClosed {
+			b.state = breakerClosed
+			b.failures = 0
+		}
+	}
+}
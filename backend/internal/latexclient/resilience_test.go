@@ -0,0 +1,184 @@
+package latexclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newBreaker(2, time.Minute, discard)
+	b.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	unavailable := &resume.UnavailableError{Err: errors.New("connection refused")}
+
+	// allow проверяет решение предохранителя и возвращает probe
+	allow := func(step string, wantOpen bool) bool {
+		t.Helper()
+		probe, err := b.allow()
+		if got := errors.Is(err, ErrCircuitOpen); got != wantOpen {
+			t.Fatalf("%s: allow err = %v, want circuit open %v", step, err, wantOpen)
+		}
+		return probe
+	}
+	state := func(step string, want breakerState) {
+		t.Helper()
+		if got := b.current(); got != want {
+			t.Fatalf("%s: state = %s, want %s", step, got, want)
+		}
+	}
+
+	allow("first request", false)
+	b.done(ctx, false, unavailable)
+	state("one failure", breakerClosed)
+
+	// ошибка рендера показывает, что сервис жив, и сбрасывает счётчик
+	b.done(ctx, false, &StatusError{Status: http.StatusBadRequest})
+	b.done(ctx, false, unavailable)
+	state("failures are not consecutive", breakerClosed)
+
+	// отмена и таймаут ничего не говорят о сервисе
+	b.done(ctx, false, context.Canceled)
+	b.done(ctx, false, unavailable)
+	state("threshold reached", breakerOpen)
+
+	_, err := b.allow()
+	var ue *resume.UnavailableError
+	if !errors.As(err, &ue) || ue.RetryAfter != time.Minute {
+		t.Fatalf("open: allow err = %v, want UnavailableError with RetryAfter 1m", err)
+	}
+
+	now = now.Add(time.Minute)
+	if !allow("after cooldown", false) {
+		t.Fatal("after cooldown: request is not a probe")
+	}
+	state("probe in flight", breakerHalfOpen)
+	allow("second request during probe", true)
+
+	b.done(ctx, true, context.DeadlineExceeded)
+	state("probe timed out", breakerOpen)
+	if !allow("retry of the timed out probe", false) {
+		t.Fatal("retry of the timed out probe is not a probe")
+	}
+
+	b.done(ctx, true, unavailable)
+	state("probe failed", breakerOpen)
+	allow("cooldown restarted", true)
+
+	now = now.Add(time.Minute)
+	probe := allow("second probe", false)
+	b.done(ctx, probe, nil)
+	state("probe succeeded", breakerClosed)
+	allow("closed again", false)
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"connection refused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "latex-service"}, true},
+		{"bad gateway", &StatusError{Status: http.StatusBadGateway}, true},
+		{"service unavailable", &resume.UnavailableError{Err: &StatusError{Status: http.StatusServiceUnavailable}}, true},
+		{"internal error", &StatusError{Status: http.StatusInternalServerError}, false},
+		{"timeout", fmt.Errorf("call latex-service: %w", context.DeadlineExceeded), false},
+		{"request too large", resume.ErrRequestTooLarge, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHedging(t *testing.T) {
+	tests := []struct {
+		name      string
+		delay     time.Duration
+		slowFirst bool // первый запрос ждёт отмены или 500 через 100 мс
+		status    int  // ответ остальных запросов
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"slow request is hedged", 20 * time.Millisecond, true, http.StatusOK, 2, false},
+		{"fast response is not hedged", time.Second, false, http.StatusOK, 1, false},
+		{"both requests fail", 20 * time.Millisecond, true, http.StatusInternalServerError, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			canceled := make(chan struct{}, 1)
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/healthz" {
+					io.WriteString(w, `{"status":"ok","workers":2}`)
+					return
+				}
+				// пока тело не дочитано, сервер не замечает отмену запроса
+				io.Copy(io.Discard, r.Body)
+				if calls.Add(1) == 1 && tt.slowFirst {
+					select {
+					case <-r.Context().Done():
+						canceled <- struct{}{}
+						return
+					case <-time.After(100 * time.Millisecond):
+						w.WriteHeader(http.StatusInternalServerError)
+						return
+					}
+				}
+				w.WriteHeader(tt.status)
+				io.WriteString(w, "%PDF-1.5")
+			}))
+			defer srv.Close()
+
+			c := NewClient([]string{srv.URL}, Config{MaxAttempts: 1, HedgeDelay: tt.delay}, discard)
+			defer c.Close()
+
+			// тело победителя остаётся открытым, пока проверяется отмена
+			// проигравшего: она не должна ждать конца чтения ответа
+			body, _, err := c.StreamResume(context.Background(), resume.Resume{FullName: "Ivan"})
+			var pdf []byte
+			if err == nil {
+				defer body.Close()
+				pdf, err = io.ReadAll(body)
+			}
+			if tt.wantErr {
+				var se *StatusError
+				if !errors.As(err, &se) || se.Status != http.StatusInternalServerError {
+					t.Fatalf("err = %v, want status 500", err)
+				}
+			} else if err != nil || string(pdf) != "%PDF-1.5" {
+				t.Fatalf("StreamResume = %q, %v", pdf, err)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("requests = %d, want %d", got, tt.wantCalls)
+			}
+
+			if tt.slowFirst && !tt.wantErr {
+				select {
+				case <-canceled:
+				case <-time.After(time.Second):
+					t.Error("losing request was not canceled")
+				}
+			}
+		})
+	}
+}
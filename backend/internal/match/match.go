@@ -0,0 +1,282 @@
+// Package match сопоставляет резюме с текстом вакансии: выделяет ключевые
+// слова вакансии, нормализует их (стемминг, синонимы вроде k8s/Kubernetes)
+// и показывает, какие из них есть в резюме и в каких секциях.
+// Всё считается локально, без внешних сервисов.
+package match
+
+import (
+	"sort"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+// Секции резюме, по которым считается покрытие.
+const (
+	SectionPosition       = "position"
+	SectionSummary        = "summary"
+	SectionSkills         = "skills"
+	SectionExperience     = "experience"
+	SectionEducation      = "education"
+	SectionCustomSections = "customSections"
+)
+
+var sectionOrder = []string{
+	SectionPosition,
+	SectionSummary,
+	SectionSkills,
+	SectionExperience,
+	SectionEducation,
+	SectionCustomSections,
+}
+
+// Причины, по которым навык предлагается вынести в секцию skills.
+const (
+	SuggestionNotInSkills = "not_in_skills" // упоминается в резюме, но не в skills
+	SuggestionMissing     = "missing"       // требуется вакансией, в резюме нет
+)
+
+// maxKeywords ограничивает число ключевых слов вакансии в отчёте.
+const maxKeywords = 40
+
+// Keyword — ключевое слово вакансии.
+type Keyword struct {
+	Keyword  string   `json:"keyword"`
+	Count    int      `json:"count"`
+	Sections []string `json:"sections,omitempty"`
+}
+
+// SectionCoverage — доля ключевых слов вакансии, найденных в секции.
+type SectionCoverage struct {
+	Section  string `json:"section"`
+	Matched  int    `json:"matched"`
+	Coverage int    `json:"coverage"` // в процентах
+}
+
+// Suggestion — навык, который стоит указать в секции skills.
+type Suggestion struct {
+	Skill  string `json:"skill"`
+	Reason string `json:"reason"`
+}
+
+// Report — результат сопоставления резюме с вакансией.
+type Report struct {
+	// Score — доля ключевых слов вакансии (с учётом частоты), найденных
+	// в резюме, в процентах.
+	Score           int               `json:"score"`
+	Matched         []Keyword         `json:"matched"`
+	Missing         []Keyword         `json:"missing"`
+	Sections        []SectionCoverage `json:"sections"`
+	SuggestedSkills []Suggestion      `json:"suggestedSkills"`
+}
+
+// term — нормализованное ключевое слово.
+type term struct {
+	key   string // "k:<название>" для известных терминов, "s:<основа>" для остальных
+	known bool
+}
+
+func (t term) display(surfaces map[string]int) string {
+	if t.known {
+		return strings.TrimPrefix(t.key, "k:")
+	}
+	best, bestN := "", 0
+	for s, n := range surfaces {
+		if n > bestN || (n == bestN && s < best) {
+			best, bestN = s, n
+		}
+	}
+	return best
+}
+
+// occurrence — вхождение термина в текст.
+type occurrence struct {
+	term    term
+	surface string
+	// named — слово написано с заглавной не в начале предложения,
+	// то есть скорее всего это название технологии или продукта.
+	named bool
+}
+
+// extract превращает текст в последовательность терминов: сначала ищутся
+// известные термины (включая многословные синонимы; неоднозначные короткие
+// синонимы — только в контексте), затем остальные слова без служебных
+// приводятся к основе.
+func extract(s string) []occurrence {
+	toks := tokenize(s)
+	var out []occurrence
+
+	// knownEnd — позиция сразу после последнего найденного термина
+	knownEnd := -1
+	for i := 0; i < len(toks); {
+		prevKnown := knownEnd == i || (i > 0 && knownEnd == i-1 && isConjunction(toks[i-1].lower))
+		if idx, n := lookup(toks, i); n > 0 && (!isAmbiguous(toks, i, n) || inContext(toks, i, prevKnown)) {
+			out = append(out, occurrence{
+				term:    term{key: "k:" + knownTerms[idx].Display, known: true},
+				surface: knownTerms[idx].Display,
+			})
+			i += n
+			knownEnd = i
+			continue
+		}
+
+		tok := toks[i]
+		i++
+		if len([]rune(tok.lower)) < 2 || isNumber(tok.lower) || isStopWord(tok.lower) {
+			continue
+		}
+		st := stem(tok.lower)
+		if isStopWord(st) {
+			continue
+		}
+		out = append(out, occurrence{
+			term:    term{key: "s:" + st},
+			surface: tok.lower,
+			named:   isCapitalized(tok.surface) && !tok.sentenceStart,
+		})
+	}
+
+	return out
+}
+
+// jobKeyword — ключевое слово вакансии до сопоставления.
+type jobKeyword struct {
+	term     term
+	count    int
+	named    bool
+	surfaces map[string]int
+}
+
+// keywords выбирает ключевые слова вакансии: известные термины и названия
+// берутся всегда, прочие слова — если встречаются хотя бы дважды.
+func keywords(jobDescription string) []*jobKeyword {
+	byKey := make(map[string]*jobKeyword)
+	var order []*jobKeyword
+
+	for _, occ := range extract(jobDescription) {
+		kw, ok := byKey[occ.term.key]
+		if !ok {
+			kw = &jobKeyword{term: occ.term, surfaces: make(map[string]int)}
+			byKey[occ.term.key] = kw
+			order = append(order, kw)
+		}
+		kw.count++
+		kw.named = kw.named || occ.named
+		kw.surfaces[occ.surface]++
+	}
+
+	var out []*jobKeyword
+	for _, kw := range order {
+		if kw.term.known || kw.named || kw.count >= 2 {
+			out = append(out, kw)
+		}
+	}
+
+	weight := func(kw *jobKeyword) int {
+		if kw.term.known {
+			return kw.count + 2
+		}
+		return kw.count
+	}
+	sort.SliceStable(out, func(i, j int) bool { return weight(out[i]) > weight(out[j]) })
+
+	if len(out) > maxKeywords {
+		out = out[:maxKeywords]
+	}
+	return out
+}
+
+// sectionTexts раскладывает резюме на тексты по секциям. Каждая строка
+// разбирается отдельно, чтобы многословные термины не склеивались через
+// границы пунктов.
+func sectionTexts(r resume.Resume) map[string][]string {
+	texts := map[string][]string{
+		SectionPosition: {r.Position},
+		SectionSummary:  {r.Summary},
+		SectionSkills:   resume.SkillTexts(r.Skills),
+	}
+	for _, e := range r.Experience {
+		texts[SectionExperience] = append(texts[SectionExperience], e.Position, e.Company, e.Description)
+		texts[SectionExperience] = append(texts[SectionExperience], resume.Texts(e.Bullets)...)
+	}
+	for _, e := range r.Education {
+		texts[SectionEducation] = append(texts[SectionEducation], e.Institution, e.Degree, e.Details)
+	}
+	for _, cs := range r.CustomSections {
+		texts[SectionCustomSections] = append(texts[SectionCustomSections], cs.Title)
+		texts[SectionCustomSections] = append(texts[SectionCustomSections], resume.Texts(cs.Items)...)
+	}
+	return texts
+}
+
+// Analyze сопоставляет резюме с текстом вакансии.
+func Analyze(r resume.Resume, jobDescription string) Report {
+	report := Report{
+		Matched:         []Keyword{},
+		Missing:         []Keyword{},
+		Sections:        []SectionCoverage{},
+		SuggestedSkills: []Suggestion{},
+	}
+
+	inSection := make(map[string]map[string]bool)
+	for section, texts := range sectionTexts(r) {
+		set := make(map[string]bool)
+		for _, text := range texts {
+			for _, occ := range extract(text) {
+				set[occ.term.key] = true
+			}
+		}
+		inSection[section] = set
+	}
+
+	kws := keywords(jobDescription)
+	matchedPerSection := make(map[string]int)
+	var total, found int
+
+	for _, kw := range kws {
+		k := Keyword{Keyword: kw.term.display(kw.surfaces), Count: kw.count}
+		for _, section := range sectionOrder {
+			if inSection[section][kw.term.key] {
+				k.Sections = append(k.Sections, section)
+				matchedPerSection[section]++
+			}
+		}
+
+		total += kw.count
+		if len(k.Sections) > 0 {
+			found += kw.count
+			report.Matched = append(report.Matched, k)
+		} else {
+			report.Missing = append(report.Missing, k)
+		}
+
+		if !kw.term.known || inSection[SectionSkills][kw.term.key] {
+			continue
+		}
+		reason := SuggestionMissing
+		if len(k.Sections) > 0 {
+			reason = SuggestionNotInSkills
+		}
+		report.SuggestedSkills = append(report.SuggestedSkills, Suggestion{Skill: k.Keyword, Reason: reason})
+	}
+
+	// сначала навыки, которые уже подтверждены опытом
+	sort.SliceStable(report.SuggestedSkills, func(i, j int) bool {
+		return report.SuggestedSkills[i].Reason == SuggestionNotInSkills &&
+			report.SuggestedSkills[j].Reason != SuggestionNotInSkills
+	})
+
+	for _, section := range sectionOrder {
+		c := SectionCoverage{Section: section, Matched: matchedPerSection[section]}
+		if len(kws) > 0 {
+			c.Coverage = 100 * c.Matched / len(kws)
+		}
+		report.Sections = append(report.Sections, c)
+	}
+
+	if total > 0 {
+		report.Score = 100 * found / total
+	}
+
+	return report
+}
@@ -0,0 +1,170 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+
+	"resume_backend/internal/resume"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in    string
+		words []string
+		start []bool
+	}{
+		{"C++, C# and Node.js.", []string{"C++", "C#", "and", "Node.js"}, []bool{true, false, false, false}},
+		{"CI/CD pipelines", []string{"CI", "CD", "pipelines"}, []bool{true, false, false}},
+		{"Done. Next: Go", []string{"Done", "Next", "Go"}, []bool{true, true, true}},
+		{"• Kafka\n• gRPC", []string{"Kafka", "gRPC"}, []bool{true, true}},
+		{"... +", nil, nil},
+	}
+	for _, tt := range tests {
+		var words []string
+		var start []bool
+		for _, tok := range tokenize(tt.in) {
+			words = append(words, tok.surface)
+			start = append(start, tok.sentenceStart)
+		}
+		if !reflect.DeepEqual(words, tt.words) || !reflect.DeepEqual(start, tt.start) {
+			t.Errorf("tokenize(%q) = %q %v, want %q %v", tt.in, words, start, tt.words, tt.start)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"developers", "develop"},
+		{"developing", "develop"},
+		{"developer", "develop"},
+		{"class", "class"},
+		{"go", "go"},
+		{"node.js", "node.js"},
+		{"разработчиков", "разработчик"},
+		{"разработчика", "разработчик"},
+		{"разработчики", "разработчик"},
+	}
+	for _, tt := range tests {
+		if got := stem(tt.in); got != tt.want {
+			t.Errorf("stem(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// keys возвращает ключи терминов текста.
+func keys(s string) []string {
+	var out []string
+	for _, occ := range extract(s) {
+		out = append(out, occ.term.key)
+	}
+	return out
+}
+
+func TestSynonyms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"k8s, kube, Kubernetes", []string{"k:Kubernetes", "k:Kubernetes", "k:Kubernetes"}},
+		{"golang", []string{"k:Go"}},
+		{"continuous integration", []string{"k:CI/CD"}},
+		{"CI/CD", []string{"k:CI/CD"}},
+		{"nodejs or Node.js", []string{"k:Node.js", "k:Node.js"}},
+		{"Spring Boot", []string{"k:Spring"}},
+		{"машинное обучение", []string{"k:Machine learning"}},
+		{"deploying and tested", []string{"s:deploy", "s:test"}},
+	}
+	for _, tt := range tests {
+		if got := keys(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("extract(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAmbiguousAliases(t *testing.T) {
+	tests := []struct {
+		in   string
+		term string
+		want bool
+	}{
+		{"We go to market fast", "Go", false},
+		{"Go to the office twice a week", "Go", false},
+		{"Experience with Go in production", "Go", true},
+		{"Go", "Go", true},
+		{"Go, Kubernetes", "Go", true},
+		{"python and go", "Go", true},
+		{"опыт разработки на go", "Go", true},
+		{"The rest of the team", "REST", false},
+		{"REST and gRPC", "REST", true},
+		{"each node in the cluster", "Node.js", false},
+		{"Frontend with Node and React", "Node.js", true},
+		{"spring 2024 release", "Spring", false},
+		{"swift delivery of features", "Swift", false},
+		{"iOS apps in Swift", "Swift", true},
+		{"rust on old cars", "Rust", false},
+		{"ML pipelines", "Machine learning", true},
+		{"mentored ml engineers", "Machine learning", false},
+		{"scripts in py", "Python", false},
+		{"ts", "TypeScript", true},
+	}
+	for _, tt := range tests {
+		got := false
+		for _, k := range keys(tt.in) {
+			got = got || k == "k:"+tt.term
+		}
+		if got != tt.want {
+			t.Errorf("%q: found %s = %v, want %v", tt.in, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	r := resume.Resume{
+		Position: "Backend developer",
+		Skills:   []resume.SkillGroup{{Items: resume.Untagged([]string{"Go", "PostgreSQL"})}},
+		Experience: []resume.ExperienceItem{{
+			Company: "Acme",
+			Bullets: resume.Untagged([]string{"Ran services on k8s"}),
+		}},
+	}
+	job := "We need Golang, Kubernetes and Kafka. Kubernetes is a must."
+
+	got := Analyze(r, job)
+	want := Report{
+		Score: 75,
+		Matched: []Keyword{
+			{Keyword: "Kubernetes", Count: 2, Sections: []string{SectionExperience}},
+			{Keyword: "Go", Count: 1, Sections: []string{SectionSkills}},
+		},
+		Missing: []Keyword{{Keyword: "Kafka", Count: 1}},
+		Sections: []SectionCoverage{
+			{Section: SectionPosition},
+			{Section: SectionSummary},
+			{Section: SectionSkills, Matched: 1, Coverage: 33},
+			{Section: SectionExperience, Matched: 1, Coverage: 33},
+			{Section: SectionEducation},
+			{Section: SectionCustomSections},
+		},
+		SuggestedSkills: []Suggestion{
+			{Skill: "Kubernetes", Reason: SuggestionNotInSkills},
+			{Skill: "Kafka", Reason: SuggestionMissing},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Analyze =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestAnalyzeEmptyJob(t *testing.T) {
+	got := Analyze(resume.Resume{Position: "Dev"}, "and the of")
+	if got.Score != 0 || len(got.Matched) != 0 || len(got.Missing) != 0 {
+		t.Errorf("Analyze of a job without keywords = %+v", got)
+	}
+	for _, c := range got.Sections {
+		if c.Coverage != 0 {
+			t.Errorf("coverage %+v without keywords", c)
+		}
+	}
+}
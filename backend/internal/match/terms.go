@@ -0,0 +1,178 @@
+package match
+
+import "strings"
+
+// knownTerm — технология или навык с каноническим написанием и синонимами.
+// Синонимы записываются в нижнем регистре; многословные сопоставляются
+// как последовательность токенов.
+type knownTerm struct {
+	Display string
+	Aliases []string
+}
+
+var knownTerms = []knownTerm{
+	{"Go", []string{"go", "golang"}},
+	{"Python", []string{"python", "py"}},
+	{"Java", []string{"java"}},
+	{"Kotlin", []string{"kotlin"}},
+	{"Scala", []string{"scala"}},
+	{"C++", []string{"c++", "cpp"}},
+	{"C#", []string{"c#", "csharp"}},
+	{".NET", []string{".net", "dotnet"}},
+	{"Rust", []string{"rust"}},
+	{"Ruby", []string{"ruby"}},
+	{"Ruby on Rails", []string{"ruby on rails", "rails", "ror"}},
+	{"PHP", []string{"php"}},
+	{"Swift", []string{"swift"}},
+	{"JavaScript", []string{"javascript", "js", "ecmascript"}},
+	{"TypeScript", []string{"typescript", "ts"}},
+	{"Node.js", []string{"node.js", "nodejs", "node"}},
+	{"React", []string{"react", "react.js", "reactjs"}},
+	{"Vue", []string{"vue", "vue.js", "vuejs"}},
+	{"Angular", []string{"angular", "angularjs"}},
+	{"HTML", []string{"html", "html5"}},
+	{"CSS", []string{"css", "css3"}},
+	{"SQL", []string{"sql"}},
+	{"NoSQL", []string{"nosql"}},
+	{"PostgreSQL", []string{"postgresql", "postgres", "psql"}},
+	{"MySQL", []string{"mysql"}},
+	{"MongoDB", []string{"mongodb", "mongo"}},
+	{"Redis", []string{"redis"}},
+	{"ClickHouse", []string{"clickhouse"}},
+	{"Elasticsearch", []string{"elasticsearch", "elastic search", "opensearch"}},
+	{"Kafka", []string{"kafka", "apache kafka"}},
+	{"RabbitMQ", []string{"rabbitmq", "rabbit mq"}},
+	{"gRPC", []string{"grpc"}},
+	{"GraphQL", []string{"graphql"}},
+	{"REST", []string{"rest", "restful", "rest api"}},
+	{"Microservices", []string{"microservices", "microservice", "микросервисы", "микросервис", "микросервисная архитектура"}},
+	{"Docker", []string{"docker"}},
+	{"Kubernetes", []string{"kubernetes", "k8s", "kube"}},
+	{"Helm", []string{"helm"}},
+	{"Terraform", []string{"terraform"}},
+	{"Ansible", []string{"ansible"}},
+	{"AWS", []string{"aws", "amazon web services"}},
+	{"GCP", []string{"gcp", "google cloud", "google cloud platform"}},
+	{"Azure", []string{"azure", "microsoft azure"}},
+	{"Linux", []string{"linux"}},
+	{"Nginx", []string{"nginx"}},
+	{"Git", []string{"git"}},
+	{"CI/CD", []string{"ci cd", "cicd", "continuous integration", "continuous delivery", "continuous deployment"}},
+	{"GitLab CI", []string{"gitlab ci"}},
+	{"GitHub Actions", []string{"github actions"}},
+	{"Jenkins", []string{"jenkins"}},
+	{"Prometheus", []string{"prometheus"}},
+	{"Grafana", []string{"grafana"}},
+	{"DevOps", []string{"devops"}},
+	{"SRE", []string{"sre", "site reliability engineering"}},
+	{"Machine learning", []string{"machine learning", "ml", "машинное обучение"}},
+	{"Deep learning", []string{"deep learning", "глубокое обучение"}},
+	{"NLP", []string{"nlp", "natural language processing"}},
+	{"PyTorch", []string{"pytorch", "torch"}},
+	{"TensorFlow", []string{"tensorflow"}},
+	{"Pandas", []string{"pandas"}},
+	{"Spark", []string{"spark", "apache spark", "pyspark"}},
+	{"Airflow", []string{"airflow", "apache airflow"}},
+	{"Django", []string{"django"}},
+	{"Flask", []string{"flask"}},
+	{"FastAPI", []string{"fastapi"}},
+	{"Spring", []string{"spring", "spring boot"}},
+	{"TDD", []string{"tdd", "test driven development"}},
+	{"OOP", []string{"oop", "object oriented programming", "ооп"}},
+	{"Agile", []string{"agile"}},
+	{"Scrum", []string{"scrum"}},
+	{"Jira", []string{"jira"}},
+	{"Figma", []string{"figma"}},
+	{"LaTeX", []string{"latex"}},
+	{"English", []string{"english", "английский", "английского"}},
+}
+
+// ambiguousAliases — короткие синонимы, которые совпадают с обычными словами
+// ("go to market", "the rest of", "spring 2024"). Термином такое слово
+// считается только по написанию или контексту, см. inContext.
+var ambiguousAliases = toSet(`go rest node spring swift rust ts py ml react rails spark torch flask`)
+
+// conjunctions связывают элементы перечисления: "Python and Go".
+var conjunctions = toSet(`and or и или`)
+
+// termIndex сопоставляет синоним (токены через пробел) с индексом в knownTerms.
+var termIndex, maxAliasTokens = buildTermIndex()
+
+func buildTermIndex() (map[string]int, int) {
+	index := make(map[string]int)
+	longest := 1
+	for i, t := range knownTerms {
+		for _, alias := range t.Aliases {
+			toks := tokenize(alias)
+			words := make([]string, len(toks))
+			for j, tok := range toks {
+				words[j] = tok.lower
+			}
+			index[strings.Join(words, " ")] = i
+			longest = max(longest, len(words))
+		}
+	}
+	return index, longest
+}
+
+// lookup ищет самый длинный синоним, который начинается с toks[i], и
+// возвращает индекс термина и число занятых токенов (0, если синонима нет).
+func lookup(toks []token, i int) (idx, n int) {
+	for n := min(maxAliasTokens, len(toks)-i); n >= 1; n-- {
+		words := make([]string, n)
+		for j := range words {
+			words[j] = toks[i+j].lower
+		}
+		if idx, ok := termIndex[strings.Join(words, " ")]; ok {
+			return idx, n
+		}
+	}
+	return -1, 0
+}
+
+// isAmbiguous сообщает, что синоним из n токенов с позиции i — неоднозначное
+// короткое слово.
+func isAmbiguous(toks []token, i, n int) bool {
+	if n != 1 {
+		return false
+	}
+	_, ok := ambiguousAliases[toks[i].lower]
+	return ok
+}
+
+// inContext решает, считать ли неоднозначный синоним toks[i] термином:
+// он должен быть записан как название ("Go" посреди предложения, "REST"),
+// составлять весь текст (пункт skills) или стоять рядом с другим термином
+// либо русским словом ("Python, go", "опыт с go"). prevKnown — предыдущее
+// слово (возможно, через союз) входит в известный термин.
+func inContext(toks []token, i int, prevKnown bool) bool {
+	tok := toks[i]
+	switch {
+	case len(toks) == 1 || prevKnown:
+		return true
+	case len(tok.surface) >= 2 && tok.surface == strings.ToUpper(tok.surface):
+		return true
+	case isCapitalized(tok.surface) && !tok.sentenceStart:
+		return true
+	case i > 0 && isCyrillic(toks[i-1].lower):
+		return true
+	}
+
+	j := i + 1
+	if j < len(toks) && isConjunction(toks[j].lower) {
+		j++
+	}
+	if j >= len(toks) {
+		return false
+	}
+	if j == i+1 && isCyrillic(toks[j].lower) {
+		return true
+	}
+	_, n := lookup(toks, j)
+	return n > 0 && !isAmbiguous(toks, j, n)
+}
+
+func isConjunction(w string) bool {
+	_, ok := conjunctions[w]
+	return ok
+}
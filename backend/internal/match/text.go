@@ -0,0 +1,178 @@
+package match
+
+import (
+	"strings"
+	"unicode"
+)
+
+// token — слово исходного текста.
+type token struct {
+	surface string
+	lower   string
+	// sentenceStart — слово стоит в начале предложения или строки, поэтому
+	// заглавная буква не говорит о том, что это название.
+	sentenceStart bool
+}
+
+// tokenize разбивает текст на слова. Внутри слова допускаются '+', '#' и
+// точка между буквами, чтобы "C++", "C#" и "Node.js" оставались целыми;
+// дефис и слэш разделяют слова ("CI/CD" -> "ci", "cd").
+func tokenize(s string) []token {
+	var out []token
+	var b strings.Builder
+	start := true
+
+	// flush завершает текущее слово; точка в конце слова закрывает предложение.
+	flush := func() {
+		raw := b.String()
+		b.Reset()
+		w := strings.TrimRight(raw, ".")
+		if strings.Trim(w, ".+#") != "" {
+			out = append(out, token{surface: w, lower: strings.ToLower(w), sentenceStart: start})
+			start = false
+		}
+		if strings.HasSuffix(raw, ".") {
+			start = true
+		}
+	}
+
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("+#.", r) {
+			b.WriteRune(r)
+			continue
+		}
+		flush()
+		if strings.ContainsRune("!?:;\n•", r) {
+			start = true
+		}
+	}
+	flush()
+
+	return out
+}
+
+// isCyrillic сообщает, написано ли слово кириллицей.
+func isCyrillic(w string) bool {
+	for _, r := range w {
+		return unicode.Is(unicode.Cyrillic, r)
+	}
+	return false
+}
+
+// stem отрезает частые окончания, чтобы "developers", "developing" и
+// "developer" давали одну основу. Это лёгкий стеммер: он ошибается на
+// исключениях, но одинаково для вакансии и резюме.
+func stem(w string) string {
+	if isCyrillic(w) {
+		return stemRussian(w)
+	}
+	return stemEnglish(w)
+}
+
+var englishSuffixes = []struct{ suffix, repl string }{
+	{"ational", "ate"},
+	{"ization", "ize"},
+	{"ations", ""},
+	{"ation", ""},
+	{"ments", ""},
+	{"ment", ""},
+	{"ness", ""},
+	{"ings", ""},
+	{"ing", ""},
+	{"ies", "y"},
+	{"ers", ""},
+	{"er", ""},
+	{"ed", ""},
+	{"ly", ""},
+	{"es", ""},
+	{"s", ""},
+}
+
+func stemEnglish(w string) string {
+	if len(w) <= 4 || strings.ContainsAny(w, "+#.") {
+		return w
+	}
+	for _, s := range englishSuffixes {
+		if !strings.HasSuffix(w, s.suffix) {
+			continue
+		}
+		if s.suffix == "s" && (strings.HasSuffix(w, "ss") || strings.HasSuffix(w, "us") || strings.HasSuffix(w, "is")) {
+			return w
+		}
+		base := strings.TrimSuffix(w, s.suffix) + s.repl
+		if len(base) < 3 {
+			return w
+		}
+		return base
+	}
+	return w
+}
+
+// russianEndings отсортированы по убыванию длины.
+var russianEndings = []string{
+	"иями", "ями", "ами", "ого", "его", "ому", "ему", "ыми", "ими", "ией", "ия", "ие",
+	"ых", "их", "ой", "ей", "ий", "ый", "ая", "яя", "ое", "ее", "ов", "ев",
+	"ах", "ях", "ам", "ям", "ом", "ем", "ую", "юю", "ть",
+	"а", "я", "о", "е", "ы", "и", "у", "ю", "ь", "й",
+}
+
+func stemRussian(w string) string {
+	rs := []rune(w)
+	for _, e := range russianEndings {
+		er := []rune(e)
+		if len(rs)-len(er) < 3 {
+			continue
+		}
+		if string(rs[len(rs)-len(er):]) == e {
+			return string(rs[:len(rs)-len(er)])
+		}
+	}
+	return w
+}
+
+// stopWords — служебные слова и типичная лексика вакансий, которые не
+// являются ключевыми словами.
+var stopWords = toSet(`
+a an and or the of to in on for with at by from as is are be been being was were will would can could
+should must may might it its this that these those we our you your they their he she his her them us
+not no yes all any each other such than then so if but about into over under up out more most less very
+also etc e.g i.e per via using use used within across who whom which what when where why how
+work working works job role position team teams company years year experience experienced strong good
+great excellent knowledge understanding ability skills skill requirements required requirement
+responsibilities responsibility nice plus bonus including include includes new like well must
+looking join help build building ensure support develop developing development
+и в во на с со по к ко из за от до для о об при без под над не ни а но или что как это мы вы вас нас
+наш наша наши ваш ваша ваши они он она их его её ее также так же уже будет будете быть есть был
+опыт опыта опытом работа работы работе работать знание знания знаний умение умения навыки навыков
+требования требование обязанности условия компания компании команда команды команде лет года год
+плюсом плюс хорошо хорошее хорошие отличное отличные более менее
+`)
+
+func toSet(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, w := range strings.Fields(s) {
+		set[w] = struct{}{}
+	}
+	return set
+}
+
+func isStopWord(w string) bool {
+	_, ok := stopWords[w]
+	return ok
+}
+
+func isNumber(w string) bool {
+	for _, r := range w {
+		if !unicode.IsDigit(r) && r != '.' && r != '+' {
+			return false
+		}
+	}
+	return true
+}
+
+func isCapitalized(w string) bool {
+	for _, r := range w {
+		return unicode.IsUpper(r)
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+// Package pdfdoc — минимальный генератор PDF без внешних зависимостей:
+// страницы A4, текст шрифтом Helvetica (его знает любой просмотрщик) или
+// встроенным TrueType, ссылки и JPEG-изображения. Координаты — в пунктах
+// от левого нижнего угла страницы, как в самом PDF.
+package pdfdoc
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Размер страницы A4 в пунктах.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document — PDF-документ в памяти. Шрифты и изображения общие для всех
+// страниц.
+type Document struct {
+	title  string
+	author string
+	pages  []*Page
+	fonts  []*Font
+	images []*Image
+}
+
+// New создаёт пустой документ.
+func New() *Document {
+	return &Document{}
+}
+
+// SetInfo задаёт заголовок и автора в свойствах документа.
+func (d *Document) SetInfo(title, author string) {
+	d.title, d.author = title, author
+}
+
+// Page — страница документа; операции дописываются в её поток содержимого.
+type Page struct {
+	content bytes.Buffer
+	links   []link
+	// color — текущий цвет; nil — ещё не задавался (чёрный по умолчанию).
+	color *[3]float64
+}
+
+type link struct {
+	rect [4]float64
+	uri  string
+}
+
+// AddPage добавляет страницу A4.
+func (d *Document) AddPage() *Page {
+	p := &Page{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// SetColor задаёт цвет текста и линий (компоненты RGB от 0 до 1).
+func (p *Page) SetColor(r, g, b float64) {
+	c := [3]float64{r, g, b}
+	if p.color != nil && *p.color == c {
+		return
+	}
+	p.color = &c
+	fmt.Fprintf(&p.content, "%s %s %s rg %[1]s %[2]s %[3]s RG\n", num(r), num(g), num(b))
+}
+
+// Text выводит строку шрифтом f; (x, y) — начало базовой линии.
+func (p *Page) Text(f *Font, size, x, y float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(&p.content, "BT /F%d %s Tf %s %s Td <%x> Tj ET\n", f.id, num(size), num(x), num(y), f.encode(s))
+}
+
+// Line рисует отрезок толщиной width.
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%s w %s %s m %s %s l S\n", num(width), num(x1), num(y1), num(x2), num(y2))
+}
+
+// Image выводит изображение в прямоугольник с левым нижним углом (x, y).
+func (p *Page) Image(img *Image, x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "q %s 0 0 %s %s %s cm /Im%d Do Q\n", num(w), num(h), num(x), num(y), img.id)
+}
+
+// Link делает прямоугольник с левым нижним углом (x, y) ссылкой на uri.
+func (p *Page) Link(x, y, w, h float64, uri string) {
+	p.links = append(p.links, link{rect: [4]float64{x, y, x + w, y + h}, uri: uri})
+}
+
+// writer собирает файл PDF и таблицу смещений объектов.
+type writer struct {
+	buf     bytes.Buffer
+	offsets []int // offsets[n-1] — смещение объекта n
+}
+
+// alloc резервирует номер объекта.
+func (w *writer) alloc() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+func (w *writer) object(n int, body string) {
+	w.offsets[n-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+// stream пишет поток, сжатый FlateDecode; dict — дополнительные ключи
+// словаря потока.
+func (w *writer) stream(n int, dict string, data []byte) error {
+	var z bytes.Buffer
+	zw := zlib.NewWriter(&z)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	w.offsets[n-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< %s /Filter /FlateDecode /Length %d >>\nstream\n", n, dict, z.Len())
+	w.buf.Write(z.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+// Bytes собирает документ. Встроенные шрифты урезаются до символов,
+// которые встретились в тексте, поэтому вызывать его нужно после вывода
+// всех страниц.
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	w := &writer{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	catalog, pages, info := w.alloc(), w.alloc(), w.alloc()
+
+	var res strings.Builder
+	res.WriteString("<< /ProcSet [/PDF /Text /ImageC /ImageB]")
+	if len(d.fonts) > 0 {
+		res.WriteString(" /Font <<")
+		for _, f := range d.fonts {
+			ref, err := f.write(w)
+			if err != nil {
+				return nil, fmt.Errorf("write font %s: %w", f.name, err)
+			}
+			fmt.Fprintf(&res, " /F%d %d 0 R", f.id, ref)
+		}
+		res.WriteString(" >>")
+	}
+	if len(d.images) > 0 {
+		res.WriteString(" /XObject <<")
+		for _, img := range d.images {
+			n := w.alloc()
+			w.offsets[n-1] = w.buf.Len()
+			fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n",
+				n, img.Width, img.Height, img.colorSpace, len(img.data))
+			w.buf.Write(img.data)
+			w.buf.WriteString("\nendstream\nendobj\n")
+			fmt.Fprintf(&res, " /Im%d %d 0 R", img.id, n)
+		}
+		res.WriteString(" >>")
+	}
+	res.WriteString(" >>")
+	resources := w.alloc()
+	w.object(resources, res.String())
+
+	kids := make([]string, len(d.pages))
+	for i, p := range d.pages {
+		content := w.alloc()
+		if err := w.stream(content, "", p.content.Bytes()); err != nil {
+			return nil, fmt.Errorf("write page %d: %w", i+1, err)
+		}
+
+		var annots []string
+		for _, l := range p.links {
+			n := w.alloc()
+			w.object(n, fmt.Sprintf("<< /Type /Annot /Subtype /Link /Rect [%s %s %s %s] /Border [0 0 0] /A << /S /URI /URI %s >> >>",
+				num(l.rect[0]), num(l.rect[1]), num(l.rect[2]), num(l.rect[3]), literal(l.uri)))
+			annots = append(annots, fmt.Sprintf("%d 0 R", n))
+		}
+
+		page := w.alloc()
+		body := fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %d 0 R /Contents %d 0 R",
+			pages, num(PageWidth), num(PageHeight), resources, content)
+		if len(annots) > 0 {
+			body += " /Annots [" + strings.Join(annots, " ") + "]"
+		}
+		w.object(page, body+" >>")
+		kids[i] = fmt.Sprintf("%d 0 R", page)
+	}
+
+	w.object(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+	w.object(info, fmt.Sprintf("<< /Title %s /Author %s /Producer (resume-backend) >>", textString(d.title), textString(d.author)))
+
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, off := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(w.offsets)+1, catalog, info, xref)
+
+	return w.buf.Bytes(), nil
+}
+
+// num форматирует число для PDF: не больше трёх знаков после запятой и
+// без экспоненты.
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 3, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" || s == "" {
+		return "0"
+	}
+	return s
+}
+
+// literal — строка PDF в круглых скобках для ASCII-значений вроде URI.
+func literal(s string) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c > 0x7e:
+			fmt.Fprintf(&b, "\\%03o", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// textString — текстовая строка PDF в UTF-16BE с BOM: так просмотрщики
+// показывают свойства документа на любом языке.
+func textString(s string) string {
+	var b strings.Builder
+	b.WriteString("<FEFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
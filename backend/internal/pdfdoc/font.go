@@ -0,0 +1,172 @@
+package pdfdoc
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Font — шрифт документа: Helvetica из стандартного набора PDF или
+// встроенный TrueType.
+type Font struct {
+	id   int
+	name string
+	// widths — ширины символов WinAnsi в тысячных долях кегля (Helvetica).
+	widths *[256]uint16
+	// tt и used — встроенный шрифт и глифы, которые попали в текст (для
+	// урезания шрифта и ToUnicode).
+	tt   *TrueType
+	used map[uint16]rune
+}
+
+// Helvetica добавляет стандартный шрифт Helvetica или Helvetica-Bold.
+// Он не встраивается и знает только WinAnsi (латиница и западноевропейские
+// символы), поэтому кириллица выводится транслитом, а остальные символы —
+// знаком вопроса. Для текста на других языках нужен TrueTypeFont.
+func (d *Document) Helvetica(bold bool) *Font {
+	f := &Font{id: len(d.fonts) + 1, name: "Helvetica", widths: &helveticaWidths}
+	if bold {
+		f.name, f.widths = "Helvetica-Bold", &helveticaBoldWidths
+	}
+	d.fonts = append(d.fonts, f)
+	return f
+}
+
+// TrueTypeFont добавляет встраиваемый шрифт. В файл попадают только глифы,
+// которые встретились в тексте.
+func (d *Document) TrueTypeFont(tt *TrueType) *Font {
+	f := &Font{id: len(d.fonts) + 1, name: tt.name, tt: tt, used: make(map[uint16]rune)}
+	d.fonts = append(d.fonts, f)
+	return f
+}
+
+// Width возвращает ширину строки в пунктах при кегле size.
+func (f *Font) Width(s string, size float64) float64 {
+	var units float64
+	if f.tt != nil {
+		for _, r := range s {
+			units += float64(f.tt.advance(f.tt.glyph(r))) * 1000 / float64(f.tt.unitsPerEm)
+		}
+	} else {
+		for _, c := range winAnsi(s) {
+			units += float64(f.widths[c])
+		}
+	}
+	return units * size / 1000
+}
+
+// encode переводит строку в коды шрифта: байты WinAnsi для Helvetica,
+// номера глифов (Identity-H) для TrueType.
+func (f *Font) encode(s string) []byte {
+	if f.tt == nil {
+		return winAnsi(s)
+	}
+	out := make([]byte, 0, 2*len(s))
+	for _, r := range s {
+		g := f.tt.glyph(r)
+		if _, ok := f.used[g]; !ok && g != 0 {
+			f.used[g] = r
+		}
+		out = append(out, byte(g>>8), byte(g))
+	}
+	return out
+}
+
+// write пишет объекты шрифта и возвращает номер объекта /Font.
+func (f *Font) write(w *writer) (int, error) {
+	if f.tt != nil {
+		return f.writeTrueType(w)
+	}
+	n := w.alloc()
+	w.object(n, fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.name))
+	return n, nil
+}
+
+// winAnsiExtra — символы WinAnsi за пределами Latin-1 (коды 0x80–0x9F).
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// cyrillic — транслитерация строчных букв кириллицы для Helvetica.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch",
+	'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g", 'ў': "u",
+}
+
+// winAnsi переводит строку в WinAnsi: кириллицу — транслитом, типографские
+// знаки без аналога — близкими символами, остальное — "?".
+func winAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			out = append(out, byte(r))
+		case winAnsiExtra[r] != 0:
+			out = append(out, winAnsiExtra[r])
+		case r == '\t':
+			out = append(out, ' ')
+		case r == '−' || r == '‐' || r == '‑':
+			out = append(out, '-')
+		case r == '№':
+			out = append(out, 'N', 'o')
+		default:
+			t, ok := cyrillic[unicode.ToLower(r)]
+			if !ok {
+				out = append(out, '?')
+				break
+			}
+			if unicode.IsUpper(r) && t != "" {
+				t = strings.ToUpper(t[:1]) + t[1:]
+			}
+			out = append(out, t...)
+		}
+	}
+	return out
+}
+
+// Ширины Helvetica и Helvetica-Bold из метрик AFM стандартных шрифтов PDF.
+var helveticaWidths, helveticaBoldWidths = buildWidths(
+	[]uint16{ // 0x20–0x7E
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	map[byte]uint16{0x85: 1000, 0x89: 1000, 0x91: 222, 0x92: 222, 0x93: 333, 0x94: 333, 0x95: 350, 0x96: 556, 0x97: 1000, 0x99: 1000, 0xa0: 278},
+	556,
+), buildWidths(
+	[]uint16{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+	map[byte]uint16{0x85: 1000, 0x89: 1000, 0x91: 278, 0x92: 278, 0x93: 500, 0x94: 500, 0x95: 350, 0x96: 556, 0x97: 1000, 0x99: 1000, 0xa0: 278},
+	611,
+)
+
+// buildWidths собирает таблицу ширин: ASCII — точно, остальные коды —
+// из extra или средней шириной буквы fallback (для переноса строк этого
+// достаточно).
+func buildWidths(ascii []uint16, extra map[byte]uint16, fallback uint16) [256]uint16 {
+	var t [256]uint16
+	for i := range t {
+		t[i] = fallback
+	}
+	copy(t[0x20:], ascii)
+	for c, w := range extra {
+		t[c] = w
+	}
+	return t
+}
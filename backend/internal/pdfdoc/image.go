@@ -0,0 +1,66 @@
+package pdfdoc
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+)
+
+// Image — изображение документа. JPEG встраивается как есть (DCTDecode),
+// без перекодирования.
+type Image struct {
+	id         int
+	Width      int
+	Height     int
+	colorSpace string
+	data       []byte
+}
+
+// AddJPEG добавляет JPEG. CMYK-файлы перекодируются в RGB: инвертированные
+// каналы Adobe-JPEG просмотрщики показывают по-разному.
+func (d *Document) AddJPEG(data []byte) (*Image, error) {
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode jpeg: %w", err)
+	}
+
+	var cs string
+	switch cfg.ColorModel {
+	case color.GrayModel:
+		cs = "DeviceGray"
+	case color.YCbCrModel:
+		cs = "DeviceRGB"
+	default:
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decode jpeg: %w", err)
+		}
+		return d.AddImage(img)
+	}
+
+	return d.addImage(cfg.Width, cfg.Height, cs, data), nil
+}
+
+// AddImage добавляет произвольное изображение, перекодируя его в JPEG;
+// прозрачные области становятся белыми.
+func (d *Document) AddImage(img image.Image) (*Image, error) {
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, rgba, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, fmt.Errorf("encode jpeg: %w", err)
+	}
+	return d.addImage(b.Dx(), b.Dy(), "DeviceRGB", buf.Bytes()), nil
+}
+
+func (d *Document) addImage(w, h int, cs string, data []byte) *Image {
+	img := &Image{id: len(d.images) + 1, Width: w, Height: h, colorSpace: cs, data: data}
+	d.images = append(d.images, img)
+	return img
+}
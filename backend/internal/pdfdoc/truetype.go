@@ -0,0 +1,482 @@
+package pdfdoc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+// TrueType — разобранный файл шрифта .ttf с контурами glyf. После
+// ParseTrueType не меняется, и один экземпляр можно использовать в
+// нескольких документах параллельно.
+type TrueType struct {
+	name       string
+	tables     map[string][]byte
+	unitsPerEm uint16
+	bbox       [4]int16
+	ascent     int16
+	descent    int16
+	capHeight  int16
+	advances   []uint16
+	cmap       map[rune]uint16
+	loca       []uint32 // loca[g]..loca[g+1] — контур глифа g в glyf
+}
+
+// Таблицы, которые попадают в урезанный шрифт. cmap не нужен: текст
+// ссылается на глифы по номерам (Identity-H).
+var subsetTables = []string{"cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "prep"}
+
+// ParseTrueType разбирает шрифт. Шрифты с контурами CFF (.otf) и
+// коллекции (.ttc) не поддерживаются.
+func ParseTrueType(data []byte) (*TrueType, error) {
+	if len(data) < 12 {
+		return nil, errors.New("font file is too short")
+	}
+	switch string(data[:4]) {
+	case "\x00\x01\x00\x00", "true":
+	case "OTTO":
+		return nil, errors.New("CFF-based OpenType fonts are not supported, use a TrueType (.ttf) font")
+	case "ttcf":
+		return nil, errors.New("font collections are not supported, use a single .ttf font")
+	default:
+		return nil, errors.New("not a TrueType font")
+	}
+
+	t := &TrueType{tables: make(map[string][]byte)}
+	numTables := int(u16(data, 4))
+	if len(data) < 12+16*numTables {
+		return nil, errors.New("truncated table directory")
+	}
+	for i := 0; i < numTables; i++ {
+		rec := data[12+16*i:]
+		tag := string(rec[:4])
+		off, length := u32(rec, 8), u32(rec, 12)
+		if uint64(off)+uint64(length) > uint64(len(data)) {
+			return nil, fmt.Errorf("table %q is out of bounds", tag)
+		}
+		t.tables[tag] = data[off : off+length]
+	}
+
+	for tag, minLen := range map[string]int{"head": 54, "hhea": 36, "maxp": 6, "hmtx": 0, "loca": 0, "glyf": 0, "cmap": 4} {
+		if b, ok := t.tables[tag]; !ok || len(b) < minLen {
+			return nil, fmt.Errorf("missing or truncated %q table", tag)
+		}
+	}
+
+	head, hhea := t.tables["head"], t.tables["hhea"]
+	t.unitsPerEm = u16(head, 18)
+	if t.unitsPerEm == 0 {
+		return nil, errors.New("unitsPerEm is zero")
+	}
+	for i := range t.bbox {
+		t.bbox[i] = int16(u16(head, 36+2*i))
+	}
+	t.ascent, t.descent = int16(u16(hhea, 4)), int16(u16(hhea, 6))
+	t.capHeight = t.ascent
+	if os2 := t.tables["OS/2"]; len(os2) >= 90 && u16(os2, 0) >= 2 {
+		t.capHeight = int16(u16(os2, 88))
+	}
+
+	numGlyphs := int(u16(t.tables["maxp"], 4))
+	if err := t.parseMetrics(int(u16(hhea, 34)), numGlyphs); err != nil {
+		return nil, err
+	}
+	if err := t.parseLoca(int16(u16(head, 50)) == 1, numGlyphs); err != nil {
+		return nil, err
+	}
+	if err := t.parseCmap(numGlyphs); err != nil {
+		return nil, err
+	}
+	t.name = t.postScriptName()
+	return t, nil
+}
+
+func (t *TrueType) parseMetrics(numHMetrics, numGlyphs int) error {
+	hmtx := t.tables["hmtx"]
+	if numHMetrics == 0 || numHMetrics > numGlyphs || len(hmtx) < 4*numHMetrics {
+		return errors.New("invalid hmtx table")
+	}
+	t.advances = make([]uint16, numGlyphs)
+	for g := range t.advances {
+		// у глифов после numHMetrics ширина последней записи
+		t.advances[g] = u16(hmtx, 4*min(g, numHMetrics-1))
+	}
+	return nil
+}
+
+func (t *TrueType) parseLoca(long bool, numGlyphs int) error {
+	loca, glyf := t.tables["loca"], t.tables["glyf"]
+	size := 2
+	if long {
+		size = 4
+	}
+	if len(loca) < size*(numGlyphs+1) {
+		return errors.New("truncated loca table")
+	}
+	t.loca = make([]uint32, numGlyphs+1)
+	for g := range t.loca {
+		if long {
+			t.loca[g] = u32(loca, 4*g)
+		} else {
+			t.loca[g] = uint32(u16(loca, 2*g)) * 2
+		}
+		if t.loca[g] > uint32(len(glyf)) || (g > 0 && t.loca[g] < t.loca[g-1]) {
+			return errors.New("invalid loca table")
+		}
+	}
+	return nil
+}
+
+// parseCmap читает соответствие символов глифам из Unicode-подтаблицы:
+// формата 12 (все плоскости), если она есть, иначе формата 4 (BMP).
+func (t *TrueType) parseCmap(numGlyphs int) error {
+	cmap := t.tables["cmap"]
+	n := int(u16(cmap, 2))
+	if len(cmap) < 4+8*n {
+		return errors.New("truncated cmap table")
+	}
+
+	var best []byte
+	bestFormat := uint16(0)
+	for i := 0; i < n; i++ {
+		platform, encoding, off := u16(cmap, 4+8*i), u16(cmap, 6+8*i), u32(cmap, 8+8*i)
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		if !unicode || int(off)+2 > len(cmap) {
+			continue
+		}
+		sub := cmap[off:]
+		if f := u16(sub, 0); (f == 4 || f == 12) && f > bestFormat {
+			best, bestFormat = sub, f
+		}
+	}
+
+	t.cmap = make(map[rune]uint16)
+	add := func(r rune, g uint32) {
+		if g != 0 && g < uint32(numGlyphs) {
+			t.cmap[r] = uint16(g)
+		}
+	}
+
+	switch bestFormat {
+	case 4:
+		if len(best) < 14 {
+			return errors.New("truncated cmap format 4")
+		}
+		segX2 := int(u16(best, 6))
+		if len(best) < 16+4*segX2 {
+			return errors.New("truncated cmap format 4")
+		}
+		for i := 0; i < segX2/2; i++ {
+			end, start := u16(best, 14+2*i), u16(best, 16+segX2+2*i)
+			delta := u16(best, 16+2*segX2+2*i)
+			roPos := 16 + 3*segX2 + 2*i
+			ro := int(u16(best, roPos))
+			for c := uint32(start); c <= uint32(end) && c != 0xffff; c++ {
+				if ro == 0 {
+					add(rune(c), uint32(uint16(c)+delta))
+					continue
+				}
+				addr := roPos + ro + 2*int(c-uint32(start))
+				if addr+2 > len(best) {
+					break
+				}
+				if g := u16(best, addr); g != 0 {
+					add(rune(c), uint32(g+delta))
+				}
+			}
+		}
+	case 12:
+		if len(best) < 16 {
+			return errors.New("truncated cmap format 12")
+		}
+		groups := int(u32(best, 12))
+		if groups > (len(best)-16)/12 {
+			return errors.New("truncated cmap format 12")
+		}
+		for i := 0; i < groups; i++ {
+			start, end, glyph := u32(best, 16+12*i), u32(best, 20+12*i), u32(best, 24+12*i)
+			if end > 0x10ffff || end < start {
+				continue
+			}
+			for c := start; c <= end; c++ {
+				add(rune(c), glyph+(c-start))
+			}
+		}
+	default:
+		return errors.New("font has no Unicode cmap")
+	}
+	return nil
+}
+
+// postScriptName возвращает имя шрифта из таблицы name (nameID 6), оставив
+// только символы, допустимые в имени PDF.
+func (t *TrueType) postScriptName() string {
+	name := t.tables["name"]
+	clean := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r < '!' || r > '~' || strings.ContainsRune("[](){}<>/%#", r) {
+				return -1
+			}
+			return r
+		}, s)
+	}
+
+	if len(name) >= 6 {
+		count, strOff := int(u16(name, 2)), int(u16(name, 4))
+		for i := 0; i < count && 6+12*i+12 <= len(name); i++ {
+			rec := name[6+12*i:]
+			platform, id := u16(rec, 0), u16(rec, 6)
+			length, off := int(u16(rec, 8)), strOff+int(u16(rec, 10))
+			if id != 6 || off+length > len(name) {
+				continue
+			}
+			raw := name[off : off+length]
+			var s string
+			switch platform {
+			case 1:
+				s = string(raw)
+			case 0, 3:
+				u := make([]uint16, len(raw)/2)
+				for j := range u {
+					u[j] = u16(raw, 2*j)
+				}
+				s = string(utf16.Decode(u))
+			}
+			if s = clean(s); s != "" {
+				return s
+			}
+		}
+	}
+	return "EmbeddedFont"
+}
+
+// glyph возвращает глиф символа; 0 (.notdef) — символа в шрифте нет.
+func (t *TrueType) glyph(r rune) uint16 {
+	return t.cmap[r]
+}
+
+func (t *TrueType) advance(g uint16) uint16 {
+	if int(g) >= len(t.advances) {
+		return 0
+	}
+	return t.advances[g]
+}
+
+func (t *TrueType) glyphData(g uint16) []byte {
+	return t.tables["glyf"][t.loca[g]:t.loca[g+1]]
+}
+
+// subset собирает файл шрифта, в котором остались только контуры glyphs и
+// глифов, из которых они составлены. Номера глифов не меняются, поэтому
+// hmtx и остальные таблицы копируются как есть.
+func (t *TrueType) subset(glyphs []uint16) []byte {
+	keep := map[uint16]bool{0: true}
+	queue := append([]uint16(nil), glyphs...)
+	for len(queue) > 0 {
+		g := queue[0]
+		queue = queue[1:]
+		if keep[g] || int(g) >= len(t.loca)-1 {
+			continue
+		}
+		keep[g] = true
+		queue = append(queue, components(t.glyphData(g))...)
+	}
+
+	var glyf []byte
+	loca := make([]byte, 4*len(t.loca))
+	for g := 0; g < len(t.loca)-1; g++ {
+		binary.BigEndian.PutUint32(loca[4*g:], uint32(len(glyf)))
+		if keep[uint16(g)] {
+			glyf = append(glyf, t.glyphData(uint16(g))...)
+			for len(glyf)%4 != 0 {
+				glyf = append(glyf, 0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*(len(t.loca)-1):], uint32(len(glyf)))
+
+	head := append([]byte(nil), t.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0)  // checkSumAdjustment считается ниже
+	binary.BigEndian.PutUint16(head[50:], 1) // loca в длинном формате
+
+	tables := map[string][]byte{"glyf": glyf, "loca": loca, "head": head}
+	for _, tag := range subsetTables {
+		if _, ok := tables[tag]; !ok && t.tables[tag] != nil {
+			tables[tag] = t.tables[tag]
+		}
+	}
+	return buildSFNT(tables)
+}
+
+// components возвращает глифы, из которых составлен составной глиф.
+func components(data []byte) []uint16 {
+	if len(data) < 10 || int16(u16(data, 0)) >= 0 {
+		return nil
+	}
+	var out []uint16
+	for off := 10; off+4 <= len(data); {
+		flags := u16(data, off)
+		out = append(out, u16(data, off+2))
+		off += 4
+		if flags&0x0001 != 0 { // ARG_1_AND_2_ARE_WORDS
+			off += 4
+		} else {
+			off += 2
+		}
+		switch {
+		case flags&0x0008 != 0: // WE_HAVE_A_SCALE
+			off += 2
+		case flags&0x0040 != 0: // WE_HAVE_AN_X_AND_Y_SCALE
+			off += 4
+		case flags&0x0080 != 0: // WE_HAVE_A_TWO_BY_TWO
+			off += 8
+		}
+		if flags&0x0020 == 0 { // MORE_COMPONENTS
+			break
+		}
+	}
+	return out
+}
+
+// buildSFNT собирает файл шрифта из таблиц с заголовком и контрольными
+// суммами.
+func buildSFNT(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	n := len(tags)
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= n {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+
+	out := make([]byte, 12+16*n)
+	binary.BigEndian.PutUint32(out[0:], 0x00010000)
+	binary.BigEndian.PutUint16(out[4:], uint16(n))
+	binary.BigEndian.PutUint16(out[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(out[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(out[10:], uint16(16*n-searchRange))
+
+	headOff := 0
+	for i, tag := range tags {
+		data := tables[tag]
+		rec := out[12+16*i:]
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[4:], checksum(data))
+		binary.BigEndian.PutUint32(rec[8:], uint32(len(out)))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(data)))
+		if tag == "head" {
+			headOff = len(out)
+		}
+		out = append(out, data...)
+		for len(out)%4 != 0 {
+			out = append(out, 0)
+		}
+	}
+	binary.BigEndian.PutUint32(out[headOff+8:], 0xb1b0afba-checksum(out))
+	return out
+}
+
+func checksum(b []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(b); i += 4 {
+		var w [4]byte
+		copy(w[:], b[i:])
+		sum += binary.BigEndian.Uint32(w[:])
+	}
+	return sum
+}
+
+// writeTrueType пишет шрифт как Type0 с CIDFontType2 и кодировкой
+// Identity-H: коды в тексте — номера глифов, а ToUnicode возвращает
+// исходные символы, чтобы текст копировался и находился поиском.
+func (f *Font) writeTrueType(w *writer) (int, error) {
+	t := f.tt
+	glyphs := make([]uint16, 0, len(f.used))
+	for g := range f.used {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	// тег подмножества — шесть заглавных букв, зависящих от набора глифов
+	h := fnv.New32a()
+	for _, g := range glyphs {
+		_ = binary.Write(h, binary.BigEndian, g)
+	}
+	tag := make([]byte, 6)
+	for i, s := 0, h.Sum32(); i < len(tag); i, s = i+1, s/26 {
+		tag[i] = 'A' + byte(s%26)
+	}
+	name := string(tag) + "+" + t.name
+
+	scale := func(v int) int { return v * 1000 / int(t.unitsPerEm) }
+
+	file := w.alloc()
+	fontFile := t.subset(glyphs)
+	if err := w.stream(file, fmt.Sprintf("/Length1 %d", len(fontFile)), fontFile); err != nil {
+		return 0, err
+	}
+
+	desc := w.alloc()
+	w.object(desc, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		name, scale(int(t.bbox[0])), scale(int(t.bbox[1])), scale(int(t.bbox[2])), scale(int(t.bbox[3])),
+		scale(int(t.ascent)), scale(int(t.descent)), scale(int(t.capHeight)), file))
+
+	var widths strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", g, scale(int(t.advance(g))))
+	}
+	cid := w.alloc()
+	w.object(cid, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /DW %d /W [%s] /CIDToGIDMap /Identity >>",
+		name, desc, scale(int(t.advance(0))), strings.TrimSpace(widths.String())))
+
+	toUnicode := w.alloc()
+	if err := w.stream(toUnicode, "", f.toUnicode(glyphs)); err != nil {
+		return 0, err
+	}
+
+	font := w.alloc()
+	w.object(font, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, cid, toUnicode))
+	return font, nil
+}
+
+// toUnicode строит CMap «глиф → символ».
+func (f *Font) toUnicode(glyphs []uint16) []byte {
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// в одном блоке bfchar допускается не больше 100 записей
+	for i := 0; i < len(glyphs); i += 100 {
+		chunk := glyphs[i:min(i+100, len(glyphs))]
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(chunk))
+		for _, g := range chunk {
+			fmt.Fprintf(&b, "<%04X> <", g)
+			for _, u := range utf16.Encode([]rune{f.used[g]}) {
+				fmt.Fprintf(&b, "%04X", u)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return []byte(b.String())
+}
+
+func u16(b []byte, off int) uint16 {
+	return binary.BigEndian.Uint16(b[off:])
+}
+
+func u32(b []byte, off int) uint32 {
+	return binary.BigEndian.Uint32(b[off:])
+}
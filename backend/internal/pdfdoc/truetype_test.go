@@ -0,0 +1,241 @@
+package pdfdoc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// Глифы тестового шрифта: 0 — .notdef, 1 — «A», 2 — «B», составной из
+// глифа 1, 3 — «C» и U+1F600 (в cmap формата 12).
+var (
+	simpleGlyph    = []byte{0, 1, 0, 0, 0, 0, 0, 10, 0, 10, 0xaa, 0xbb}
+	compositeGlyph = []byte{0xff, 0xff, 0, 0, 0, 0, 0, 10, 0, 10, 0, 0, 0, 1, 0, 0}
+	otherGlyph     = []byte{0, 1, 0, 0, 0, 0, 0, 10, 0, 10, 0xcc, 0xdd}
+)
+
+type fontSpec struct {
+	cmapFormat int
+	unitsPerEm uint16
+	name       string
+	drop       string // таблица, которой нет в файле
+}
+
+// buildFont собирает минимальный TrueType-шрифт из четырёх глифов.
+func buildFont(spec fontSpec) []byte {
+	be := binary.BigEndian
+
+	head := make([]byte, 54)
+	be.PutUint16(head[18:], spec.unitsPerEm)
+	be.PutUint16(head[40:], 1000) // xMax
+	be.PutUint16(head[42:], 800)  // yMax
+
+	hhea := make([]byte, 36)
+	be.PutUint16(hhea[4:], 800)
+	be.PutUint16(hhea[6:], uint16(0xffff-199)) // -200
+	be.PutUint16(hhea[34:], 2)                 // у глифов 2 и 3 ширина глифа 1
+
+	maxp := make([]byte, 6)
+	be.PutUint32(maxp[0:], 0x00005000)
+	be.PutUint16(maxp[4:], 4)
+
+	hmtx := make([]byte, 8)
+	be.PutUint16(hmtx[0:], 500)
+	be.PutUint16(hmtx[4:], 600)
+
+	glyf := append(append(append([]byte(nil), simpleGlyph...), compositeGlyph...), otherGlyph...)
+	loca := make([]byte, 10)
+	for g, off := range []int{0, 0, len(simpleGlyph), len(simpleGlyph) + len(compositeGlyph), len(glyf)} {
+		be.PutUint16(loca[2*g:], uint16(off/2))
+	}
+
+	var sub []byte
+	put16 := func(v uint16) { sub = be.AppendUint16(sub, v) }
+	put32 := func(v uint32) { sub = be.AppendUint32(sub, v) }
+	switch spec.cmapFormat {
+	case 4:
+		// сегменты 'A'..'C' -> 1..3 и завершающий 0xFFFF
+		put16(4)
+		put16(32)
+		put16(0)
+		put16(4) // segCountX2
+		put16(4)
+		put16(1)
+		put16(0)
+		put16('C')
+		put16(0xffff)
+		put16(0)
+		put16('A')
+		put16(0xffff)
+		put16(0xffc0) // 'A' + delta = 1
+		put16(1)
+		put16(0)
+		put16(0)
+	case 12:
+		put16(12)
+		put16(0)
+		put32(40)
+		put32(0)
+		put32(2)
+		put32('A')
+		put32('C')
+		put32(1)
+		put32(0x1f600)
+		put32(0x1f600)
+		put32(3)
+	}
+	cmap := []byte{0, 0, 0, 1, 0, 3, 0, 1, 0, 0, 0, 12}
+	if spec.cmapFormat == 12 {
+		cmap[7] = 10
+	}
+	cmap = append(cmap, sub...)
+
+	tables := map[string][]byte{"head": head, "hhea": hhea, "maxp": maxp, "hmtx": hmtx, "loca": loca, "glyf": glyf, "cmap": cmap}
+	if spec.name != "" {
+		var str []byte
+		for _, u := range utf16.Encode([]rune(spec.name)) {
+			str = be.AppendUint16(str, u)
+		}
+		name := []byte{0, 0, 0, 1, 0, 18, 0, 3, 0, 1, 4, 9, 0, 6}
+		name = be.AppendUint16(name, uint16(len(str)))
+		name = be.AppendUint16(name, 0)
+		tables["name"] = append(name, str...)
+	}
+	delete(tables, spec.drop)
+	return buildSFNT(tables)
+}
+
+func TestParseTrueType(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		runes   map[rune]uint16
+		psName  string
+		wantErr string
+	}{
+		{
+			name:   "cmap format 4",
+			data:   buildFont(fontSpec{cmapFormat: 4, unitsPerEm: 1000, name: "Test Sans/Bold"}),
+			runes:  map[rune]uint16{'A': 1, 'B': 2, 'C': 3, 'D': 0},
+			psName: "TestSansBold",
+		},
+		{
+			name:   "cmap format 12",
+			data:   buildFont(fontSpec{cmapFormat: 12, unitsPerEm: 1000}),
+			runes:  map[rune]uint16{'A': 1, 'C': 3, 0x1f600: 3, 0x1f601: 0},
+			psName: "EmbeddedFont",
+		},
+		{name: "too short", data: []byte("true"), wantErr: "too short"},
+		{name: "CFF", data: append([]byte("OTTO"), make([]byte, 12)...), wantErr: "CFF-based"},
+		{name: "collection", data: append([]byte("ttcf"), make([]byte, 12)...), wantErr: "collections"},
+		{name: "not a font", data: make([]byte, 16), wantErr: "not a TrueType font"},
+		{name: "no glyf", data: buildFont(fontSpec{cmapFormat: 4, unitsPerEm: 1000, drop: "glyf"}), wantErr: `"glyf" table`},
+		{name: "zero unitsPerEm", data: buildFont(fontSpec{cmapFormat: 4}), wantErr: "unitsPerEm"},
+		{name: "no Unicode cmap", data: buildFont(fontSpec{unitsPerEm: 1000}), wantErr: "no Unicode cmap"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			font, err := ParseTrueType(tt.data)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for r, want := range tt.runes {
+				if g := font.glyph(r); g != want {
+					t.Errorf("glyph(%U) = %d, want %d", r, g, want)
+				}
+			}
+			if font.name != tt.psName {
+				t.Errorf("name = %q, want %q", font.name, tt.psName)
+			}
+			if font.advance(1) != 600 || font.advance(3) != 600 || font.advance(0) != 500 {
+				t.Errorf("advances = %v, want [500 600 600 600]", font.advances)
+			}
+		})
+	}
+}
+
+func TestSubset(t *testing.T) {
+	font, err := ParseTrueType(buildFont(fontSpec{cmapFormat: 4, unitsPerEm: 1000}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// составной глиф тянет за собой глиф 1, глиф 3 не нужен
+	out := font.subset([]uint16{2})
+	if sum := checksum(out); sum != 0xb1b0afba {
+		t.Errorf("file checksum = %#x, want 0xb1b0afba", sum)
+	}
+
+	tables := sfntTables(t, out)
+	if _, ok := tables["cmap"]; ok {
+		t.Error("subset keeps cmap")
+	}
+	loca, glyf := tables["loca"], tables["glyf"]
+	if len(loca) != 4*5 {
+		t.Fatalf("loca is %d bytes, want long offsets for 4 glyphs", len(loca))
+	}
+	glyph := func(g int) []byte { return glyf[u32(loca, 4*g):u32(loca, 4*g+4)] }
+	if !bytes.Equal(glyph(1), simpleGlyph) || !bytes.Equal(glyph(2), compositeGlyph) {
+		t.Errorf("glyphs 1 and 2 were not copied: %x, %x", glyph(1), glyph(2))
+	}
+	if len(glyph(3)) != 0 {
+		t.Errorf("unused glyph 3 kept: %x", glyph(3))
+	}
+	if int16(u16(tables["head"], 50)) != 1 {
+		t.Error("head.indexToLocFormat is not long")
+	}
+}
+
+func TestComponents(t *testing.T) {
+	twoParts := []byte{0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0,
+		0x00, 0x21, 0, 5, 0, 0, 0, 0, // слова, MORE_COMPONENTS
+		0x00, 0x08, 0, 7, 0, 0, 0, 0, // масштаб
+	}
+	tests := []struct {
+		name string
+		data []byte
+		want []uint16
+	}{
+		{"simple", simpleGlyph, nil},
+		{"one component", compositeGlyph, []uint16{1}},
+		{"two components", twoParts, []uint16{5, 7}},
+		{"truncated", compositeGlyph[:10], nil},
+		{"empty", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := components(tt.data)
+			if len(got) != len(tt.want) {
+				t.Fatalf("components = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("components = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// sfntTables разбирает каталог таблиц файла шрифта.
+func sfntTables(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	tables := make(map[string][]byte)
+	for i := 0; i < int(u16(data, 4)); i++ {
+		rec := data[12+16*i:]
+		off, length := u32(rec, 8), u32(rec, 12)
+		if checksum(data[off:off+length]) != u32(rec, 4) && string(rec[:4]) != "head" {
+			t.Errorf("table %q: wrong checksum", rec[:4])
+		}
+		tables[string(rec[:4])] = data[off : off+length]
+	}
+	return tables
+}
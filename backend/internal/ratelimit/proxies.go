@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Proxies — доверенные прокси (nginx-gateway): только их X-Forwarded-For
+// принимается во внимание при определении адреса клиента.
+type Proxies []netip.Prefix
+
+// ParseProxies разбирает список адресов и подсетей через запятую
+// ("10.0.0.0/8, 172.18.0.5"); пустая строка — доверенных прокси нет.
+func ParseProxies(s string) (Proxies, error) {
+	var p Proxies
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.Contains(part, "/") {
+			prefix, err := netip.ParsePrefix(part)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q: %w", part, err)
+			}
+			p = append(p, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(part)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", part, err)
+		}
+		p = append(p, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return p, nil
+}
+
+func (p Proxies) trusted(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range p {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP возвращает адрес клиента. Если запрос пришёл от доверенного
+// прокси, X-Forwarded-For просматривается справа налево: первый адрес не
+// из списка доверенных и есть клиент. Левее него значения мог подставить
+// сам клиент, поэтому они не читаются.
+func (p Proxies) ClientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, err := netip.ParseAddr(host)
+	if err != nil || !p.trusted(remote) {
+		return host
+	}
+
+	client := remote
+	hops := r.Header.Values("X-Forwarded-For")
+	for i := len(hops) - 1; i >= 0; i-- {
+		parts := strings.Split(hops[i], ",")
+		for j := len(parts) - 1; j >= 0; j-- {
+			addr, err := netip.ParseAddr(strings.TrimSpace(parts[j]))
+			if err != nil {
+				return client.Unmap().String()
+			}
+			client = addr
+			if !p.trusted(addr) {
+				return addr.Unmap().String()
+			}
+		}
+	}
+	return client.Unmap().String()
+}
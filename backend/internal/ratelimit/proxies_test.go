@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseProxies(t *testing.T) {
+	p, err := ParseProxies(" 10.0.0.0/8, 172.18.0.5 ,,fd00::/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.0/8", "172.18.0.5/32", "fd00::/8"}
+	if len(p) != len(want) {
+		t.Fatalf("proxies = %v, want %v", p, want)
+	}
+	for i := range want {
+		if p[i].String() != want[i] {
+			t.Errorf("proxies[%d] = %s, want %s", i, p[i], want[i])
+		}
+	}
+
+	for _, bad := range []string{"10.0.0.0/33", "gateway", "10.0.0.1/8/1"} {
+		if _, err := ParseProxies(bad); err == nil {
+			t.Errorf("ParseProxies(%q): no error", bad)
+		}
+	}
+	if p, err := ParseProxies(""); err != nil || len(p) != 0 {
+		t.Errorf("ParseProxies(\"\") = %v, %v; want empty", p, err)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseProxies("10.0.0.0/8, 192.168.1.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"no proxy", "203.0.113.7:5000", nil, "203.0.113.7"},
+		{"untrusted peer is not believed", "203.0.113.7:5000", []string{"198.51.100.1"}, "203.0.113.7"},
+		{"gateway", "10.0.0.2:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"chain of proxies", "10.0.0.2:5000", []string{"198.51.100.1, 192.168.1.1, 10.1.1.1"}, "198.51.100.1"},
+		{"spoofed entry on the left", "10.0.0.2:5000", []string{"1.2.3.4, 198.51.100.1"}, "198.51.100.1"},
+		{"several headers", "10.0.0.2:5000", []string{"1.2.3.4", "198.51.100.1, 10.1.1.1"}, "198.51.100.1"},
+		{"garbage stops the walk", "10.0.0.2:5000", []string{"198.51.100.1, unknown, 10.1.1.1"}, "10.1.1.1"},
+		{"only trusted hops", "10.0.0.2:5000", []string{"10.1.1.1, 192.168.1.1"}, "10.1.1.1"},
+		{"gateway without header", "10.0.0.2:5000", nil, "10.0.0.2"},
+		{"IPv4-mapped gateway", "[::ffff:10.0.0.2]:5000", []string{"198.51.100.1"}, "198.51.100.1"},
+		{"IPv6 client", "10.0.0.2:5000", []string{"2001:db8::1"}, "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+			if got := proxies.ClientIP(r); got != tt.want {
+				t.Errorf("ClientIP = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
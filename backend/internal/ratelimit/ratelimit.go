@@ -0,0 +1,162 @@
+// Package ratelimit — ограничение частоты запросов одного клиента
+// алгоритмом token bucket. У каждого ключа клиента своя корзина: токены
+// пополняются с постоянной скоростью до ёмкости Burst, запрос забирает
+// один токен. Состояние хранится в памяти процесса; корзины, которые
+// давно полны и не используются, периодически удаляются.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit — лимит одного клиента.
+type Limit struct {
+	// Rate — сколько запросов в секунду восполняется; 0 — без ограничения.
+	Rate float64
+	// Burst — ёмкость корзины: сколько запросов можно сделать подряд.
+	Burst int
+	// MaxInFlight — сколько запросов клиента может выполняться
+	// одновременно; 0 — без ограничения.
+	MaxInFlight int
+}
+
+// Enabled сообщает, ограничивает ли лимит хоть что-то.
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Decision — результат проверки лимита.
+type Decision struct {
+	Allowed bool
+	// Limit — ёмкость корзины, Remaining — сколько токенов осталось.
+	Limit     int
+	Remaining int
+	// Reset — через сколько корзина снова будет полной.
+	Reset time.Duration
+	// RetryAfter — через сколько имеет смысл повторить отклонённый запрос.
+	RetryAfter time.Duration
+	// Concurrency — запрос отклонён из-за MaxInFlight, а не частоты.
+	Concurrency bool
+}
+
+type bucket struct {
+	limit    Limit
+	tokens   float64
+	last     time.Time
+	inFlight int
+}
+
+// Limiter хранит корзины клиентов.
+type Limiter struct {
+	// limit — лимит клиентов, для которых Acquire не получил своего.
+	limit Limit
+	// sweepEvery — как часто удаляются неиспользуемые корзины.
+	sweepEvery time.Duration
+	now        func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+const defaultSweepInterval = time.Minute
+
+// New создаёт Limiter с лимитом limit на каждого клиента; нулевой лимит
+// пропускает всех, кроме клиентов со своим лимитом.
+func New(limit Limit) *Limiter {
+	return &Limiter{
+		limit:      limit,
+		sweepEvery: defaultSweepInterval,
+		now:        time.Now,
+		buckets:    make(map[string]*bucket),
+	}
+}
+
+// Acquire забирает токен из корзины клиента key. limit — собственный
+// лимит клиента (например, API-ключа); если он не задан, действует общий.
+// Без лимита запрос пропускается с нулевым Decision.Limit. Если запрос
+// пропущен, после его обработки нужно вызвать Release с тем же ключом.
+func (l *Limiter) Acquire(key string, limit Limit) Decision {
+	if !limit.Enabled() {
+		limit = l.limit
+	}
+	if !limit.Enabled() {
+		return Decision{Allowed: true}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.refill(now)
+	if b.limit != limit {
+		b.limit, b.tokens = limit, math.Min(b.tokens, float64(limit.Burst))
+	}
+
+	d := Decision{Limit: limit.Burst}
+	switch {
+	case b.tokens < 1:
+		d.RetryAfter = b.duration(1 - b.tokens)
+	case limit.MaxInFlight > 0 && b.inFlight >= limit.MaxInFlight:
+		// когда освободится слот, неизвестно — повторить стоит скоро
+		d.RetryAfter, d.Concurrency = time.Second, true
+	default:
+		b.tokens--
+		b.inFlight++
+		d.Allowed = true
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = b.duration(float64(limit.Burst) - b.tokens)
+	return d
+}
+
+// Release отмечает, что пропущенный запрос клиента key завершился.
+func (l *Limiter) Release(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if b, ok := l.buckets[key]; ok && b.inFlight > 0 {
+		b.inFlight--
+	}
+}
+
+// refill пополняет корзину за время, прошедшее с прошлого обращения.
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.last = now
+	}
+}
+
+// duration — за сколько восполняется tokens токенов.
+func (b *bucket) duration(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / b.limit.Rate * float64(time.Second))
+}
+
+// sweep удаляет корзины без выполняющихся запросов, которые уже
+// восполнились: новая корзина для того же клиента будет такой же.
+// Вызывается под l.mu не чаще раза в sweepEvery.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.sweepEvery {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		b.refill(now)
+		if b.inFlight == 0 && b.tokens >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestLimiter(limit Limit) (*Limiter, *time.Time) {
+	now := time.Unix(1000, 0)
+	l := New(limit)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestAcquireRate(t *testing.T) {
+	l, now := newTestLimiter(Limit{Rate: 1, Burst: 2})
+
+	steps := []struct {
+		name       string
+		advance    time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+	}{
+		{"first", 0, true, 1, 0},
+		{"second", 0, true, 0, 0},
+		{"bucket is empty", 0, false, 0, time.Second},
+		{"half a token", 500 * time.Millisecond, false, 0, 500 * time.Millisecond},
+		{"token refilled", 500 * time.Millisecond, true, 0, 0},
+		{"refill is capped by burst", time.Hour, true, 1, 0},
+	}
+	for _, s := range steps {
+		*now = now.Add(s.advance)
+		d := l.Acquire("ip:1", Limit{})
+		if d.Allowed {
+			l.Release("ip:1")
+		}
+		if d.Allowed != s.allowed || d.Remaining != s.remaining || d.RetryAfter != s.retryAfter || d.Limit != 2 {
+			t.Fatalf("%s: decision = %+v, want allowed %v, remaining %d, retry after %v", s.name, d, s.allowed, s.remaining, s.retryAfter)
+		}
+	}
+
+	// корзины клиентов независимы
+	if d := l.Acquire("ip:2", Limit{}); !d.Allowed {
+		t.Errorf("another client: decision = %+v, want allowed", d)
+	}
+}
+
+func TestAcquireConcurrency(t *testing.T) {
+	l, _ := newTestLimiter(Limit{Rate: 10, Burst: 10, MaxInFlight: 1})
+
+	if d := l.Acquire("k", Limit{}); !d.Allowed {
+		t.Fatalf("first: decision = %+v", d)
+	}
+	d := l.Acquire("k", Limit{})
+	if d.Allowed || !d.Concurrency || d.RetryAfter != time.Second {
+		t.Fatalf("second in flight: decision = %+v, want rejected by concurrency", d)
+	}
+	if d.Remaining != 9 {
+		t.Errorf("rejected request took a token: remaining = %d, want 9", d.Remaining)
+	}
+
+	l.Release("k")
+	if d := l.Acquire("k", Limit{}); !d.Allowed {
+		t.Errorf("after release: decision = %+v, want allowed", d)
+	}
+}
+
+func TestAcquireOwnLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		def       Limit
+		own       Limit
+		wantLimit int
+	}{
+		{"default limit", Limit{Rate: 1, Burst: 5}, Limit{}, 5},
+		{"own limit replaces default", Limit{Rate: 1, Burst: 5}, Limit{Rate: 1, Burst: 50}, 50},
+		{"own limit without default", Limit{}, Limit{Rate: 1, Burst: 3}, 3},
+		{"no limit", Limit{}, Limit{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, _ := newTestLimiter(tt.def)
+			d := l.Acquire("k", tt.own)
+			if !d.Allowed || d.Limit != tt.wantLimit {
+				t.Errorf("decision = %+v, want allowed with limit %d", d, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestAcquireLimitChange(t *testing.T) {
+	l, _ := newTestLimiter(Limit{})
+	for i := 0; i < 2; i++ {
+		l.Acquire("k", Limit{Rate: 1, Burst: 10})
+		l.Release("k")
+	}
+
+	// лимит ключа уменьшили: накопленные токены урезаются до нового Burst
+	d := l.Acquire("k", Limit{Rate: 1, Burst: 3})
+	if !d.Allowed || d.Limit != 3 || d.Remaining != 2 {
+		t.Errorf("decision = %+v, want allowed with limit 3 and 2 remaining", d)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	l, now := newTestLimiter(Limit{Rate: 1, Burst: 1})
+	l.Acquire("idle", Limit{})
+	l.Release("idle")
+	l.Acquire("busy", Limit{})
+
+	*now = now.Add(2 * defaultSweepInterval)
+	l.Acquire("other", Limit{})
+
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("refilled idle bucket was not removed")
+	}
+	if _, ok := l.buckets["busy"]; !ok {
+		t.Error("bucket with a request in flight was removed")
+	}
+}
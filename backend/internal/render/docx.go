@@ -0,0 +1,275 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+// Геометрия страницы A4 с полями 1.5 см, как в LaTeX-шаблоне (в twips).
+const (
+	docxPageWidth  = 11906
+	docxPageHeight = 16838
+	docxMargin     = 850
+	docxTextWidth  = docxPageWidth - 2*docxMargin
+)
+
+// DOCXRenderer рендерит резюме в DOCX (Office Open XML), собирая пакет
+// вручную через archive/zip. Фото в DOCX не встраивается: документ
+// предназначен в первую очередь для загрузки в ATS.
+type DOCXRenderer struct {
+	now func() time.Time
+}
+
+// NewDOCXRenderer создаёт рендерер DOCX.
+func NewDOCXRenderer() *DOCXRenderer {
+	return &DOCXRenderer{now: time.Now}
+}
+
+// Format реализует resume.Renderer.
+func (*DOCXRenderer) Format() resume.Format {
+	return resume.FormatDOCX
+}
+
+// docxBody накапливает параграфы word/document.xml и связи для гиперссылок.
+type docxBody struct {
+	b     strings.Builder
+	links []string // URL гиперссылок, rId = "rIdLink<N>"
+}
+
+// Render реализует resume.Renderer.
+func (d *DOCXRenderer) Render(_ context.Context, r resume.Resume) ([]byte, error) {
+	var body docxBody
+
+	body.paragraph("Title", body.run(strings.TrimSpace(r.FullName), ""))
+	if p := strings.TrimSpace(r.Position); p != "" {
+		body.paragraph("Subtitle", body.run(p, ""))
+	}
+
+	var contacts []string
+	if email := strings.TrimSpace(r.Contacts.Email); email != "" {
+		contacts = append(contacts, body.hyperlink("mailto:"+email, email))
+	}
+	for _, c := range nonEmpty([]string{r.Contacts.Phone, r.Contacts.Location}) {
+		contacts = append(contacts, body.run(c, ""))
+	}
+	for _, l := range r.Contacts.Links {
+		if strings.TrimSpace(l.URL) == "" {
+			continue
+		}
+		contacts = append(contacts, body.hyperlink(strings.TrimSpace(l.URL), linkLabel(l)))
+	}
+	if len(contacts) > 0 {
+		body.paragraph("Contacts", strings.Join(contacts, body.run(" • ", "")))
+	}
+
+	if s := strings.TrimSpace(r.Summary); s != "" {
+		body.paragraph("Heading1", body.run("Summary", ""))
+		body.paragraph("", body.run(s, ""))
+	}
+
+	if skills := resume.SkillLines(r.Skills); len(skills) > 0 {
+		body.paragraph("Heading1", body.run("Skills", ""))
+		body.paragraph("", body.run(strings.Join(skills, ", "), ""))
+	}
+
+	if len(r.Experience) > 0 {
+		body.paragraph("Heading1", body.run("Experience", ""))
+		for _, e := range r.Experience {
+			if !hasExperience(e) {
+				continue
+			}
+			body.entryHeading(experienceTitle(e), dateRange(e.StartDate, e.EndDate))
+			if loc := strings.TrimSpace(e.Location); loc != "" {
+				body.paragraph("Meta", body.run(loc, ""))
+			}
+			if desc := strings.TrimSpace(e.Description); desc != "" {
+				body.paragraph("", body.run(desc, ""))
+			}
+			for _, item := range nonEmptyTexts(e.Bullets) {
+				body.paragraph("ListBullet", body.run(item, ""))
+			}
+		}
+	}
+
+	if len(r.Education) > 0 {
+		body.paragraph("Heading1", body.run("Education", ""))
+		for _, e := range r.Education {
+			if !hasEducation(e) {
+				continue
+			}
+			body.entryHeading(educationTitle(e), dateRange(e.StartDate, e.EndDate))
+			if loc := strings.TrimSpace(e.Location); loc != "" {
+				body.paragraph("Meta", body.run(loc, ""))
+			}
+			if details := strings.TrimSpace(e.Details); details != "" {
+				body.paragraph("", body.run(details, ""))
+			}
+		}
+	}
+
+	for _, cs := range r.CustomSections {
+		if strings.TrimSpace(cs.Title) == "" {
+			continue
+		}
+		body.paragraph("Heading1", body.run(strings.TrimSpace(cs.Title), ""))
+		for _, item := range nonEmptyTexts(cs.Items) {
+			body.paragraph("ListBullet", body.run(item, ""))
+		}
+	}
+
+	return d.pack(r, &body)
+}
+
+// paragraph добавляет <w:p> со стилем style (пустой — стиль Normal).
+func (b *docxBody) paragraph(style, runs string) {
+	b.b.WriteString("<w:p>")
+	if style != "" {
+		b.b.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	b.b.WriteString(runs)
+	b.b.WriteString("</w:p>")
+}
+
+// entryHeading добавляет заголовок записи с датами, выровненными по правому
+// краю табуляцией (аналог \hfill в LaTeX-шаблоне).
+func (b *docxBody) entryHeading(title, dates string) {
+	runs := b.run(title, "<w:b/>")
+	if dates != "" {
+		runs += "<w:r><w:tab/></w:r>" + b.run(dates, "")
+	}
+	b.paragraph("EntryHeading", runs)
+}
+
+// run возвращает <w:r> с текстом и необязательными свойствами rPr.
+func (b *docxBody) run(text, rPr string) string {
+	var out strings.Builder
+	out.WriteString("<w:r>")
+	if rPr != "" {
+		out.WriteString("<w:rPr>" + rPr + "</w:rPr>")
+	}
+	out.WriteString(`<w:t xml:space="preserve">`)
+	out.WriteString(xmlEscape(text))
+	out.WriteString("</w:t></w:r>")
+	return out.String()
+}
+
+// hyperlink регистрирует внешнюю связь и возвращает <w:hyperlink>.
+func (b *docxBody) hyperlink(url, text string) string {
+	b.links = append(b.links, url)
+	id := fmt.Sprintf("rIdLink%d", len(b.links))
+	return `<w:hyperlink r:id="` + id + `">` + b.run(text, `<w:rStyle w:val="Hyperlink"/>`) + "</w:hyperlink>"
+}
+
+// pack собирает zip-пакет DOCX.
+func (d *DOCXRenderer) pack(r resume.Resume, body *docxBody) ([]byte, error) {
+	var rels strings.Builder
+	rels.WriteString(xml.Header)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	rels.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	rels.WriteString(`<Relationship Id="rIdNumbering" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>`)
+	for i, url := range body.links {
+		fmt.Fprintf(&rels,
+			`<Relationship Id="rIdLink%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`,
+			i+1, xmlEscape(url))
+	}
+	rels.WriteString(`</Relationships>`)
+
+	document := xml.Header +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>` +
+		body.b.String() +
+		fmt.Sprintf(`<w:sectPr><w:pgSz w:w="%d" w:h="%d"/><w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="0" w:footer="0" w:gutter="0"/></w:sectPr>`,
+			docxPageWidth, docxPageHeight, docxMargin, docxMargin, docxMargin, docxMargin) +
+		`</w:body></w:document>`
+
+	core := xml.Header +
+		`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		`<dc:title>` + xmlEscape(strings.TrimSpace(r.FullName+" — "+r.Position)) + `</dc:title>` +
+		`<dc:creator>` + xmlEscape(strings.TrimSpace(r.FullName)) + `</dc:creator>` +
+		`<dcterms:created xsi:type="dcterms:W3CDTF">` + d.now().UTC().Format(time.RFC3339) + `</dcterms:created>` +
+		`</cp:coreProperties>`
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxPackageRels},
+		{"docProps/core.xml", core},
+		{"word/document.xml", document},
+		{"word/styles.xml", docxStyles},
+		{"word/numbering.xml", docxNumbering},
+		{"word/_rels/document.xml.rels", rels.String()},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, fmt.Errorf("create %s: %w", f.name, err)
+		}
+		if _, err := w.Write([]byte(f.content)); err != nil {
+			return nil, fmt.Errorf("write %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close docx archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const docxContentTypes = xml.Header +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>` +
+	`</Types>`
+
+const docxPackageRels = xml.Header +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>` +
+	`</Relationships>`
+
+// docxStyles — минимальный набор стилей: размеры шрифтов примерно повторяют
+// LaTeX-шаблон (\LARGE для имени, \large для позиции, 11pt для текста).
+var docxStyles = xml.Header +
+	`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:cs="Calibri"/><w:sz w:val="22"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="60" w:line="264" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:rPr><w:b/><w:sz w:val="40"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Subtitle"><w:name w:val="Subtitle"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="120"/></w:pPr><w:rPr><w:color w:val="52606D"/><w:sz w:val="28"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Contacts"><w:name w:val="Contacts"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="240"/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="80"/><w:pBdr><w:bottom w:val="single" w:sz="4" w:space="1" w:color="D9E2EC"/></w:pBdr><w:outlineLvl w:val="0"/></w:pPr>` +
+	`<w:rPr><w:b/><w:caps/><w:sz w:val="26"/></w:rPr></w:style>` +
+	fmt.Sprintf(`<w:style w:type="paragraph" w:styleId="EntryHeading"><w:name w:val="Entry Heading"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:tabs><w:tab w:val="right" w:pos="%d"/></w:tabs><w:spacing w:before="120" w:after="0"/></w:pPr></w:style>`, docxTextWidth) +
+	`<w:style w:type="paragraph" w:styleId="Meta"><w:name w:val="Meta"/><w:basedOn w:val="Normal"/><w:rPr><w:i/><w:color w:val="52606D"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="ListBullet"><w:name w:val="List Bullet"/><w:basedOn w:val="Normal"/><w:pPr><w:numPr><w:numId w:val="1"/></w:numPr><w:spacing w:after="20"/><w:ind w:left="360" w:hanging="360"/></w:pPr></w:style>` +
+	`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:rPr><w:color w:val="1D4ED8"/><w:u w:val="single"/></w:rPr></w:style>` +
+	`</w:styles>`
+
+const docxNumbering = xml.Header +
+	`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="singleLevel"/>` +
+	`<w:lvl w:ilvl="0"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="•"/><w:lvlJc w:val="left"/>` +
+	`<w:pPr><w:ind w:left="360" w:hanging="360"/></w:pPr></w:lvl></w:abstractNum>` +
+	`<w:num w:numId="1"><w:abstractNumId w:val="0"/></w:num>` +
+	`</w:numbering>`
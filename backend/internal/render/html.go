@@ -0,0 +1,142 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+//go:embed theme.css
+var themeCSS string
+
+//go:embed html.tmpl
+var htmlTemplateSource string
+
+var htmlTemplate = template.Must(template.New("resume").Parse(htmlTemplateSource))
+
+// base64Re допускает только алфавит base64, чтобы data URI фото нельзя было
+// использовать для внедрения разметки.
+var base64Re = regexp.MustCompile(`^[A-Za-z0-9+/=\s]+$`)
+
+// HTMLRenderer рендерит резюме в самодостаточную HTML-страницу: CSS-тема
+// встроена в <style>, фото — в data URI.
+type HTMLRenderer struct{}
+
+// NewHTMLRenderer создаёт рендерер HTML.
+func NewHTMLRenderer() *HTMLRenderer {
+	return &HTMLRenderer{}
+}
+
+// Format реализует resume.Renderer.
+func (HTMLRenderer) Format() resume.Format {
+	return resume.FormatHTML
+}
+
+type htmlContact struct {
+	Text string
+	URL  string
+}
+
+type htmlEntry struct {
+	Title       string
+	Dates       string
+	Location    string
+	Description string
+	Bullets     []string
+}
+
+type htmlSection struct {
+	Title string
+	Items []string
+}
+
+type htmlPage struct {
+	CSS        template.CSS
+	FullName   string
+	Position   string
+	Summary    string
+	Contacts   []htmlContact
+	Photo      template.URL
+	Skills     []string
+	Experience []htmlEntry
+	Education  []htmlEntry
+	Sections   []htmlSection
+}
+
+// Render реализует resume.Renderer.
+func (HTMLRenderer) Render(_ context.Context, r resume.Resume) ([]byte, error) {
+	page := htmlPage{
+		CSS:      template.CSS(themeCSS),
+		FullName: strings.TrimSpace(r.FullName),
+		Position: strings.TrimSpace(r.Position),
+		Summary:  strings.TrimSpace(r.Summary),
+		Skills:   resume.SkillLines(r.Skills),
+	}
+
+	if email := strings.TrimSpace(r.Contacts.Email); email != "" {
+		page.Contacts = append(page.Contacts, htmlContact{Text: email, URL: "mailto:" + email})
+	}
+	for _, c := range nonEmpty([]string{r.Contacts.Phone, r.Contacts.Location}) {
+		page.Contacts = append(page.Contacts, htmlContact{Text: c})
+	}
+	for _, l := range r.Contacts.Links {
+		if strings.TrimSpace(l.URL) == "" {
+			continue
+		}
+		page.Contacts = append(page.Contacts, htmlContact{Text: linkLabel(l), URL: strings.TrimSpace(l.URL)})
+	}
+
+	if r.Photo != nil && strings.TrimSpace(r.Photo.Data) != "" {
+		mime := strings.ToLower(strings.TrimSpace(r.Photo.MimeType))
+		if (mime == "image/jpeg" || mime == "image/png") && base64Re.MatchString(r.Photo.Data) {
+			page.Photo = template.URL("data:" + mime + ";base64," + strings.Join(strings.Fields(r.Photo.Data), ""))
+		}
+	}
+
+	for _, e := range r.Experience {
+		if !hasExperience(e) {
+			continue
+		}
+		page.Experience = append(page.Experience, htmlEntry{
+			Title:       experienceTitle(e),
+			Dates:       dateRange(e.StartDate, e.EndDate),
+			Location:    strings.TrimSpace(e.Location),
+			Description: strings.TrimSpace(e.Description),
+			Bullets:     nonEmptyTexts(e.Bullets),
+		})
+	}
+
+	for _, e := range r.Education {
+		if !hasEducation(e) {
+			continue
+		}
+		page.Education = append(page.Education, htmlEntry{
+			Title:       educationTitle(e),
+			Dates:       dateRange(e.StartDate, e.EndDate),
+			Location:    strings.TrimSpace(e.Location),
+			Description: strings.TrimSpace(e.Details),
+		})
+	}
+
+	for _, cs := range r.CustomSections {
+		if strings.TrimSpace(cs.Title) == "" {
+			continue
+		}
+		page.Sections = append(page.Sections, htmlSection{
+			Title: strings.TrimSpace(cs.Title),
+			Items: nonEmptyTexts(cs.Items),
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, page); err != nil {
+		return nil, fmt.Errorf("execute html template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
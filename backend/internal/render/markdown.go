@@ -0,0 +1,135 @@
+package render
+
+import (
+	"context"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+// markdownReplacer экранирует символы, которые Markdown трактует как разметку.
+var markdownReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	`*`, `\*`,
+	`_`, `\_`,
+	`[`, `\[`,
+	`]`, `\]`,
+	`<`, `&lt;`,
+	`#`, `\#`,
+	`|`, `\|`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownReplacer.Replace(strings.TrimSpace(s))
+}
+
+// MarkdownRenderer рендерит резюме в Markdown (CommonMark).
+type MarkdownRenderer struct{}
+
+// NewMarkdownRenderer создаёт рендерер Markdown.
+func NewMarkdownRenderer() *MarkdownRenderer {
+	return &MarkdownRenderer{}
+}
+
+// Format реализует resume.Renderer.
+func (MarkdownRenderer) Format() resume.Format {
+	return resume.FormatMarkdown
+}
+
+// Render реализует resume.Renderer.
+func (MarkdownRenderer) Render(_ context.Context, r resume.Resume) ([]byte, error) {
+	var b strings.Builder
+
+	b.WriteString("# " + escapeMarkdown(r.FullName) + "\n\n")
+	if p := strings.TrimSpace(r.Position); p != "" {
+		b.WriteString("**" + escapeMarkdown(p) + "**\n\n")
+	}
+
+	var contacts []string
+	if email := strings.TrimSpace(r.Contacts.Email); email != "" {
+		contacts = append(contacts, "["+escapeMarkdown(email)+"](mailto:"+email+")")
+	}
+	for _, c := range nonEmpty([]string{r.Contacts.Phone, r.Contacts.Location}) {
+		contacts = append(contacts, escapeMarkdown(c))
+	}
+	for _, l := range r.Contacts.Links {
+		if strings.TrimSpace(l.URL) == "" {
+			continue
+		}
+		contacts = append(contacts, "["+escapeMarkdown(linkLabel(l))+"]("+markdownURL(l.URL)+")")
+	}
+	if len(contacts) > 0 {
+		b.WriteString(strings.Join(contacts, " · ") + "\n\n")
+	}
+
+	if s := strings.TrimSpace(r.Summary); s != "" {
+		b.WriteString("## Summary\n\n" + escapeMarkdown(s) + "\n\n")
+	}
+
+	if skills := resume.SkillLines(r.Skills); len(skills) > 0 {
+		b.WriteString("## Skills\n\n")
+		for _, s := range skills {
+			b.WriteString("- " + escapeMarkdown(s) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Experience) > 0 {
+		b.WriteString("## Experience\n\n")
+		for _, e := range r.Experience {
+			if !hasExperience(e) {
+				continue
+			}
+			b.WriteString("### " + escapeMarkdown(experienceTitle(e)) + "\n\n")
+			if meta := nonEmpty([]string{e.Location, dateRange(e.StartDate, e.EndDate)}); len(meta) > 0 {
+				b.WriteString("*" + escapeMarkdown(strings.Join(meta, " | ")) + "*\n\n")
+			}
+			if d := strings.TrimSpace(e.Description); d != "" {
+				b.WriteString(escapeMarkdown(d) + "\n\n")
+			}
+			if bullets := nonEmptyTexts(e.Bullets); len(bullets) > 0 {
+				for _, item := range bullets {
+					b.WriteString("- " + escapeMarkdown(item) + "\n")
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+
+	if len(r.Education) > 0 {
+		b.WriteString("## Education\n\n")
+		for _, e := range r.Education {
+			if !hasEducation(e) {
+				continue
+			}
+			b.WriteString("### " + escapeMarkdown(educationTitle(e)) + "\n\n")
+			if meta := nonEmpty([]string{e.Location, dateRange(e.StartDate, e.EndDate)}); len(meta) > 0 {
+				b.WriteString("*" + escapeMarkdown(strings.Join(meta, " | ")) + "*\n\n")
+			}
+			if d := strings.TrimSpace(e.Details); d != "" {
+				b.WriteString(escapeMarkdown(d) + "\n\n")
+			}
+		}
+	}
+
+	for _, cs := range r.CustomSections {
+		if strings.TrimSpace(cs.Title) == "" {
+			continue
+		}
+		b.WriteString("## " + escapeMarkdown(cs.Title) + "\n\n")
+		if items := nonEmptyTexts(cs.Items); len(items) > 0 {
+			for _, item := range items {
+				b.WriteString("- " + escapeMarkdown(item) + "\n")
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	return []byte(strings.TrimRight(b.String(), "\n") + "\n"), nil
+}
+
+// markdownURL экранирует символы, ломающие ссылку вида [label](url).
+func markdownURL(u string) string {
+	return strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(strings.TrimSpace(u))
+}
@@ -0,0 +1,357 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image/png"
+	"strings"
+	"unicode/utf8"
+
+	"resume_backend/internal/pdfdoc"
+	"resume_backend/internal/resume"
+)
+
+// Геометрия страницы — A4 с полями 1.5 см и фото 3×4 см, как в LaTeX-шаблоне
+// (в пунктах).
+const (
+	pdfMargin    = 42.52
+	pdfTextWidth = pdfdoc.PageWidth - 2*pdfMargin
+	pdfPhotoW    = 85.04
+	pdfPhotoH    = 113.39
+	pdfIndent    = 14
+)
+
+// Цвета текста (RGB).
+var (
+	pdfInk   = [3]float64{0.1, 0.1, 0.1}
+	pdfMuted = [3]float64{0.4, 0.4, 0.4}
+	pdfLink  = [3]float64{0.1, 0.3, 0.6}
+)
+
+// PDFRenderer — запасной рендерер PDF: простая вёрстка резюме в одну
+// колонку, собранная прямо в backend без LaTeX. resume.Service переходит на
+// него, пока latex-service недоступен. Секции и их порядок те же, что в
+// шаблоне, но без его типографики.
+type PDFRenderer struct {
+	regular *pdfdoc.TrueType
+	bold    *pdfdoc.TrueType
+}
+
+// NewPDFRenderer создаёт запасной рендерер. regular и bold — TrueType-шрифты
+// с кириллицей; без regular используется Helvetica, в которой кириллица
+// выводится транслитом, без bold заголовки набираются шрифтом regular.
+func NewPDFRenderer(regular, bold *pdfdoc.TrueType) *PDFRenderer {
+	return &PDFRenderer{regular: regular, bold: bold}
+}
+
+// RenderResume реализует resume.PDFRenderer.
+func (p *PDFRenderer) RenderResume(_ context.Context, r resume.Resume) ([]byte, error) {
+	doc := pdfdoc.New()
+	doc.SetInfo(strings.TrimSpace(r.FullName), strings.TrimSpace(r.FullName))
+
+	l := &pdfLayout{doc: doc}
+	switch {
+	case p.regular == nil:
+		l.regular, l.bold = doc.Helvetica(false), doc.Helvetica(true)
+	case p.bold == nil:
+		l.regular = doc.TrueTypeFont(p.regular)
+		l.bold = l.regular
+	default:
+		l.regular, l.bold = doc.TrueTypeFont(p.regular), doc.TrueTypeFont(p.bold)
+	}
+	l.newPage()
+
+	l.header(r)
+
+	if skills := resume.SkillLines(r.Skills); len(skills) > 0 {
+		l.section("Skills")
+		l.paragraph(l.regular, 10, pdfMargin, pdfTextWidth, strings.Join(skills, ", "), pdfInk)
+	}
+
+	if len(r.Experience) > 0 {
+		l.section("Experience")
+		for _, e := range r.Experience {
+			if !hasExperience(e) {
+				continue
+			}
+			l.entryHeading(experienceTitle(e), dateRange(e.StartDate, e.EndDate))
+			if loc := strings.TrimSpace(e.Location); loc != "" {
+				l.paragraph(l.regular, 9.5, pdfMargin, pdfTextWidth, loc, pdfMuted)
+			}
+			if desc := strings.TrimSpace(e.Description); desc != "" {
+				l.paragraph(l.regular, 10, pdfMargin, pdfTextWidth, desc, pdfInk)
+			}
+			for _, item := range nonEmptyTexts(e.Bullets) {
+				l.bullet("•", item)
+			}
+		}
+	}
+
+	if len(r.Education) > 0 {
+		l.section("Education")
+		for _, e := range r.Education {
+			if !hasEducation(e) {
+				continue
+			}
+			l.entryHeading(educationTitle(e), dateRange(e.StartDate, e.EndDate))
+			if loc := strings.TrimSpace(e.Location); loc != "" {
+				l.paragraph(l.regular, 9.5, pdfMargin, pdfTextWidth, loc, pdfMuted)
+			}
+			if details := strings.TrimSpace(e.Details); details != "" {
+				l.paragraph(l.regular, 10, pdfMargin, pdfTextWidth, details, pdfInk)
+			}
+		}
+	}
+
+	for _, cs := range r.CustomSections {
+		if strings.TrimSpace(cs.Title) == "" {
+			continue
+		}
+		l.section(strings.TrimSpace(cs.Title))
+		symbol := strings.TrimSpace(cs.BulletSymbol)
+		if symbol == "" {
+			symbol = "•"
+		}
+		for _, item := range nonEmptyTexts(cs.Items) {
+			l.bullet(symbol, item)
+		}
+	}
+
+	return doc.Bytes()
+}
+
+// pdfLayout ведёт вывод сверху вниз с переносом на новую страницу.
+type pdfLayout struct {
+	doc     *pdfdoc.Document
+	page    *pdfdoc.Page
+	regular *pdfdoc.Font
+	bold    *pdfdoc.Font
+	// y — верхняя граница свободного места на странице.
+	y float64
+}
+
+func (l *pdfLayout) newPage() {
+	l.page = l.doc.AddPage()
+	l.y = pdfdoc.PageHeight - pdfMargin
+}
+
+// ensure переходит на новую страницу, если до нижнего поля меньше h.
+func (l *pdfLayout) ensure(h float64) {
+	if l.y-h < pdfMargin {
+		l.newPage()
+	}
+}
+
+// leading — высота строки для кегля size.
+func leading(size float64) float64 {
+	return size * 1.35
+}
+
+// text выводит одну строку в позиции x и сдвигает y.
+func (l *pdfLayout) text(f *pdfdoc.Font, size, x float64, s string, color [3]float64) {
+	l.ensure(leading(size))
+	l.page.SetColor(color[0], color[1], color[2])
+	l.page.Text(f, size, x, l.y-size, s)
+	l.y -= leading(size)
+}
+
+// paragraph выводит текст с переносом по словам в колонку шириной width.
+func (l *pdfLayout) paragraph(f *pdfdoc.Font, size, x, width float64, s string, color [3]float64) {
+	for _, line := range wrapText(f, size, width, s) {
+		l.text(f, size, x, line, color)
+	}
+}
+
+// header выводит имя, позицию, summary и контакты; фото — справа, как в
+// шаблоне. Фото, которое не удалось декодировать, пропускается.
+func (l *pdfLayout) header(r resume.Resume) {
+	top := l.y
+	width := pdfTextWidth
+
+	if img := l.photo(r.Photo); img != nil {
+		w, h := pdfPhotoW, pdfPhotoW*float64(img.Height)/float64(img.Width)
+		if h > pdfPhotoH {
+			w, h = pdfPhotoH*float64(img.Width)/float64(img.Height), pdfPhotoH
+		}
+		l.page.Image(img, pdfdoc.PageWidth-pdfMargin-w, top-h, w, h)
+		width -= pdfPhotoW + pdfIndent
+		defer func() { l.y = min(l.y, top-h-pdfIndent) }()
+	}
+
+	l.paragraph(l.bold, 20, pdfMargin, width, strings.TrimSpace(r.FullName), pdfInk)
+	if pos := strings.TrimSpace(r.Position); pos != "" {
+		l.paragraph(l.regular, 13, pdfMargin, width, pos, pdfMuted)
+	}
+	if s := strings.TrimSpace(r.Summary); s != "" {
+		l.y -= 4
+		l.paragraph(l.regular, 10, pdfMargin, width, s, pdfInk)
+	}
+
+	var contacts []pdfRun
+	if email := strings.TrimSpace(r.Contacts.Email); email != "" {
+		contacts = append(contacts, pdfRun{text: email, uri: "mailto:" + email})
+	}
+	for _, c := range nonEmpty([]string{r.Contacts.Phone, r.Contacts.Location}) {
+		contacts = append(contacts, pdfRun{text: c})
+	}
+	for _, link := range r.Contacts.Links {
+		if u := strings.TrimSpace(link.URL); u != "" {
+			contacts = append(contacts, pdfRun{text: linkLabel(link), uri: u})
+		}
+	}
+	if len(contacts) > 0 {
+		l.y -= 4
+		l.runs(contacts, 9.5, width)
+	}
+}
+
+// pdfRun — фрагмент строки; с uri он становится ссылкой.
+type pdfRun struct {
+	text string
+	uri  string
+}
+
+// runs выводит фрагменты через разделитель, перенося целые фрагменты на
+// следующую строку.
+func (l *pdfLayout) runs(runs []pdfRun, size, width float64) {
+	const sep = "  ·  "
+	sepW := l.regular.Width(sep, size)
+
+	l.ensure(leading(size))
+	x := pdfMargin
+	for i, run := range runs {
+		w := l.regular.Width(run.text, size)
+		if i > 0 {
+			if x+sepW+w > pdfMargin+width {
+				l.y -= leading(size)
+				l.ensure(leading(size))
+				x = pdfMargin
+			} else {
+				l.page.SetColor(pdfMuted[0], pdfMuted[1], pdfMuted[2])
+				l.page.Text(l.regular, size, x, l.y-size, sep)
+				x += sepW
+			}
+		}
+
+		color := pdfInk
+		if run.uri != "" {
+			color = pdfLink
+			l.page.Link(x, l.y-leading(size), w, leading(size), run.uri)
+		}
+		l.page.SetColor(color[0], color[1], color[2])
+		l.page.Text(l.regular, size, x, l.y-size, run.text)
+		x += w
+	}
+	l.y -= leading(size)
+}
+
+// section выводит заголовок раздела с линией под ним. Заголовок не
+// остаётся последней строкой страницы.
+func (l *pdfLayout) section(title string) {
+	l.y -= 10
+	l.ensure(leading(12) + 6 + 2*leading(10))
+	l.text(l.bold, 12, pdfMargin, title, pdfInk)
+	l.page.SetColor(pdfMuted[0], pdfMuted[1], pdfMuted[2])
+	l.page.Line(pdfMargin, l.y+2, pdfMargin+pdfTextWidth, l.y+2, 0.5)
+	l.y -= 4
+}
+
+// entryHeading выводит заголовок записи опыта или образования с периодом,
+// выровненным по правому краю.
+func (l *pdfLayout) entryHeading(title, period string) {
+	const size = 10.5
+	l.y -= 3
+	l.ensure(2 * leading(size))
+
+	periodW := l.regular.Width(period, 9.5)
+	if period != "" {
+		l.page.SetColor(pdfMuted[0], pdfMuted[1], pdfMuted[2])
+		l.page.Text(l.regular, 9.5, pdfMargin+pdfTextWidth-periodW, l.y-size, period)
+		periodW += pdfIndent
+	}
+	l.paragraph(l.bold, size, pdfMargin, pdfTextWidth-periodW, title, pdfInk)
+}
+
+// bullet выводит пункт списка с висячим отступом.
+func (l *pdfLayout) bullet(symbol, text string) {
+	const size = 10
+	lines := wrapText(l.regular, size, pdfTextWidth-pdfIndent-4, text)
+	for i, line := range lines {
+		if i == 0 {
+			l.ensure(leading(size))
+			l.page.SetColor(pdfInk[0], pdfInk[1], pdfInk[2])
+			l.page.Text(l.regular, size, pdfMargin+4, l.y-size, symbol)
+		}
+		l.text(l.regular, size, pdfMargin+pdfIndent+4, line, pdfInk)
+	}
+}
+
+// photo добавляет фото в документ: JPEG встраивается как есть, PNG
+// перекодируется.
+func (l *pdfLayout) photo(p *resume.Photo) *pdfdoc.Image {
+	if p == nil || strings.TrimSpace(p.Data) == "" {
+		return nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(p.Data), ""))
+	if err != nil {
+		return nil
+	}
+
+	var img *pdfdoc.Image
+	switch strings.ToLower(strings.TrimSpace(p.MimeType)) {
+	case "image/jpeg", "image/jpg":
+		img, err = l.doc.AddJPEG(raw)
+	case "image/png":
+		decoded, derr := png.Decode(bytes.NewReader(raw))
+		if derr != nil {
+			return nil
+		}
+		img, err = l.doc.AddImage(decoded)
+	default:
+		return nil
+	}
+	if err != nil || img.Width == 0 || img.Height == 0 {
+		return nil
+	}
+	return img
+}
+
+// wrapText разбивает текст на строки не шире width. Переводы строк
+// сохраняются; слово длиннее строки разрезается по символам.
+func wrapText(f *pdfdoc.Font, size, width float64, s string) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if f.Width(candidate, size) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for f.Width(word, size) > width && utf8.RuneCountInString(word) > 1 {
+				cut := len(word)
+				for cut > 0 && f.Width(word[:cut], size) > width {
+					_, n := utf8.DecodeLastRuneInString(word[:cut])
+					cut -= n
+				}
+				if cut == 0 {
+					_, cut = utf8.DecodeRuneInString(word)
+				}
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			line = word
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+// Package render содержит рендереры резюме, работающие целиком внутри backend:
+// HTML, Markdown, plain text, DOCX и запасной PDF на случай недоступности
+// latex-service. Порядок секций повторяет LaTeX-шаблон:
+// шапка (имя, позиция, summary, контакты), Skills, Experience, Education,
+// кастомные секции.
+package render
+
+import (
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+// dateRange форматирует период так же, как LaTeX-шаблон: "start – end"
+// или только начало, если конец не указан.
+func dateRange(start, end string) string {
+	start = strings.TrimSpace(start)
+	end = strings.TrimSpace(end)
+	switch {
+	case start != "" && end != "":
+		return start + " – " + end
+	case start != "":
+		return start
+	default:
+		return end
+	}
+}
+
+// nonEmpty возвращает элементы без пустых строк.
+func nonEmpty(items []string) []string {
+	out := make([]string, 0, len(items))
+	for _, s := range items {
+		if strings.TrimSpace(s) != "" {
+			out = append(out, strings.TrimSpace(s))
+		}
+	}
+	return out
+}
+
+// nonEmptyTexts — nonEmpty для навыков, пунктов и элементов разделов.
+func nonEmptyTexts(items []resume.TaggedText) []string {
+	return nonEmpty(resume.Texts(items))
+}
+
+// hasExperience/hasEducation повторяют правило LaTeX-рендерера: запись без
+// названия и компании (учебного заведения и степени) пропускается.
+func hasExperience(e resume.ExperienceItem) bool {
+	return strings.TrimSpace(e.Company) != "" || strings.TrimSpace(e.Position) != ""
+}
+
+func hasEducation(e resume.EducationItem) bool {
+	return strings.TrimSpace(e.Institution) != "" || strings.TrimSpace(e.Degree) != ""
+}
+
+// linkLabel возвращает подпись ссылки или сам URL, если подписи нет.
+func linkLabel(l resume.Link) string {
+	if strings.TrimSpace(l.Label) != "" {
+		return strings.TrimSpace(l.Label)
+	}
+	return strings.TrimSpace(l.URL)
+}
+
+// experienceTitle собирает строку "Position at Company".
+func experienceTitle(e resume.ExperienceItem) string {
+	title := strings.TrimSpace(e.Position)
+	if company := strings.TrimSpace(e.Company); company != "" {
+		if title == "" {
+			return company
+		}
+		title += " at " + company
+	}
+	return title
+}
+
+// educationTitle собирает строку "Institution — Degree".
+func educationTitle(e resume.EducationItem) string {
+	parts := nonEmpty([]string{e.Institution, e.Degree})
+	return strings.Join(parts, " — ")
+}
@@ -0,0 +1,120 @@
+package render
+
+import (
+	"context"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+// TextRenderer рендерит резюме в plain text без разметки — формат, который
+// надёжнее всего разбирают ATS-порталы.
+type TextRenderer struct{}
+
+// NewTextRenderer создаёт рендерер plain text.
+func NewTextRenderer() *TextRenderer {
+	return &TextRenderer{}
+}
+
+// Format реализует resume.Renderer.
+func (TextRenderer) Format() resume.Format {
+	return resume.FormatText
+}
+
+// Render реализует resume.Renderer.
+func (TextRenderer) Render(_ context.Context, r resume.Resume) ([]byte, error) {
+	var b strings.Builder
+
+	b.WriteString(strings.TrimSpace(r.FullName) + "\n")
+	if p := strings.TrimSpace(r.Position); p != "" {
+		b.WriteString(p + "\n")
+	}
+
+	contacts := nonEmpty([]string{r.Contacts.Email, r.Contacts.Phone, r.Contacts.Location})
+	for _, l := range r.Contacts.Links {
+		if strings.TrimSpace(l.URL) == "" {
+			continue
+		}
+		if label := strings.TrimSpace(l.Label); label != "" {
+			contacts = append(contacts, label+": "+strings.TrimSpace(l.URL))
+		} else {
+			contacts = append(contacts, strings.TrimSpace(l.URL))
+		}
+	}
+	for _, c := range contacts {
+		b.WriteString(c + "\n")
+	}
+
+	if s := strings.TrimSpace(r.Summary); s != "" {
+		textSection(&b, "Summary")
+		b.WriteString(s + "\n")
+	}
+
+	if skills := resume.SkillLines(r.Skills); len(skills) > 0 {
+		textSection(&b, "Skills")
+		b.WriteString(strings.Join(skills, ", ") + "\n")
+	}
+
+	if len(r.Experience) > 0 {
+		textSection(&b, "Experience")
+		first := true
+		for _, e := range r.Experience {
+			if !hasExperience(e) {
+				continue
+			}
+			if !first {
+				b.WriteString("\n")
+			}
+			first = false
+
+			b.WriteString(experienceTitle(e) + "\n")
+			if meta := nonEmpty([]string{e.Location, dateRange(e.StartDate, e.EndDate)}); len(meta) > 0 {
+				b.WriteString(strings.Join(meta, " | ") + "\n")
+			}
+			if d := strings.TrimSpace(e.Description); d != "" {
+				b.WriteString(d + "\n")
+			}
+			for _, item := range nonEmptyTexts(e.Bullets) {
+				b.WriteString("- " + item + "\n")
+			}
+		}
+	}
+
+	if len(r.Education) > 0 {
+		textSection(&b, "Education")
+		first := true
+		for _, e := range r.Education {
+			if !hasEducation(e) {
+				continue
+			}
+			if !first {
+				b.WriteString("\n")
+			}
+			first = false
+
+			b.WriteString(educationTitle(e) + "\n")
+			if meta := nonEmpty([]string{e.Location, dateRange(e.StartDate, e.EndDate)}); len(meta) > 0 {
+				b.WriteString(strings.Join(meta, " | ") + "\n")
+			}
+			if d := strings.TrimSpace(e.Details); d != "" {
+				b.WriteString(d + "\n")
+			}
+		}
+	}
+
+	for _, cs := range r.CustomSections {
+		if strings.TrimSpace(cs.Title) == "" {
+			continue
+		}
+		textSection(&b, cs.Title)
+		for _, item := range nonEmptyTexts(cs.Items) {
+			b.WriteString("- " + item + "\n")
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+func textSection(b *strings.Builder, title string) {
+	b.WriteString("\n" + strings.ToUpper(strings.TrimSpace(title)) + "\n")
+}
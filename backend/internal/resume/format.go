@@ -0,0 +1,108 @@
+package resume
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format — формат выходного документа.
+type Format string
+
+const (
+	FormatPDF      Format = "pdf"
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+	FormatText     Format = "text"
+	FormatDOCX     Format = "docx"
+)
+
+// formatInfo описывает MIME-тип и расширение файла формата.
+var formatInfo = map[Format]struct {
+	contentType string
+	ext         string
+}{
+	FormatPDF:      {"application/pdf", "pdf"},
+	FormatHTML:     {"text/html; charset=utf-8", "html"},
+	FormatMarkdown: {"text/markdown; charset=utf-8", "md"},
+	FormatText:     {"text/plain; charset=utf-8", "txt"},
+	FormatDOCX:     {"application/vnd.openxmlformats-officedocument.wordprocessingml.document", "docx"},
+}
+
+// ContentType возвращает MIME-тип формата.
+func (f Format) ContentType() string {
+	return formatInfo[f].contentType
+}
+
+// Filename возвращает имя файла для Content-Disposition.
+func (f Format) Filename() string {
+	return "resume." + f.Ext()
+}
+
+// Ext возвращает расширение файла формата без точки.
+func (f Format) Ext() string {
+	return formatInfo[f].ext
+}
+
+// ParseFormat разбирает значение ?format= (pdf, html, md, markdown, txt, text, docx).
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "pdf":
+		return FormatPDF, nil
+	case "html", "htm":
+		return FormatHTML, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "txt", "text", "plain":
+		return FormatText, nil
+	case "docx":
+		return FormatDOCX, nil
+	}
+	return "", fmt.Errorf("unknown format %q", s)
+}
+
+// FormatForMediaType сопоставляет MIME-тип из Accept с форматом.
+func FormatForMediaType(mt string) (Format, bool) {
+	switch strings.ToLower(mt) {
+	case "application/pdf":
+		return FormatPDF, true
+	case "text/html", "application/xhtml+xml":
+		return FormatHTML, true
+	case "text/markdown", "text/x-markdown":
+		return FormatMarkdown, true
+	case "text/plain":
+		return FormatText, true
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return FormatDOCX, true
+	}
+	return "", false
+}
+
+// Renderer — формат-нейтральный рендерер резюме. В отличие от PDFRenderer
+// реализации не обращаются к внешним сервисам.
+type Renderer interface {
+	Format() Format
+	Render(ctx context.Context, r Resume) ([]byte, error)
+}
+
+// Output — результат рендеринга в выбранном формате.
+type Output struct {
+	Format Format
+	Data   []byte
+	// Body — документ потоком вместо Data (только у Service.RenderStream);
+	// его нужно закрыть. Size — длина Body в байтах, -1 — неизвестна.
+	Body io.ReadCloser
+	Size int64
+	// Simplified — PDF собран запасным рендерером с упрощённой вёрсткой,
+	// потому что LaTeX-рендерер недоступен.
+	Simplified bool
+}
+
+// Close закрывает Body, если он есть.
+func (o Output) Close() error {
+	if o.Body == nil {
+		return nil
+	}
+	return o.Body.Close()
+}
@@ -0,0 +1,50 @@
+package resume
+
+import (
+	"regexp"
+
+	"resume_contract/metrics"
+)
+
+var (
+	validationFailures = metrics.Default.NewCounter(
+		"resume_validation_failures_total",
+		"Resume validation errors by field; list indexes are dropped from field paths.",
+		"field",
+	)
+	outputBytes = metrics.Default.NewHistogram(
+		"resume_output_bytes",
+		"Size of rendered documents by format.",
+		metrics.SizeBuckets,
+		"format",
+	)
+)
+
+var fieldIndexRe = regexp.MustCompile(`\[\d+\]`)
+
+// observeValidation считает ошибки валидации по полям. Индексы списков
+// убираются (experience[3].company — experience[].company), чтобы число
+// рядов метрики не зависело от длины документов.
+func observeValidation(err error) error {
+	if ve, ok := err.(*ValidationError); ok {
+		for _, fe := range ve.Errors {
+			validationFailures.Inc(fieldIndexRe.ReplaceAllString(fe.Field, "[]"))
+		}
+	}
+	return err
+}
+
+// observeOutput записывает размер готового документа. Поток неизвестной
+// длины не учитывается.
+func observeOutput(out Output, err error) (Output, error) {
+	if err != nil {
+		return out, err
+	}
+	switch {
+	case out.Body == nil:
+		outputBytes.Observe(float64(len(out.Data)), string(out.Format))
+	case out.Size >= 0:
+		outputBytes.Observe(float64(out.Size), string(out.Format))
+	}
+	return out, nil
+}
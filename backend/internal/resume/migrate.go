@@ -0,0 +1,221 @@
+package resume
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CurrentSchemaVersion — версия формата документа резюме, которую понимает
+// текущая сборка backend. Документы без поля schemaVersion считаются версией 0.
+//
+// История версий:
+//   - 1 — первая версия с полем schemaVersion (форма документа MVP);
+//   - 2 — навыки сгруппированы: skills — список {"name", "items"};
+//   - 3 — у кастомных разделов есть тип: customSections[].type.
+const CurrentSchemaVersion = 3
+
+// SchemaVersionError возвращается, если документ новее, чем умеет backend.
+// Версия не того типа (строка, дробь, отрицательное число) — обычная
+// ошибка разбора документа.
+type SchemaVersionError struct {
+	Got int
+}
+
+func (e *SchemaVersionError) Error() string {
+	return fmt.Sprintf("unsupported schemaVersion %d (current: %d)", e.Got, CurrentSchemaVersion)
+}
+
+// ErrTrailingData — после JSON-документа есть ещё данные (второй объект
+// или мусор), которые иначе молча отбрасывались бы.
+var ErrTrailingData = errors.New("unexpected data after the JSON document")
+
+// migration поднимает документ с версии from на from+1. Шаги работают с
+// «сырым» JSON-документом, чтобы старые поля можно было прочитать даже
+// после удаления их из структуры Resume.
+type migration struct {
+	from int
+	up   func(doc map[string]any) error
+}
+
+// migrations — упорядоченная цепочка шагов. Новый шаг добавляется в конец
+// вместе с увеличением CurrentSchemaVersion.
+// Номер версии в документе проставляет Migrate после каждого шага.
+var migrations = []migration{
+	{from: 0, up: migrateV0ToV1},
+	{from: 1, up: migrateV1ToV2},
+	{from: 2, up: migrateV2ToV3},
+}
+
+// migrateV0ToV1 — документы MVP не содержали schemaVersion, но по форме
+// совпадают с версией 1, поэтому достаточно проставить версию.
+func migrateV0ToV1(doc map[string]any) error {
+	return nil
+}
+
+// migrateV1ToV2 переносит плоский список навыков в одну группу без
+// названия: ["Go", "SQL"] — [{"name": "", "items": ["Go", "SQL"]}].
+// Навыки с тегами переносятся как есть.
+func migrateV1ToV2(doc map[string]any) error {
+	raw, ok := doc["skills"]
+	if !ok || raw == nil {
+		return nil
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("skills must be an array")
+	}
+	if len(items) == 0 {
+		return nil
+	}
+	doc["skills"] = []any{map[string]any{"name": "", "items": items}}
+	return nil
+}
+
+// migrateV2ToV3 проставляет тип кастомным разделам по их заголовку
+// («Projects» — projects, «Certificates» — certifications и т.д.);
+// остальные получают тип custom.
+func migrateV2ToV3(doc map[string]any) error {
+	raw, ok := doc["customSections"]
+	if !ok || raw == nil {
+		return nil
+	}
+	sections, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf("customSections must be an array")
+	}
+	for i, item := range sections {
+		section, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("customSections[%d] must be an object", i)
+		}
+		if _, ok := section["type"]; ok {
+			continue
+		}
+		title, _ := section["title"].(string)
+		section["type"] = SectionTypeForTitle(title)
+	}
+	return nil
+}
+
+// sectionTitles — заголовки разделов, по которым угадывается тип.
+var sectionTitles = map[string]string{
+	"projects":       SectionProjects,
+	"side projects":  SectionProjects,
+	"open source":    SectionProjects,
+	"certifications": SectionCertifications,
+	"certificates":   SectionCertifications,
+	"awards":         SectionAwards,
+	"honors":         SectionAwards,
+	"achievements":   SectionAwards,
+	"publications":   SectionPublications,
+	"talks":          SectionPublications,
+	"languages":      SectionLanguages,
+	"volunteering":   SectionVolunteering,
+	"volunteer work": SectionVolunteering,
+	"interests":      SectionInterests,
+	"hobbies":        SectionInterests,
+}
+
+// SectionTypeForTitle угадывает тип раздела по заголовку; незнакомый
+// заголовок — SectionCustom.
+func SectionTypeForTitle(title string) string {
+	if t, ok := sectionTitles[strings.ToLower(strings.TrimSpace(title))]; ok {
+		return t
+	}
+	return SectionCustom
+}
+
+// Migrate поднимает JSON-документ резюме до CurrentSchemaVersion шаг за шагом.
+// Возвращает обновлённый документ и исходную версию.
+func Migrate(raw []byte) ([]byte, int, error) {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, 0, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, 0, ErrTrailingData
+	}
+	if doc == nil {
+		return nil, 0, fmt.Errorf("resume document must be a JSON object")
+	}
+
+	from, err := documentVersion(doc)
+	if err != nil {
+		return nil, 0, err
+	}
+	if from == CurrentSchemaVersion {
+		return raw, from, nil
+	}
+
+	for v := from; v < CurrentSchemaVersion; v++ {
+		step := migrations[v]
+		if err := step.up(doc); err != nil {
+			return nil, from, fmt.Errorf("migrate schemaVersion %d -> %d: %w", v, v+1, err)
+		}
+		doc["schemaVersion"] = v + 1
+	}
+
+	out, err := json.Marshal(doc)
+	if err != nil {
+		return nil, from, fmt.Errorf("marshal migrated resume: %w", err)
+	}
+	return out, from, nil
+}
+
+// DecodeJSON мигрирует документ до текущей версии и строго декодирует его
+// в Resume (неизвестные поля — ошибка).
+func DecodeJSON(raw []byte) (Resume, error) {
+	upgraded, _, err := Migrate(raw)
+	if err != nil {
+		return Resume{}, err
+	}
+
+	var r Resume
+	dec := json.NewDecoder(bytes.NewReader(upgraded))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&r); err != nil {
+		return Resume{}, err
+	}
+	return r, nil
+}
+
+func documentVersion(doc map[string]any) (int, error) {
+	raw, ok := doc["schemaVersion"]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+
+	num, ok := raw.(json.Number)
+	if !ok {
+		return 0, fmt.Errorf("schemaVersion must be an integer, got %s", jsonKind(raw))
+	}
+	v, err := num.Int64()
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("schemaVersion must be a non-negative integer, got %s", num)
+	}
+	if v > CurrentSchemaVersion {
+		return 0, &SchemaVersionError{Got: int(v)}
+	}
+	return int(v), nil
+}
+
+// jsonKind называет тип JSON-значения для сообщений об ошибках.
+func jsonKind(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "number"
+}
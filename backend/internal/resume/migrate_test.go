@@ -0,0 +1,192 @@
+package resume
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "migrate", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func jsonEqual(t *testing.T, got, want []byte) bool {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("unmarshal result: %v", err)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("unmarshal fixture: %v", err)
+	}
+	return reflect.DeepEqual(g, w)
+}
+
+func TestMigrateFixtures(t *testing.T) {
+	want := readFixture(t, "v3.json")
+
+	tests := []struct {
+		fixture string
+		from    int
+	}{
+		{"v0.json", 0},
+		{"v1.json", 1},
+		{"v2.json", 2},
+		{"v3.json", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, from, err := Migrate(readFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			if from != tt.from {
+				t.Errorf("from = %d, want %d", from, tt.from)
+			}
+			if !jsonEqual(t, got, want) {
+				t.Errorf("migrated document differs from v3.json:\n%s", got)
+			}
+		})
+	}
+}
+
+// Каждый шаг по отдельности переводит фикстуру версии N в фикстуру N+1.
+func TestMigrationSteps(t *testing.T) {
+	for _, step := range migrations {
+		from, to := step.from, step.from+1
+		var doc map[string]any
+		dec := json.NewDecoder(bytes.NewReader(readFixture(t, fixtureName(from))))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			t.Fatal(err)
+		}
+		if err := step.up(doc); err != nil {
+			t.Fatalf("step %d -> %d: %v", from, to, err)
+		}
+		doc["schemaVersion"] = to
+		got, err := json.Marshal(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !jsonEqual(t, got, readFixture(t, fixtureName(to))) {
+			t.Errorf("step %d -> %d:\n%s", from, to, got)
+		}
+	}
+}
+
+func fixtureName(v int) string {
+	return fmt.Sprintf("v%d.json", v)
+}
+
+func TestMigrateSkills(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"absent", `{}`, `{"schemaVersion":3}`},
+		{"null", `{"skills":null}`, `{"schemaVersion":3,"skills":null}`},
+		{"empty", `{"skills":[]}`, `{"schemaVersion":3,"skills":[]}`},
+		{"flat", `{"skills":["Go","SQL"]}`, `{"schemaVersion":3,"skills":[{"name":"","items":["Go","SQL"]}]}`},
+		{"already grouped", `{"schemaVersion":2,"skills":[{"name":"Cloud","items":["AWS"]}]}`, `{"schemaVersion":3,"skills":[{"name":"Cloud","items":["AWS"]}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := Migrate([]byte(tt.in))
+			if err != nil {
+				t.Fatalf("Migrate: %v", err)
+			}
+			if !jsonEqual(t, got, []byte(tt.want)) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSectionTypeForTitle(t *testing.T) {
+	tests := map[string]string{
+		"Projects":        SectionProjects,
+		"  certificates ": SectionCertifications,
+		"Awards":          SectionAwards,
+		"Languages":       SectionLanguages,
+		"Hobbies":         SectionInterests,
+		"Homelab":         SectionCustom,
+		"":                SectionCustom,
+	}
+	for title, want := range tests {
+		if got := SectionTypeForTitle(title); got != want {
+			t.Errorf("SectionTypeForTitle(%q) = %q, want %q", title, got, want)
+		}
+	}
+}
+
+func TestMigrateKeepsExplicitSectionType(t *testing.T) {
+	got, _, err := Migrate([]byte(`{"schemaVersion":2,"customSections":[{"title":"Projects","type":"custom"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), `"type":"custom"`) {
+		t.Errorf("explicit type was overwritten: %s", got)
+	}
+}
+
+func TestMigrateErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		version bool   // ожидается SchemaVersionError
+		msg     string // подстрока сообщения
+	}{
+		{"too new", `{"schemaVersion":4}`, true, "unsupported schemaVersion 4"},
+		{"string", `{"schemaVersion":"2"}`, false, "schemaVersion must be an integer, got string"},
+		{"boolean", `{"schemaVersion":true}`, false, "got boolean"},
+		{"fraction", `{"schemaVersion":1.5}`, false, "non-negative integer, got 1.5"},
+		{"negative", `{"schemaVersion":-1}`, false, "non-negative integer, got -1"},
+		{"not an object", `[]`, false, "cannot unmarshal array"},
+		{"trailing data", `{} {}`, false, ErrTrailingData.Error()},
+		{"skills not array", `{"schemaVersion":1,"skills":"Go"}`, false, "migrate schemaVersion 1 -> 2: skills must be an array"},
+		{"section not object", `{"schemaVersion":2,"customSections":["x"]}`, false, "customSections[0] must be an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Migrate([]byte(tt.in))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var sve *SchemaVersionError
+			if got := errors.As(err, &sve); got != tt.version {
+				t.Errorf("SchemaVersionError = %v, want %v (%v)", got, tt.version, err)
+			}
+			if !strings.Contains(err.Error(), tt.msg) {
+				t.Errorf("error %q does not contain %q", err, tt.msg)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONUpgradesOldDocuments(t *testing.T) {
+	r, err := DecodeJSON(readFixture(t, "v0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.SchemaVersion != CurrentSchemaVersion {
+		t.Errorf("SchemaVersion = %d", r.SchemaVersion)
+	}
+	if got := SkillTexts(r.Skills); !reflect.DeepEqual(got, []string{"Go", "Kubernetes"}) {
+		t.Errorf("skills = %v", got)
+	}
+	if r.CustomSections[0].Type != SectionProjects {
+		t.Errorf("section type = %q", r.CustomSections[0].Type)
+	}
+}
@@ -16,13 +16,14 @@ type Contacts struct {
 
 // ExperienceItem описывает один блок опыта работы.
 type ExperienceItem struct {
-	Company     string   `json:"company"`
-	Position    string   `json:"position"`
-	Location    string   `json:"location"`
-	StartDate   string   `json:"startDate"` // формат YYYY-MM
-	EndDate     string   `json:"endDate"`   // формат YYYY-MM или пусто, если по настоящее время
-	Description string   `json:"description"`
-	Bullets     []string `json:"bullets"`
+	Company     string       `json:"company"`
+	Position    string       `json:"position"`
+	Location    string       `json:"location"`
+	StartDate   string       `json:"startDate"` // формат YYYY-MM
+	EndDate     string       `json:"endDate"`   // формат YYYY-MM или пусто, если по настоящее время
+	Description string       `json:"description"`
+	Bullets     []TaggedText `json:"bullets"`
+	Tags        []string     `json:"tags,omitempty"` // см. Variant
 }
 
 // EducationItem описывает одну запись об обучении.
@@ -35,11 +36,46 @@ type EducationItem struct {
 	Details     string `json:"details"`
 }
 
-// CustomSection — кастомный раздел (например, Homelab).
+// SkillGroup — группа навыков (например, «Languages» или «Cloud»).
+// Навыки группы без названия выводятся по одному, как простой список.
+type SkillGroup struct {
+	Name  string       `json:"name"`
+	Items []TaggedText `json:"items"`
+}
+
+// CustomSection — кастомный раздел (например, Homelab). Type говорит, что
+// это за раздел (см. SectionTypes); вёрстку он не меняет, но по нему раздел
+// попадает в нужное место при экспорте, например в projects JSON Resume.
 type CustomSection struct {
-	Title        string   `json:"title"`
-	BulletSymbol string   `json:"bulletSymbol"`
-	Items        []string `json:"items"`
+	Title        string       `json:"title"`
+	Type         string       `json:"type"`
+	BulletSymbol string       `json:"bulletSymbol"`
+	Items        []TaggedText `json:"items"`
+}
+
+// Типы кастомных разделов.
+const (
+	SectionCustom         = "custom"
+	SectionProjects       = "projects"
+	SectionCertifications = "certifications"
+	SectionAwards         = "awards"
+	SectionPublications   = "publications"
+	SectionLanguages      = "languages"
+	SectionVolunteering   = "volunteering"
+	SectionInterests      = "interests"
+)
+
+// SectionTypes — допустимые значения CustomSection.Type. Пустой тип
+// равнозначен SectionCustom.
+var SectionTypes = []string{
+	SectionCustom,
+	SectionProjects,
+	SectionCertifications,
+	SectionAwards,
+	SectionPublications,
+	SectionLanguages,
+	SectionVolunteering,
+	SectionInterests,
 }
 
 // Photo — опциональное фото в base64.
@@ -48,13 +84,31 @@ type Photo struct {
 	Data     string `json:"data"`
 }
 
+// DecodedSize оценивает размер фото в байтах по длине base64, не декодируя
+// его: каждые 4 символа — 3 байта, без учёта паддинга и пробелов.
+func (p *Photo) DecodedSize() int64 {
+	if p == nil {
+		return 0
+	}
+	n := 0
+	for i := 0; i < len(p.Data); i++ {
+		switch p.Data[i] {
+		case '=', ' ', '\t', '\r', '\n':
+		default:
+			n++
+		}
+	}
+	return int64(n) * 3 / 4
+}
+
 // Resume — основная доменная модель резюме.
 type Resume struct {
+	SchemaVersion  int              `json:"schemaVersion"` // см. CurrentSchemaVersion
 	FullName       string           `json:"fullName"`
 	Position       string           `json:"position"`
 	Summary        string           `json:"summary"`
 	Contacts       Contacts         `json:"contacts"`
-	Skills         []string         `json:"skills"`
+	Skills         []SkillGroup     `json:"skills"`
 	Experience     []ExperienceItem `json:"experience"`
 	Education      []EducationItem  `json:"education"`
 	CustomSections []CustomSection  `json:"customSections"`
@@ -0,0 +1,50 @@
+package resume
+
+import (
+	contract "resume_contract"
+)
+
+// Этапы рендера, которые видит пользователь (см. Progress).
+const (
+	StageValidating = "validating" // проверка тегов, варианта и данных
+	StageTemplate   = "template"   // latex-service заполняет шаблон
+	StagePhoto      = "photo"      // latex-service обрабатывает фото
+	StageCompiling  = "compiling"  // проходы pdflatex; Pass/MaxPasses заполнены, если известны
+	StageRendering  = "rendering"  // рендер в backend (HTML, Markdown, DOCX, ...)
+)
+
+// Progress — текущий этап рендера. Percent — грубая оценка готовности
+// 0..100 для индикатора, а не измеренная доля работы.
+type Progress struct {
+	Stage     string `json:"stage"`
+	Pass      int    `json:"pass,omitempty"`
+	MaxPasses int    `json:"maxPasses,omitempty"`
+	Percent   int    `json:"percent"`
+}
+
+// ProgressFunc получает этапы рендера. nil допустим и означает «не сообщать».
+type ProgressFunc func(Progress)
+
+func (f ProgressFunc) report(p Progress) {
+	if f != nil {
+		f(p)
+	}
+}
+
+// progressFromEvent переводит событие latex-service в Progress. Проходы
+// pdflatex занимают диапазон 35..95%, финальные события не переводятся.
+func progressFromEvent(ev contract.RenderEvent) (Progress, bool) {
+	switch ev.Stage {
+	case contract.StageTemplate:
+		return Progress{Stage: StageTemplate, Percent: 20}, true
+	case contract.StagePhoto:
+		return Progress{Stage: StagePhoto, Percent: 30}, true
+	case contract.StageCompile:
+		p := Progress{Stage: StageCompiling, Pass: ev.Pass, MaxPasses: ev.MaxPasses, Percent: 35}
+		if ev.MaxPasses > 0 {
+			p.Percent += 60 * min(ev.Pass, ev.MaxPasses) / ev.MaxPasses
+		}
+		return p, true
+	}
+	return Progress{}, false
+}
@@ -0,0 +1,53 @@
+package resume
+
+import (
+	"testing"
+
+	contract "resume_contract"
+)
+
+func TestProgressFromEvent(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   contract.RenderEvent
+		want Progress
+		ok   bool
+	}{
+		{"template", contract.RenderEvent{Stage: contract.StageTemplate}, Progress{Stage: StageTemplate, Percent: 20}, true},
+		{"photo", contract.RenderEvent{Stage: contract.StagePhoto}, Progress{Stage: StagePhoto, Percent: 30}, true},
+		{"pass count unknown", contract.RenderEvent{Stage: contract.StageCompile, Pass: 1},
+			Progress{Stage: StageCompiling, Pass: 1, Percent: 35}, true},
+		{"first of three passes", contract.RenderEvent{Stage: contract.StageCompile, Pass: 1, MaxPasses: 3},
+			Progress{Stage: StageCompiling, Pass: 1, MaxPasses: 3, Percent: 55}, true},
+		{"second of three passes", contract.RenderEvent{Stage: contract.StageCompile, Pass: 2, MaxPasses: 3},
+			Progress{Stage: StageCompiling, Pass: 2, MaxPasses: 3, Percent: 75}, true},
+		{"last pass", contract.RenderEvent{Stage: contract.StageCompile, Pass: 3, MaxPasses: 3},
+			Progress{Stage: StageCompiling, Pass: 3, MaxPasses: 3, Percent: 95}, true},
+		// latexmk может сделать лишний проход, процент не выходит за 95
+		{"extra pass", contract.RenderEvent{Stage: contract.StageCompile, Pass: 4, MaxPasses: 3},
+			Progress{Stage: StageCompiling, Pass: 4, MaxPasses: 3, Percent: 95}, true},
+		{"done", contract.RenderEvent{Stage: contract.StageDone}, Progress{}, false},
+		{"error", contract.RenderEvent{Stage: contract.StageError}, Progress{}, false},
+		{"unknown stage", contract.RenderEvent{Stage: "bibtex"}, Progress{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := progressFromEvent(tt.ev)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("progressFromEvent = %+v, %v, want %+v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestProgressFuncNil(t *testing.T) {
+	var f ProgressFunc
+	f.report(Progress{Stage: StageRendering})
+
+	var got []Progress
+	f = func(p Progress) { got = append(got, p) }
+	f.report(Progress{Stage: StageRendering, Percent: 50})
+	if len(got) != 1 || got[0].Stage != StageRendering {
+		t.Errorf("reported %+v", got)
+	}
+}
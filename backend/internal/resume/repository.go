@@ -0,0 +1,152 @@
+package resume
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound возвращается репозиторием, если резюме с таким id нет.
+var ErrNotFound = errors.New("resume not found")
+
+// ErrRevisionNotFound возвращается, если у резюме нет ревизии с таким номером.
+var ErrRevisionNotFound = errors.New("resume revision not found")
+
+// ErrVersionConflict — базовая ошибка оптимистичной блокировки.
+var ErrVersionConflict = errors.New("resume version conflict")
+
+// VersionConflictError возвращается при изменении резюме, если ожидаемая
+// версия не совпала с текущей (документ успели изменить параллельно).
+type VersionConflictError struct {
+	ID       string
+	Expected int
+	Current  int
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("resume %s: expected version %d, current is %d", e.ID, e.Expected, e.Current)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// StoredResume — резюме, сохранённое в репозитории. Version начинается с 1
+// и увеличивается при каждом изменении.
+type StoredResume struct {
+	ID        string    `json:"id"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Resume    Resume    `json:"resume"`
+}
+
+// UnmarshalJSON прогоняет сохранённое резюме через миграции, чтобы документы,
+// записанные прошлой версией backend, читались после изменения схемы.
+func (s *StoredResume) UnmarshalJSON(data []byte) error {
+	var env struct {
+		ID        string          `json:"id"`
+		Version   int             `json:"version"`
+		CreatedAt time.Time       `json:"createdAt"`
+		UpdatedAt time.Time       `json:"updatedAt"`
+		Resume    json.RawMessage `json:"resume"`
+	}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return err
+	}
+
+	r, err := DecodeJSON(env.Resume)
+	if err != nil {
+		return fmt.Errorf("stored resume %s: %w", env.ID, err)
+	}
+
+	*s = StoredResume{
+		ID:        env.ID,
+		Version:   env.Version,
+		CreatedAt: env.CreatedAt,
+		UpdatedAt: env.UpdatedAt,
+		Resume:    r,
+	}
+	return nil
+}
+
+// Summary — краткое описание сохранённого резюме для списка.
+type Summary struct {
+	ID        string    `json:"id"`
+	Version   int       `json:"version"`
+	FullName  string    `json:"fullName"`
+	Position  string    `json:"position"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Summarize возвращает краткое описание сохранённого резюме.
+func (s StoredResume) Summarize() Summary {
+	return Summary{
+		ID:        s.ID,
+		Version:   s.Version,
+		FullName:  s.Resume.FullName,
+		Position:  s.Resume.Position,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// RevisionInfo описывает неизменяемую ревизию резюме. Номер ревизии
+// совпадает с версией документа, которую она зафиксировала.
+type RevisionInfo struct {
+	Revision  int       `json:"revision"`
+	CreatedAt time.Time `json:"createdAt"`
+	FullName  string    `json:"fullName"`
+	Position  string    `json:"position"`
+}
+
+// RevisionInfo возвращает описание ревизии, которую образует эта версия.
+func (s StoredResume) RevisionInfo() RevisionInfo {
+	return RevisionInfo{
+		Revision:  s.Version,
+		CreatedAt: s.UpdatedAt,
+		FullName:  s.Resume.FullName,
+		Position:  s.Resume.Position,
+	}
+}
+
+// Repository хранит резюме. Update и Delete принимают версию, которую видел
+// клиент, и возвращают *VersionConflictError, если она устарела.
+// Каждая сохранённая версия остаётся доступной как неизменяемая ревизия,
+// пока резюме не удалено.
+type Repository interface {
+	List(ctx context.Context) ([]Summary, error)
+	Get(ctx context.Context, id string) (StoredResume, error)
+	Create(ctx context.Context, r Resume) (StoredResume, error)
+	Update(ctx context.Context, id string, version int, r Resume) (StoredResume, error)
+	Delete(ctx context.Context, id string, version int) error
+
+	// Revisions возвращает ревизии резюме по возрастанию номера.
+	Revisions(ctx context.Context, id string) ([]RevisionInfo, error)
+	// Revision возвращает резюме в том виде, в каком оно было в ревизии rev.
+	Revision(ctx context.Context, id string, rev int) (StoredResume, error)
+}
+
+// NewID генерирует случайный идентификатор резюме.
+func NewID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("crypto/rand: %v", err))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// ValidID проверяет, что id похож на сгенерированный NewID: это защищает
+// файловые реализации от путей вида "../x".
+func ValidID(id string) bool {
+	if len(id) != 16 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
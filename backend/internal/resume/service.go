@@ -2,8 +2,13 @@ package resume
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
+	"time"
+
+	contract "resume_contract"
 )
 
 // PDFRenderer описывает зависимость сервиса от внешнего LaTeX-сервиса.
@@ -11,23 +16,78 @@ type PDFRenderer interface {
 	RenderResume(ctx context.Context, r Resume) ([]byte, error)
 }
 
-// Service реализует бизнес-логику генерации PDF.
+// ErrRendererUnavailable — рендерер PDF недоступен: сервис не отвечает,
+// перегружен или отключён предохранителем. Проверяется через errors.Is.
+var ErrRendererUnavailable = errors.New("pdf renderer is unavailable")
+
+// UnavailableError — ошибка PDFRenderer, после которой запрос имеет смысл
+// повторить позже. RetryAfter — подсказка рендерера, через сколько (0 —
+// неизвестно).
+type UnavailableError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return "pdf renderer is unavailable: " + e.Err.Error()
+}
+
+func (e *UnavailableError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UnavailableError) Is(target error) bool {
+	return target == ErrRendererUnavailable
+}
+
+// ErrCircuitOpen — рендерер даже не пытался выполнить запрос: предохранитель
+// разомкнут после серии отказов. Приходит внутри UnavailableError; по нему
+// Service переходит на запасной рендерер (см. SetFallback).
+var ErrCircuitOpen = errors.New("pdf renderer circuit breaker is open")
+
+// ErrOutputTooLarge — готовый документ больше допустимого размера
+// (MAX_PDF_SIZE). Проверяется через errors.Is.
+var ErrOutputTooLarge = errors.New("rendered document exceeds the size limit")
+
+// ErrRequestTooLarge — резюме не помещается в запрос к рендереру
+// (MAX_RENDER_BODY_SIZE latex-service). Проверяется через errors.Is.
+var ErrRequestTooLarge = errors.New("render request exceeds the renderer size limit")
+
+// Service реализует бизнес-логику генерации PDF и других форматов.
 type Service struct {
-	renderer PDFRenderer
-	logger   *log.Logger
+	renderer  PDFRenderer
+	fallback  PDFRenderer
+	renderers map[Format]Renderer
+	logger    *slog.Logger
 }
 
-// NewService создаёт новый сервис резюме.
-func NewService(renderer PDFRenderer, logger *log.Logger) *Service {
+// NewService создаёт новый сервис резюме. renderers — дополнительные
+// форматы помимо PDF (HTML, Markdown и т.д.).
+func NewService(renderer PDFRenderer, logger *slog.Logger, renderers ...Renderer) *Service {
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
 	}
+
+	byFormat := make(map[Format]Renderer, len(renderers))
+	for _, r := range renderers {
+		byFormat[r.Format()] = r
+	}
+
 	return &Service{
-		renderer: renderer,
-		logger:   logger,
+		renderer:  renderer,
+		renderers: byFormat,
+		logger:    logger,
 	}
 }
 
+// SetFallback задаёт запасной PDF-рендерер. Он используется, только пока
+// основной отключён предохранителем (ErrCircuitOpen), — на остальные ошибки
+// клиент получает ответ основного. Документ запасного рендерера помечается
+// Output.Simplified.
+func (s *Service) SetFallback(r PDFRenderer) {
+	s.fallback = r
+}
+
 // GeneratePDF валидирует данные резюме и делегирует генерацию LaTeX-сервису.
 func (s *Service) GeneratePDF(ctx context.Context, r Resume) ([]byte, error) {
 	if err := ValidateResume(r); err != nil {
@@ -35,11 +95,239 @@ func (s *Service) GeneratePDF(ctx context.Context, r Resume) ([]byte, error) {
 		return nil, err
 	}
 
+	out, err := observeOutput(s.renderPDF(ctx, r))
+	return out.Data, err
+}
+
+// prepare проверяет теги, применяет вариант и валидирует то, что осталось:
+// лимиты (например, число записей опыта) относятся к итоговому документу,
+// а не к мастер-профилю.
+func (s *Service) prepare(r Resume, v Variant) (Resume, error) {
+	if err := ValidateTags(r); err != nil {
+		return Resume{}, err
+	}
+	r = v.Apply(r)
+	if err := ValidateResume(r); err != nil {
+		return Resume{}, err
+	}
+	return r, nil
+}
+
+func (s *Service) renderPDF(ctx context.Context, r Resume) (Output, error) {
 	pdf, err := s.renderer.RenderResume(ctx, r)
 	if err != nil {
-		s.logger.Printf("RenderResume error: %v", err)
-		return nil, fmt.Errorf("latex render failed: %w", err)
+		s.logger.ErrorContext(ctx, "render PDF failed", "error", err)
+		return s.renderFallback(ctx, r, fmt.Errorf("latex render failed: %w", err))
 	}
 
+	return Output{Format: FormatPDF, Data: pdf}, nil
+}
+
+// renderFallback рендерит PDF запасным рендерером, если основной отказал
+// из-за разомкнутого предохранителя; иначе возвращает err. Если не
+// справился и запасной, клиент получает исходную ошибку недоступности
+// с её Retry-After.
+func (s *Service) renderFallback(ctx context.Context, r Resume, err error) (Output, error) {
+	if s.fallback == nil || !errors.Is(err, ErrCircuitOpen) {
+		return Output{}, err
+	}
+
+	pdf, ferr := s.fallback.RenderResume(ctx, r)
+	if ferr != nil {
+		s.logger.ErrorContext(ctx, "fallback PDF render failed", "error", ferr)
+		return Output{}, err
+	}
+	s.logger.WarnContext(ctx, "PDF rendered with simplified fallback layout", "error", err)
+	return Output{Format: FormatPDF, Data: pdf, Simplified: true}, nil
+}
+
+// Supports сообщает, умеет ли сервис отдавать формат f.
+func (s *Service) Supports(f Format) bool {
+	if f == FormatPDF {
+		return true
+	}
+	_, ok := s.renderers[f]
+	return ok
+}
+
+// Render применяет вариант v, валидирует резюме и рендерит его в формат f.
+func (s *Service) Render(ctx context.Context, r Resume, f Format, v Variant) (Output, error) {
+	return s.RenderProgress(ctx, r, f, v, nil)
+}
+
+// PDFStreamer — необязательная возможность PDFRenderer: PDF потоком прямо
+// из ответа рендерера, без сборки документа в памяти. size — длина
+// потока в байтах, -1 — неизвестна.
+type PDFStreamer interface {
+	StreamResume(ctx context.Context, r Resume) (body io.ReadCloser, size int64, err error)
+}
+
+// RenderStream — Render, который отдаёт PDF потоком в Output.Body, если
+// рендерер это умеет (PDFStreamer). Остальные форматы и запасной PDF
+// возвращаются как обычно, в Output.Data.
+func (s *Service) RenderStream(ctx context.Context, r Resume, f Format, v Variant) (Output, error) {
+	streamer, ok := s.renderer.(PDFStreamer)
+	if f != FormatPDF || !ok {
+		return s.Render(ctx, r, f, v)
+	}
+
+	r, err := s.prepare(r, v)
+	if err != nil {
+		return Output{}, err
+	}
+
+	body, size, err := streamer.StreamResume(ctx, r)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "stream PDF failed", "error", err)
+		return observeOutput(s.renderFallback(ctx, r, fmt.Errorf("latex render failed: %w", err)))
+	}
+	return observeOutput(Output{Format: FormatPDF, Body: body, Size: size}, nil)
+}
+
+// ProgressRenderer — необязательная возможность PDFRenderer: рендер
+// с потоком событий о шаблоне, фото и проходах latexmk.
+type ProgressRenderer interface {
+	RenderResumeProgress(ctx context.Context, r Resume, report func(contract.RenderEvent)) ([]byte, error)
+}
+
+// RenderProgress — Render, который сообщает об этапах в report. Если
+// PDFRenderer не умеет ProgressRenderer, компиляция PDF видна одним этапом.
+func (s *Service) RenderProgress(ctx context.Context, r Resume, f Format, v Variant, report ProgressFunc) (Output, error) {
+	renderer, ok := s.renderers[f]
+	if !ok && f != FormatPDF {
+		return Output{}, fmt.Errorf("format %q is not supported", f)
+	}
+
+	report.report(Progress{Stage: StageValidating, Percent: 5})
+	r, err := s.prepare(r, v)
+	if err != nil {
+		return Output{}, err
+	}
+
+	if f == FormatPDF {
+		return observeOutput(s.renderPDFProgress(ctx, r, report))
+	}
+
+	report.report(Progress{Stage: StageRendering, Percent: 50})
+	data, err := renderer.Render(ctx, r)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "render failed", "format", f, "error", err)
+		return Output{}, fmt.Errorf("%s render failed: %w", f, err)
+	}
+
+	return observeOutput(Output{Format: f, Data: data}, nil)
+}
+
+func (s *Service) renderPDFProgress(ctx context.Context, r Resume, report ProgressFunc) (Output, error) {
+	pr, ok := s.renderer.(ProgressRenderer)
+	if report == nil || !ok {
+		report.report(Progress{Stage: StageCompiling, Percent: 35})
+		return s.renderPDF(ctx, r)
+	}
+
+	pdf, err := pr.RenderResumeProgress(ctx, r, func(ev contract.RenderEvent) {
+		if p, ok := progressFromEvent(ev); ok {
+			report(p)
+		}
+	})
+	if err != nil {
+		s.logger.ErrorContext(ctx, "render PDF with progress failed", "error", err)
+		return s.renderFallback(ctx, r, fmt.Errorf("latex render failed: %w", err))
+	}
+	return Output{Format: FormatPDF, Data: pdf}, nil
+}
+
+// CapacityReporter — необязательная возможность PDFRenderer: сколько PDF
+// он рендерит одновременно.
+type CapacityReporter interface {
+	Capacity(ctx context.Context) (int, error)
+}
+
+// PDFCapacity возвращает размер пула PDF-рендера или 0, если он неизвестен.
+func (s *Service) PDFCapacity(ctx context.Context) int {
+	cr, ok := s.renderer.(CapacityReporter)
+	if !ok {
+		return 0
+	}
+	n, err := cr.Capacity(ctx)
+	if err != nil {
+		s.logger.WarnContext(ctx, "latex-service capacity unknown", "error", err)
+		return 0
+	}
+	return n
+}
+
+// StatusReporter — необязательная возможность PDFRenderer: состояние
+// рендерера (узлы, предохранитель) для health-check backend.
+type StatusReporter interface {
+	RendererStatus() any
+}
+
+// RendererStatus возвращает состояние PDF-рендерера или nil, если он о нём
+// не сообщает.
+func (s *Service) RendererStatus() any {
+	if sr, ok := s.renderer.(StatusReporter); ok {
+		return sr.RendererStatus()
+	}
+	return nil
+}
+
+// ATSChecker — необязательная возможность PDFRenderer: рендер с проверкой
+// извлекаемости текста так, как его увидит ATS.
+type ATSChecker interface {
+	CheckATS(ctx context.Context, r Resume) (contract.ATSReport, []byte, error)
+}
+
+// ErrATSNotSupported возвращается, если PDFRenderer не умеет ATS-проверку.
+var ErrATSNotSupported = errors.New("ats check is not supported by the renderer")
+
+// CheckATS применяет вариант v, валидирует резюме, рендерит PDF и
+// возвращает его вместе с отчётом о том, какие поля извлекаются из него
+// как обычный текст.
+func (s *Service) CheckATS(ctx context.Context, r Resume, v Variant) (contract.ATSReport, []byte, error) {
+	checker, ok := s.renderer.(ATSChecker)
+	if !ok {
+		return contract.ATSReport{}, nil, ErrATSNotSupported
+	}
+
+	r, err := s.prepare(r, v)
+	if err != nil {
+		return contract.ATSReport{}, nil, err
+	}
+
+	report, pdf, err := checker.CheckATS(ctx, r)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "ATS check failed", "error", err)
+		return contract.ATSReport{}, nil, fmt.Errorf("ats check failed: %w", err)
+	}
+	// latex-service видит навыки плоским списком строк
+	for i := range report.Fields {
+		report.Fields[i].Field = SkillSourcePath(r.Skills, report.Fields[i].Field)
+	}
+
+	return report, pdf, nil
+}
+
+// DiffRenderer — необязательная возможность PDFRenderer: рендер визуального
+// сравнения двух версий резюме.
+type DiffRenderer interface {
+	RenderDiff(ctx context.Context, doc contract.DiffDocument) ([]byte, error)
+}
+
+// ErrDiffNotSupported возвращается, если PDFRenderer не умеет рендерить diff.
+var ErrDiffNotSupported = errors.New("diff rendering is not supported by the renderer")
+
+// RenderDiff рендерит визуальный diff в PDF.
+func (s *Service) RenderDiff(ctx context.Context, doc contract.DiffDocument) ([]byte, error) {
+	renderer, ok := s.renderer.(DiffRenderer)
+	if !ok {
+		return nil, ErrDiffNotSupported
+	}
+
+	pdf, err := renderer.RenderDiff(ctx, doc)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "render diff PDF failed", "error", err)
+		return nil, fmt.Errorf("diff render failed: %w", err)
+	}
 	return pdf, nil
 }
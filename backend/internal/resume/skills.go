@@ -0,0 +1,105 @@
+package resume
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SkillTexts возвращает навыки всех групп подряд, без названий групп.
+func SkillTexts(groups []SkillGroup) []string {
+	var out []string
+	for _, g := range groups {
+		out = append(out, Texts(g.Items)...)
+	}
+	return out
+}
+
+// SkillCount — число навыков во всех группах.
+func SkillCount(groups []SkillGroup) int {
+	n := 0
+	for _, g := range groups {
+		n += len(g.Items)
+	}
+	return n
+}
+
+// SkillLines раскладывает навыки в строки для вывода: навыки группы без
+// названия — по одному в строке, группа с названием — одной строкой
+// «Cloud: AWS, Kubernetes». Пустые навыки и группы пропускаются.
+func SkillLines(groups []SkillGroup) []string {
+	lines, _ := skillLines(groups)
+	return lines
+}
+
+// skillLines — SkillLines вместе с путём каждой строки в исходном резюме:
+// skills[i].items[j] у навыка группы без названия, skills[i] у группы
+// с названием.
+func skillLines(groups []SkillGroup) (lines, paths []string) {
+	for i, g := range groups {
+		var items, itemPaths []string
+		for j, t := range Texts(g.Items) {
+			if t = strings.TrimSpace(t); t != "" {
+				items = append(items, t)
+				itemPaths = append(itemPaths, fmt.Sprintf("skills[%d].items[%d]", i, j))
+			}
+		}
+		name := strings.TrimSpace(g.Name)
+		switch {
+		case len(items) == 0:
+		case name == "":
+			lines = append(lines, items...)
+			paths = append(paths, itemPaths...)
+		default:
+			lines = append(lines, name+": "+strings.Join(items, ", "))
+			paths = append(paths, fmt.Sprintf("skills[%d]", i))
+		}
+	}
+	return lines, paths
+}
+
+// skillLineRe — путь строки навыков в контракте latex-service.
+var skillLineRe = regexp.MustCompile(`^skills\[(\d+)\]$`)
+
+// SkillSourcePath переводит путь skills[k] из ответа latex-service, где k —
+// номер строки SkillLines, в путь исходного резюме. Остальные пути
+// возвращаются как есть.
+func SkillSourcePath(groups []SkillGroup, path string) string {
+	m := skillLineRe.FindStringSubmatch(path)
+	if m == nil {
+		return path
+	}
+	k, _ := strconv.Atoi(m[1])
+	if _, paths := skillLines(groups); k < len(paths) {
+		return paths[k]
+	}
+	return path
+}
+
+// Ungrouped собирает навыки в одну группу без названия.
+func Ungrouped(texts []string) []SkillGroup {
+	if len(texts) == 0 {
+		return nil
+	}
+	return []SkillGroup{{Items: Untagged(texts)}}
+}
+
+// NormalizeSectionType возвращает тип раздела в нижнем регистре; пустой
+// тип — SectionCustom.
+func NormalizeSectionType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+	if t == "" {
+		return SectionCustom
+	}
+	return t
+}
+
+func validSectionType(t string) bool {
+	for _, st := range SectionTypes {
+		if st == NormalizeSectionType(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package resume
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSkillLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		groups []SkillGroup
+		want   []string
+	}{
+		{"none", nil, nil},
+		{"unnamed", []SkillGroup{{Items: Untagged([]string{"Go", " ", "SQL"})}}, []string{"Go", "SQL"}},
+		{"named", []SkillGroup{{Name: "Cloud", Items: Untagged([]string{"AWS", "GCP"})}}, []string{"Cloud: AWS, GCP"}},
+		{"empty named group", []SkillGroup{{Name: "Cloud"}}, nil},
+		{"mixed", []SkillGroup{
+			{Items: Untagged([]string{"Go"})},
+			{Name: " Data ", Items: Untagged([]string{"PostgreSQL"})},
+		}, []string{"Go", "Data: PostgreSQL"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SkillLines(tt.groups); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SkillLines = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkillSourcePath(t *testing.T) {
+	groups := []SkillGroup{
+		{Items: Untagged([]string{"Go", " ", "SQL"})},
+		{Name: "Empty"},
+		{Name: "Cloud", Items: Untagged([]string{"AWS", "GCP"})},
+		{Items: Untagged([]string{"Docker"})},
+	}
+	// строки SkillLines: Go, SQL, "Cloud: AWS, GCP", Docker
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"skills[0]", "skills[0].items[0]"},
+		{"skills[1]", "skills[0].items[2]"},
+		{"skills[2]", "skills[2]"},
+		{"skills[3]", "skills[3].items[0]"},
+		{"skills[4]", "skills[4]"},
+		{"fullName", "fullName"},
+		{"experience[0].bullets[1]", "experience[0].bullets[1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := SkillSourcePath(groups, tt.path); got != tt.want {
+				t.Errorf("SkillSourcePath = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVariantFiltersSkillGroups(t *testing.T) {
+	r := Resume{Skills: []SkillGroup{
+		{Items: []TaggedText{{Text: "Go"}, {Text: "Terraform", Tags: []string{"sre"}}}},
+		{Name: "Management", Items: []TaggedText{{Text: "Hiring", Tags: []string{"lead"}}}},
+	}}
+	v, err := ParseVariant("sre")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := v.Apply(r).Skills
+	if len(got) != 1 {
+		t.Fatalf("groups = %+v, want only the unnamed group", got)
+	}
+	if texts := Texts(got[0].Items); !reflect.DeepEqual(texts, []string{"Go", "Terraform"}) {
+		t.Errorf("skills = %v", texts)
+	}
+}
+
+func TestValidateSkillGroupsAndSectionTypes(t *testing.T) {
+	r := Resume{
+		FullName: "John Doe",
+		Position: "Engineer",
+		Summary:  "Summary",
+		Skills: []SkillGroup{
+			{Name: "Languages", Items: Untagged([]string{"Go", "a very long skill name that is definitely over fifty characters"})},
+		},
+		CustomSections: []CustomSection{
+			{Title: "Projects", Type: "Projects"},
+			{Title: "Misc", Type: "blog"},
+		},
+	}
+
+	err := ValidateResume(r)
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("err = %v, want *ValidationError", err)
+	}
+	var fields []string
+	for _, fe := range ve.Errors {
+		fields = append(fields, fe.Field)
+	}
+	want := []string{"skills[0].items[1]", "customSections[1].type"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
@@ -0,0 +1,59 @@
+package resume
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// TaggedText — строка резюме (навык, пункт опыта, элемент кастомного раздела)
+// с необязательными тегами для вариантов. В JSON записывается либо обычной
+// строкой, либо объектом {"text": "...", "tags": ["sre"]}; без тегов
+// сериализуется обратно в строку, так что документы без тегов не меняются.
+type TaggedText struct {
+	Text string   `json:"text"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Texts возвращает тексты элементов без тегов.
+func Texts(items []TaggedText) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.Text
+	}
+	return out
+}
+
+// Untagged превращает строки в элементы без тегов.
+func Untagged(texts []string) []TaggedText {
+	out := make([]TaggedText, len(texts))
+	for i, t := range texts {
+		out[i] = TaggedText{Text: t}
+	}
+	return out
+}
+
+func (t *TaggedText) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		*t = TaggedText{}
+		return json.Unmarshal(data, &t.Text)
+	}
+
+	// отдельный тип без методов, чтобы не уйти в рекурсию
+	type plain TaggedText
+	var p plain
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return err
+	}
+	*t = TaggedText(p)
+	return nil
+}
+
+func (t TaggedText) MarshalJSON() ([]byte, error) {
+	if len(t.Tags) == 0 {
+		return json.Marshal(t.Text)
+	}
+	type plain TaggedText
+	return json.Marshal(plain(t))
+}
@@ -32,6 +32,29 @@ func (e *ValidationError) Empty() bool {
 	return len(e.Errors) == 0
 }
 
+// PhotoTooLargeError — фото больше допустимого размера. Size — оценка
+// размера после декодирования base64 (см. Photo.DecodedSize).
+type PhotoTooLargeError struct {
+	Size int64
+	Max  int64
+}
+
+func (e *PhotoTooLargeError) Error() string {
+	return fmt.Sprintf("photo is too large (%d bytes, max %d)", e.Size, e.Max)
+}
+
+// CheckPhotoSize проверяет размер фото до декодирования base64, чтобы
+// слишком большое фото отклонялось сразу. max <= 0 — без ограничения.
+func CheckPhotoSize(r Resume, max int64) error {
+	if max <= 0 {
+		return nil
+	}
+	if size := r.Photo.DecodedSize(); size > max {
+		return &PhotoTooLargeError{Size: size, Max: max}
+	}
+	return nil
+}
+
 var emailRe = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
 
 // ValidateResume выполняет базовую валидацию резюме.
@@ -61,12 +84,20 @@ func ValidateResume(r Resume) error {
 
 	validateContacts(r.Contacts, &ve)
 
-	if len(r.Skills) > 50 {
+	if SkillCount(r.Skills) > 50 {
 		ve.Add("skills", "Too many skills (max 50)")
 	}
-	for i, skill := range r.Skills {
-		if len(skill) > 50 {
-			ve.Add(fmt.Sprintf("skills[%d]", i), "Skill is too long (max 50 characters)")
+	if len(r.Skills) > 10 {
+		ve.Add("skills", "Too many skill groups (max 10)")
+	}
+	for i, g := range r.Skills {
+		if len(g.Name) > 50 {
+			ve.Add(fmt.Sprintf("skills[%d].name", i), "Skill group name is too long (max 50 characters)")
+		}
+		for j, skill := range g.Items {
+			if len(skill.Text) > 50 {
+				ve.Add(fmt.Sprintf("skills[%d].items[%d]", i, j), "Skill is too long (max 50 characters)")
+			}
 		}
 	}
 
@@ -79,9 +110,14 @@ func ValidateResume(r Resume) error {
 	if len(r.CustomSections) > 10 {
 		ve.Add("customSections", "Too many custom sections (max 10)")
 	}
+	for i, cs := range r.CustomSections {
+		if !validSectionType(cs.Type) {
+			ve.Add(fmt.Sprintf("customSections[%d].type", i), "Unknown section type (expected one of: "+strings.Join(SectionTypes, ", ")+")")
+		}
+	}
 
 	if !ve.Empty() {
-		return &ve
+		return observeValidation(&ve)
 	}
 	return nil
 }
@@ -0,0 +1,207 @@
+package resume
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tagRe — допустимый тег: буквы, цифры, '_' и '-'.
+var tagRe = regexp.MustCompile(`^[\pL\pN_-]+$`)
+
+const maxTagLength = 32
+
+// Variant отбирает из «мастер»-резюме записи по тегам. Выражение — список
+// термов через запятую:
+//
+//	backend          — включить записи с тегом backend
+//	backend+go       — включить записи, у которых есть оба тега
+//	!management      — исключить записи с тегом management (также -management)
+//
+// Записи без тегов остаются всегда. Запись с тегами остаётся, если у неё нет
+// исключённых тегов и (при наличии включающих термов) она подходит хотя бы
+// под один из них. Пустой Variant ничего не отбрасывает.
+type Variant struct {
+	include [][]string
+	exclude []string
+	expr    string
+}
+
+// ParseVariant разбирает выражение варианта.
+func ParseVariant(expr string) (Variant, error) {
+	v := Variant{expr: strings.TrimSpace(expr)}
+	if v.expr == "" {
+		return v, nil
+	}
+
+	for _, term := range strings.Split(v.expr, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		if strings.HasPrefix(term, "!") || strings.HasPrefix(term, "-") {
+			tag := normalizeTag(term[1:])
+			if !validTag(tag) {
+				return Variant{}, fmt.Errorf("invalid tag %q in variant", term[1:])
+			}
+			v.exclude = append(v.exclude, tag)
+			continue
+		}
+
+		var all []string
+		for _, tag := range strings.Split(term, "+") {
+			tag = normalizeTag(tag)
+			if !validTag(tag) {
+				return Variant{}, fmt.Errorf("invalid tag %q in variant", tag)
+			}
+			all = append(all, tag)
+		}
+		v.include = append(v.include, all)
+	}
+
+	return v, nil
+}
+
+// IsZero сообщает, что вариант ничего не фильтрует.
+func (v Variant) IsZero() bool {
+	return len(v.include) == 0 && len(v.exclude) == 0
+}
+
+func (v Variant) String() string {
+	return v.expr
+}
+
+// keep решает, остаётся ли запись с тегами tags.
+func (v Variant) keep(tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	set := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		set[normalizeTag(t)] = true
+	}
+
+	for _, t := range v.exclude {
+		if set[t] {
+			return false
+		}
+	}
+	if len(v.include) == 0 {
+		return true
+	}
+
+	for _, all := range v.include {
+		ok := true
+		for _, t := range all {
+			if !set[t] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (v Variant) filter(items []TaggedText) []TaggedText {
+	if items == nil {
+		return nil
+	}
+	out := make([]TaggedText, 0, len(items))
+	for _, item := range items {
+		if v.keep(item.Tags) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// Apply возвращает копию резюме, в которой оставлены только подходящие под
+// вариант записи опыта, пункты, навыки и элементы кастомных разделов.
+// Группа навыков или кастомный раздел, у которых не осталось элементов,
+// удаляются целиком.
+func (v Variant) Apply(r Resume) Resume {
+	if v.IsZero() {
+		return r
+	}
+
+	out := r
+	out.Skills = nil
+	for _, g := range r.Skills {
+		items := v.filter(g.Items)
+		if len(g.Items) > 0 && len(items) == 0 {
+			continue
+		}
+		g.Items = items
+		out.Skills = append(out.Skills, g)
+	}
+
+	out.Experience = nil
+	for _, e := range r.Experience {
+		if !v.keep(e.Tags) {
+			continue
+		}
+		e.Bullets = v.filter(e.Bullets)
+		out.Experience = append(out.Experience, e)
+	}
+
+	out.CustomSections = nil
+	for _, cs := range r.CustomSections {
+		items := v.filter(cs.Items)
+		if len(cs.Items) > 0 && len(items) == 0 {
+			continue
+		}
+		cs.Items = items
+		out.CustomSections = append(out.CustomSections, cs)
+	}
+
+	return out
+}
+
+func normalizeTag(t string) string {
+	return strings.ToLower(strings.TrimSpace(t))
+}
+
+func validTag(t string) bool {
+	return len(t) <= maxTagLength && tagRe.MatchString(t)
+}
+
+// ValidateTags проверяет формат тегов во всём документе. Вызывается до
+// применения варианта, чтобы ошибки в отброшенных записях тоже были видны.
+func ValidateTags(r Resume) error {
+	var ve ValidationError
+
+	check := func(field string, tags []string) {
+		for i, t := range tags {
+			if !validTag(normalizeTag(t)) {
+				ve.Add(fmt.Sprintf("%s.tags[%d]", field, i), fmt.Sprintf("Tag must consist of letters, digits, '_' or '-' (max %d characters)", maxTagLength))
+			}
+		}
+	}
+
+	for i, g := range r.Skills {
+		for j, s := range g.Items {
+			check(fmt.Sprintf("skills[%d].items[%d]", i, j), s.Tags)
+		}
+	}
+	for i, e := range r.Experience {
+		check(fmt.Sprintf("experience[%d]", i), e.Tags)
+		for j, b := range e.Bullets {
+			check(fmt.Sprintf("experience[%d].bullets[%d]", i, j), b.Tags)
+		}
+	}
+	for i, cs := range r.CustomSections {
+		for j, item := range cs.Items {
+			check(fmt.Sprintf("customSections[%d].items[%d]", i, j), item.Tags)
+		}
+	}
+
+	if !ve.Empty() {
+		return observeValidation(&ve)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package resumecodec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+var (
+	resumeType     = reflect.TypeOf(resume.Resume{})
+	taggedTextType = reflect.TypeOf(resume.TaggedText{})
+)
+
+// checkResume строго сверяет дерево JSON-значений со структурой resume.Resume:
+// неизвестные поля и несовпадения типов возвращаются списком проблем.
+func checkResume(tree any) []Problem {
+	var problems []Problem
+	checkValue(tree, resumeType, "", &problems)
+	return problems
+}
+
+func checkValue(v any, t reflect.Type, path string, problems *[]Problem) {
+	if v == nil {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		checkValue(v, t.Elem(), path, problems)
+
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if t == taggedTextType && !ok {
+			// TaggedText допускает краткую запись обычной строкой
+			if _, isString := v.(string); !isString {
+				addTypeProblem(problems, path, "a string or an object", v)
+			}
+			return
+		}
+		if !ok {
+			addTypeProblem(problems, path, "an object", v)
+			return
+		}
+		fields := jsonFields(t)
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			ft, ok := fields[k]
+			if !ok {
+				*problems = append(*problems, Problem{
+					Field:   joinPath(path, k),
+					Message: fmt.Sprintf("unknown field %q", k),
+				})
+				continue
+			}
+			checkValue(obj[k], ft, joinPath(path, k), problems)
+		}
+
+	case reflect.Slice:
+		arr, ok := v.([]any)
+		if !ok {
+			addTypeProblem(problems, path, "a list", v)
+			return
+		}
+		for i, item := range arr {
+			checkValue(item, t.Elem(), indexPath(path, i), problems)
+		}
+
+	case reflect.String:
+		if _, ok := v.(string); !ok {
+			addTypeProblem(problems, path, "a string", v)
+		}
+
+	case reflect.Int, reflect.Int64:
+		n, ok := v.(json.Number)
+		if !ok {
+			addTypeProblem(problems, path, "an integer", v)
+			return
+		}
+		if _, err := n.Int64(); err != nil {
+			addTypeProblem(problems, path, "an integer", v)
+		}
+
+	case reflect.Bool:
+		if _, ok := v.(bool); !ok {
+			addTypeProblem(problems, path, "a boolean", v)
+		}
+	}
+}
+
+func addTypeProblem(problems *[]Problem, path, want string, got any) {
+	*problems = append(*problems, Problem{
+		Field:   path,
+		Message: fmt.Sprintf("expected %s, got %s", want, describe(got)),
+	})
+}
+
+func describe(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "a list"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// jsonFields возвращает поля структуры по их JSON-именам.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
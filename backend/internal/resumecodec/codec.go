@@ -0,0 +1,155 @@
+// Package resumecodec декодирует резюме из текстовых форматов, которые удобно
+// править руками (YAML, TOML), в resume.Resume.
+//
+// Документ сначала переводится в нейтральное дерево JSON-значений с позициями
+// ключей, затем проходит через ту же цепочку миграций, что и JSON (resume.Migrate),
+// строго сверяется со структурой resume.Resume и только после этого декодируется.
+// Так ошибки вида «неизвестное поле» сообщают строку и колонку исходного файла.
+package resumecodec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"resume_backend/internal/resume"
+)
+
+// Поддерживаемые форматы.
+const (
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// Position — позиция в исходном тексте (нумерация с 1).
+type Position struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Problem описывает одну ошибку документа. Field и Message совместимы
+// с resume.FieldError, Line/Column равны нулю, если позиция неизвестна.
+type Problem struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// DocumentError агрегирует синтаксические и структурные ошибки документа.
+type DocumentError struct {
+	Format   string
+	Problems []Problem
+}
+
+func (e *DocumentError) Error() string {
+	parts := make([]string, 0, len(e.Problems))
+	for _, p := range e.Problems {
+		var b strings.Builder
+		switch {
+		case p.Line > 0 && p.Column > 0:
+			fmt.Fprintf(&b, "line %d, column %d: ", p.Line, p.Column)
+		case p.Line > 0:
+			fmt.Fprintf(&b, "line %d: ", p.Line)
+		}
+		if p.Field != "" {
+			b.WriteString(p.Field + ": ")
+		}
+		b.WriteString(p.Message)
+		parts = append(parts, b.String())
+	}
+	return fmt.Sprintf("invalid %s document: %s", e.Format, strings.Join(parts, "; "))
+}
+
+// positions сопоставляет путь поля (в нотации resume.FieldError, например
+// "contacts.links[0].url") с позицией ключа в исходном тексте.
+type positions map[string]Position
+
+// FormatForMediaType возвращает формат по MIME-типу запроса или "" для неподдерживаемых.
+func FormatForMediaType(mt string) string {
+	switch mt {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FormatYAML
+	case "application/toml", "text/toml":
+		return FormatTOML
+	}
+	return ""
+}
+
+// Decode декодирует документ указанного формата в resume.Resume.
+func Decode(format string, raw []byte) (resume.Resume, error) {
+	var (
+		doc map[string]any
+		pos positions
+		err error
+	)
+
+	switch format {
+	case FormatYAML:
+		doc, pos, err = parseYAML(raw)
+	case FormatTOML:
+		doc, pos, err = parseTOML(raw)
+	default:
+		return resume.Resume{}, fmt.Errorf("unsupported format %q", format)
+	}
+	if err != nil {
+		return resume.Resume{}, err
+	}
+
+	asJSON, err := json.Marshal(doc)
+	if err != nil {
+		return resume.Resume{}, fmt.Errorf("convert %s to json: %w", format, err)
+	}
+
+	upgraded, _, err := resume.Migrate(asJSON)
+	if err != nil {
+		return resume.Resume{}, err
+	}
+
+	var tree any
+	dec := json.NewDecoder(bytes.NewReader(upgraded))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return resume.Resume{}, fmt.Errorf("decode migrated %s: %w", format, err)
+	}
+
+	if problems := checkResume(tree); len(problems) > 0 {
+		for i := range problems {
+			if p, ok := lookupPosition(pos, problems[i].Field); ok {
+				problems[i].Line = p.Line
+				problems[i].Column = p.Column
+			}
+		}
+		return resume.Resume{}, &DocumentError{Format: format, Problems: problems}
+	}
+
+	return resume.DecodeJSON(upgraded)
+}
+
+// lookupPosition ищет позицию поля, а если её нет (например, значение внутри
+// inline-таблицы TOML) — позицию ближайшего родителя.
+func lookupPosition(pos positions, path string) (Position, bool) {
+	for path != "" {
+		if p, ok := pos[path]; ok {
+			return p, true
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return Position{}, false
+}
+
+func joinPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+func indexPath(parent string, i int) string {
+	return fmt.Sprintf("%s[%d]", parent, i)
+}
@@ -0,0 +1,157 @@
+package resumecodec
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		doc    string
+		want   Problem
+	}{
+		{
+			name:   "yaml unknown field",
+			format: FormatYAML,
+			doc:    "fullName: Ivan\ncontacts:\n  emial: ivan@example.com\n",
+			want:   Problem{Field: "contacts.emial", Message: `unknown field "emial"`, Line: 3, Column: 3},
+		},
+		{
+			name:   "yaml unknown field in a list",
+			format: FormatYAML,
+			doc:    "fullName: Ivan\nexperience:\n  - company: A\n  - company: B\n    titel: Dev\n",
+			want:   Problem{Field: "experience[1].titel", Message: `unknown field "titel"`, Line: 5, Column: 5},
+		},
+		{
+			name:   "yaml type mismatch",
+			format: FormatYAML,
+			doc:    "fullName: Ivan\nposition: [Dev]\n",
+			want:   Problem{Field: "position", Message: "expected a string, got a list", Line: 2, Column: 1},
+		},
+		{
+			name:   "yaml several documents",
+			format: FormatYAML,
+			doc:    "fullName: Ivan\n---\nfullName: Petr\n",
+			want:   Problem{Message: "expected a single YAML document", Line: 2, Column: 1},
+		},
+		{
+			name:   "yaml syntax error",
+			format: FormatYAML,
+			doc:    "fullName: Ivan\n  position: Dev\n",
+			want:   Problem{Message: "mapping values are not allowed in this context", Line: 2},
+		},
+		{
+			name:   "yaml alias cycle",
+			format: FormatYAML,
+			doc:    "fullName: &x [*x]",
+			want:   Problem{Field: "fullName[0]", Message: "alias *x refers to a value that contains it", Line: 1, Column: 15},
+		},
+		{
+			name:   "toml unknown field",
+			format: FormatTOML,
+			doc:    "fullName = \"Ivan\"\n\n[contacts]\nemial = \"ivan@example.com\"\n",
+			want:   Problem{Field: "contacts.emial", Message: `unknown field "emial"`, Line: 4, Column: 1},
+		},
+		{
+			name:   "toml unknown field in an array of tables",
+			format: FormatTOML,
+			doc:    "[[experience]]\ncompany = \"A\"\n\n[[experience]]\n  company = \"B\"\n  titel = \"Dev\"\n",
+			want:   Problem{Field: "experience[1].titel", Message: `unknown field "titel"`, Line: 6, Column: 3},
+		},
+		{
+			name:   "toml after a multiline string",
+			format: FormatTOML,
+			doc:    "summary = \"\"\"\n[[experience]]\nkey = \"\"\"\n\n[[experience]]\ncompany = \"A\"\ntitel = \"Dev\"\n",
+			want:   Problem{Field: "experience[0].titel", Message: `unknown field "titel"`, Line: 7, Column: 1},
+		},
+		{
+			name:   "toml after a multiline literal string in an array",
+			format: FormatTOML,
+			doc:    "[[experience]]\nbullets = ['''\n]\n[contacts]\n''']\n\n[contacts]\nemial = \"x\"\n",
+			want:   Problem{Field: "contacts.emial", Message: `unknown field "emial"`, Line: 8, Column: 1},
+		},
+		{
+			name:   "toml inline table",
+			format: FormatTOML,
+			doc:    "fullName = \"Ivan\"\ncontacts = { emial = \"x\" }\n",
+			want:   Problem{Field: "contacts.emial", Message: `unknown field "emial"`, Line: 2, Column: 1},
+		},
+		{
+			name:   "toml type mismatch",
+			format: FormatTOML,
+			doc:    "fullName = 1\n",
+			want:   Problem{Field: "fullName", Message: "expected a string, got a number", Line: 1, Column: 1},
+		},
+		{
+			name:   "toml syntax error",
+			format: FormatTOML,
+			doc:    "fullName = \"Ivan\"\nposition = \n",
+			want:   Problem{Message: "expected value but found '\\n' instead", Line: 2, Column: 12},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.format, []byte(tt.doc))
+			var de *DocumentError
+			if !errors.As(err, &de) {
+				t.Fatalf("err = %v, want *DocumentError", err)
+			}
+			if len(de.Problems) != 1 || de.Problems[0] != tt.want {
+				t.Errorf("problems = %+v, want [%+v]", de.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		doc    string
+	}{
+		{"yaml", FormatYAML, "schemaVersion: 3\nfullName: Ivan\nposition: &p Dev\nsummary: *p\nskills:\n  - name: Go\n    items: [gRPC]\n"},
+		{"toml", FormatTOML, "schemaVersion = 3\nfullName = \"Ivan\"\nposition = \"Dev\"\nsummary = \"\"\"\nDev\"\"\"\n\n[[skills]]\nname = \"Go\"\nitems = [\"gRPC\"]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Decode(tt.format, []byte(tt.doc))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.FullName != "Ivan" || r.Position != "Dev" || r.Summary != "Dev" {
+				t.Errorf("resume = %+v", r)
+			}
+			if len(r.Skills) != 1 || r.Skills[0].Name != "Go" || len(r.Skills[0].Items) != 1 {
+				t.Errorf("skills = %+v", r.Skills)
+			}
+		})
+	}
+}
+
+func TestDecodeLimitsAliasExpansion(t *testing.T) {
+	// каждый уровень раскрывает предыдущий десять раз
+	var b strings.Builder
+	b.WriteString("a0: &a0 [lol, lol, lol, lol, lol, lol, lol, lol, lol, lol]\n")
+	for i := 1; i <= 9; i++ {
+		prev := "*a" + string(rune('0'+i-1))
+		b.WriteString("a" + string(rune('0'+i)) + ": &a" + string(rune('0'+i)) + " [" + strings.Repeat(prev+", ", 9) + prev + "]\n")
+	}
+	b.WriteString("fullName: Ivan\n")
+
+	start := time.Now()
+	_, err := Decode(FormatYAML, []byte(b.String()))
+	var de *DocumentError
+	if !errors.As(err, &de) || !strings.Contains(de.Problems[0].Message, "too large") {
+		t.Fatalf("err = %v, want the alias expansion limit", err)
+	}
+	if de.Problems[0].Line == 0 {
+		t.Error("alias expansion error has no position")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Decode took %v", d)
+	}
+}
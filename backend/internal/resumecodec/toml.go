@@ -0,0 +1,244 @@
+package resumecodec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// parseTOML разбирает TOML в дерево JSON-значений. Парсер не отдаёт позиции
+// ключей, поэтому они восстанавливаются отдельным построчным проходом.
+func parseTOML(raw []byte) (map[string]any, positions, error) {
+	var doc map[string]any
+	if _, err := toml.Decode(string(raw), &doc); err != nil {
+		var pe toml.ParseError
+		if errors.As(err, &pe) {
+			return nil, nil, &DocumentError{Format: FormatTOML, Problems: []Problem{{
+				Message: pe.Message,
+				Line:    pe.Position.Line,
+				Column:  pe.Position.Col,
+			}}}
+		}
+		return nil, nil, &DocumentError{Format: FormatTOML, Problems: []Problem{{Message: err.Error()}}}
+	}
+
+	val, err := tomlValue(doc)
+	if err != nil {
+		return nil, nil, err
+	}
+	return val.(map[string]any), tomlPositions(string(raw)), nil
+}
+
+// tomlValue приводит значения BurntSushi/toml к JSON-совместимым типам.
+func tomlValue(v any) (any, error) {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			conv, err := tomlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = conv
+		}
+		return out, nil
+	case []map[string]any:
+		out := make([]any, 0, len(val))
+		for _, item := range val {
+			conv, err := tomlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, conv)
+		}
+		return out, nil
+	case []any:
+		out := make([]any, 0, len(val))
+		for _, item := range val {
+			conv, err := tomlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, conv)
+		}
+		return out, nil
+	case int64:
+		return json.Number(strconv.FormatInt(val, 10)), nil
+	case float64:
+		return json.Number(strconv.FormatFloat(val, 'g', -1, 64)), nil
+	case time.Time:
+		// Даты модель хранит строками; локальная дата TOML (2020-01-15)
+		// превращается в ту же строку.
+		if val.Location().String() == "date-local" {
+			return val.Format("2006-01-02"), nil
+		}
+		return val.Format(time.RFC3339), nil
+	case string, bool:
+		return val, nil
+	}
+	return nil, fmt.Errorf("unsupported TOML value of type %T", v)
+}
+
+// tomlPositions построчно находит заголовки таблиц ([a], [[a]]) и ключи
+// (key = value) и сопоставляет их пути с позициями. Значения внутри
+// многострочных массивов и inline-таблиц получают позицию родительского ключа,
+// строки внутри многострочных строк в тройных кавычках пропускаются.
+func tomlPositions(src string) positions {
+	pos := make(positions)
+	arrayIdx := make(map[string]int) // путь массива таблиц -> число элементов
+	table := ""
+	depth := 0
+	var sc tomlScanner
+
+	resolve := func(name string) string {
+		path := ""
+		for _, part := range splitTOMLKey(name) {
+			path = joinPath(path, part)
+			if n, ok := arrayIdx[path]; ok {
+				path = indexPath(path, n-1)
+			}
+		}
+		return path
+	}
+
+	for i, line := range strings.Split(src, "\n") {
+		lineNo := i + 1
+		trimmed := strings.TrimSpace(line)
+		col := len(line) - len(strings.TrimLeft(line, " \t")) + 1
+
+		if depth > 0 || sc.multi != "" {
+			depth += sc.scan(line)
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "[["):
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(stripComment(trimmed), "[["), "]]"))
+			parent := ""
+			parts := splitTOMLKey(name)
+			if len(parts) > 1 {
+				parent = resolve(strings.Join(parts[:len(parts)-1], "."))
+			}
+			arrPath := joinPath(parent, parts[len(parts)-1])
+			if _, ok := pos[arrPath]; !ok {
+				pos[arrPath] = Position{Line: lineNo, Column: col}
+			}
+			arrayIdx[arrPath]++
+			table = indexPath(arrPath, arrayIdx[arrPath]-1)
+			pos[table] = Position{Line: lineNo, Column: col}
+
+		case strings.HasPrefix(trimmed, "["):
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(stripComment(trimmed), "["), "]"))
+			table = resolve(name)
+			pos[table] = Position{Line: lineNo, Column: col}
+
+		default:
+			key, rest, ok := strings.Cut(trimmed, "=")
+			if !ok {
+				continue
+			}
+			path := table
+			for _, part := range splitTOMLKey(strings.TrimSpace(key)) {
+				path = joinPath(path, part)
+			}
+			pos[path] = Position{Line: lineNo, Column: col}
+			depth = sc.scan(rest)
+			if depth < 0 {
+				depth = 0
+			}
+		}
+	}
+
+	return pos
+}
+
+// splitTOMLKey делит ключ по точкам с учётом кавычек: a."b.c" -> [a, b.c].
+func splitTOMLKey(key string) []string {
+	var (
+		parts []string
+		cur   strings.Builder
+		quote rune
+	)
+	for _, r := range key {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+				continue
+			}
+			cur.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '.':
+			parts = append(parts, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	return append(parts, strings.TrimSpace(cur.String()))
+}
+
+// tomlScanner считает баланс скобок [] и {} вне строк и комментариев и
+// помнит незакрытую многострочную строку между вызовами scan.
+type tomlScanner struct {
+	multi string // открывающий разделитель многострочной строки или ""
+}
+
+func (sc *tomlScanner) scan(s string) int {
+	delta := 0
+	for i := 0; i < len(s); i++ {
+		if sc.multi != "" {
+			end := strings.Index(s[i:], sc.multi)
+			if end < 0 {
+				return delta
+			}
+			// """a"""" — кавычки сразу за разделителем принадлежат строке
+			i += end + len(sc.multi)
+			for i < len(s) && s[i] == sc.multi[0] {
+				i++
+			}
+			i--
+			sc.multi = ""
+			continue
+		}
+
+		switch c := s[i]; c {
+		case '"', '\'':
+			delim := s[i : i+1]
+			if strings.HasPrefix(s[i:], strings.Repeat(delim, 3)) {
+				sc.multi = strings.Repeat(delim, 3)
+				i += 2
+				continue
+			}
+			// однострочная строка; в "..." кавычку экранирует обратная косая
+			for i++; i < len(s) && s[i] != c; i++ {
+				if c == '"' && s[i] == '\\' {
+					i++
+				}
+			}
+		case '#':
+			return delta
+		case '[', '{':
+			delta++
+		case ']', '}':
+			delta--
+		}
+	}
+	return delta
+}
+
+func stripComment(s string) string {
+	if i := strings.Index(s, "#"); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
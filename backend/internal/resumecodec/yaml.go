@@ -0,0 +1,194 @@
+package resumecodec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlLineRe вытаскивает номер строки из сообщений yaml.v3 вида "yaml: line 3: ...".
+var yamlLineRe = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// maxAliasExpansion ограничивает объём, который могут добавить раскрытые
+// алиасы: узел стоит 1, скаляр — ещё и длину значения. Без лимита вложенные
+// якоря («billion laughs») раздувают документ экспоненциально.
+const maxAliasExpansion = 256 << 10
+
+// yamlTree переводит узлы yaml.v3 в дерево JSON-значений.
+type yamlTree struct {
+	pos positions
+	// open — узлы с якорем, которые сейчас раскрываются: алиас на любой
+	// из них означает цикл (&x [*x])
+	open   map[*yaml.Node]bool
+	alias  int // глубина вложенности раскрываемых алиасов
+	budget int // остаток maxAliasExpansion
+}
+
+// parseYAML разбирает YAML в дерево JSON-значений и запоминает позиции ключей.
+func parseYAML(raw []byte) (map[string]any, positions, error) {
+	var root yaml.Node
+	dec := yaml.NewDecoder(bytes.NewReader(raw))
+	if err := dec.Decode(&root); err != nil && err != io.EOF {
+		return nil, nil, yamlSyntaxError(err)
+	}
+	// yaml.Unmarshal молча отбросил бы все документы после первого
+	var next yaml.Node
+	switch err := dec.Decode(&next); {
+	case err == io.EOF:
+	case err != nil:
+		return nil, nil, yamlSyntaxError(err)
+	default:
+		return nil, nil, &DocumentError{Format: FormatYAML, Problems: []Problem{{
+			Message: "expected a single YAML document",
+			Line:    next.Line,
+			Column:  next.Column,
+		}}}
+	}
+
+	if root.Kind == 0 {
+		return nil, nil, &DocumentError{Format: FormatYAML, Problems: []Problem{{Message: "document is empty"}}}
+	}
+
+	tree := &yamlTree{pos: make(positions), open: make(map[*yaml.Node]bool), budget: maxAliasExpansion}
+	val, err := tree.value(&root, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, ok := val.(map[string]any)
+	if !ok {
+		return nil, nil, &DocumentError{Format: FormatYAML, Problems: []Problem{{
+			Message: "top-level value must be a mapping",
+			Line:    root.Line,
+			Column:  root.Column,
+		}}}
+	}
+	return doc, tree.pos, nil
+}
+
+func (t *yamlTree) value(n *yaml.Node, path string) (any, error) {
+	if t.alias > 0 {
+		t.budget -= 1 + len(n.Value)
+		if t.budget < 0 {
+			return nil, yamlNodeError(n, path, "aliases expand to a document that is too large")
+		}
+	}
+	if n.Anchor != "" {
+		t.open[n] = true
+		defer delete(t.open, n)
+	}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return t.value(n.Content[0], path)
+
+	case yaml.AliasNode:
+		if n.Alias == nil || t.open[n.Alias] {
+			return nil, yamlNodeError(n, path, fmt.Sprintf("alias *%s refers to a value that contains it", n.Value))
+		}
+		t.alias++
+		defer func() { t.alias-- }()
+		return t.value(n.Alias, path)
+
+	case yaml.MappingNode:
+		obj := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, val := n.Content[i], n.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return nil, yamlNodeError(key, path, "mapping keys must be scalars")
+			}
+			childPath := joinPath(path, key.Value)
+			if _, dup := obj[key.Value]; dup {
+				return nil, yamlNodeError(key, childPath, "duplicate key")
+			}
+			t.pos[childPath] = Position{Line: key.Line, Column: key.Column}
+			child, err := t.value(val, childPath)
+			if err != nil {
+				return nil, err
+			}
+			obj[key.Value] = child
+		}
+		return obj, nil
+
+	case yaml.SequenceNode:
+		arr := make([]any, 0, len(n.Content))
+		for i, item := range n.Content {
+			childPath := indexPath(path, i)
+			t.pos[childPath] = Position{Line: item.Line, Column: item.Column}
+			child, err := t.value(item, childPath)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, child)
+		}
+		return arr, nil
+
+	case yaml.ScalarNode:
+		return yamlScalar(n, path)
+	}
+
+	return nil, yamlNodeError(n, path, "unsupported YAML node")
+}
+
+// yamlScalar переводит скаляр YAML в JSON-значение по его разрешённому тегу.
+// Даты (!!timestamp) остаются строками: модель резюме хранит их текстом.
+func yamlScalar(n *yaml.Node, path string) (any, error) {
+	switch n.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return nil, yamlNodeError(n, path, err.Error())
+		}
+		return b, nil
+	case "!!int":
+		var i int64
+		if err := n.Decode(&i); err != nil {
+			return nil, yamlNodeError(n, path, err.Error())
+		}
+		return json.Number(strconv.FormatInt(i, 10)), nil
+	case "!!float":
+		var f float64
+		if err := n.Decode(&f); err != nil {
+			return nil, yamlNodeError(n, path, err.Error())
+		}
+		return json.Number(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	default:
+		return n.Value, nil
+	}
+}
+
+func yamlNodeError(n *yaml.Node, path, msg string) error {
+	return &DocumentError{Format: FormatYAML, Problems: []Problem{{
+		Field:   path,
+		Message: msg,
+		Line:    n.Line,
+		Column:  n.Column,
+	}}}
+}
+
+// yamlSyntaxError приводит ошибку парсера yaml.v3 к DocumentError.
+// Парсер сообщает только строку, колонка остаётся неизвестной.
+func yamlSyntaxError(err error) error {
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		return &DocumentError{Format: FormatYAML, Problems: []Problem{{Message: fmt.Sprint(te.Errors)}}}
+	}
+
+	p := Problem{Message: err.Error()}
+	if m := yamlLineRe.FindStringSubmatch(err.Error()); m != nil {
+		p.Line, _ = strconv.Atoi(m[1])
+		p.Message = m[2]
+	}
+	return &DocumentError{Format: FormatYAML, Problems: []Problem{p}}
+}
@@ -0,0 +1,71 @@
+package resumediff
+
+// pair — сопоставление элемента старого списка (a) с элементом нового (b);
+// -1 означает, что пары нет (элемент удалён или добавлен).
+type pair struct {
+	a, b int
+}
+
+// align сопоставляет два списка: сначала по наибольшей общей
+// подпоследовательности равных элементов, затем элементы в промежутках между
+// совпадениями попарно считаются изменёнными, а остаток — удалённым или
+// добавленным. Так вставка одного пункта не превращает все последующие
+// в «изменённые».
+func align(n, m int, equal func(i, j int) bool) []pair {
+	// lcs[i][j] — длина НОП для суффиксов a[i:] и b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if equal(i, j) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []pair
+	var gapA, gapB []int
+	flush := func() {
+		k := min(len(gapA), len(gapB))
+		for x := 0; x < k; x++ {
+			out = append(out, pair{gapA[x], gapB[x]})
+		}
+		for _, i := range gapA[k:] {
+			out = append(out, pair{i, -1})
+		}
+		for _, j := range gapB[k:] {
+			out = append(out, pair{-1, j})
+		}
+		gapA, gapB = gapA[:0], gapB[:0]
+	}
+
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case equal(i, j):
+			flush()
+			out = append(out, pair{i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			gapA = append(gapA, i)
+			i++
+		default:
+			gapB = append(gapB, j)
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		gapA = append(gapA, i)
+	}
+	for ; j < m; j++ {
+		gapB = append(gapB, j)
+	}
+	flush()
+
+	return out
+}
@@ -0,0 +1,383 @@
+// Package resumediff сравнивает две версии резюме: структурно (какие поля,
+// записи и пункты добавлены, удалены или изменены) и построчно для
+// визуального diff в PDF.
+package resumediff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+)
+
+// Виды изменений.
+const (
+	OpAdded   = "added"
+	OpRemoved = "removed"
+	OpChanged = "changed"
+)
+
+// Change — одно структурное изменение. Path записывается так же, как поле
+// в FieldError ("experience[1].bullets[0]"); для удалённых элементов индекс
+// берётся из старой версии, для остальных — из новой.
+type Change struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	Old  any    `json:"old,omitempty"`
+	New  any    `json:"new,omitempty"`
+}
+
+// Result — результат сравнения двух версий.
+type Result struct {
+	Changes []Change
+	// Sections — построчное представление для визуального diff.
+	Sections []contract.DiffSection
+}
+
+// Compare сравнивает версию a (старую) с версией b (новой).
+func Compare(a, b resume.Resume) Result {
+	d := &differ{}
+
+	d.section("Header")
+	d.scalar("fullName", "Name", a.FullName, b.FullName)
+	d.scalar("position", "Position", a.Position, b.Position)
+	d.scalar("summary", "Summary", a.Summary, b.Summary)
+	d.scalar("contacts.email", "Email", a.Contacts.Email, b.Contacts.Email)
+	d.scalar("contacts.phone", "Phone", a.Contacts.Phone, b.Contacts.Phone)
+	d.scalar("contacts.location", "Location", a.Contacts.Location, b.Contacts.Location)
+	d.links(a.Contacts.Links, b.Contacts.Links)
+	d.photo(a.Photo, b.Photo)
+
+	d.section("Skills")
+	d.skills(a.Skills, b.Skills)
+
+	d.section("Experience")
+	d.experience(a.Experience, b.Experience)
+
+	d.section("Education")
+	d.education(a.Education, b.Education)
+
+	d.customSections(a.CustomSections, b.CustomSections)
+
+	res := Result{Changes: d.changes, Sections: []contract.DiffSection{}}
+	if res.Changes == nil {
+		res.Changes = []Change{}
+	}
+	for _, s := range d.sections {
+		if len(s.Lines) > 0 {
+			res.Sections = append(res.Sections, s)
+		}
+	}
+	return res
+}
+
+type differ struct {
+	changes  []Change
+	sections []contract.DiffSection
+}
+
+func (d *differ) section(title string) {
+	d.sections = append(d.sections, contract.DiffSection{Title: title})
+}
+
+func (d *differ) line(kind, text, old string, bullet bool) {
+	s := &d.sections[len(d.sections)-1]
+	s.Lines = append(s.Lines, contract.DiffLine{Kind: kind, Text: text, Old: old, Bullet: bullet})
+}
+
+func (d *differ) change(op, path string, old, new any) {
+	d.changes = append(d.changes, Change{Op: op, Path: path, Old: old, New: new})
+}
+
+// scalar сравнивает строковое поле шапки; пустое значение считается
+// отсутствующим.
+func (d *differ) scalar(path, label, a, b string) {
+	d.fieldChange(path, a, b)
+	d.visualLine(label+": ", a, b)
+}
+
+func (d *differ) links(a, b []resume.Link) {
+	text := func(l resume.Link) string {
+		if strings.TrimSpace(l.Label) == "" {
+			return l.URL
+		}
+		return l.Label + " (" + l.URL + ")"
+	}
+	for _, p := range align(len(a), len(b), func(i, j int) bool { return a[i] == b[j] }) {
+		switch {
+		case p.a < 0:
+			d.change(OpAdded, fmt.Sprintf("contacts.links[%d]", p.b), nil, b[p.b])
+			d.line(contract.DiffAdded, "Link: "+text(b[p.b]), "", false)
+		case p.b < 0:
+			d.change(OpRemoved, fmt.Sprintf("contacts.links[%d]", p.a), a[p.a], nil)
+			d.line(contract.DiffRemoved, "Link: "+text(a[p.a]), "", false)
+		case a[p.a] == b[p.b]:
+			d.line(contract.DiffSame, "Link: "+text(b[p.b]), "", false)
+		default:
+			d.change(OpChanged, fmt.Sprintf("contacts.links[%d]", p.b), a[p.a], b[p.b])
+			d.line(contract.DiffChanged, "Link: "+text(b[p.b]), "Link: "+text(a[p.a]), false)
+		}
+	}
+}
+
+func (d *differ) photo(a, b *resume.Photo) {
+	switch {
+	case a == nil && b == nil:
+	case a == nil:
+		d.change(OpAdded, "photo", nil, nil)
+		d.line(contract.DiffAdded, "Photo", "", false)
+	case b == nil:
+		d.change(OpRemoved, "photo", nil, nil)
+		d.line(contract.DiffRemoved, "Photo", "", false)
+	case *a != *b:
+		d.change(OpChanged, "photo", nil, nil)
+		d.line(contract.DiffChanged, "Photo (new image)", "Photo (old image)", false)
+	}
+}
+
+// texts сравнивает список навыков, пунктов или элементов раздела по тексту.
+// Если текст совпал, а теги — нет, изменение записывается в path[i].tags.
+func (d *differ) texts(path string, a, b []resume.TaggedText) {
+	equal := func(i, j int) bool { return strings.TrimSpace(a[i].Text) == strings.TrimSpace(b[j].Text) }
+
+	for _, p := range align(len(a), len(b), equal) {
+		switch {
+		case p.a < 0:
+			d.change(OpAdded, fmt.Sprintf("%s[%d]", path, p.b), nil, b[p.b].Text)
+			d.line(contract.DiffAdded, b[p.b].Text, "", true)
+		case p.b < 0:
+			d.change(OpRemoved, fmt.Sprintf("%s[%d]", path, p.a), a[p.a].Text, nil)
+			d.line(contract.DiffRemoved, a[p.a].Text, "", true)
+		case equal(p.a, p.b):
+			if !slices.Equal(a[p.a].Tags, b[p.b].Tags) {
+				d.change(OpChanged, fmt.Sprintf("%s[%d].tags", path, p.b), a[p.a].Tags, b[p.b].Tags)
+			}
+			d.line(contract.DiffSame, b[p.b].Text, "", true)
+		default:
+			d.change(OpChanged, fmt.Sprintf("%s[%d]", path, p.b), a[p.a].Text, b[p.b].Text)
+			d.line(contract.DiffChanged, b[p.b].Text, a[p.a].Text, true)
+		}
+	}
+}
+
+// lines выводит все строки записи одним видом (для добавленных и удалённых);
+// пустой заголовок (группа навыков без названия) пропускается.
+func (d *differ) lines(kind string, header string, fields []string, items []resume.TaggedText) {
+	if strings.TrimSpace(header) != "" {
+		d.line(kind, header, "", false)
+	}
+	for _, f := range fields {
+		if strings.TrimSpace(f) != "" {
+			d.line(kind, f, "", false)
+		}
+	}
+	for _, item := range items {
+		d.line(kind, item.Text, "", true)
+	}
+}
+
+func experienceHeader(e resume.ExperienceItem) string {
+	return joinHeader(joinNonEmpty(" at ", e.Position, e.Company), e.Location, e.StartDate, e.EndDate)
+}
+
+func educationHeader(e resume.EducationItem) string {
+	return joinHeader(joinNonEmpty(" — ", e.Institution, e.Degree), e.Location, e.StartDate, e.EndDate)
+}
+
+func joinHeader(title, location, start, end string) string {
+	s := title
+	if location = strings.TrimSpace(location); location != "" {
+		s += ", " + location
+	}
+	if dates := joinNonEmpty(" – ", start, end); dates != "" {
+		s += " (" + dates + ")"
+	}
+	return s
+}
+
+func joinNonEmpty(sep string, parts ...string) string {
+	var out []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return strings.Join(out, sep)
+}
+
+func (d *differ) experience(a, b []resume.ExperienceItem) {
+	// записи считаются одной и той же, если совпадают компания и должность
+	key := func(e resume.ExperienceItem) string {
+		return strings.ToLower(strings.TrimSpace(e.Company) + "\x00" + strings.TrimSpace(e.Position))
+	}
+
+	for _, p := range align(len(a), len(b), func(i, j int) bool { return key(a[i]) == key(b[j]) }) {
+		switch {
+		case p.a < 0:
+			e := b[p.b]
+			d.change(OpAdded, fmt.Sprintf("experience[%d]", p.b), nil, e)
+			d.lines(contract.DiffAdded, experienceHeader(e), []string{e.Description}, e.Bullets)
+		case p.b < 0:
+			e := a[p.a]
+			d.change(OpRemoved, fmt.Sprintf("experience[%d]", p.a), e, nil)
+			d.lines(contract.DiffRemoved, experienceHeader(e), []string{e.Description}, e.Bullets)
+		default:
+			ea, eb := a[p.a], b[p.b]
+			path := fmt.Sprintf("experience[%d]", p.b)
+
+			ha, hb := experienceHeader(ea), experienceHeader(eb)
+			if ha == hb {
+				d.line(contract.DiffSame, hb, "", false)
+			} else {
+				d.line(contract.DiffChanged, hb, ha, false)
+			}
+			d.fieldChange(path+".company", ea.Company, eb.Company)
+			d.fieldChange(path+".position", ea.Position, eb.Position)
+			d.fieldChange(path+".location", ea.Location, eb.Location)
+			d.fieldChange(path+".startDate", ea.StartDate, eb.StartDate)
+			d.fieldChange(path+".endDate", ea.EndDate, eb.EndDate)
+			if !slices.Equal(ea.Tags, eb.Tags) {
+				d.change(OpChanged, path+".tags", ea.Tags, eb.Tags)
+			}
+
+			d.textLine(path+".description", ea.Description, eb.Description)
+			d.texts(path+".bullets", ea.Bullets, eb.Bullets)
+		}
+	}
+}
+
+func (d *differ) education(a, b []resume.EducationItem) {
+	key := func(e resume.EducationItem) string {
+		return strings.ToLower(strings.TrimSpace(e.Institution) + "\x00" + strings.TrimSpace(e.Degree))
+	}
+
+	for _, p := range align(len(a), len(b), func(i, j int) bool { return key(a[i]) == key(b[j]) }) {
+		switch {
+		case p.a < 0:
+			e := b[p.b]
+			d.change(OpAdded, fmt.Sprintf("education[%d]", p.b), nil, e)
+			d.lines(contract.DiffAdded, educationHeader(e), []string{e.Details}, nil)
+		case p.b < 0:
+			e := a[p.a]
+			d.change(OpRemoved, fmt.Sprintf("education[%d]", p.a), e, nil)
+			d.lines(contract.DiffRemoved, educationHeader(e), []string{e.Details}, nil)
+		default:
+			ea, eb := a[p.a], b[p.b]
+			path := fmt.Sprintf("education[%d]", p.b)
+
+			ha, hb := educationHeader(ea), educationHeader(eb)
+			if ha == hb {
+				d.line(contract.DiffSame, hb, "", false)
+			} else {
+				d.line(contract.DiffChanged, hb, ha, false)
+			}
+			d.fieldChange(path+".institution", ea.Institution, eb.Institution)
+			d.fieldChange(path+".degree", ea.Degree, eb.Degree)
+			d.fieldChange(path+".location", ea.Location, eb.Location)
+			d.fieldChange(path+".startDate", ea.StartDate, eb.StartDate)
+			d.fieldChange(path+".endDate", ea.EndDate, eb.EndDate)
+			d.textLine(path+".details", ea.Details, eb.Details)
+		}
+	}
+}
+
+// skills сравнивает группы навыков, сопоставляя их по названию; навыки
+// внутри группы сравниваются как список.
+func (d *differ) skills(a, b []resume.SkillGroup) {
+	key := func(g resume.SkillGroup) string { return strings.ToLower(strings.TrimSpace(g.Name)) }
+
+	for _, p := range align(len(a), len(b), func(i, j int) bool { return key(a[i]) == key(b[j]) }) {
+		switch {
+		case p.a < 0:
+			g := b[p.b]
+			d.change(OpAdded, fmt.Sprintf("skills[%d]", p.b), nil, g)
+			d.lines(contract.DiffAdded, g.Name, nil, g.Items)
+		case p.b < 0:
+			g := a[p.a]
+			d.change(OpRemoved, fmt.Sprintf("skills[%d]", p.a), g, nil)
+			d.lines(contract.DiffRemoved, g.Name, nil, g.Items)
+		default:
+			ga, gb := a[p.a], b[p.b]
+			path := fmt.Sprintf("skills[%d]", p.b)
+			switch {
+			case strings.TrimSpace(ga.Name) != strings.TrimSpace(gb.Name):
+				d.change(OpChanged, path+".name", ga.Name, gb.Name)
+				d.line(contract.DiffChanged, gb.Name, ga.Name, false)
+			case strings.TrimSpace(gb.Name) != "":
+				d.line(contract.DiffSame, gb.Name, "", false)
+			}
+			d.texts(path+".items", ga.Items, gb.Items)
+		}
+	}
+}
+
+func (d *differ) customSections(a, b []resume.CustomSection) {
+	key := func(cs resume.CustomSection) string { return strings.ToLower(strings.TrimSpace(cs.Title)) }
+
+	for _, p := range align(len(a), len(b), func(i, j int) bool { return key(a[i]) == key(b[j]) }) {
+		switch {
+		case p.a < 0:
+			cs := b[p.b]
+			d.section(cs.Title)
+			d.change(OpAdded, fmt.Sprintf("customSections[%d]", p.b), nil, cs)
+			d.lines(contract.DiffAdded, cs.Title, nil, cs.Items)
+		case p.b < 0:
+			cs := a[p.a]
+			d.section(cs.Title)
+			d.change(OpRemoved, fmt.Sprintf("customSections[%d]", p.a), cs, nil)
+			d.lines(contract.DiffRemoved, cs.Title, nil, cs.Items)
+		default:
+			ca, cb := a[p.a], b[p.b]
+			path := fmt.Sprintf("customSections[%d]", p.b)
+			d.section(cb.Title)
+			if strings.TrimSpace(ca.Title) != strings.TrimSpace(cb.Title) {
+				d.change(OpChanged, path+".title", ca.Title, cb.Title)
+				d.line(contract.DiffChanged, cb.Title, ca.Title, false)
+			}
+			d.fieldChange(path+".type", ca.Type, cb.Type)
+			d.fieldChange(path+".bulletSymbol", ca.BulletSymbol, cb.BulletSymbol)
+			d.texts(path+".items", ca.Items, cb.Items)
+		}
+	}
+}
+
+// fieldChange записывает структурное изменение поля без отдельной строки
+// в визуальном diff (поле входит в заголовок записи).
+func (d *differ) fieldChange(path, a, b string) {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	switch {
+	case a == b:
+	case a == "":
+		d.change(OpAdded, path, nil, b)
+	case b == "":
+		d.change(OpRemoved, path, a, nil)
+	default:
+		d.change(OpChanged, path, a, b)
+	}
+}
+
+// textLine — поле, которое в визуальном diff выводится отдельной строкой.
+func (d *differ) textLine(path, a, b string) {
+	d.fieldChange(path, a, b)
+	d.visualLine("", a, b)
+}
+
+// visualLine добавляет строку визуального diff для пары значений поля.
+func (d *differ) visualLine(prefix, a, b string) {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	switch {
+	case a == b:
+		if b != "" {
+			d.line(contract.DiffSame, prefix+b, "", false)
+		}
+	case a == "":
+		d.line(contract.DiffAdded, prefix+b, "", false)
+	case b == "":
+		d.line(contract.DiffRemoved, prefix+a, "", false)
+	default:
+		d.line(contract.DiffChanged, prefix+b, prefix+a, false)
+	}
+}
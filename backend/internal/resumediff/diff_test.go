@@ -0,0 +1,140 @@
+package resumediff
+
+import (
+	"reflect"
+	"testing"
+
+	"resume_backend/internal/resume"
+
+	contract "resume_contract"
+)
+
+func TestAlign(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want []pair
+	}{
+		{"equal", []string{"x", "y"}, []string{"x", "y"}, []pair{{0, 0}, {1, 1}}},
+		{"insert in the middle", []string{"x", "z"}, []string{"x", "y", "z"}, []pair{{0, 0}, {-1, 1}, {1, 2}}},
+		{"remove first", []string{"x", "y"}, []string{"y"}, []pair{{0, -1}, {1, 0}}},
+		{"change in gap", []string{"x", "old", "z"}, []string{"x", "new", "z"}, []pair{{0, 0}, {1, 1}, {2, 2}}},
+		{"longer gap", []string{"a"}, []string{"b", "c"}, []pair{{0, 0}, {-1, 1}}},
+		{"empty old", nil, []string{"x"}, []pair{{-1, 0}}},
+		{"both empty", nil, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := align(len(tt.a), len(tt.b), func(i, j int) bool { return tt.a[i] == tt.b[j] })
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("align = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func base() resume.Resume {
+	return resume.Resume{
+		FullName: "Ivan Ivanov",
+		Position: "Backend developer",
+		Skills: []resume.SkillGroup{
+			{Items: resume.Untagged([]string{"Go", "SQL"})},
+			{Name: "Cloud", Items: resume.Untagged([]string{"AWS"})},
+		},
+		Experience: []resume.ExperienceItem{{
+			Company:  "Example",
+			Position: "Engineer",
+			Bullets:  resume.Untagged([]string{"Built things", "Fixed things"}),
+		}},
+		CustomSections: []resume.CustomSection{
+			{Title: "Projects", Type: resume.SectionProjects, Items: resume.Untagged([]string{"gopdf"})},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(r *resume.Resume)
+		want   []Change
+	}{
+		{"no changes", func(r *resume.Resume) {}, []Change{}},
+		{
+			"scalar changed",
+			func(r *resume.Resume) { r.Position = "Senior developer" },
+			[]Change{{Op: OpChanged, Path: "position", Old: "Backend developer", New: "Senior developer"}},
+		},
+		{
+			"field added",
+			func(r *resume.Resume) { r.Contacts.Email = "ivan@example.com" },
+			[]Change{{Op: OpAdded, Path: "contacts.email", New: "ivan@example.com"}},
+		},
+		{
+			"skill added to a group",
+			func(r *resume.Resume) { r.Skills[0].Items = resume.Untagged([]string{"Go", "Rust", "SQL"}) },
+			[]Change{{Op: OpAdded, Path: "skills[0].items[1]", New: "Rust"}},
+		},
+		{
+			"group renamed by case stays aligned",
+			func(r *resume.Resume) { r.Skills[1].Name = "cloud" },
+			[]Change{{Op: OpChanged, Path: "skills[1].name", Old: "Cloud", New: "cloud"}},
+		},
+		{
+			"skill group removed",
+			func(r *resume.Resume) { r.Skills = r.Skills[:1] },
+			[]Change{{Op: OpRemoved, Path: "skills[1]", Old: base().Skills[1]}},
+		},
+		{
+			"bullet tags changed",
+			func(r *resume.Resume) { r.Experience[0].Bullets[1].Tags = []string{"sre"} },
+			[]Change{{Op: OpChanged, Path: "experience[0].bullets[1].tags", Old: []string(nil), New: []string{"sre"}}},
+		},
+		{
+			"bullet rewritten",
+			func(r *resume.Resume) { r.Experience[0].Bullets[0].Text = "Built services" },
+			[]Change{{Op: OpChanged, Path: "experience[0].bullets[0]", Old: "Built things", New: "Built services"}},
+		},
+		{
+			"section type changed",
+			func(r *resume.Resume) { r.CustomSections[0].Type = resume.SectionCustom },
+			[]Change{{Op: OpChanged, Path: "customSections[0].type", Old: "projects", New: "custom"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := base()
+			tt.modify(&b)
+			got := Compare(base(), b).Changes
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Changes = %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareSections(t *testing.T) {
+	b := base()
+	b.Skills[0].Items = resume.Untagged([]string{"Go"})
+	res := Compare(base(), b)
+
+	var skills *contract.DiffSection
+	for i := range res.Sections {
+		if res.Sections[i].Title == "Skills" {
+			skills = &res.Sections[i]
+		}
+	}
+	if skills == nil {
+		t.Fatal("no Skills section")
+	}
+
+	// группа без названия не даёт строки заголовка
+	want := []contract.DiffLine{
+		{Kind: contract.DiffSame, Text: "Go", Bullet: true},
+		{Kind: contract.DiffRemoved, Text: "SQL", Bullet: true},
+		{Kind: contract.DiffSame, Text: "Cloud"},
+		{Kind: contract.DiffSame, Text: "AWS", Bullet: true},
+	}
+	if !reflect.DeepEqual(skills.Lines, want) {
+		t.Errorf("Skills lines = %+v\nwant %+v", skills.Lines, want)
+	}
+}
@@ -0,0 +1,59 @@
+package share
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Пароли ссылок хранятся как PBKDF2-HMAC-SHA256 в формате
+// pbkdf2-sha256$<iterations>$<salt>$<hash> (base64 без паддинга).
+const (
+	passwordScheme     = "pbkdf2-sha256"
+	passwordIterations = 210000
+	passwordSaltSize   = 16
+	passwordKeySize    = 32
+)
+
+func hashPassword(password string) (string, error) {
+	salt := make([]byte, passwordSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("generate salt: %w", err)
+	}
+	key := pbkdf2.Key([]byte(password), salt, passwordIterations, passwordKeySize, sha256.New)
+	return fmt.Sprintf("%s$%d$%s$%s",
+		passwordScheme,
+		passwordIterations,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// checkPassword сравнивает пароль с хэшем за постоянное время.
+// Испорченный хэш не совпадает ни с одним паролем.
+func checkPassword(hash, password string) bool {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != passwordScheme {
+		return false
+	}
+	iter, err := strconv.Atoi(parts[1])
+	if err != nil || iter < 1 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+	got := pbkdf2.Key([]byte(password), salt, iter, len(want), sha256.New)
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
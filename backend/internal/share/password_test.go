@@ -0,0 +1,55 @@
+package share
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPassword(t *testing.T) {
+	// векторы PBKDF2-HMAC-SHA256 (RFC 7914, раздел 11) в формате хранения
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+	}{
+		{"one iteration", "pbkdf2-sha256$1$c2FsdA$Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs", "password", true},
+		{"two iterations", "pbkdf2-sha256$2$c2FsdA$rk0Mla9rRtMtCt/5KPBt0CowP47zwlHf1uLYWpVHTEM", "password", true},
+		{"wrong password", "pbkdf2-sha256$1$c2FsdA$Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs", "Password", false},
+		{"wrong iterations", "pbkdf2-sha256$2$c2FsdA$Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs", "password", false},
+		{"unknown scheme", "bcrypt$1$c2FsdA$Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs", "password", false},
+		{"zero iterations", "pbkdf2-sha256$0$c2FsdA$Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs", "password", false},
+		{"bad salt", "pbkdf2-sha256$1$!!!$Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs", "password", false},
+		{"empty key", "pbkdf2-sha256$1$c2FsdA$", "password", false},
+		{"missing part", "pbkdf2-sha256$1$c2FsdA", "password", false},
+		{"empty", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkPassword(tt.hash, tt.password); got != tt.want {
+				t.Errorf("checkPassword = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	a, err := hashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := hashPassword("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(a, "pbkdf2-sha256$210000$") {
+		t.Errorf("hash = %q, want pbkdf2-sha256 with 210000 iterations", a)
+	}
+	if a == b {
+		t.Error("two hashes of one password are equal, salt is not random")
+	}
+	if !checkPassword(a, "secret") || checkPassword(a, "Secret") {
+		t.Error("hash does not verify its own password")
+	}
+}
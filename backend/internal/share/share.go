@@ -0,0 +1,469 @@
+// Package share выдаёт публичные ссылки на сохранённые резюме: подписанные
+// токены с ограниченным сроком действия, опциональным паролем, отзывом и
+// счётчиком обращений. Ссылки хранятся в JSON-файле рядом с резюме; сами
+// токены не хранятся — они выводятся из id ссылки, срока действия и ключа.
+// Счётчики обращений меняются в памяти и сбрасываются в файл периодически.
+package share
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+const (
+	// DefaultTTL — срок действия ссылки, если клиент его не указал.
+	DefaultTTL = 7 * 24 * time.Hour
+	// MaxTTL — максимальный срок действия ссылки.
+	MaxTTL = 90 * 24 * time.Hour
+	// retention — сколько истёкшие и отозванные ссылки остаются в списке,
+	// прежде чем удаляются из файла вместе со счётчиками.
+	retention = 30 * 24 * time.Hour
+	// defaultFlushInterval — как часто счётчики обращений сбрасываются в файл.
+	defaultFlushInterval = 10 * time.Second
+)
+
+var (
+	// ErrNotFound — у резюме нет ссылки с таким id.
+	ErrNotFound = errors.New("share link not found")
+	// ErrInvalidToken — подпись токена не сошлась или ссылки уже нет.
+	ErrInvalidToken = errors.New("invalid share token")
+	// ErrExpired — срок действия ссылки истёк.
+	ErrExpired = errors.New("share link expired")
+	// ErrRevoked — ссылка отозвана владельцем.
+	ErrRevoked = errors.New("share link revoked")
+	// ErrPasswordRequired — ссылка защищена паролем, а он не передан.
+	ErrPasswordRequired = errors.New("share link password required")
+	// ErrWrongPassword — передан неверный пароль.
+	ErrWrongPassword = errors.New("wrong share link password")
+)
+
+// Статусы ссылки в Info.
+const (
+	StatusActive  = "active"
+	StatusExpired = "expired"
+	StatusRevoked = "revoked"
+)
+
+// Link — ссылка в том виде, в каком она хранится в файле.
+type Link struct {
+	ID           string        `json:"id"`
+	ResumeID     string        `json:"resumeId"`
+	Format       resume.Format `json:"format"`
+	Variant      string        `json:"variant,omitempty"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	ExpiresAt    time.Time     `json:"expiresAt"`
+	RevokedAt    *time.Time    `json:"revokedAt,omitempty"`
+	PasswordHash string        `json:"passwordHash,omitempty"`
+	AccessCount  int64         `json:"accessCount"`
+	LastAccessAt *time.Time    `json:"lastAccessAt,omitempty"`
+}
+
+// Info — ссылка в ответах API: без хэша пароля, с токеном и статусом.
+type Info struct {
+	ID           string        `json:"id"`
+	ResumeID     string        `json:"resumeId"`
+	Token        string        `json:"token"`
+	URL          string        `json:"url"`
+	Format       resume.Format `json:"format"`
+	Variant      string        `json:"variant,omitempty"`
+	Protected    bool          `json:"passwordProtected"`
+	Status       string        `json:"status"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	ExpiresAt    time.Time     `json:"expiresAt"`
+	RevokedAt    *time.Time    `json:"revokedAt,omitempty"`
+	AccessCount  int64         `json:"accessCount"`
+	LastAccessAt *time.Time    `json:"lastAccessAt,omitempty"`
+}
+
+// Options — параметры новой ссылки. Нулевой TTL означает DefaultTTL,
+// пустой Password — ссылку без пароля.
+type Options struct {
+	ResumeID string
+	Format   resume.Format
+	Variant  resume.Variant
+	TTL      time.Duration
+	Password string
+}
+
+// Store хранит ссылки в JSON-файле и проверяет токены. Создание, отзыв и
+// удаление ссылок записываются сразу, счётчики обращений — при следующем
+// сбросе (раз в flushInterval и при Close), чтобы публичный просмотр не
+// переписывал файл целиком.
+type Store struct {
+	path   string
+	signer signer
+	now    func() time.Time
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	links map[string]*Link
+	// gen — номер изменения ссылок.
+	gen uint64
+
+	// fileMu сериализует запись файла; written — gen последнего
+	// записанного снимка, чтобы более старый снимок не затёр новый.
+	fileMu  sync.Mutex
+	written uint64
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type fileData struct {
+	Links []*Link `json:"links"`
+}
+
+// Open читает ссылки из файла path (отсутствующий файл — пустой список) и
+// запускает сброс счётчиков обращений раз в flushInterval (<= 0 — 10 секунд).
+// key — секрет подписи токенов, не короче KeySize байт. Store нужно закрыть
+// (Close), чтобы сохранить последние обращения.
+func Open(path string, key []byte, flushInterval time.Duration, logger *slog.Logger) (*Store, error) {
+	if len(key) < KeySize {
+		return nil, fmt.Errorf("share key must be at least %d bytes", KeySize)
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if flushInterval <= 0 {
+		flushInterval = defaultFlushInterval
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create share dir: %w", err)
+	}
+
+	s := &Store{
+		path:   path,
+		signer: signer{key: key},
+		now:    time.Now,
+		logger: logger,
+		links:  make(map[string]*Link),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("read share links: %w", err)
+	default:
+		var fd fileData
+		if err := json.Unmarshal(data, &fd); err != nil {
+			return nil, fmt.Errorf("decode share links %s: %w", path, err)
+		}
+		for _, l := range fd.Links {
+			s.links[l.ID] = l
+		}
+	}
+
+	go s.flushLoop(flushInterval)
+	return s, nil
+}
+
+// Close останавливает периодический сброс и записывает ссылки в файл.
+func (s *Store) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	<-s.done
+	return s.save()
+}
+
+// Create выдаёт новую ссылку на резюме.
+func (s *Store) Create(opts Options) (Info, error) {
+	ttl := opts.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	if ttl < 0 || ttl > MaxTTL {
+		return Info{}, fmt.Errorf("share link lifetime must be between 1s and %s", MaxTTL)
+	}
+
+	now := s.now().UTC().Truncate(time.Second)
+	l := &Link{
+		ID:        resume.NewID(),
+		ResumeID:  opts.ResumeID,
+		Format:    opts.Format,
+		Variant:   opts.Variant.String(),
+		CreatedAt: now,
+		// срок хранится с точностью до секунды, как и в токене
+		ExpiresAt: now.Add(ttl).Truncate(time.Second),
+	}
+	if opts.Password != "" {
+		hash, err := hashPassword(opts.Password)
+		if err != nil {
+			return Info{}, err
+		}
+		l.PasswordHash = hash
+	}
+
+	s.mu.Lock()
+	s.links[l.ID] = l
+	s.gen++
+	info := s.info(l)
+	s.mu.Unlock()
+
+	if err := s.save(); err != nil {
+		s.mu.Lock()
+		delete(s.links, l.ID)
+		s.gen++
+		s.mu.Unlock()
+		return Info{}, err
+	}
+	return info, nil
+}
+
+// List возвращает ссылки резюме, новые — первыми.
+func (s *Store) List(resumeID string) []Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := []Info{}
+	for _, l := range s.links {
+		if l.ResumeID == resumeID {
+			out = append(out, s.info(l))
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.After(out[j].CreatedAt) })
+	return out
+}
+
+// Get возвращает ссылку резюме по id.
+func (s *Store) Get(resumeID, id string) (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.links[id]
+	if !ok || l.ResumeID != resumeID {
+		return Info{}, ErrNotFound
+	}
+	return s.info(l), nil
+}
+
+// Revoke отзывает ссылку. Повторный отзыв не меняет время отзыва.
+func (s *Store) Revoke(resumeID, id string) (Info, error) {
+	s.mu.Lock()
+	l, ok := s.links[id]
+	if !ok || l.ResumeID != resumeID {
+		s.mu.Unlock()
+		return Info{}, ErrNotFound
+	}
+	if l.RevokedAt != nil {
+		defer s.mu.Unlock()
+		return s.info(l), nil
+	}
+	now := s.now().UTC()
+	l.RevokedAt = &now
+	s.gen++
+	info := s.info(l)
+	s.mu.Unlock()
+
+	if err := s.save(); err != nil {
+		s.mu.Lock()
+		l.RevokedAt = nil
+		s.gen++
+		s.mu.Unlock()
+		return Info{}, err
+	}
+	return info, nil
+}
+
+// DeleteResume удаляет все ссылки на резюме, например после его удаления.
+func (s *Store) DeleteResume(resumeID string) error {
+	s.mu.Lock()
+	removed := false
+	for id, l := range s.links {
+		if l.ResumeID == resumeID {
+			delete(s.links, id)
+			removed = true
+		}
+	}
+	if removed {
+		s.gen++
+	}
+	s.mu.Unlock()
+
+	if !removed {
+		return nil
+	}
+	return s.save()
+}
+
+// Authorize проверяет токен и пароль и возвращает ссылку, если доступ
+// разрешён. Обращение не считается: после успешной выдачи документа
+// вызывающий отмечает его через RecordAccess.
+func (s *Store) Authorize(token, password string) (Link, error) {
+	id, expires, err := s.signer.verify(token)
+	if err != nil {
+		return Link{}, err
+	}
+
+	s.mu.Lock()
+	l, ok := s.links[id]
+	if !ok || !l.ExpiresAt.Equal(expires) {
+		s.mu.Unlock()
+		return Link{}, ErrInvalidToken
+	}
+	now := s.now().UTC()
+	link := *l
+	s.mu.Unlock()
+
+	switch {
+	case link.RevokedAt != nil:
+		return Link{}, ErrRevoked
+	case !now.Before(expires):
+		return Link{}, ErrExpired
+	case link.PasswordHash != "" && password == "":
+		return Link{}, ErrPasswordRequired
+	// PBKDF2 намеренно медленный, поэтому проверяется без блокировки
+	case link.PasswordHash != "" && !checkPassword(link.PasswordHash, password):
+		return Link{}, ErrWrongPassword
+	}
+	return link, nil
+}
+
+// RecordAccess увеличивает счётчик обращений по ссылке id; в файл он
+// попадёт при следующем сбросе. Ссылку могли удалить, пока выдавался
+// документ, — тогда считать нечего.
+func (s *Store) RecordAccess(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.links[id]
+	if !ok {
+		return
+	}
+	now := s.now().UTC()
+	l.AccessCount++
+	l.LastAccessAt = &now
+	s.gen++
+}
+
+func (s *Store) info(l *Link) Info {
+	// id всегда сгенерирован NewID, поэтому ошибки подписи здесь нет
+	token, _ := s.signer.sign(l.ID, l.ExpiresAt)
+
+	status := StatusActive
+	switch {
+	case l.RevokedAt != nil:
+		status = StatusRevoked
+	case !s.now().Before(l.ExpiresAt):
+		status = StatusExpired
+	}
+
+	return Info{
+		ID:           l.ID,
+		ResumeID:     l.ResumeID,
+		Token:        token,
+		URL:          "/s/" + token,
+		Format:       l.Format,
+		Variant:      l.Variant,
+		Protected:    l.PasswordHash != "",
+		Status:       status,
+		CreatedAt:    l.CreatedAt,
+		ExpiresAt:    l.ExpiresAt,
+		RevokedAt:    l.RevokedAt,
+		AccessCount:  l.AccessCount,
+		LastAccessAt: l.LastAccessAt,
+	}
+}
+
+// flushLoop периодически сбрасывает счётчики обращений в файл до Close.
+func (s *Store) flushLoop(interval time.Duration) {
+	defer close(s.done)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-t.C:
+			if err := s.save(); err != nil {
+				s.logger.Error("flush share links failed", "path", s.path, "error", err)
+			}
+		}
+	}
+}
+
+// save атомарно перезаписывает файл снимком ссылок, если они изменились с
+// прошлой записи. Снимок делается под s.mu, а запись и fsync идут уже без
+// неё, чтобы медленный диск не задерживал проверку токенов и выдачу
+// документов. Ссылки, истёкшие или отозванные больше retention назад, при
+// этом выбрасываются.
+func (s *Store) save() error {
+	data, gen, err := s.snapshot()
+	if err != nil {
+		return err
+	}
+
+	s.fileMu.Lock()
+	defer s.fileMu.Unlock()
+	if gen <= s.written {
+		// этот или более новый снимок уже записан
+		return nil
+	}
+	if err := s.writeFile(data); err != nil {
+		return err
+	}
+	s.written = gen
+	return nil
+}
+
+func (s *Store) snapshot() ([]byte, uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.now().Add(-retention)
+	fd := fileData{Links: make([]*Link, 0, len(s.links))}
+	for id, l := range s.links {
+		end := l.ExpiresAt
+		if l.RevokedAt != nil && l.RevokedAt.Before(end) {
+			end = *l.RevokedAt
+		}
+		if end.Before(cutoff) {
+			delete(s.links, id)
+			continue
+		}
+		fd.Links = append(fd.Links, l)
+	}
+	sort.Slice(fd.Links, func(i, j int) bool { return fd.Links[i].CreatedAt.Before(fd.Links[j].CreatedAt) })
+
+	data, err := json.MarshalIndent(fd, "", "  ")
+	if err != nil {
+		return nil, 0, fmt.Errorf("encode share links: %w", err)
+	}
+	return data, s.gen, nil
+}
+
+func (s *Store) writeFile(data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write share links: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync share links: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close share links: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("rename share links: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package share
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+var testKey = bytes.Repeat([]byte{7}, KeySize)
+
+func openStore(t *testing.T) (*Store, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "shares.json")
+	return reopen(t, path), path
+}
+
+// reopen открывает хранилище по path и закрывает его в конце теста.
+func reopen(t *testing.T, path string) *Store {
+	t.Helper()
+	s, err := Open(path, testKey, time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestAuthorize(t *testing.T) {
+	s, _ := openStore(t)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	open, err := s.Create(Options{ResumeID: "r1", Format: resume.FormatPDF})
+	if err != nil {
+		t.Fatal(err)
+	}
+	protected, err := s.Create(Options{ResumeID: "r1", Format: resume.FormatPDF, Password: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked, err := s.Create(Options{ResumeID: "r1", Format: resume.FormatPDF})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Revoke("r1", revoked.ID); err != nil {
+		t.Fatal(err)
+	}
+	expired, err := s.Create(Options{ResumeID: "r1", Format: resume.FormatPDF, TTL: time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return now.Add(time.Hour) }
+
+	forged := []byte(open.Token)
+	forged[len(forged)-1] ^= 1
+
+	tests := []struct {
+		name     string
+		token    string
+		password string
+		err      error
+	}{
+		{"open", open.Token, "", nil},
+		{"protected", protected.Token, "secret", nil},
+		{"password required", protected.Token, "", ErrPasswordRequired},
+		{"wrong password", protected.Token, "Secret", ErrWrongPassword},
+		{"revoked", revoked.Token, "", ErrRevoked},
+		{"expired", expired.Token, "", ErrExpired},
+		{"forged", string(forged), "", ErrInvalidToken},
+		{"garbage", "abc", "", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Authorize(tt.token, tt.password)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	// проверка доступа сама по себе обращением не считается
+	if info, _ := s.Get("r1", open.ID); info.AccessCount != 0 {
+		t.Errorf("AccessCount after Authorize = %d, want 0", info.AccessCount)
+	}
+}
+
+func TestRecordAccessPersists(t *testing.T) {
+	s, path := openStore(t)
+	info, err := s.Create(Options{ResumeID: "r1", Format: resume.FormatPDF})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.RecordAccess(info.ID)
+		}()
+	}
+	wg.Wait()
+
+	// ссылка удалена, пока выдавался документ
+	s.RecordAccess("0000000000000000")
+
+	// счётчики пишутся на диск не при каждом просмотре, а при сбросе
+	before, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.RecordAccess(info.ID)
+	after, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("RecordAccess rewrote the file")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopen(t, path).Get("r1", info.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessCount != 9 || got.LastAccessAt == nil {
+		t.Errorf("after reopen: AccessCount = %d, LastAccessAt = %v; want 9 and set", got.AccessCount, got.LastAccessAt)
+	}
+}
+
+func TestDeleteResume(t *testing.T) {
+	s, path := openStore(t)
+	a, _ := s.Create(Options{ResumeID: "r1", Format: resume.FormatPDF})
+	b, _ := s.Create(Options{ResumeID: "r2", Format: resume.FormatPDF})
+
+	if err := s.DeleteResume("r1"); err != nil {
+		t.Fatal(err)
+	}
+	reopened := reopen(t, path)
+	if _, err := reopened.Get("r1", a.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("link of a deleted resume: err = %v, want ErrNotFound", err)
+	}
+	if _, err := reopened.Get("r2", b.ID); err != nil {
+		t.Errorf("link of another resume: %v", err)
+	}
+}
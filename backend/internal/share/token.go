@@ -0,0 +1,94 @@
+package share
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// KeySize — длина секрета подписи в байтах.
+const KeySize = 32
+
+// signatureSize — сколько байт HMAC-SHA256 остаётся в токене: 128 бит
+// достаточно, а ссылка получается короче.
+const signatureSize = 16
+
+var encoding = base64.RawURLEncoding
+
+// signer подписывает токены вида <payload>.<signature>, где payload — id
+// ссылки (8 байт) и срок действия (unix-секунды, 8 байт). Подпись проверяется
+// до обращения к хранилищу, поэтому подобранные токены не стоят ничего.
+type signer struct {
+	key []byte
+}
+
+func (s signer) sign(id string, expires time.Time) (string, error) {
+	raw, err := hex.DecodeString(id)
+	if err != nil || len(raw) != 8 {
+		return "", fmt.Errorf("invalid share id %q", id)
+	}
+	payload := binary.BigEndian.AppendUint64(raw, uint64(expires.Unix()))
+	return encoding.EncodeToString(payload) + "." + encoding.EncodeToString(s.mac(payload)), nil
+}
+
+// verify проверяет подпись и возвращает id ссылки и срок её действия.
+func (s signer) verify(token string) (string, time.Time, error) {
+	p, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	payload, err := encoding.DecodeString(p)
+	if err != nil || len(payload) != 16 {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	mac, err := encoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(mac, s.mac(payload)) {
+		return "", time.Time{}, ErrInvalidToken
+	}
+	expires := time.Unix(int64(binary.BigEndian.Uint64(payload[8:])), 0).UTC()
+	return hex.EncodeToString(payload[:8]), expires, nil
+}
+
+func (s signer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write(payload)
+	return h.Sum(nil)[:signatureSize]
+}
+
+// LoadOrCreateKey читает секрет подписи из файла path (hex), а если файла
+// нет — генерирует новый и сохраняет его с правами 0600. Без постоянного
+// ключа все выданные ссылки перестали бы работать после перезапуска.
+func LoadOrCreateKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil || len(key) < KeySize {
+			return nil, fmt.Errorf("share key %s must contain at least %d hex-encoded bytes", path, KeySize)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("read share key: %w", err)
+	}
+
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate share key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create share key dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0o600); err != nil {
+		return nil, fmt.Errorf("write share key: %w", err)
+	}
+	return key, nil
+}
@@ -0,0 +1,454 @@
+// Package filedb — встраиваемое хранилище резюме в одном файле (по аналогии
+// с SQLite): журнал записей с контрольными суммами, который целиком читается
+// в память при открытии и периодически уплотняется. Каждая запись put —
+// неизменяемая ревизия; уплотнение выбрасывает только удалённые резюме.
+//
+// Формат файла:
+//
+//	"RESUMEDB" uint32(formatVersion)
+//	{ uint32(len) uint32(crc32) payload }...
+//
+// payload — JSON-запись record. Числа записываются в little-endian. Если
+// процесс упал посреди записи, при следующем открытии недописанный хвост
+// отбрасывается.
+package filedb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+const (
+	magic         = "RESUMEDB"
+	formatVersion = 1
+	headerSize    = len(magic) + 4
+
+	// maxRecordSize защищает от чтения мусорной длины как гигабайтной записи.
+	maxRecordSize = 16 << 20
+
+	// Журнал уплотняется, когда в нём больше compactMinRecords записей и
+	// записей удалённых резюме среди них больше, чем живых ревизий.
+	compactMinRecords = 256
+)
+
+const (
+	opPut    = "put"
+	opDelete = "delete"
+)
+
+// record — одна запись журнала.
+type record struct {
+	Op      string          `json:"op"`
+	ID      string          `json:"id"`
+	Version int             `json:"version,omitempty"`
+	Doc     json.RawMessage `json:"doc,omitempty"`
+}
+
+// DB реализует resume.Repository поверх одного файла.
+type DB struct {
+	path string
+
+	mu      sync.RWMutex
+	file    *os.File
+	docs    map[string]json.RawMessage         // id -> текущая версия (resume.StoredResume)
+	revs    map[string]map[int]json.RawMessage // id -> версия -> ревизия
+	live    int                                // число ревизий живых резюме
+	records int                                // число записей в журнале
+	now     func() time.Time
+	logger  *slog.Logger
+}
+
+// Open открывает (или создаёт) файл базы и читает журнал в память.
+func Open(path string, logger *slog.Logger) (*DB, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", path, err)
+	}
+
+	db := &DB{
+		path:   path,
+		file:   f,
+		docs:   make(map[string]json.RawMessage),
+		revs:   make(map[string]map[int]json.RawMessage),
+		now:    time.Now,
+		logger: logger,
+	}
+	if err := db.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Close закрывает файл базы.
+func (db *DB) Close() error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	return db.file.Close()
+}
+
+// load читает заголовок и журнал. Повреждённый хвост обрезается.
+func (db *DB) load() error {
+	info, err := db.file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat %s: %w", db.path, err)
+	}
+	if info.Size() == 0 {
+		if _, err := db.file.Write(header()); err != nil {
+			return fmt.Errorf("write header: %w", err)
+		}
+		return db.file.Sync()
+	}
+
+	r := bufio.NewReader(db.file)
+	head := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, head); err != nil || !bytes.Equal(head, header()) {
+		return fmt.Errorf("%s is not a resume database (format %d)", db.path, formatVersion)
+	}
+
+	offset := int64(headerSize)
+	for {
+		payload, n, err := readRecord(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// недописанная или битая запись: всё после offset отбрасываем
+			db.logger.Warn("filedb: dropping damaged tail", "path", db.path, "offset", offset, "error", err)
+			if err := db.file.Truncate(offset); err != nil {
+				return fmt.Errorf("truncate damaged tail: %w", err)
+			}
+			break
+		}
+
+		var rec record
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			return fmt.Errorf("decode record at offset %d: %w", offset, err)
+		}
+		db.apply(rec)
+		offset += n
+	}
+
+	if _, err := db.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek: %w", err)
+	}
+	return nil
+}
+
+func (db *DB) apply(rec record) {
+	db.records++
+	switch rec.Op {
+	case opPut:
+		db.docs[rec.ID] = rec.Doc
+		if db.revs[rec.ID] == nil {
+			db.revs[rec.ID] = make(map[int]json.RawMessage)
+		}
+		if _, ok := db.revs[rec.ID][rec.Version]; !ok {
+			db.live++
+		}
+		db.revs[rec.ID][rec.Version] = rec.Doc
+	case opDelete:
+		db.live -= len(db.revs[rec.ID])
+		delete(db.docs, rec.ID)
+		delete(db.revs, rec.ID)
+	}
+}
+
+func header() []byte {
+	b := make([]byte, headerSize)
+	copy(b, magic)
+	binary.LittleEndian.PutUint32(b[len(magic):], formatVersion)
+	return b
+}
+
+// readRecord читает одну запись и возвращает её payload и размер в байтах.
+func readRecord(r io.Reader) ([]byte, int64, error) {
+	var head [8]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		if err == io.EOF {
+			return nil, 0, io.EOF
+		}
+		return nil, 0, err
+	}
+
+	size := binary.LittleEndian.Uint32(head[0:4])
+	sum := binary.LittleEndian.Uint32(head[4:8])
+	if size > maxRecordSize {
+		return nil, 0, fmt.Errorf("record too large: %d bytes", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, 0, err
+	}
+	if crc32.ChecksumIEEE(payload) != sum {
+		return nil, 0, errors.New("record checksum mismatch")
+	}
+	return payload, int64(len(head)) + int64(size), nil
+}
+
+func encodeRecord(rec record) ([]byte, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]byte, 8+len(payload))
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.LittleEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	copy(buf[8:], payload)
+	return buf, nil
+}
+
+// append дописывает запись в журнал и применяет её к памяти. Вызывается под mu.
+func (db *DB) append(rec record) error {
+	buf, err := encodeRecord(rec)
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+	offset, err := db.file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return fmt.Errorf("seek: %w", err)
+	}
+	_, err = db.file.Write(buf)
+	if err == nil {
+		err = db.file.Sync()
+	}
+	if err != nil {
+		// откатываем частично записанную запись, иначе следующие записи
+		// окажутся за битым участком и потеряются при открытии
+		_ = db.file.Truncate(offset)
+		_, _ = db.file.Seek(offset, io.SeekStart)
+		return fmt.Errorf("write record: %w", err)
+	}
+	db.apply(rec)
+
+	if db.records > compactMinRecords && db.records > 2*db.live {
+		if err := db.compact(); err != nil {
+			// журнал остаётся корректным, просто не уплотнённым
+			db.logger.Error("filedb: compaction failed", "path", db.path, "error", err)
+		}
+	}
+	return nil
+}
+
+// compact переписывает журнал, оставляя только ревизии живых резюме.
+// Вызывается под mu.
+func (db *DB) compact() error {
+	tmpPath := db.path + ".compact"
+	tmp, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	w := bufio.NewWriter(tmp)
+	_, _ = w.Write(header())
+	ids := make([]string, 0, len(db.revs))
+	for id := range db.revs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		// ревизии пишутся по возрастанию версии: последняя станет текущей
+		for _, v := range sortedVersions(db.revs[id]) {
+			buf, err := encodeRecord(record{Op: opPut, ID: id, Version: v, Doc: db.revs[id][v]})
+			if err != nil {
+				tmp.Close()
+				return err
+			}
+			_, _ = w.Write(buf)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := os.Rename(tmpPath, db.path); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	db.file.Close()
+	db.file = tmp
+	db.records = db.live
+	return nil
+}
+
+func sortedVersions(revs map[int]json.RawMessage) []int {
+	out := make([]int, 0, len(revs))
+	for v := range revs {
+		out = append(out, v)
+	}
+	sort.Ints(out)
+	return out
+}
+
+func (db *DB) decode(id string) (resume.StoredResume, error) {
+	raw, ok := db.docs[id]
+	if !ok {
+		return resume.StoredResume{}, resume.ErrNotFound
+	}
+	var doc resume.StoredResume
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return resume.StoredResume{}, fmt.Errorf("decode resume %s: %w", id, err)
+	}
+	return doc, nil
+}
+
+func (db *DB) put(doc resume.StoredResume) error {
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("encode resume %s: %w", doc.ID, err)
+	}
+	return db.append(record{Op: opPut, ID: doc.ID, Version: doc.Version, Doc: raw})
+}
+
+// List возвращает все резюме, последние изменённые — первыми.
+func (db *DB) List(ctx context.Context) ([]resume.Summary, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	out := make([]resume.Summary, 0, len(db.docs))
+	for id := range db.docs {
+		doc, err := db.decode(id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, doc.Summarize())
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out, nil
+}
+
+// Get возвращает резюме по id.
+func (db *DB) Get(ctx context.Context, id string) (resume.StoredResume, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.decode(id)
+}
+
+// Create сохраняет новое резюме с версией 1.
+func (db *DB) Create(ctx context.Context, r resume.Resume) (resume.StoredResume, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	now := db.now().UTC()
+	doc := resume.StoredResume{
+		ID:        resume.NewID(),
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Resume:    r,
+	}
+	if err := db.put(doc); err != nil {
+		return resume.StoredResume{}, err
+	}
+	return doc, nil
+}
+
+// Update заменяет резюме, если его текущая версия равна version.
+func (db *DB) Update(ctx context.Context, id string, version int, r resume.Resume) (resume.StoredResume, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	doc, err := db.decode(id)
+	if err != nil {
+		return resume.StoredResume{}, err
+	}
+	if doc.Version != version {
+		return resume.StoredResume{}, &resume.VersionConflictError{ID: id, Expected: version, Current: doc.Version}
+	}
+
+	doc.Version++
+	doc.UpdatedAt = db.now().UTC()
+	doc.Resume = r
+	if err := db.put(doc); err != nil {
+		return resume.StoredResume{}, err
+	}
+	return doc, nil
+}
+
+// Delete удаляет резюме, если его текущая версия равна version.
+func (db *DB) Delete(ctx context.Context, id string, version int) error {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	doc, err := db.decode(id)
+	if err != nil {
+		return err
+	}
+	if doc.Version != version {
+		return &resume.VersionConflictError{ID: id, Expected: version, Current: doc.Version}
+	}
+	return db.append(record{Op: opDelete, ID: id})
+}
+
+// Revisions возвращает ревизии резюме по возрастанию номера.
+func (db *DB) Revisions(ctx context.Context, id string) ([]resume.RevisionInfo, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	revs, ok := db.revs[id]
+	if !ok {
+		return nil, resume.ErrNotFound
+	}
+
+	out := make([]resume.RevisionInfo, 0, len(revs))
+	for _, v := range sortedVersions(revs) {
+		var doc resume.StoredResume
+		if err := json.Unmarshal(revs[v], &doc); err != nil {
+			return nil, fmt.Errorf("decode revision %s@%d: %w", id, v, err)
+		}
+		out = append(out, doc.RevisionInfo())
+	}
+	return out, nil
+}
+
+// Revision возвращает резюме в том виде, в каком оно было в ревизии rev.
+func (db *DB) Revision(ctx context.Context, id string, rev int) (resume.StoredResume, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	revs, ok := db.revs[id]
+	if !ok {
+		return resume.StoredResume{}, resume.ErrNotFound
+	}
+	raw, ok := revs[rev]
+	if !ok {
+		return resume.StoredResume{}, resume.ErrRevisionNotFound
+	}
+
+	var doc resume.StoredResume
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return resume.StoredResume{}, fmt.Errorf("decode revision %s@%d: %w", id, rev, err)
+	}
+	return doc, nil
+}
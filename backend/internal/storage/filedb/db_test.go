@@ -0,0 +1,199 @@
+package filedb
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"resume_backend/internal/resume"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func openDB(t *testing.T, path string) *DB {
+	t.Helper()
+	db, err := Open(path, discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.Size()
+}
+
+func TestOpenDropsDamagedTail(t *testing.T) {
+	tests := []struct {
+		name string
+		// damage портит файл, в котором последней идёт запись по смещению last
+		damage func(f *os.File, last, size int64) error
+	}{
+		{"torn payload", func(f *os.File, last, size int64) error {
+			return f.Truncate(size - 3)
+		}},
+		{"torn header", func(f *os.File, last, size int64) error {
+			return f.Truncate(last + 5)
+		}},
+		{"checksum mismatch", func(f *os.File, last, size int64) error {
+			_, err := f.WriteAt([]byte{'#'}, size-2)
+			return err
+		}},
+		{"garbage length", func(f *os.File, last, size int64) error {
+			var b [4]byte
+			binary.LittleEndian.PutUint32(b[:], maxRecordSize+1)
+			_, err := f.WriteAt(b[:], last)
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			path := filepath.Join(t.TempDir(), "resumes.db")
+			db := openDB(t, path)
+
+			kept, err := db.Create(ctx, resume.Resume{FullName: "v1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.Update(ctx, kept.ID, 1, resume.Resume{FullName: "v2"}); err != nil {
+				t.Fatal(err)
+			}
+			last := fileSize(t, path)
+			lost, err := db.Create(ctx, resume.Resume{FullName: "lost"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			db.Close()
+
+			f, err := os.OpenFile(path, os.O_RDWR, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = tt.damage(f, last, fileSize(t, path))
+			f.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			db = openDB(t, path)
+			if got := fileSize(t, path); got != last {
+				t.Errorf("file size after open = %d, want %d", got, last)
+			}
+			if _, err := db.Get(ctx, lost.ID); !errors.Is(err, resume.ErrNotFound) {
+				t.Errorf("damaged record: err = %v, want ErrNotFound", err)
+			}
+			revs, err := db.Revisions(ctx, kept.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(revs) != 2 {
+				t.Errorf("Revisions = %+v, want 2", revs)
+			}
+
+			// новые записи ложатся на место отброшенного хвоста и переживают
+			// следующее открытие
+			if _, err := db.Update(ctx, kept.ID, 2, resume.Resume{FullName: "v3"}); err != nil {
+				t.Fatal(err)
+			}
+			db.Close()
+			db = openDB(t, path)
+			got, err := db.Get(ctx, kept.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Version != 3 || got.Resume.FullName != "v3" {
+				t.Errorf("after reopen: version %d %q, want 3 \"v3\"", got.Version, got.Resume.FullName)
+			}
+		})
+	}
+}
+
+func TestOpenRejectsForeignFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resumes.db")
+	if err := os.WriteFile(path, []byte("SQLite format 3\x00"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, discard); err == nil {
+		t.Fatal("Open accepted a file without the RESUMEDB header")
+	}
+	// чужой файл не должен обрезаться как повреждённый журнал
+	if got := fileSize(t, path); got != 16 {
+		t.Errorf("file size = %d, want 16", got)
+	}
+}
+
+func TestCompaction(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "resumes.db")
+	db := openDB(t, path)
+
+	kept, err := db.Create(ctx, resume.Resume{FullName: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Update(ctx, kept.ID, 1, resume.Resume{FullName: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+	var deleted []string
+	for i := 0; i < compactMinRecords/2+1; i++ {
+		doc, err := db.Create(ctx, resume.Resume{FullName: "tmp"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Delete(ctx, doc.ID, 1); err != nil {
+			t.Fatal(err)
+		}
+		deleted = append(deleted, doc.ID)
+	}
+
+	if db.records > compactMinRecords {
+		t.Fatalf("records = %d, journal was not compacted", db.records)
+	}
+	if _, err := os.Stat(path + ".compact"); !os.IsNotExist(err) {
+		t.Errorf("temporary compaction file left behind: %v", err)
+	}
+
+	// запись после уплотнения идёт уже в новый файл
+	if _, err := db.Update(ctx, kept.ID, 2, resume.Resume{FullName: "v3"}); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	db = openDB(t, path)
+
+	list, err := db.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != kept.ID {
+		t.Fatalf("List = %+v, want only %s", list, kept.ID)
+	}
+	for _, id := range deleted {
+		if _, err := db.Get(ctx, id); !errors.Is(err, resume.ErrNotFound) {
+			t.Fatalf("deleted %s: err = %v, want ErrNotFound", id, err)
+		}
+	}
+	for rev, want := range map[int]string{1: "v1", 2: "v2", 3: "v3"} {
+		got, err := db.Revision(ctx, kept.ID, rev)
+		if err != nil {
+			t.Fatalf("Revision %d: %v", rev, err)
+		}
+		if got.Resume.FullName != want {
+			t.Errorf("Revision %d = %q, want %q", rev, got.Resume.FullName, want)
+		}
+	}
+	if cur, _ := db.Get(ctx, kept.ID); cur.Version != 3 {
+		t.Errorf("current version = %d, want 3", cur.Version)
+	}
+}
@@ -0,0 +1,266 @@
+// Package fsstore хранит резюме в каталоге файловой системы: по одному
+// JSON-файлу <id>.json на резюме и неизменяемые ревизии в
+// revisions/<id>/<version>.json. Файлы записываются атомарно (временный файл
+// и rename), поэтому каталог можно бэкапить обычным копированием.
+package fsstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"resume_backend/internal/resume"
+)
+
+// Store реализует resume.Repository поверх каталога.
+type Store struct {
+	dir string
+	// mu сериализует изменения: проверка версии и запись должны быть атомарны.
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// Open создаёт каталог dir (если его нет) и возвращает хранилище.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	return &Store{dir: dir, now: time.Now}, nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *Store) revisionDir(id string) string {
+	return filepath.Join(s.dir, "revisions", id)
+}
+
+func (s *Store) revisionPath(id string, rev int) string {
+	return filepath.Join(s.revisionDir(id), strconv.Itoa(rev)+".json")
+}
+
+// List возвращает все резюме, последние изменённые — первыми.
+func (s *Store) List(ctx context.Context) ([]resume.Summary, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("read storage dir: %w", err)
+	}
+
+	out := []resume.Summary{}
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() || !resume.ValidID(id) {
+			continue
+		}
+		doc, err := s.read(id)
+		if errors.Is(err, resume.ErrNotFound) {
+			// удалён между ReadDir и чтением
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, doc.Summarize())
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].UpdatedAt.After(out[j].UpdatedAt) })
+	return out, nil
+}
+
+// Get возвращает резюме по id.
+func (s *Store) Get(ctx context.Context, id string) (resume.StoredResume, error) {
+	if !resume.ValidID(id) {
+		return resume.StoredResume{}, resume.ErrNotFound
+	}
+	return s.read(id)
+}
+
+// Create сохраняет новое резюме с версией 1.
+func (s *Store) Create(ctx context.Context, r resume.Resume) (resume.StoredResume, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	doc := resume.StoredResume{
+		ID:        resume.NewID(),
+		Version:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Resume:    r,
+	}
+	if err := s.write(doc); err != nil {
+		return resume.StoredResume{}, err
+	}
+	return doc, nil
+}
+
+// Update заменяет резюме, если его текущая версия равна version.
+func (s *Store) Update(ctx context.Context, id string, version int, r resume.Resume) (resume.StoredResume, error) {
+	if !resume.ValidID(id) {
+		return resume.StoredResume{}, resume.ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.read(id)
+	if err != nil {
+		return resume.StoredResume{}, err
+	}
+	if doc.Version != version {
+		return resume.StoredResume{}, &resume.VersionConflictError{ID: id, Expected: version, Current: doc.Version}
+	}
+
+	doc.Version++
+	doc.UpdatedAt = s.now().UTC()
+	doc.Resume = r
+	if err := s.write(doc); err != nil {
+		return resume.StoredResume{}, err
+	}
+	return doc, nil
+}
+
+// Delete удаляет резюме, если его текущая версия равна version.
+func (s *Store) Delete(ctx context.Context, id string, version int) error {
+	if !resume.ValidID(id) {
+		return resume.ErrNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.read(id)
+	if err != nil {
+		return err
+	}
+	if doc.Version != version {
+		return &resume.VersionConflictError{ID: id, Expected: version, Current: doc.Version}
+	}
+
+	if err := os.Remove(s.path(id)); err != nil {
+		return fmt.Errorf("remove resume %s: %w", id, err)
+	}
+	if err := os.RemoveAll(s.revisionDir(id)); err != nil {
+		return fmt.Errorf("remove revisions of %s: %w", id, err)
+	}
+	return nil
+}
+
+// Revisions возвращает ревизии резюме по возрастанию номера.
+func (s *Store) Revisions(ctx context.Context, id string) ([]resume.RevisionInfo, error) {
+	if _, err := s.Get(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.revisionDir(id))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("read revisions of %s: %w", id, err)
+	}
+
+	out := []resume.RevisionInfo{}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		rev, err := strconv.Atoi(name)
+		if !ok || err != nil {
+			continue
+		}
+		doc, err := s.Revision(ctx, id, rev)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, doc.RevisionInfo())
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Revision < out[j].Revision })
+	return out, nil
+}
+
+// Revision возвращает резюме в том виде, в каком оно было в ревизии rev.
+func (s *Store) Revision(ctx context.Context, id string, rev int) (resume.StoredResume, error) {
+	if !resume.ValidID(id) {
+		return resume.StoredResume{}, resume.ErrNotFound
+	}
+
+	doc, err := readFile(s.revisionPath(id, rev))
+	if errors.Is(err, resume.ErrNotFound) {
+		if _, err := s.read(id); err != nil {
+			return resume.StoredResume{}, err
+		}
+		return resume.StoredResume{}, resume.ErrRevisionNotFound
+	}
+	return doc, err
+}
+
+func (s *Store) read(id string) (resume.StoredResume, error) {
+	return readFile(s.path(id))
+}
+
+func readFile(path string) (resume.StoredResume, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return resume.StoredResume{}, resume.ErrNotFound
+	}
+	if err != nil {
+		return resume.StoredResume{}, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	var doc resume.StoredResume
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return resume.StoredResume{}, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// write сохраняет документ: сначала неизменяемую ревизию, затем текущую
+// версию, чтобы текущая версия никогда не опережала историю.
+func (s *Store) write(doc resume.StoredResume) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode resume %s: %w", doc.ID, err)
+	}
+
+	if err := os.MkdirAll(s.revisionDir(doc.ID), 0o755); err != nil {
+		return fmt.Errorf("create revisions dir: %w", err)
+	}
+	if err := writeAtomic(s.revisionPath(doc.ID, doc.Version), data); err != nil {
+		return err
+	}
+	return writeAtomic(s.path(doc.ID), data)
+}
+
+// writeAtomic записывает файл через временный файл в том же каталоге,
+// fsync и rename поверх старого.
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package fsstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"resume_backend/internal/resume"
+)
+
+func TestRevisions(t *testing.T) {
+	ctx := context.Background()
+	s, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := s.Create(ctx, resume.Resume{FullName: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Update(ctx, doc.ID, 1, resume.Resume{FullName: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	revs, err := s.Revisions(ctx, doc.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 2 || revs[0].Revision != 1 || revs[1].Revision != 2 {
+		t.Fatalf("Revisions = %+v, want revisions 1 and 2", revs)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		rev  int
+		want string
+		err  error
+	}{
+		{"first", doc.ID, 1, "v1", nil},
+		{"current", doc.ID, 2, "v2", nil},
+		{"missing revision", doc.ID, 3, "", resume.ErrRevisionNotFound},
+		{"missing resume", resume.NewID(), 1, "", resume.ErrNotFound},
+		{"invalid id", "../x", 1, "", resume.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Revision(ctx, tt.id, tt.rev)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if got.Resume.FullName != tt.want {
+				t.Errorf("FullName = %q, want %q", got.Resume.FullName, tt.want)
+			}
+		})
+	}
+
+	if err := s.Delete(ctx, doc.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Revisions(ctx, doc.ID); !errors.Is(err, resume.ErrNotFound) {
+		t.Errorf("Revisions after delete: err = %v, want ErrNotFound", err)
+	}
+}
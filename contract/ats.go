@@ -0,0 +1,45 @@
+package contract
+
+// Статусы поля в ATS-отчёте.
+const (
+	ATSStatusOK        = "ok"
+	ATSStatusMissing   = "missing"
+	ATSStatusReordered = "reordered"
+	ATSStatusGarbled   = "garbled"
+)
+
+// Причины статуса garbled.
+const (
+	ATSReasonLigature    = "ligature"    // совпадает только после разложения лигатур (fi, fl, ...)
+	ATSReasonHyphenation = "hyphenation" // слово разорвано переносом в конце строки
+	ATSReasonEncoding    = "encoding"    // часть символов не отображается в Unicode
+	ATSReasonPartial     = "partial"     // извлекается лишь часть слов поля
+)
+
+// ATSReport — результат проверки того, как текст резюме извлекается из PDF.
+// Возвращается latex-service в режиме ?check=ats вместе с PDF.
+type ATSReport struct {
+	// Score — итоговая оценка 0..100, где 100 — все поля извлекаются как есть
+	// и в исходном порядке.
+	Score   int              `json:"score"`
+	Summary ATSSummary       `json:"summary"`
+	Fields  []ATSFieldResult `json:"fields"`
+	// Issues — человекочитаемые предупреждения о критичных полях.
+	Issues        []string `json:"issues"`
+	ExtractedText string   `json:"extractedText"`
+}
+
+// ATSSummary — количество полей по статусам.
+type ATSSummary struct {
+	OK        int `json:"ok"`
+	Missing   int `json:"missing"`
+	Reordered int `json:"reordered"`
+	Garbled   int `json:"garbled"`
+}
+
+// ATSFieldResult — результат проверки одного поля резюме.
+type ATSFieldResult struct {
+	Field  string `json:"field"` // путь в нотации FieldError, например "experience[0].bullets[1]"
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
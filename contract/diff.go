@@ -0,0 +1,33 @@
+package contract
+
+// Виды строк визуального diff.
+const (
+	DiffSame    = "same"
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// DiffDocument — визуальное сравнение двух версий резюме, которое
+// latex-service рендерит в PDF с подсветкой изменений.
+type DiffDocument struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	Title         string        `json:"title"`
+	Subtitle      string        `json:"subtitle"`
+	Sections      []DiffSection `json:"sections"`
+}
+
+// DiffSection — раздел резюме в визуальном diff.
+type DiffSection struct {
+	Title string     `json:"title"`
+	Lines []DiffLine `json:"lines"`
+}
+
+// DiffLine — строка визуального diff. Для DiffChanged в Old лежит прежний
+// текст, в Text — новый.
+type DiffLine struct {
+	Kind   string `json:"kind"`
+	Text   string `json:"text"`
+	Old    string `json:"old,omitempty"`
+	Bullet bool   `json:"bullet,omitempty"` // строка — пункт списка
+}
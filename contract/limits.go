@@ -0,0 +1,11 @@
+package contract
+
+// DefaultMaxRequestSize — предельный размер запроса рендера по умолчанию:
+// тела POST /internal/v1/render* и сообщения gRPC. latex-service меняет его
+// через MAX_RENDER_BODY_SIZE, backend должен знать то же значение
+// (LATEX_MAX_REQUEST_SIZE): от него считаются лимиты тела и фото.
+const DefaultMaxRequestSize = 4 << 20
+
+// ErrCodePayloadTooLarge — код ошибки в JSON-конверте и причина ошибки
+// gRPC, если запрос больше допустимого размера.
+const ErrCodePayloadTooLarge = "payload_too_large"
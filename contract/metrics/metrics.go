@@ -0,0 +1,313 @@
+// Package metrics — счётчики и гистограммы в текстовом формате
+// Prometheus (exposition format 0.0.4) без внешних зависимостей. Оба
+// сервиса регистрируют метрики в Default и отдают его на /metrics.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType — тип ответа /metrics.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DurationBuckets — границы гистограмм длительности в секундах: от 5 мс
+// до минуты, с запасом на долгий latexmk.
+var DurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// SizeBuckets — границы гистограмм размера в байтах: от 1 КиБ до 64 МиБ.
+var SizeBuckets = ExponentialBuckets(1024, 4, 9)
+
+// ExponentialBuckets возвращает n границ: start, start*factor, ...
+func ExponentialBuckets(start, factor float64, n int) []float64 {
+	b := make([]float64, n)
+	for i := range b {
+		b[i] = start
+		start *= factor
+	}
+	return b
+}
+
+// Method возвращает метод запроса для метки method. Метод приходит от
+// клиента как есть, поэтому всё, кроме стандартных методов HTTP,
+// сводится к "other": иначе каждый выдуманный метод давал бы новый ряд.
+func Method(m string) string {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return m
+	}
+	return "other"
+}
+
+// Registry — набор метрик, которые выводятся вместе.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]bool
+}
+
+// Default — реестр процесса.
+var Default = NewRegistry()
+
+// NewRegistry создаёт пустой реестр.
+func NewRegistry() *Registry {
+	return &Registry{names: map[string]bool{}}
+}
+
+type metric interface {
+	write(w *bufio.Writer)
+}
+
+func (r *Registry) register(name string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.names[name] {
+		panic("metrics: duplicate metric " + name)
+	}
+	r.names[name] = true
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteTo выводит все метрики реестра в порядке регистрации.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	ms := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	for _, m := range ms {
+		m.write(bw)
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// Handler отдаёт метрики реестра в ответ на GET.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", ContentType)
+		w.Header().Set("Cache-Control", "no-store")
+		if req.Method == http.MethodHead {
+			return
+		}
+		_, _ = r.WriteTo(w)
+	})
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// desc — имя, описание и имена меток метрики.
+type desc struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (d desc) header(w *bufio.Writer, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", d.name, escapeHelp(d.help), d.name, typ)
+}
+
+// series возвращает ключ ряда по значениям меток, проверяя их число.
+func (d desc) series(values []string) string {
+	if len(values) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.name, len(d.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+// labelPairs форматирует метки ряда; extra добавляется последней (le у
+// гистограмм).
+func (d desc) labelPairs(key, extra string) string {
+	var pairs []string
+	if len(d.labels) > 0 {
+		for i, v := range strings.Split(key, "\xff") {
+			pairs = append(pairs, d.labels[i]+`="`+escapeLabel(v)+`"`)
+		}
+	}
+	if extra != "" {
+		pairs = append(pairs, extra)
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// Counter — монотонно растущий счётчик с метками.
+type Counter struct {
+	desc
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounter регистрирует счётчик. Ряды появляются при первом
+// увеличении, у счётчика без меток — сразу с нулём.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{desc: desc{name: name, help: help, labels: labels}, values: map[string]float64{}}
+	if len(labels) == 0 {
+		c.values[""] = 0
+	}
+	r.register(name, c)
+	return c
+}
+
+// Inc увеличивает ряд с данными значениями меток на единицу.
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add увеличивает ряд на v; отрицательные v игнорируются.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	key := c.series(labelValues)
+	c.mu.Lock()
+	c.values[key] += v
+	c.mu.Unlock()
+}
+
+func (c *Counter) write(w *bufio.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.header(w, "counter")
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, c.labelPairs(key, ""), formatFloat(c.values[key]))
+	}
+}
+
+// Histogram — гистограмма с метками.
+type Histogram struct {
+	desc
+	buckets []float64
+	mu      sync.Mutex
+	rows    map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	counts []uint64 // не накопительные, по границам buckets
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram регистрирует гистограмму с границами buckets (по
+// возрастанию; +Inf добавляется сама).
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if !sort.Float64sAreSorted(buckets) {
+		panic("metrics: buckets of " + name + " are not sorted")
+	}
+	h := &Histogram{desc: desc{name: name, help: help, labels: labels}, buckets: buckets, rows: map[string]*histogramSeries{}}
+	r.register(name, h)
+	return h
+}
+
+// Observe добавляет наблюдение v в ряд с данными значениями меток.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	key := h.series(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s := h.rows[key]
+	if s == nil {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.rows[key] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// ObserveDuration добавляет длительность в секундах.
+func (h *Histogram) ObserveDuration(d time.Duration, labelValues ...string) {
+	h.Observe(d.Seconds(), labelValues...)
+}
+
+func (h *Histogram) write(w *bufio.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.header(w, "histogram")
+	for _, key := range sortedKeys(h.rows) {
+		s := h.rows[key]
+		var cum uint64
+		for i, b := range h.buckets {
+			cum += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, `le="`+formatFloat(b)+`"`), cum)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, h.labelPairs(key, `le="+Inf"`), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, h.labelPairs(key, ""), formatFloat(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, h.labelPairs(key, ""), s.count)
+	}
+}
+
+// GaugeFunc — значение, которое вычисляется при каждом выводе метрик.
+type GaugeFunc struct {
+	desc
+	fn func() float64
+}
+
+// NewGaugeFunc регистрирует gauge без меток, значение которого
+// возвращает fn.
+func (r *Registry) NewGaugeFunc(name, help string, fn func() float64) *GaugeFunc {
+	g := &GaugeFunc{desc: desc{name: name, help: help}, fn: fn}
+	r.register(name, g)
+	return g
+}
+
+func (g *GaugeFunc) write(w *bufio.Writer) {
+	g.header(w, "gauge")
+	fmt.Fprintf(w, "%s %s\n", g.name, formatFloat(g.fn()))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
@@ -0,0 +1,32 @@
+package contract
+
+// ProgressContentType — тип ответа /internal/v1/render?progress=1: поток
+// RenderEvent, по одному JSON-объекту на строку.
+const ProgressContentType = "application/x-ndjson"
+
+// Этапы рендера в потоке прогресса.
+const (
+	StageTemplate = "template" // подстановка данных в шаблон
+	StagePhoto    = "photo"    // декодирование и проверка фото
+	StageCompile  = "compile"  // очередной проход pdflatex под управлением latexmk
+	StageDone     = "done"     // последнее событие успешного рендера, содержит PDF
+	StageError    = "error"    // последнее событие неудачного рендера
+)
+
+// RenderEvent — событие потока прогресса. Поток всегда заканчивается ровно
+// одним событием StageDone или StageError: статус HTTP к этому моменту уже
+// отправлен, поэтому ошибка передаётся в самом потоке.
+type RenderEvent struct {
+	Stage string `json:"stage"`
+	// Pass — номер прохода для StageCompile, начиная с 1.
+	Pass int `json:"pass,omitempty"`
+	// MaxPasses — верхняя граница числа проходов (max_repeat latexmk);
+	// фактически их обычно меньше.
+	MaxPasses int `json:"maxPasses,omitempty"`
+	// PDF — готовый документ в событии StageDone (base64 в JSON).
+	PDF []byte `json:"pdf,omitempty"`
+	// Error и Message — код и описание ошибки в событии StageError,
+	// коды те же, что в JSON-ошибках latex-service.
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,202 @@
+package renderpb
+
+import (
+	"encoding/base64"
+	"strings"
+
+	contract "resume_contract"
+)
+
+// ResumeFromContract переводит contract.Resume в сообщение gRPC. Фото
+// декодируется из base64 в байты; фото с некорректным base64 опускается —
+// latex-service всё равно рендерит такое резюме без фото.
+func ResumeFromContract(r contract.Resume) *Resume {
+	out := &Resume{
+		SchemaVersion: int32(r.SchemaVersion),
+		FullName:      r.FullName,
+		Position:      r.Position,
+		Summary:       r.Summary,
+		Contacts: &Contacts{
+			Email:    r.Contacts.Email,
+			Phone:    r.Contacts.Phone,
+			Location: r.Contacts.Location,
+		},
+		Skills: r.Skills,
+	}
+
+	for _, l := range r.Contacts.Links {
+		out.Contacts.Links = append(out.Contacts.Links, &Link{Label: l.Label, Url: l.URL})
+	}
+
+	for _, e := range r.Experience {
+		out.Experience = append(out.Experience, &ExperienceEntry{
+			Company:     e.Company,
+			Position:    e.Position,
+			Location:    e.Location,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+			Description: e.Description,
+			Bullets:     e.Bullets,
+		})
+	}
+
+	for _, e := range r.Education {
+		out.Education = append(out.Education, &EducationEntry{
+			Institution: e.Institution,
+			Degree:      e.Degree,
+			Location:    e.Location,
+			StartDate:   e.StartDate,
+			EndDate:     e.EndDate,
+			Details:     e.Details,
+		})
+	}
+
+	for _, cs := range r.CustomSections {
+		out.CustomSections = append(out.CustomSections, &CustomSection{
+			Title:        cs.Title,
+			BulletSymbol: cs.BulletSymbol,
+			Items:        cs.Items,
+		})
+	}
+
+	if r.Photo != nil {
+		if data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(r.Photo.Data)); err == nil && len(data) > 0 {
+			out.Photo = &Photo{MimeType: r.Photo.MimeType, Data: data}
+		}
+	}
+
+	return out
+}
+
+// ResumeToContract — обратное преобразование. Фото снова кодируется
+// в base64: рендерер latex-service работает с contract.Resume.
+func ResumeToContract(r *Resume) contract.Resume {
+	out := contract.Resume{
+		SchemaVersion: int(r.GetSchemaVersion()),
+		FullName:      r.GetFullName(),
+		Position:      r.GetPosition(),
+		Summary:       r.GetSummary(),
+		Contacts: contract.Contacts{
+			Email:    r.GetContacts().GetEmail(),
+			Phone:    r.GetContacts().GetPhone(),
+			Location: r.GetContacts().GetLocation(),
+		},
+		Skills: r.GetSkills(),
+	}
+
+	for _, l := range r.GetContacts().GetLinks() {
+		out.Contacts.Links = append(out.Contacts.Links, contract.Link{Label: l.GetLabel(), URL: l.GetUrl()})
+	}
+
+	for _, e := range r.GetExperience() {
+		out.Experience = append(out.Experience, contract.ExperienceEntry{
+			Company:     e.GetCompany(),
+			Position:    e.GetPosition(),
+			Location:    e.GetLocation(),
+			StartDate:   e.GetStartDate(),
+			EndDate:     e.GetEndDate(),
+			Description: e.GetDescription(),
+			Bullets:     e.GetBullets(),
+		})
+	}
+
+	for _, e := range r.GetEducation() {
+		out.Education = append(out.Education, contract.EducationEntry{
+			Institution: e.GetInstitution(),
+			Degree:      e.GetDegree(),
+			Location:    e.GetLocation(),
+			StartDate:   e.GetStartDate(),
+			EndDate:     e.GetEndDate(),
+			Details:     e.GetDetails(),
+		})
+	}
+
+	for _, cs := range r.GetCustomSections() {
+		out.CustomSections = append(out.CustomSections, contract.CustomSection{
+			Title:        cs.GetTitle(),
+			BulletSymbol: cs.GetBulletSymbol(),
+			Items:        cs.GetItems(),
+		})
+	}
+
+	if p := r.GetPhoto(); len(p.GetData()) > 0 {
+		out.Photo = &contract.Photo{
+			MimeType: p.GetMimeType(),
+			Data:     base64.StdEncoding.EncodeToString(p.GetData()),
+		}
+	}
+
+	return out
+}
+
+// DiffFromContract переводит contract.DiffDocument в сообщение gRPC.
+func DiffFromContract(doc contract.DiffDocument) *DiffDocument {
+	out := &DiffDocument{
+		SchemaVersion: int32(doc.SchemaVersion),
+		Title:         doc.Title,
+		Subtitle:      doc.Subtitle,
+	}
+	for _, s := range doc.Sections {
+		sec := &DiffSection{Title: s.Title}
+		for _, l := range s.Lines {
+			sec.Lines = append(sec.Lines, &DiffLine{Kind: l.Kind, Text: l.Text, Old: l.Old, Bullet: l.Bullet})
+		}
+		out.Sections = append(out.Sections, sec)
+	}
+	return out
+}
+
+// DiffToContract — обратное преобразование.
+func DiffToContract(doc *DiffDocument) contract.DiffDocument {
+	out := contract.DiffDocument{
+		SchemaVersion: int(doc.GetSchemaVersion()),
+		Title:         doc.GetTitle(),
+		Subtitle:      doc.GetSubtitle(),
+	}
+	for _, s := range doc.GetSections() {
+		sec := contract.DiffSection{Title: s.GetTitle()}
+		for _, l := range s.GetLines() {
+			sec.Lines = append(sec.Lines, contract.DiffLine{Kind: l.GetKind(), Text: l.GetText(), Old: l.GetOld(), Bullet: l.GetBullet()})
+		}
+		out.Sections = append(out.Sections, sec)
+	}
+	return out
+}
+
+// ATSReportFromContract переводит contract.ATSReport в сообщение gRPC.
+func ATSReportFromContract(r contract.ATSReport) *AtsReport {
+	out := &AtsReport{
+		Score: int32(r.Score),
+		Summary: &AtsSummary{
+			Ok:        int32(r.Summary.OK),
+			Missing:   int32(r.Summary.Missing),
+			Reordered: int32(r.Summary.Reordered),
+			Garbled:   int32(r.Summary.Garbled),
+		},
+		Issues:        r.Issues,
+		ExtractedText: r.ExtractedText,
+	}
+	for _, f := range r.Fields {
+		out.Fields = append(out.Fields, &AtsFieldResult{Field: f.Field, Status: f.Status, Reason: f.Reason})
+	}
+	return out
+}
+
+// ATSReportToContract — обратное преобразование.
+func ATSReportToContract(r *AtsReport) contract.ATSReport {
+	out := contract.ATSReport{
+		Score: int(r.GetScore()),
+		Summary: contract.ATSSummary{
+			OK:        int(r.GetSummary().GetOk()),
+			Missing:   int(r.GetSummary().GetMissing()),
+			Reordered: int(r.GetSummary().GetReordered()),
+			Garbled:   int(r.GetSummary().GetGarbled()),
+		},
+		Issues:        r.GetIssues(),
+		ExtractedText: r.GetExtractedText(),
+	}
+	for _, f := range r.GetFields() {
+		out.Fields = append(out.Fields, contract.ATSFieldResult{Field: f.GetField(), Status: f.GetStatus(), Reason: f.GetReason()})
+	}
+	return out
+}
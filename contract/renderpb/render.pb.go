@@ -0,0 +1,1570 @@
+// Внутренний gRPC-API latex-service — альтернатива HTTP-маршрутам
+// /internal/v1/render*. Сообщения повторяют типы пакета contract;
+// отличия: фото передаётся сырыми байтами, а не base64, и PDF приходит
+// частями (PdfChunk) в потоке ответа.
+//
+// После изменения файла перегенерируйте код: buf generate (см. buf.gen.yaml).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: render/v1/render.proto
+
+package renderpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RenderRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Resume *Resume                `protobuf:"bytes,1,opt,name=resume,proto3" json:"resume,omitempty"`
+	// progress — присылать события этапов (Progress).
+	Progress bool `protobuf:"varint,2,opt,name=progress,proto3" json:"progress,omitempty"`
+	// check_ats — выполнить ATS-проверку и прислать AtsReport.
+	CheckAts      bool `protobuf:"varint,3,opt,name=check_ats,json=checkAts,proto3" json:"check_ats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderRequest) Reset() {
+	*x = RenderRequest{}
+	mi := &file_render_v1_render_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderRequest) ProtoMessage() {}
+
+func (x *RenderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderRequest.ProtoReflect.Descriptor instead.
+func (*RenderRequest) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RenderRequest) GetResume() *Resume {
+	if x != nil {
+		return x.Resume
+	}
+	return nil
+}
+
+func (x *RenderRequest) GetProgress() bool {
+	if x != nil {
+		return x.Progress
+	}
+	return false
+}
+
+func (x *RenderRequest) GetCheckAts() bool {
+	if x != nil {
+		return x.CheckAts
+	}
+	return false
+}
+
+type RenderDiffRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Document      *DiffDocument          `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderDiffRequest) Reset() {
+	*x = RenderDiffRequest{}
+	mi := &file_render_v1_render_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderDiffRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderDiffRequest) ProtoMessage() {}
+
+func (x *RenderDiffRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderDiffRequest.ProtoReflect.Descriptor instead.
+func (*RenderDiffRequest) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RenderDiffRequest) GetDocument() *DiffDocument {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+// RenderResponse — сообщение потока ответа: событие этапа, отчёт или
+// очередная часть PDF. Поток успешного рендера заканчивается частью
+// с last = true.
+type RenderResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*RenderResponse_Progress
+	//	*RenderResponse_AtsReport
+	//	*RenderResponse_Chunk
+	Payload       isRenderResponse_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenderResponse) Reset() {
+	*x = RenderResponse{}
+	mi := &file_render_v1_render_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderResponse) ProtoMessage() {}
+
+func (x *RenderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderResponse.ProtoReflect.Descriptor instead.
+func (*RenderResponse) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RenderResponse) GetPayload() isRenderResponse_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *RenderResponse) GetProgress() *Progress {
+	if x != nil {
+		if x, ok := x.Payload.(*RenderResponse_Progress); ok {
+			return x.Progress
+		}
+	}
+	return nil
+}
+
+func (x *RenderResponse) GetAtsReport() *AtsReport {
+	if x != nil {
+		if x, ok := x.Payload.(*RenderResponse_AtsReport); ok {
+			return x.AtsReport
+		}
+	}
+	return nil
+}
+
+func (x *RenderResponse) GetChunk() *PdfChunk {
+	if x != nil {
+		if x, ok := x.Payload.(*RenderResponse_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isRenderResponse_Payload interface {
+	isRenderResponse_Payload()
+}
+
+type RenderResponse_Progress struct {
+	Progress *Progress `protobuf:"bytes,1,opt,name=progress,proto3,oneof"`
+}
+
+type RenderResponse_AtsReport struct {
+	AtsReport *AtsReport `protobuf:"bytes,2,opt,name=ats_report,json=atsReport,proto3,oneof"`
+}
+
+type RenderResponse_Chunk struct {
+	Chunk *PdfChunk `protobuf:"bytes,3,opt,name=chunk,proto3,oneof"`
+}
+
+func (*RenderResponse_Progress) isRenderResponse_Payload() {}
+
+func (*RenderResponse_AtsReport) isRenderResponse_Payload() {}
+
+func (*RenderResponse_Chunk) isRenderResponse_Payload() {}
+
+// Progress — этап рендера, как contract.RenderEvent без PDF и ошибки.
+type Progress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Pass          int32                  `protobuf:"varint,2,opt,name=pass,proto3" json:"pass,omitempty"`
+	MaxPasses     int32                  `protobuf:"varint,3,opt,name=max_passes,json=maxPasses,proto3" json:"max_passes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_render_v1_render_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Progress) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *Progress) GetPass() int32 {
+	if x != nil {
+		return x.Pass
+	}
+	return 0
+}
+
+func (x *Progress) GetMaxPasses() int32 {
+	if x != nil {
+		return x.MaxPasses
+	}
+	return 0
+}
+
+type PdfChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Data  []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// size — полный размер PDF, только в первой части.
+	Size          int64 `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	Last          bool  `protobuf:"varint,3,opt,name=last,proto3" json:"last,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PdfChunk) Reset() {
+	*x = PdfChunk{}
+	mi := &file_render_v1_render_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PdfChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PdfChunk) ProtoMessage() {}
+
+func (x *PdfChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PdfChunk.ProtoReflect.Descriptor instead.
+func (*PdfChunk) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PdfChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PdfChunk) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PdfChunk) GetLast() bool {
+	if x != nil {
+		return x.Last
+	}
+	return false
+}
+
+type StatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusRequest) Reset() {
+	*x = StatusRequest{}
+	mi := &file_render_v1_render_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusRequest) ProtoMessage() {}
+
+func (x *StatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusRequest.ProtoReflect.Descriptor instead.
+func (*StatusRequest) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{5}
+}
+
+type StatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       int32                  `protobuf:"varint,1,opt,name=workers,proto3" json:"workers,omitempty"`
+	Busy          int32                  `protobuf:"varint,2,opt,name=busy,proto3" json:"busy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusResponse) Reset() {
+	*x = StatusResponse{}
+	mi := &file_render_v1_render_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusResponse) ProtoMessage() {}
+
+func (x *StatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusResponse.ProtoReflect.Descriptor instead.
+func (*StatusResponse) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StatusResponse) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *StatusResponse) GetBusy() int32 {
+	if x != nil {
+		return x.Busy
+	}
+	return 0
+}
+
+// Resume — contract.Resume.
+type Resume struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SchemaVersion  int32                  `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	FullName       string                 `protobuf:"bytes,2,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Position       string                 `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Summary        string                 `protobuf:"bytes,4,opt,name=summary,proto3" json:"summary,omitempty"`
+	Contacts       *Contacts              `protobuf:"bytes,5,opt,name=contacts,proto3" json:"contacts,omitempty"`
+	Skills         []string               `protobuf:"bytes,6,rep,name=skills,proto3" json:"skills,omitempty"`
+	Experience     []*ExperienceEntry     `protobuf:"bytes,7,rep,name=experience,proto3" json:"experience,omitempty"`
+	Education      []*EducationEntry      `protobuf:"bytes,8,rep,name=education,proto3" json:"education,omitempty"`
+	CustomSections []*CustomSection       `protobuf:"bytes,9,rep,name=custom_sections,json=customSections,proto3" json:"custom_sections,omitempty"`
+	Photo          *Photo                 `protobuf:"bytes,10,opt,name=photo,proto3" json:"photo,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Resume) Reset() {
+	*x = Resume{}
+	mi := &file_render_v1_render_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Resume) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Resume) ProtoMessage() {}
+
+func (x *Resume) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Resume.ProtoReflect.Descriptor instead.
+func (*Resume) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Resume) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Resume) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *Resume) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *Resume) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Resume) GetContacts() *Contacts {
+	if x != nil {
+		return x.Contacts
+	}
+	return nil
+}
+
+func (x *Resume) GetSkills() []string {
+	if x != nil {
+		return x.Skills
+	}
+	return nil
+}
+
+func (x *Resume) GetExperience() []*ExperienceEntry {
+	if x != nil {
+		return x.Experience
+	}
+	return nil
+}
+
+func (x *Resume) GetEducation() []*EducationEntry {
+	if x != nil {
+		return x.Education
+	}
+	return nil
+}
+
+func (x *Resume) GetCustomSections() []*CustomSection {
+	if x != nil {
+		return x.CustomSections
+	}
+	return nil
+}
+
+func (x *Resume) GetPhoto() *Photo {
+	if x != nil {
+		return x.Photo
+	}
+	return nil
+}
+
+type Contacts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Phone         string                 `protobuf:"bytes,2,opt,name=phone,proto3" json:"phone,omitempty"`
+	Location      string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Links         []*Link                `protobuf:"bytes,4,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Contacts) Reset() {
+	*x = Contacts{}
+	mi := &file_render_v1_render_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Contacts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Contacts) ProtoMessage() {}
+
+func (x *Contacts) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Contacts.ProtoReflect.Descriptor instead.
+func (*Contacts) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Contacts) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Contacts) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Contacts) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Contacts) GetLinks() []*Link {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+type Link struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Label         string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Link) Reset() {
+	*x = Link{}
+	mi := &file_render_v1_render_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Link) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Link) ProtoMessage() {}
+
+func (x *Link) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Link.ProtoReflect.Descriptor instead.
+func (*Link) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Link) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Link) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type ExperienceEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Company       string                 `protobuf:"bytes,1,opt,name=company,proto3" json:"company,omitempty"`
+	Position      string                 `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	Location      string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	StartDate     string                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	Bullets       []string               `protobuf:"bytes,7,rep,name=bullets,proto3" json:"bullets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExperienceEntry) Reset() {
+	*x = ExperienceEntry{}
+	mi := &file_render_v1_render_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExperienceEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExperienceEntry) ProtoMessage() {}
+
+func (x *ExperienceEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExperienceEntry.ProtoReflect.Descriptor instead.
+func (*ExperienceEntry) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ExperienceEntry) GetCompany() string {
+	if x != nil {
+		return x.Company
+	}
+	return ""
+}
+
+func (x *ExperienceEntry) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *ExperienceEntry) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *ExperienceEntry) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *ExperienceEntry) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *ExperienceEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ExperienceEntry) GetBullets() []string {
+	if x != nil {
+		return x.Bullets
+	}
+	return nil
+}
+
+type EducationEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Institution   string                 `protobuf:"bytes,1,opt,name=institution,proto3" json:"institution,omitempty"`
+	Degree        string                 `protobuf:"bytes,2,opt,name=degree,proto3" json:"degree,omitempty"`
+	Location      string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	StartDate     string                 `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate       string                 `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	Details       string                 `protobuf:"bytes,6,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EducationEntry) Reset() {
+	*x = EducationEntry{}
+	mi := &file_render_v1_render_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EducationEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EducationEntry) ProtoMessage() {}
+
+func (x *EducationEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EducationEntry.ProtoReflect.Descriptor instead.
+func (*EducationEntry) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *EducationEntry) GetInstitution() string {
+	if x != nil {
+		return x.Institution
+	}
+	return ""
+}
+
+func (x *EducationEntry) GetDegree() string {
+	if x != nil {
+		return x.Degree
+	}
+	return ""
+}
+
+func (x *EducationEntry) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *EducationEntry) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *EducationEntry) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *EducationEntry) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+type CustomSection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	BulletSymbol  string                 `protobuf:"bytes,2,opt,name=bullet_symbol,json=bulletSymbol,proto3" json:"bullet_symbol,omitempty"`
+	Items         []string               `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CustomSection) Reset() {
+	*x = CustomSection{}
+	mi := &file_render_v1_render_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CustomSection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CustomSection) ProtoMessage() {}
+
+func (x *CustomSection) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CustomSection.ProtoReflect.Descriptor instead.
+func (*CustomSection) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CustomSection) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CustomSection) GetBulletSymbol() string {
+	if x != nil {
+		return x.BulletSymbol
+	}
+	return ""
+}
+
+func (x *CustomSection) GetItems() []string {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Photo — фото резюме; data — сами байты изображения.
+type Photo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MimeType      string                 `protobuf:"bytes,1,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Photo) Reset() {
+	*x = Photo{}
+	mi := &file_render_v1_render_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Photo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Photo) ProtoMessage() {}
+
+func (x *Photo) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Photo.ProtoReflect.Descriptor instead.
+func (*Photo) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Photo) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *Photo) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// DiffDocument — contract.DiffDocument.
+type DiffDocument struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SchemaVersion int32                  `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Subtitle      string                 `protobuf:"bytes,3,opt,name=subtitle,proto3" json:"subtitle,omitempty"`
+	Sections      []*DiffSection         `protobuf:"bytes,4,rep,name=sections,proto3" json:"sections,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffDocument) Reset() {
+	*x = DiffDocument{}
+	mi := &file_render_v1_render_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffDocument) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffDocument) ProtoMessage() {}
+
+func (x *DiffDocument) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffDocument.ProtoReflect.Descriptor instead.
+func (*DiffDocument) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DiffDocument) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *DiffDocument) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *DiffDocument) GetSubtitle() string {
+	if x != nil {
+		return x.Subtitle
+	}
+	return ""
+}
+
+func (x *DiffDocument) GetSections() []*DiffSection {
+	if x != nil {
+		return x.Sections
+	}
+	return nil
+}
+
+type DiffSection struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Lines         []*DiffLine            `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffSection) Reset() {
+	*x = DiffSection{}
+	mi := &file_render_v1_render_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffSection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffSection) ProtoMessage() {}
+
+func (x *DiffSection) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffSection.ProtoReflect.Descriptor instead.
+func (*DiffSection) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *DiffSection) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *DiffSection) GetLines() []*DiffLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+type DiffLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Old           string                 `protobuf:"bytes,3,opt,name=old,proto3" json:"old,omitempty"`
+	Bullet        bool                   `protobuf:"varint,4,opt,name=bullet,proto3" json:"bullet,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffLine) Reset() {
+	*x = DiffLine{}
+	mi := &file_render_v1_render_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffLine) ProtoMessage() {}
+
+func (x *DiffLine) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffLine.ProtoReflect.Descriptor instead.
+func (*DiffLine) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DiffLine) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *DiffLine) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *DiffLine) GetOld() string {
+	if x != nil {
+		return x.Old
+	}
+	return ""
+}
+
+func (x *DiffLine) GetBullet() bool {
+	if x != nil {
+		return x.Bullet
+	}
+	return false
+}
+
+// AtsReport — contract.ATSReport.
+type AtsReport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         int32                  `protobuf:"varint,1,opt,name=score,proto3" json:"score,omitempty"`
+	Summary       *AtsSummary            `protobuf:"bytes,2,opt,name=summary,proto3" json:"summary,omitempty"`
+	Fields        []*AtsFieldResult      `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
+	Issues        []string               `protobuf:"bytes,4,rep,name=issues,proto3" json:"issues,omitempty"`
+	ExtractedText string                 `protobuf:"bytes,5,opt,name=extracted_text,json=extractedText,proto3" json:"extracted_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AtsReport) Reset() {
+	*x = AtsReport{}
+	mi := &file_render_v1_render_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AtsReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AtsReport) ProtoMessage() {}
+
+func (x *AtsReport) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AtsReport.ProtoReflect.Descriptor instead.
+func (*AtsReport) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AtsReport) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *AtsReport) GetSummary() *AtsSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *AtsReport) GetFields() []*AtsFieldResult {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *AtsReport) GetIssues() []string {
+	if x != nil {
+		return x.Issues
+	}
+	return nil
+}
+
+func (x *AtsReport) GetExtractedText() string {
+	if x != nil {
+		return x.ExtractedText
+	}
+	return ""
+}
+
+type AtsSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ok            int32                  `protobuf:"varint,1,opt,name=ok,proto3" json:"ok,omitempty"`
+	Missing       int32                  `protobuf:"varint,2,opt,name=missing,proto3" json:"missing,omitempty"`
+	Reordered     int32                  `protobuf:"varint,3,opt,name=reordered,proto3" json:"reordered,omitempty"`
+	Garbled       int32                  `protobuf:"varint,4,opt,name=garbled,proto3" json:"garbled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AtsSummary) Reset() {
+	*x = AtsSummary{}
+	mi := &file_render_v1_render_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AtsSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AtsSummary) ProtoMessage() {}
+
+func (x *AtsSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AtsSummary.ProtoReflect.Descriptor instead.
+func (*AtsSummary) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AtsSummary) GetOk() int32 {
+	if x != nil {
+		return x.Ok
+	}
+	return 0
+}
+
+func (x *AtsSummary) GetMissing() int32 {
+	if x != nil {
+		return x.Missing
+	}
+	return 0
+}
+
+func (x *AtsSummary) GetReordered() int32 {
+	if x != nil {
+		return x.Reordered
+	}
+	return 0
+}
+
+func (x *AtsSummary) GetGarbled() int32 {
+	if x != nil {
+		return x.Garbled
+	}
+	return 0
+}
+
+type AtsFieldResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=field,proto3" json:"field,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AtsFieldResult) Reset() {
+	*x = AtsFieldResult{}
+	mi := &file_render_v1_render_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AtsFieldResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AtsFieldResult) ProtoMessage() {}
+
+func (x *AtsFieldResult) ProtoReflect() protoreflect.Message {
+	mi := &file_render_v1_render_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AtsFieldResult.ProtoReflect.Descriptor instead.
+func (*AtsFieldResult) Descriptor() ([]byte, []int) {
+	return file_render_v1_render_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AtsFieldResult) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *AtsFieldResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AtsFieldResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+var File_render_v1_render_proto protoreflect.FileDescriptor
+
+const file_render_v1_render_proto_rawDesc = "" +
+	"\n" +
+	"\x16render/v1/render.proto\x12\x10resume.render.v1\"z\n" +
+	"\rRenderRequest\x120\n" +
+	"\x06resume\x18\x01 \x01(\v2\x18.resume.render.v1.ResumeR\x06resume\x12\x1a\n" +
+	"\bprogress\x18\x02 \x01(\bR\bprogress\x12\x1b\n" +
+	"\tcheck_ats\x18\x03 \x01(\bR\bcheckAts\"O\n" +
+	"\x11RenderDiffRequest\x12:\n" +
+	"\bdocument\x18\x01 \x01(\v2\x1e.resume.render.v1.DiffDocumentR\bdocument\"\xc7\x01\n" +
+	"\x0eRenderResponse\x128\n" +
+	"\bprogress\x18\x01 \x01(\v2\x1a.resume.render.v1.ProgressH\x00R\bprogress\x12<\n" +
+	"\n" +
+	"ats_report\x18\x02 \x01(\v2\x1b.resume.render.v1.AtsReportH\x00R\tatsReport\x122\n" +
+	"\x05chunk\x18\x03 \x01(\v2\x1a.resume.render.v1.PdfChunkH\x00R\x05chunkB\t\n" +
+	"\apayload\"S\n" +
+	"\bProgress\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x12\n" +
+	"\x04pass\x18\x02 \x01(\x05R\x04pass\x12\x1d\n" +
+	"\n" +
+	"max_passes\x18\x03 \x01(\x05R\tmaxPasses\"F\n" +
+	"\bPdfChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\x12\x12\n" +
+	"\x04last\x18\x03 \x01(\bR\x04last\"\x0f\n" +
+	"\rStatusRequest\">\n" +
+	"\x0eStatusResponse\x12\x18\n" +
+	"\aworkers\x18\x01 \x01(\x05R\aworkers\x12\x12\n" +
+	"\x04busy\x18\x02 \x01(\x05R\x04busy\"\xce\x03\n" +
+	"\x06Resume\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\x05R\rschemaVersion\x12\x1b\n" +
+	"\tfull_name\x18\x02 \x01(\tR\bfullName\x12\x1a\n" +
+	"\bposition\x18\x03 \x01(\tR\bposition\x12\x18\n" +
+	"\asummary\x18\x04 \x01(\tR\asummary\x126\n" +
+	"\bcontacts\x18\x05 \x01(\v2\x1a.resume.render.v1.ContactsR\bcontacts\x12\x16\n" +
+	"\x06skills\x18\x06 \x03(\tR\x06skills\x12A\n" +
+	"\n" +
+	"experience\x18\a \x03(\v2!.resume.render.v1.ExperienceEntryR\n" +
+	"experience\x12>\n" +
+	"\teducation\x18\b \x03(\v2 .resume.render.v1.EducationEntryR\teducation\x12H\n" +
+	"\x0fcustom_sections\x18\t \x03(\v2\x1f.resume.render.v1.CustomSectionR\x0ecustomSections\x12-\n" +
+	"\x05photo\x18\n" +
+	" \x01(\v2\x17.resume.render.v1.PhotoR\x05photo\"\x80\x01\n" +
+	"\bContacts\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x02 \x01(\tR\x05phone\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12,\n" +
+	"\x05links\x18\x04 \x03(\v2\x16.resume.render.v1.LinkR\x05links\".\n" +
+	"\x04Link\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\"\xd9\x01\n" +
+	"\x0fExperienceEntry\x12\x18\n" +
+	"\acompany\x18\x01 \x01(\tR\acompany\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\tR\bposition\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x05 \x01(\tR\aendDate\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x18\n" +
+	"\abullets\x18\a \x03(\tR\abullets\"\xba\x01\n" +
+	"\x0eEducationEntry\x12 \n" +
+	"\vinstitution\x18\x01 \x01(\tR\vinstitution\x12\x16\n" +
+	"\x06degree\x18\x02 \x01(\tR\x06degree\x12\x1a\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x05 \x01(\tR\aendDate\x12\x18\n" +
+	"\adetails\x18\x06 \x01(\tR\adetails\"`\n" +
+	"\rCustomSection\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12#\n" +
+	"\rbullet_symbol\x18\x02 \x01(\tR\fbulletSymbol\x12\x14\n" +
+	"\x05items\x18\x03 \x03(\tR\x05items\"8\n" +
+	"\x05Photo\x12\x1b\n" +
+	"\tmime_type\x18\x01 \x01(\tR\bmimeType\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"\xa2\x01\n" +
+	"\fDiffDocument\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\x05R\rschemaVersion\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x1a\n" +
+	"\bsubtitle\x18\x03 \x01(\tR\bsubtitle\x129\n" +
+	"\bsections\x18\x04 \x03(\v2\x1d.resume.render.v1.DiffSectionR\bsections\"U\n" +
+	"\vDiffSection\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x120\n" +
+	"\x05lines\x18\x02 \x03(\v2\x1a.resume.render.v1.DiffLineR\x05lines\"\\\n" +
+	"\bDiffLine\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12\x10\n" +
+	"\x03old\x18\x03 \x01(\tR\x03old\x12\x16\n" +
+	"\x06bullet\x18\x04 \x01(\bR\x06bullet\"\xd2\x01\n" +
+	"\tAtsReport\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x05R\x05score\x126\n" +
+	"\asummary\x18\x02 \x01(\v2\x1c.resume.render.v1.AtsSummaryR\asummary\x128\n" +
+	"\x06fields\x18\x03 \x03(\v2 .resume.render.v1.AtsFieldResultR\x06fields\x12\x16\n" +
+	"\x06issues\x18\x04 \x03(\tR\x06issues\x12%\n" +
+	"\x0eextracted_text\x18\x05 \x01(\tR\rextractedText\"n\n" +
+	"\n" +
+	"AtsSummary\x12\x0e\n" +
+	"\x02ok\x18\x01 \x01(\x05R\x02ok\x12\x18\n" +
+	"\amissing\x18\x02 \x01(\x05R\amissing\x12\x1c\n" +
+	"\treordered\x18\x03 \x01(\x05R\treordered\x12\x18\n" +
+	"\agarbled\x18\x04 \x01(\x05R\agarbled\"V\n" +
+	"\x0eAtsFieldResult\x12\x14\n" +
+	"\x05field\x18\x01 \x01(\tR\x05field\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason2\x82\x02\n" +
+	"\rRenderService\x12M\n" +
+	"\x06Render\x12\x1f.resume.render.v1.RenderRequest\x1a .resume.render.v1.RenderResponse0\x01\x12U\n" +
+	"\n" +
+	"RenderDiff\x12#.resume.render.v1.RenderDiffRequest\x1a .resume.render.v1.RenderResponse0\x01\x12K\n" +
+	"\x06Status\x12\x1f.resume.render.v1.StatusRequest\x1a .resume.render.v1.StatusResponseB#Z!resume_contract/renderpb;renderpbb\x06proto3"
+
+var (
+	file_render_v1_render_proto_rawDescOnce sync.Once
+	file_render_v1_render_proto_rawDescData []byte
+)
+
+func file_render_v1_render_proto_rawDescGZIP() []byte {
+	file_render_v1_render_proto_rawDescOnce.Do(func() {
+		file_render_v1_render_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_render_v1_render_proto_rawDesc), len(file_render_v1_render_proto_rawDesc)))
+	})
+	return file_render_v1_render_proto_rawDescData
+}
+
+var file_render_v1_render_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_render_v1_render_proto_goTypes = []any{
+	(*RenderRequest)(nil),     // 0: resume.render.v1.RenderRequest
+	(*RenderDiffRequest)(nil), // 1: resume.render.v1.RenderDiffRequest
+	(*RenderResponse)(nil),    // 2: resume.render.v1.RenderResponse
+	(*Progress)(nil),          // 3: resume.render.v1.Progress
+	(*PdfChunk)(nil),          // 4: resume.render.v1.PdfChunk
+	(*StatusRequest)(nil),     // 5: resume.render.v1.StatusRequest
+	(*StatusResponse)(nil),    // 6: resume.render.v1.StatusResponse
+	(*Resume)(nil),            // 7: resume.render.v1.Resume
+	(*Contacts)(nil),          // 8: resume.render.v1.Contacts
+	(*Link)(nil),              // 9: resume.render.v1.Link
+	(*ExperienceEntry)(nil),   // 10: resume.render.v1.ExperienceEntry
+	(*EducationEntry)(nil),    // 11: resume.render.v1.EducationEntry
+	(*CustomSection)(nil),     // 12: resume.render.v1.CustomSection
+	(*Photo)(nil),             // 13: resume.render.v1.Photo
+	(*DiffDocument)(nil),      // 14: resume.render.v1.DiffDocument
+	(*DiffSection)(nil),       // 15: resume.render.v1.DiffSection
+	(*DiffLine)(nil),          // 16: resume.render.v1.DiffLine
+	(*AtsReport)(nil),         // 17: resume.render.v1.AtsReport
+	(*AtsSummary)(nil),        // 18: resume.render.v1.AtsSummary
+	(*AtsFieldResult)(nil),    // 19: resume.render.v1.AtsFieldResult
+}
+var file_render_v1_render_proto_depIdxs = []int32{
+	7,  // 0: resume.render.v1.RenderRequest.resume:type_name -> resume.render.v1.Resume
+	14, // 1: resume.render.v1.RenderDiffRequest.document:type_name -> resume.render.v1.DiffDocument
+	3,  // 2: resume.render.v1.RenderResponse.progress:type_name -> resume.render.v1.Progress
+	17, // 3: resume.render.v1.RenderResponse.ats_report:type_name -> resume.render.v1.AtsReport
+	4,  // 4: resume.render.v1.RenderResponse.chunk:type_name -> resume.render.v1.PdfChunk
+	8,  // 5: resume.render.v1.Resume.contacts:type_name -> resume.render.v1.Contacts
+	10, // 6: resume.render.v1.Resume.experience:type_name -> resume.render.v1.ExperienceEntry
+	11, // 7: resume.render.v1.Resume.education:type_name -> resume.render.v1.EducationEntry
+	12, // 8: resume.render.v1.Resume.custom_sections:type_name -> resume.render.v1.CustomSection
+	13, // 9: resume.render.v1.Resume.photo:type_name -> resume.render.v1.Photo
+	9,  // 10: resume.render.v1.Contacts.links:type_name -> resume.render.v1.Link
+	15, // 11: resume.render.v1.DiffDocument.sections:type_name -> resume.render.v1.DiffSection
+	16, // 12: resume.render.v1.DiffSection.lines:type_name -> resume.render.v1.DiffLine
+	18, // 13: resume.render.v1.AtsReport.summary:type_name -> resume.render.v1.AtsSummary
+	19, // 14: resume.render.v1.AtsReport.fields:type_name -> resume.render.v1.AtsFieldResult
+	0,  // 15: resume.render.v1.RenderService.Render:input_type -> resume.render.v1.RenderRequest
+	1,  // 16: resume.render.v1.RenderService.RenderDiff:input_type -> resume.render.v1.RenderDiffRequest
+	5,  // 17: resume.render.v1.RenderService.Status:input_type -> resume.render.v1.StatusRequest
+	2,  // 18: resume.render.v1.RenderService.Render:output_type -> resume.render.v1.RenderResponse
+	2,  // 19: resume.render.v1.RenderService.RenderDiff:output_type -> resume.render.v1.RenderResponse
+	6,  // 20: resume.render.v1.RenderService.Status:output_type -> resume.render.v1.StatusResponse
+	18, // [18:21] is the sub-list for method output_type
+	15, // [15:18] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_render_v1_render_proto_init() }
+func file_render_v1_render_proto_init() {
+	if File_render_v1_render_proto != nil {
+		return
+	}
+	file_render_v1_render_proto_msgTypes[2].OneofWrappers = []any{
+		(*RenderResponse_Progress)(nil),
+		(*RenderResponse_AtsReport)(nil),
+		(*RenderResponse_Chunk)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_render_v1_render_proto_rawDesc), len(file_render_v1_render_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_render_v1_render_proto_goTypes,
+		DependencyIndexes: file_render_v1_render_proto_depIdxs,
+		MessageInfos:      file_render_v1_render_proto_msgTypes,
+	}.Build()
+	File_render_v1_render_proto = out.File
+	file_render_v1_render_proto_goTypes = nil
+	file_render_v1_render_proto_depIdxs = nil
+}
@@ -0,0 +1,237 @@
+// Внутренний gRPC-API latex-service — альтернатива HTTP-маршрутам
+// /internal/v1/render*. Сообщения повторяют типы пакета contract;
+// отличия: фото передаётся сырыми байтами, а не base64, и PDF приходит
+// частями (PdfChunk) в потоке ответа.
+//
+// После изменения файла перегенерируйте код: buf generate (см. buf.gen.yaml).
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: render/v1/render.proto
+
+package renderpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	RenderService_Render_FullMethodName     = "/resume.render.v1.RenderService/Render"
+	RenderService_RenderDiff_FullMethodName = "/resume.render.v1.RenderService/RenderDiff"
+	RenderService_Status_FullMethodName     = "/resume.render.v1.RenderService/Status"
+)
+
+// RenderServiceClient is the client API for RenderService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// RenderService — рендер PDF в latex-service.
+//
+// Ошибки передаются статусами gRPC: INVALID_ARGUMENT — некорректный
+// запрос (причина unsupported_schema_version в google.rpc.ErrorInfo —
+// несовместимая версия контракта), INTERNAL — ошибка рендера,
+// UNAVAILABLE — узел временно не может принять запрос. Дедлайн вызова
+// ограничивает и latexmk.
+type RenderServiceClient interface {
+	// Render рендерит резюме. Если в запросе progress, сначала приходят
+	// события этапов, затем PDF частями; ats_report — отчёт ATS-проверки,
+	// если она запрошена, приходит перед PDF.
+	Render(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RenderResponse], error)
+	// RenderDiff рендерит визуальный diff двух версий резюме.
+	RenderDiff(ctx context.Context, in *RenderDiffRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RenderResponse], error)
+	// Status возвращает размер пула latexmk, как /healthz.
+	Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error)
+}
+
+type renderServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRenderServiceClient(cc grpc.ClientConnInterface) RenderServiceClient {
+	return &renderServiceClient{cc}
+}
+
+func (c *renderServiceClient) Render(ctx context.Context, in *RenderRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RenderResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RenderService_ServiceDesc.Streams[0], RenderService_Render_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RenderRequest, RenderResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RenderService_RenderClient = grpc.ServerStreamingClient[RenderResponse]
+
+func (c *renderServiceClient) RenderDiff(ctx context.Context, in *RenderDiffRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RenderResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &RenderService_ServiceDesc.Streams[1], RenderService_RenderDiff_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RenderDiffRequest, RenderResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RenderService_RenderDiffClient = grpc.ServerStreamingClient[RenderResponse]
+
+func (c *renderServiceClient) Status(ctx context.Context, in *StatusRequest, opts ...grpc.CallOption) (*StatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StatusResponse)
+	err := c.cc.Invoke(ctx, RenderService_Status_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RenderServiceServer is the server API for RenderService service.
+// All implementations must embed UnimplementedRenderServiceServer
+// for forward compatibility.
+//
+// RenderService — рендер PDF в latex-service.
+//
+// Ошибки передаются статусами gRPC: INVALID_ARGUMENT — некорректный
+// запрос (причина unsupported_schema_version в google.rpc.ErrorInfo —
+// несовместимая версия контракта), INTERNAL — ошибка рендера,
+// UNAVAILABLE — узел временно не может принять запрос. Дедлайн вызова
+// ограничивает и latexmk.
+type RenderServiceServer interface {
+	// Render рендерит резюме. Если в запросе progress, сначала приходят
+	// события этапов, затем PDF частями; ats_report — отчёт ATS-проверки,
+	// если она запрошена, приходит перед PDF.
+	Render(*RenderRequest, grpc.ServerStreamingServer[RenderResponse]) error
+	// RenderDiff рендерит визуальный diff двух версий резюме.
+	RenderDiff(*RenderDiffRequest, grpc.ServerStreamingServer[RenderResponse]) error
+	// Status возвращает размер пула latexmk, как /healthz.
+	Status(context.Context, *StatusRequest) (*StatusResponse, error)
+	mustEmbedUnimplementedRenderServiceServer()
+}
+
+// UnimplementedRenderServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedRenderServiceServer struct{}
+
+func (UnimplementedRenderServiceServer) Render(*RenderRequest, grpc.ServerStreamingServer[RenderResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Render not implemented")
+}
+func (UnimplementedRenderServiceServer) RenderDiff(*RenderDiffRequest, grpc.ServerStreamingServer[RenderResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method RenderDiff not implemented")
+}
+func (UnimplementedRenderServiceServer) Status(context.Context, *StatusRequest) (*StatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Status not implemented")
+}
+func (UnimplementedRenderServiceServer) mustEmbedUnimplementedRenderServiceServer() {}
+func (UnimplementedRenderServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeRenderServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RenderServiceServer will
+// result in compilation errors.
+type UnsafeRenderServiceServer interface {
+	mustEmbedUnimplementedRenderServiceServer()
+}
+
+func RegisterRenderServiceServer(s grpc.ServiceRegistrar, srv RenderServiceServer) {
+	// If the following call pancis, it indicates UnimplementedRenderServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&RenderService_ServiceDesc, srv)
+}
+
+func _RenderService_Render_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RenderRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RenderServiceServer).Render(m, &grpc.GenericServerStream[RenderRequest, RenderResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RenderService_RenderServer = grpc.ServerStreamingServer[RenderResponse]
+
+func _RenderService_RenderDiff_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RenderDiffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RenderServiceServer).RenderDiff(m, &grpc.GenericServerStream[RenderDiffRequest, RenderResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type RenderService_RenderDiffServer = grpc.ServerStreamingServer[RenderResponse]
+
+func _RenderService_Status_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RenderServiceServer).Status(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RenderService_Status_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RenderServiceServer).Status(ctx, req.(*StatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// RenderService_ServiceDesc is the grpc.ServiceDesc for RenderService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var RenderService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "resume.render.v1.RenderService",
+	HandlerType: (*RenderServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Status",
+			Handler:    _RenderService_Status_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Render",
+			Handler:       _RenderService_Render_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "RenderDiff",
+			Handler:       _RenderService_RenderDiff_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "render/v1/render.proto",
+}
@@ -0,0 +1,37 @@
+package renderpb
+
+import (
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain — домен google.rpc.ErrorInfo в ошибках latex-service.
+const ErrorDomain = "latex-service"
+
+// Error возвращает ошибку gRPC со статусом c и причиной reason — тем же
+// кодом, что в JSON-ошибках HTTP-API (render_failed,
+// unsupported_schema_version и т.д.).
+func Error(c codes.Code, reason, msg string) error {
+	st := status.New(c, msg)
+	if withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: ErrorDomain}); err == nil {
+		st = withInfo
+	}
+	return st.Err()
+}
+
+// Reason возвращает причину из ErrorInfo ошибки gRPC или "", если её нет.
+func Reason(err error) string {
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		return ""
+	}
+	for _, d := range se.GRPCStatus().Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,99 @@
+// Package requestid — сквозной идентификатор запроса. Backend принимает
+// его в заголовке X-Request-ID или создаёт сам, передаёт в latex-service
+// (заголовком HTTP или метаданными gRPC) и пишет в каждую строку лога
+// обоих сервисов, так что запрос к backend можно сопоставить с запуском
+// latexmk.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	// Header — HTTP-заголовок с идентификатором запроса.
+	Header = "X-Request-ID"
+	// MetadataKey — ключ метаданных gRPC с идентификатором запроса.
+	MetadataKey = "x-request-id"
+	// LogKey — имя атрибута с идентификатором в записях лога.
+	LogKey = "request_id"
+
+	maxLen = 128
+)
+
+// New создаёт случайный идентификатор.
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Valid сообщает, можно ли принять идентификатор от клиента: не длиннее
+// 128 символов из букв, цифр и «-_.:» — чтобы он безопасно попадал в
+// заголовки и логи.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FromRequest возвращает идентификатор из заголовка X-Request-ID или,
+// если его нет или он некорректен, новый.
+func FromRequest(r *http.Request) string {
+	if id := r.Header.Get(Header); Valid(id) {
+		return id
+	}
+	return New()
+}
+
+type ctxKey struct{}
+
+// WithID возвращает контекст с идентификатором запроса.
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса или "", если его нет.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Handler добавляет к записям лога идентификатор запроса из контекста
+// (атрибут request_id), если запись сделана методом *Context.
+type Handler struct {
+	slog.Handler
+}
+
+// NewHandler оборачивает h.
+func NewHandler(h slog.Handler) *Handler {
+	return &Handler{Handler: h}
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		r.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{Handler: h.Handler.WithGroup(name)}
+}
@@ -1,7 +1,9 @@
-package model
+package contract
 
-// Resume описывает структуру резюме, используемую latex-service.
+// Resume описывает резюме в том виде, в котором backend передаёт его
+// в latex-service. Любое изменение полей требует повышения SchemaVersion.
 type Resume struct {
+	SchemaVersion  int               `json:"schemaVersion"`
 	FullName       string            `json:"fullName"`
 	Position       string            `json:"position"`
 	Summary        string            `json:"summary"`
@@ -32,8 +34,8 @@ type ExperienceEntry struct {
 	Company     string   `json:"company"`
 	Position    string   `json:"position"`
 	Location    string   `json:"location"`
-	StartDate   string   `json:"startDate"`
-	EndDate     string   `json:"endDate"`
+	StartDate   string   `json:"startDate"` // формат YYYY-MM
+	EndDate     string   `json:"endDate"`   // формат YYYY-MM или пусто
 	Description string   `json:"description"`
 	Bullets     []string `json:"bullets"`
 }
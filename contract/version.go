@@ -0,0 +1,99 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion — текущая версия контракта, которую отправляет backend.
+const SchemaVersion = 1
+
+// MinSupportedSchemaVersion — минимальная версия, которую latex-service ещё
+// умеет рендерить.
+const MinSupportedSchemaVersion = 1
+
+// ErrCodeUnsupportedSchemaVersion — код ошибки в JSON-конверте
+// {"error": ..., "message": ...} при несовместимой версии контракта.
+const ErrCodeUnsupportedSchemaVersion = "unsupported_schema_version"
+
+// ErrUnsupportedSchemaVersion — sentinel для errors.Is; VersionError
+// и ошибки клиента latex-service оборачивают его.
+var ErrUnsupportedSchemaVersion = errors.New(ErrCodeUnsupportedSchemaVersion)
+
+// VersionError сообщает, что версия контракта не поддерживается получателем.
+type VersionError struct {
+	Got          int
+	MinSupported int
+	MaxSupported int
+}
+
+func (e *VersionError) Error() string {
+	if e.Got == 0 {
+		return fmt.Sprintf("schemaVersion is missing (supported: %d..%d)", e.MinSupported, e.MaxSupported)
+	}
+	return fmt.Sprintf("schemaVersion %d is not supported (supported: %d..%d)", e.Got, e.MinSupported, e.MaxSupported)
+}
+
+// Unwrap позволяет сравнивать VersionError с ErrUnsupportedSchemaVersion.
+func (e *VersionError) Unwrap() error {
+	return ErrUnsupportedSchemaVersion
+}
+
+// CheckSchemaVersion проверяет, что версия v входит в поддерживаемый диапазон.
+func CheckSchemaVersion(v int) error {
+	if v < MinSupportedSchemaVersion || v > SchemaVersion {
+		return &VersionError{
+			Got:          v,
+			MinSupported: MinSupportedSchemaVersion,
+			MaxSupported: SchemaVersion,
+		}
+	}
+	return nil
+}
+
+// Decode читает резюме из r. Сначала проверяется только schemaVersion,
+// и лишь затем документ декодируется строго (DisallowUnknownFields):
+// так новая версия с дополнительными полями получает понятную
+// VersionError, а не «unknown field».
+func Decode(r io.Reader) (Resume, error) {
+	var res Resume
+	if err := decodeVersioned(r, &res); err != nil {
+		return Resume{}, err
+	}
+	return res, nil
+}
+
+// DecodeDiff — то же, что Decode, для DiffDocument.
+func DecodeDiff(r io.Reader) (DiffDocument, error) {
+	var doc DiffDocument
+	if err := decodeVersioned(r, &doc); err != nil {
+		return DiffDocument{}, err
+	}
+	return doc, nil
+}
+
+// decodeVersioned сначала проверяет schemaVersion, затем строго
+// декодирует документ в v.
+func decodeVersioned(r io.Reader, v any) error {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read body: %w", err)
+	}
+
+	var probe struct {
+		SchemaVersion int `json:"schemaVersion"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return err
+	}
+	if err := CheckSchemaVersion(probe.SchemaVersion); err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
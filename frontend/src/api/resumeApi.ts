@@ -2,7 +2,13 @@ import type { ResumeRequest } from '@/types/resume';
 
 const API_URL = '/api/v1/resume/pdf';
 
-export async function generateResumePdf(data: ResumeRequest): Promise<Blob> {
+export interface GeneratedPdf {
+  blob: Blob;
+  // PDF собран запасным рендерером backend: LaTeX-сервис недоступен
+  simplified: boolean;
+}
+
+export async function generateResumePdf(data: ResumeRequest): Promise<GeneratedPdf> {
   const response = await fetch(API_URL, {
     method: 'POST',
     headers: {
@@ -25,5 +31,8 @@ export async function generateResumePdf(data: ResumeRequest): Promise<Blob> {
   }
 
   const blob = await response.blob();
-  return blob;
+  return {
+    blob,
+    simplified: response.headers.get('X-Resume-Layout') === 'simplified'
+  };
 }
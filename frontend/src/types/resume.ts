@@ -40,7 +40,11 @@ export interface Photo {
   data: string;
 }
 
+// Текущая версия формата документа, см. resume.CurrentSchemaVersion в backend.
+export const RESUME_SCHEMA_VERSION = 1;
+
 export interface ResumeRequest {
+  schemaVersion: number;
   fullName: string;
   position: string;
   summary: string;
@@ -54,6 +58,7 @@ export interface ResumeRequest {
 
 export function createEmptyResume(): ResumeRequest {
   return {
+    schemaVersion: RESUME_SCHEMA_VERSION,
     fullName: '',
     position: '',
     summary: '',
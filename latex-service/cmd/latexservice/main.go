@@ -1,25 +1,61 @@
 package main
 
 import (
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
 
 	"latex_service/internal/config"
 	httphandler "latex_service/internal/http"
 	"latex_service/internal/latex"
+	"latex_service/internal/rpc"
+
+	"resume_contract/metrics"
+	"resume_contract/requestid"
 )
 
 func main() {
+	// JSON-логи; записи в контексте запроса получают его request_id
+	logger := slog.New(requestid.NewHandler(slog.NewJSONHandler(os.Stdout, nil)))
+	slog.SetDefault(logger)
+
 	cfg := config.Load()
+	logger.Info("starting latex-service", "addr", cfg.HTTPAddr, "workers", cfg.Workers)
 
-	logger := log.Default()
-	logger.Printf("starting latex-service on %s", cfg.HTTPAddr)
+	renderer := latex.NewRenderer(cfg.TemplatePath, cfg.Workers, logger)
+	renderer.SetMaxOutputSize(cfg.MaxPDFSize)
 
-	renderer := latex.NewRenderer(cfg.TemplatePath, logger)
+	metrics.Default.NewGaugeFunc("latexmk_workers", "Size of the latexmk worker pool.", func() float64 {
+		return float64(renderer.Workers())
+	})
+	metrics.Default.NewGaugeFunc("latexmk_workers_busy", "latexmk workers currently compiling.", func() float64 {
+		return float64(renderer.Busy())
+	})
 
 	server := httphandler.NewServer(renderer, logger)
+	server.SetMaxBodySize(cfg.MaxRenderBodySize)
+
+	if cfg.GRPCAddr != "" {
+		lis, err := net.Listen("tcp", cfg.GRPCAddr)
+		if err != nil {
+			fatal(logger, "listen gRPC failed", err)
+		}
+		logger.Info("serving gRPC", "addr", cfg.GRPCAddr)
+		go func() {
+			if err := rpc.NewServer(renderer, cfg.MaxRenderBodySize, logger).Serve(lis); err != nil {
+				fatal(logger, "gRPC server exited with error", err)
+			}
+		}()
+	}
 
 	if err := http.ListenAndServe(cfg.HTTPAddr, server); err != nil {
-		logger.Fatalf("server exited with error: %v", err)
+		fatal(logger, "server exited with error", err)
 	}
 }
+
+// fatal пишет ошибку в лог и завершает процесс.
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, "error", err)
+	os.Exit(1)
+}
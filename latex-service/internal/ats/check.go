@@ -0,0 +1,384 @@
+// Package ats проверяет, насколько корректно система отслеживания кандидатов
+// (ATS) извлечёт текст из сгенерированного PDF: текст вытаскивается из PDF
+// средствами Go (pdftext) и сравнивается с полями исходного резюме.
+package ats
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"latex_service/internal/pdftext"
+
+	contract "resume_contract"
+)
+
+// Веса статусов при расчёте оценки.
+var statusWeight = map[string]float64{
+	contract.ATSStatusOK:        1,
+	contract.ATSStatusReordered: 0.75,
+	contract.ATSStatusGarbled:   0.4,
+	contract.ATSStatusMissing:   0,
+}
+
+// criticalFields — поля, без которых рекрутер не сможет связаться с кандидатом;
+// в оценке они весят втрое больше остальных.
+var criticalFields = map[string]string{
+	"fullName":       "Full name",
+	"contacts.email": "Email address",
+	"contacts.phone": "Phone number",
+}
+
+const criticalWeight = 3
+
+// field — ожидаемое в PDF значение поля.
+type field struct {
+	path  string
+	value string
+}
+
+// Check извлекает текст из pdf и сверяет его с резюме.
+func Check(r contract.Resume, pdf []byte) contract.ATSReport {
+	report := contract.ATSReport{
+		Fields: []contract.ATSFieldResult{},
+		Issues: []string{},
+	}
+
+	text, err := pdftext.Extract(pdf)
+	if err != nil {
+		report.Issues = append(report.Issues, fmt.Sprintf("Text could not be extracted from the PDF: %v", err))
+		for _, f := range expectedFields(r) {
+			report.Fields = append(report.Fields, contract.ATSFieldResult{Field: f.path, Status: contract.ATSStatusMissing})
+		}
+		report.Summary.Missing = len(report.Fields)
+		return report
+	}
+	report.ExtractedText = text.Strict
+
+	strict := normalize(text.Strict)
+	dehyphenated := normalize(dehyphenate(text.Strict))
+	canonical := normalize(dehyphenate(text.Lenient))
+
+	fields := expectedFields(r)
+	positions := make([]int, len(fields))
+	cursor := 0
+
+	for i, f := range fields {
+		want := normalizeSource(f.value)
+		res := contract.ATSFieldResult{Field: f.path, Status: contract.ATSStatusOK}
+
+		switch {
+		case find(strict, want, 0) >= 0:
+		case find(dehyphenated, want, 0) >= 0:
+			res.Status, res.Reason = contract.ATSStatusGarbled, contract.ATSReasonHyphenation
+		case find(canonical, want, 0) >= 0:
+			res.Status, res.Reason = contract.ATSStatusGarbled, contract.ATSReasonEncoding
+			if hasLigature(want) {
+				res.Reason = contract.ATSReasonLigature
+			}
+		default:
+			res.Status = contract.ATSStatusMissing
+		}
+
+		// Позиция в каноническом тексте: сначала ищем после предыдущего поля
+		// (порядок чтения), затем с начала.
+		pos := find(canonical, want, cursor)
+		if pos < 0 {
+			pos = find(canonical, want, 0)
+		}
+		if pos < 0 && res.Status == contract.ATSStatusMissing {
+			if p, ok := partialMatch(canonical, want, cursor); ok {
+				res.Status, res.Reason = contract.ATSStatusGarbled, contract.ATSReasonPartial
+				pos = p
+			}
+		}
+		positions[i] = pos
+		if pos >= cursor {
+			cursor = pos + 1
+		}
+
+		report.Fields = append(report.Fields, res)
+	}
+
+	inOrder := longestIncreasing(positions)
+	for i := range report.Fields {
+		if positions[i] >= 0 && !inOrder[i] && report.Fields[i].Status == contract.ATSStatusOK {
+			report.Fields[i].Status = contract.ATSStatusReordered
+		}
+	}
+
+	var total, got float64
+	for _, res := range report.Fields {
+		w := 1.0
+		if label, ok := criticalFields[res.Field]; ok {
+			w = criticalWeight
+			if res.Status != contract.ATSStatusOK {
+				report.Issues = append(report.Issues, criticalIssue(label, res))
+			}
+		}
+		total += w
+		got += w * statusWeight[res.Status]
+
+		switch res.Status {
+		case contract.ATSStatusOK:
+			report.Summary.OK++
+		case contract.ATSStatusMissing:
+			report.Summary.Missing++
+		case contract.ATSStatusReordered:
+			report.Summary.Reordered++
+		case contract.ATSStatusGarbled:
+			report.Summary.Garbled++
+		}
+	}
+
+	report.Score = 100
+	if total > 0 {
+		report.Score = int(math.Round(100 * got / total))
+	}
+
+	if n := strings.Count(text.Strict, "�"); n > 0 {
+		report.Issues = append(report.Issues, fmt.Sprintf("%d glyphs have no Unicode mapping and will be read as garbage", n))
+	}
+	if strings.ContainsAny(text.Strict, "ﬀﬁﬂﬃﬄ") {
+		report.Issues = append(report.Issues, "Ligatures (ff, fi, fl) are extracted as single characters, keywords containing them may not match")
+	}
+
+	return report
+}
+
+func criticalIssue(label string, res contract.ATSFieldResult) string {
+	switch res.Status {
+	case contract.ATSStatusMissing:
+		return label + " is not extractable from the PDF"
+	case contract.ATSStatusGarbled:
+		return fmt.Sprintf("%s is extracted incorrectly (%s)", label, res.Reason)
+	default:
+		return label + " is extracted out of reading order"
+	}
+}
+
+// expectedFields перечисляет поля в том порядке, в котором их выводит
+// LaTeX-шаблон, пропуская то, что рендерер тоже пропускает.
+func expectedFields(r contract.Resume) []field {
+	var out []field
+	add := func(path, value string) {
+		if strings.TrimSpace(value) != "" {
+			out = append(out, field{path: path, value: value})
+		}
+	}
+
+	add("fullName", r.FullName)
+	add("position", r.Position)
+	add("summary", r.Summary)
+	add("contacts.email", r.Contacts.Email)
+	add("contacts.phone", r.Contacts.Phone)
+	add("contacts.location", r.Contacts.Location)
+	for i, l := range r.Contacts.Links {
+		if strings.TrimSpace(l.URL) == "" {
+			continue
+		}
+		label := l.Label
+		if strings.TrimSpace(label) == "" {
+			label = l.URL
+		}
+		add(fmt.Sprintf("contacts.links[%d].label", i), label)
+	}
+
+	// навыки приходят строками вывода: skills[k] — номер строки в контракте,
+	// backend сам переводит его в путь своей модели
+	for i, s := range r.Skills {
+		add(fmt.Sprintf("skills[%d]", i), s)
+	}
+
+	for i, e := range r.Experience {
+		if strings.TrimSpace(e.Company) == "" && strings.TrimSpace(e.Position) == "" {
+			continue
+		}
+		p := fmt.Sprintf("experience[%d]", i)
+		add(p+".position", e.Position)
+		add(p+".company", e.Company)
+		add(p+".location", e.Location)
+		add(p+".description", e.Description)
+		for j, b := range e.Bullets {
+			add(fmt.Sprintf("%s.bullets[%d]", p, j), b)
+		}
+	}
+
+	for i, e := range r.Education {
+		if strings.TrimSpace(e.Institution) == "" && strings.TrimSpace(e.Degree) == "" {
+			continue
+		}
+		p := fmt.Sprintf("education[%d]", i)
+		add(p+".institution", e.Institution)
+		add(p+".degree", e.Degree)
+		add(p+".location", e.Location)
+		add(p+".details", e.Details)
+	}
+
+	for i, cs := range r.CustomSections {
+		if strings.TrimSpace(cs.Title) == "" {
+			continue
+		}
+		p := fmt.Sprintf("customSections[%d]", i)
+		add(p+".title", cs.Title)
+		for j, item := range cs.Items {
+			add(fmt.Sprintf("%s.items[%d]", p, j), item)
+		}
+	}
+
+	return out
+}
+
+var textNormalizer = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'",
+	"“", `"`, "”", `"`, "„", `"`,
+	"–", "-", "—", "-", "−", "-",
+	" ", " ",
+)
+
+// sourceNormalizer повторяет TeX-лигатуры, которые меняют исходный текст:
+// "--" и "---" превращаются в тире, пары обратных и прямых апострофов —
+// в кавычки.
+var sourceNormalizer = strings.NewReplacer(
+	"---", "-", "--", "-",
+	"``", `"`, "''", `"`,
+)
+
+var spaceRe = regexp.MustCompile(`\s+`)
+
+func normalize(s string) string {
+	s = textNormalizer.Replace(s)
+	s = strings.ToLower(s)
+	return strings.TrimSpace(spaceRe.ReplaceAllString(s, " "))
+}
+
+func normalizeSource(s string) string {
+	return normalize(sourceNormalizer.Replace(s))
+}
+
+// hyphenRe находит перенос слова в конце строки.
+var hyphenRe = regexp.MustCompile(`(\pL)-\n(\pL)`)
+
+func dehyphenate(s string) string {
+	return hyphenRe.ReplaceAllString(s, "$1$2")
+}
+
+func hasLigature(s string) bool {
+	return strings.Contains(s, "ff") || strings.Contains(s, "fi") || strings.Contains(s, "fl")
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// find ищет needle в text начиная с from так, чтобы совпадение не было
+// частью более длинного слова ("go" не находится внутри "google").
+func find(text, needle string, from int) int {
+	if needle == "" || from > len(text) {
+		return -1
+	}
+	first, last := firstRune(needle), lastRune(needle)
+
+	for off := from; off <= len(text)-len(needle); {
+		i := strings.Index(text[off:], needle)
+		if i < 0 {
+			return -1
+		}
+		i += off
+		okBefore := !isWordChar(first) || i == 0 || !isWordChar(runeBefore(text, i))
+		end := i + len(needle)
+		okAfter := !isWordChar(last) || end == len(text) || !isWordChar(runeAt(text, end))
+		if okBefore && okAfter {
+			return i
+		}
+		off = i + 1
+	}
+	return -1
+}
+
+// partialMatch считает долю слов поля, найденных в тексте. Если найдено не
+// меньше половины — поле считается частично извлечённым.
+func partialMatch(text, want string, cursor int) (int, bool) {
+	words := strings.FieldsFunc(want, func(r rune) bool { return !isWordChar(r) })
+	if len(words) < 2 {
+		return -1, false
+	}
+	found, firstPos := 0, -1
+	for _, w := range words {
+		pos := find(text, w, cursor)
+		if pos < 0 {
+			pos = find(text, w, 0)
+		}
+		if pos >= 0 {
+			found++
+			if firstPos < 0 {
+				firstPos = pos
+			}
+		}
+	}
+	return firstPos, found*2 >= len(words)
+}
+
+// longestIncreasing отмечает поля, входящие в наибольшую возрастающую
+// подпоследовательность позиций; остальные найдены не в порядке чтения.
+func longestIncreasing(pos []int) []bool {
+	n := len(pos)
+	length := make([]int, n)
+	prev := make([]int, n)
+	best, bestEnd := 0, -1
+
+	for i := 0; i < n; i++ {
+		prev[i] = -1
+		if pos[i] < 0 {
+			continue
+		}
+		length[i] = 1
+		for j := 0; j < i; j++ {
+			if pos[j] >= 0 && pos[j] < pos[i] && length[j]+1 > length[i] {
+				length[i] = length[j] + 1
+				prev[i] = j
+			}
+		}
+		if length[i] > best {
+			best, bestEnd = length[i], i
+		}
+	}
+
+	in := make([]bool, n)
+	for i := bestEnd; i >= 0; i = prev[i] {
+		in[i] = true
+	}
+	return in
+}
+
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return 0
+}
+
+func lastRune(s string) rune {
+	rs := []rune(s)
+	if len(rs) == 0 {
+		return 0
+	}
+	return rs[len(rs)-1]
+}
+
+func runeAt(s string, i int) rune {
+	for _, r := range s[i:] {
+		return r
+	}
+	return 0
+}
+
+func runeBefore(s string, i int) rune {
+	rs := []rune(s[:i])
+	if len(rs) == 0 {
+		return 0
+	}
+	return rs[len(rs)-1]
+}
@@ -0,0 +1,222 @@
+package ats
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	contract "resume_contract"
+)
+
+// buildPDF собирает одностраничный PDF, в котором lines идут строками
+// сверху вниз шрифтом Helvetica.
+func buildPDF(lines ...string) []byte {
+	var content strings.Builder
+	content.WriteString("BT /F1 12 Tf 72 760 Td")
+	for i, l := range lines {
+		if i > 0 {
+			content.WriteString(" 0 -16 Td")
+		}
+		l = strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`).Replace(l)
+		fmt.Fprintf(&content, " (%s) Tj", l)
+	}
+	content.WriteString(" ET")
+
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources 4 0 R /Contents 5 0 R >>",
+		"<< /Font << /F1 6 0 R >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.5\n")
+	for i, o := range objs {
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	out.WriteString("trailer << /Root 1 0 R >>\n%%EOF\n")
+	return out.Bytes()
+}
+
+var sample = contract.Resume{
+	FullName: "Ivan Ivanov",
+	Position: "Backend developer",
+	Summary:  "Builds reliable services in Kubernetes",
+	Contacts: contract.Contacts{Email: "ivan@example.com", Phone: "+7 900 000-00-00"},
+	Skills:   []string{"Go", "Cloud: AWS, GCP"},
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		status map[string]contract.ATSFieldResult // поля, отличные от ok
+		issue  string
+		score  int
+	}{
+		{
+			name:  "everything in order",
+			lines: []string{"Ivan Ivanov", "Backend developer", "Builds reliable services in Kubernetes", "ivan@example.com", "+7 900 000-00-00", "Go", "Cloud: AWS, GCP"},
+			score: 100,
+		},
+		{
+			name:  "missing phone",
+			lines: []string{"Ivan Ivanov", "Backend developer", "Builds reliable services in Kubernetes", "ivan@example.com", "Go", "Cloud: AWS, GCP"},
+			status: map[string]contract.ATSFieldResult{
+				"contacts.phone": {Status: contract.ATSStatusMissing},
+			},
+			issue: "Phone number is not extractable from the PDF",
+			score: 77,
+		},
+		{
+			name:  "position before the name",
+			lines: []string{"Backend developer", "Ivan Ivanov", "Builds reliable services in Kubernetes", "ivan@example.com", "+7 900 000-00-00", "Go", "Cloud: AWS, GCP"},
+			status: map[string]contract.ATSFieldResult{
+				"position": {Status: contract.ATSStatusReordered},
+			},
+			score: 98,
+		},
+		{
+			name:  "hyphenated word",
+			lines: []string{"Ivan Ivanov", "Backend developer", "Builds reliable services in Kuber-", "netes", "ivan@example.com", "+7 900 000-00-00", "Go", "Cloud: AWS, GCP"},
+			status: map[string]contract.ATSFieldResult{
+				"summary": {Status: contract.ATSStatusGarbled, Reason: contract.ATSReasonHyphenation},
+			},
+			score: 95,
+		},
+		{
+			name:  "some words lost",
+			lines: []string{"Ivan Ivanov", "Backend developer", "Builds reliable systems in Kubernetes", "ivan@example.com", "+7 900 000-00-00", "Go", "Cloud: AWS, GCP"},
+			status: map[string]contract.ATSFieldResult{
+				"summary": {Status: contract.ATSStatusGarbled, Reason: contract.ATSReasonPartial},
+			},
+			score: 95,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Check(sample, buildPDF(tt.lines...))
+
+			var paths []string
+			for _, f := range report.Fields {
+				paths = append(paths, f.Field)
+				want, ok := tt.status[f.Field]
+				if !ok {
+					want = contract.ATSFieldResult{Status: contract.ATSStatusOK}
+				}
+				want.Field = f.Field
+				if f != want {
+					t.Errorf("field %s = %+v, want %+v", f.Field, f, want)
+				}
+			}
+			wantPaths := []string{"fullName", "position", "summary", "contacts.email", "contacts.phone", "skills[0]", "skills[1]"}
+			if !reflect.DeepEqual(paths, wantPaths) {
+				t.Errorf("fields = %v, want %v", paths, wantPaths)
+			}
+			if report.Score != tt.score {
+				t.Errorf("score = %d, want %d", report.Score, tt.score)
+			}
+			if tt.issue != "" && !contains(report.Issues, tt.issue) {
+				t.Errorf("issues = %q, want %q", report.Issues, tt.issue)
+			}
+		})
+	}
+}
+
+func TestCheckUnreadablePDF(t *testing.T) {
+	report := Check(sample, []byte("not a pdf"))
+	if report.Summary.Missing != len(report.Fields) || len(report.Fields) != 7 {
+		t.Errorf("summary = %+v for %d fields, want all missing", report.Summary, len(report.Fields))
+	}
+	if report.Score != 0 {
+		t.Errorf("score = %d, want 0", report.Score)
+	}
+	if len(report.Issues) != 1 || !strings.HasPrefix(report.Issues[0], "Text could not be extracted from the PDF") {
+		t.Errorf("issues = %q", report.Issues)
+	}
+}
+
+func TestExpectedFields(t *testing.T) {
+	r := contract.Resume{
+		FullName: "Ivan",
+		Summary:  "  ",
+		Contacts: contract.Contacts{Links: []contract.Link{
+			{Label: "GitHub", URL: "https://github.com/ivan"},
+			{URL: "https://ivan.dev"},
+			{Label: "No URL"},
+		}},
+		Experience: []contract.ExperienceEntry{
+			{Description: "no company or position"},
+			{Company: "Acme", Bullets: []string{"", "Shipped"}},
+		},
+		Education:      []contract.EducationEntry{{Degree: "BSc"}},
+		CustomSections: []contract.CustomSection{{Items: []string{"untitled"}}, {Title: "Talks", Items: []string{"GopherCon"}}},
+	}
+
+	var got []string
+	for _, f := range expectedFields(r) {
+		got = append(got, f.path+"="+f.value)
+	}
+	want := []string{
+		"fullName=Ivan",
+		"contacts.links[0].label=GitHub",
+		"contacts.links[1].label=https://ivan.dev",
+		"experience[1].company=Acme",
+		"experience[1].bullets[1]=Shipped",
+		"education[0].degree=BSc",
+		"customSections[1].title=Talks",
+		"customSections[1].items[0]=GopherCon",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expectedFields =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		text, needle string
+		from         int
+		want         int
+	}{
+		{"we use go daily", "go", 0, 7},
+		{"google and go", "go", 0, 11},
+		{"golang", "go", 0, -1},
+		{"c++ and c", "c++", 0, 0},
+		{"go, go", "go", 1, 4},
+		{"go", "", 0, -1},
+	}
+	for _, tt := range tests {
+		if got := find(tt.text, tt.needle, tt.from); got != tt.want {
+			t.Errorf("find(%q, %q, %d) = %d, want %d", tt.text, tt.needle, tt.from, got, tt.want)
+		}
+	}
+}
+
+func TestLongestIncreasing(t *testing.T) {
+	tests := []struct {
+		pos  []int
+		want []bool
+	}{
+		{[]int{0, 5, 9}, []bool{true, true, true}},
+		{[]int{5, 0, 9, 12}, []bool{true, false, true, true}},
+		{[]int{0, -1, 4}, []bool{true, false, true}},
+		{nil, []bool{}},
+	}
+	for _, tt := range tests {
+		if got := longestIncreasing(tt.pos); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("longestIncreasing(%v) = %v, want %v", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
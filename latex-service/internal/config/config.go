@@ -1,13 +1,33 @@
 package config
 
-import "os"
+import (
+	"log/slog"
+	"os"
+	"runtime"
+	"strconv"
+
+	contract "resume_contract"
+)
 
 // Config описывает конфигурацию latex-service.
 type Config struct {
 	// HTTPAddr — адрес, на котором слушает HTTP-сервер, например ":8081".
 	HTTPAddr string
+	// GRPCAddr — адрес gRPC-сервера (renderpb.RenderService), например
+	// ":9090"; пусто — gRPC выключен.
+	GRPCAddr string
 	// TemplatePath — путь к LaTeX-шаблону, например "templates/resume_template.tex".
 	TemplatePath string
+	// Workers — сколько latexmk может работать одновременно; остальные
+	// запросы ждут свободного слота.
+	Workers int
+	// MaxPDFSize — предельный размер готового PDF в байтах; документ
+	// больше отклоняется с ошибкой output_too_large.
+	MaxPDFSize int64
+	// MaxRenderBodySize — предельный размер запроса рендера в байтах: тела
+	// HTTP-запроса и сообщения gRPC. Больший запрос отклоняется с
+	// payload_too_large; backend выводит из этого значения свои лимиты.
+	MaxRenderBodySize int64
 }
 
 // Load загружает конфигурацию из переменных окружения с дефолтами.
@@ -22,8 +42,42 @@ func Load() Config {
 		templatePath = "templates/resume_template.tex"
 	}
 
+	workers := runtime.NumCPU()
+	if v := os.Getenv("LATEX_WORKERS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			slog.Warn("config: invalid value, using default", "name", "LATEX_WORKERS", "value", v, "default", workers)
+		} else {
+			workers = n
+		}
+	}
+
+	maxPDFSize := int64(20 << 20)
+	if v := os.Getenv("MAX_PDF_SIZE"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			slog.Warn("config: invalid value, using default", "name", "MAX_PDF_SIZE", "value", v, "default", maxPDFSize)
+		} else {
+			maxPDFSize = n
+		}
+	}
+
+	maxRenderBodySize := int64(contract.DefaultMaxRequestSize)
+	if v := os.Getenv("MAX_RENDER_BODY_SIZE"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			slog.Warn("config: invalid value, using default", "name", "MAX_RENDER_BODY_SIZE", "value", v, "default", maxRenderBodySize)
+		} else {
+			maxRenderBodySize = n
+		}
+	}
+
 	return Config{
-		HTTPAddr:     httpAddr,
-		TemplatePath: templatePath,
+		HTTPAddr:          httpAddr,
+		GRPCAddr:          os.Getenv("GRPC_ADDR"),
+		TemplatePath:      templatePath,
+		Workers:           workers,
+		MaxPDFSize:        maxPDFSize,
+		MaxRenderBodySize: maxRenderBodySize,
 	}
 }
@@ -2,21 +2,29 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime/multipart"
 	stdhttp "net/http"
+	"net/textproto"
+	"sync"
 	"time"
 
-	"latex_service/internal/model"
+	"latex_service/internal/ats"
+	"latex_service/internal/latex"
+
+	contract "resume_contract"
+	"resume_contract/requestid"
 )
 
 // errorResponse описывает формат JSON-ошибки latex-service.
 type errorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
+	// RequestID — идентификатор запроса для поиска в логах.
+	RequestID string `json:"requestId,omitempty"`
 }
 
-const maxRenderBodySize = 256 * 1024 // 256 KB
-
 // handleHealth обрабатывает /healthz.
 func (s *Server) handleHealth(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 	if r.Method != stdhttp.MethodGet && r.Method != stdhttp.MethodHead {
@@ -31,6 +39,9 @@ func (s *Server) handleHealth(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 		"status":  "ok",
 		"time":    time.Now().UTC().Format(time.RFC3339),
 		"service": "latex-service",
+		// размер пула latexmk: по нему backend выбирает параллельность пакетного рендера
+		"workers": s.renderer.Workers(),
+		"busy":    s.renderer.Busy(),
 	}
 	_ = json.NewEncoder(w).Encode(resp)
 }
@@ -42,39 +53,187 @@ func (s *Server) handleRender(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 		return
 	}
 
-	r.Body = stdhttp.MaxBytesReader(w, r.Body, maxRenderBodySize)
+	r.Body = stdhttp.MaxBytesReader(w, r.Body, s.maxBodySize)
 	defer r.Body.Close()
 
-	var payload model.Resume
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
+	payload, err := contract.Decode(r.Body)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
 
-	if err := dec.Decode(&payload); err != nil {
-		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_json", fmt.Sprintf("Failed to decode JSON: %v", err))
+	if r.URL.Query().Get("progress") == "1" {
+		if r.URL.Query().Get("check") != "" {
+			writeJSONError(w, stdhttp.StatusBadRequest, "invalid_request", "progress=1 cannot be combined with check")
+			return
+		}
+		s.streamRender(w, r, payload)
+		return
+	}
+
+	if r.URL.Query().Get("check") == "ats" {
+		pdf, err := s.renderer.Render(r.Context(), payload)
+		if err != nil {
+			s.writeRenderError(w, r, err)
+			return
+		}
+		s.writeATSResponse(w, r, payload, pdf)
 		return
 	}
 
-	pdf, err := s.renderer.Render(r.Context(), payload)
+	pdf, err := s.renderer.RenderFile(r.Context(), payload)
 	if err != nil {
-		s.logger.Printf("failed to render PDF: %v", err)
-		writeJSONError(w, stdhttp.StatusInternalServerError, "render_failed", "Failed to render PDF")
+		s.writeRenderError(w, r, err)
 		return
 	}
+	defer pdf.Close()
+
+	writePDF(w, r, pdf)
+}
 
+// writePDF отдаёт PDF потоком из файла компиляции: с Content-Length
+// и поддержкой Range для докачки.
+func writePDF(w stdhttp.ResponseWriter, r *stdhttp.Request, pdf *latex.PDF) {
 	w.Header().Set("Content-Type", "application/pdf")
+	stdhttp.ServeContent(w, r, "resume.pdf", time.Time{}, pdf)
+}
+
+// writeRenderError отвечает ошибкой рендера: слишком большой PDF — 422
+// output_too_large, остальное — 500 render_failed.
+func (s *Server) writeRenderError(w stdhttp.ResponseWriter, r *stdhttp.Request, err error) {
+	s.logger.ErrorContext(r.Context(), "failed to render PDF", "error", err)
+	code, msg := renderErrorCode(err)
+	status := stdhttp.StatusInternalServerError
+	if code == codeOutputTooLarge {
+		status = stdhttp.StatusUnprocessableEntity
+	}
+	writeJSONError(w, status, code, msg)
+}
+
+// codeOutputTooLarge — код ошибки, если PDF превысил MAX_PDF_SIZE.
+const codeOutputTooLarge = "output_too_large"
+
+// renderErrorCode возвращает код и сообщение ошибки рендера для JSON-ответа
+// и события error потока прогресса.
+func renderErrorCode(err error) (code, msg string) {
+	if errors.Is(err, latex.ErrOutputTooLarge) {
+		return codeOutputTooLarge, "Generated PDF exceeds the size limit"
+	}
+	return "render_failed", "Failed to render PDF"
+}
+
+// streamRender рендерит PDF, передавая этапы работы потоком NDJSON
+// (contract.RenderEvent). Поток завершается событием done с PDF или error.
+func (s *Server) streamRender(w stdhttp.ResponseWriter, r *stdhttp.Request, payload contract.Resume) {
+	rc := stdhttp.NewResponseController(w)
+	w.Header().Set("Content-Type", contract.ProgressContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(stdhttp.StatusOK)
+
+	// события проходов приходят из горутин, читающих вывод latexmk
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	send := func(ev contract.RenderEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		if err := enc.Encode(ev); err != nil {
+			s.logger.WarnContext(r.Context(), "failed to write progress event", "error", err)
+			return
+		}
+		_ = rc.Flush()
+	}
+
+	pdf, err := s.renderer.Render(latex.WithProgress(r.Context(), send), payload)
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "failed to render PDF", "error", err)
+		code, msg := renderErrorCode(err)
+		send(contract.RenderEvent{Stage: contract.StageError, Error: code, Message: msg})
+		return
+	}
+	send(contract.RenderEvent{Stage: contract.StageDone, PDF: pdf})
+}
+
+// writeATSResponse отвечает multipart/mixed: первая часть — JSON-отчёт
+// ATS-проверки, вторая — сам PDF.
+func (s *Server) writeATSResponse(w stdhttp.ResponseWriter, r *stdhttp.Request, payload contract.Resume, pdf []byte) {
+	report := ats.Check(payload, pdf)
+
+	mw := multipart.NewWriter(w)
+	w.Header().Set("Content-Type", "multipart/mixed; boundary="+mw.Boundary())
 	w.WriteHeader(stdhttp.StatusOK)
 
-	if _, err := w.Write(pdf); err != nil {
-		s.logger.Printf("failed to write PDF response: %v", err)
+	part, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/json"}})
+	if err == nil {
+		err = json.NewEncoder(part).Encode(report)
+	}
+	if err == nil {
+		part, err = mw.CreatePart(textproto.MIMEHeader{"Content-Type": {"application/pdf"}})
+	}
+	if err == nil {
+		_, err = part.Write(pdf)
+	}
+	if err == nil {
+		err = mw.Close()
+	}
+	if err != nil {
+		s.logger.ErrorContext(r.Context(), "failed to write ATS response", "error", err)
+	}
+}
+
+// handleRenderDiff обрабатывает POST /internal/v1/render/diff: PDF
+// с визуальным сравнением двух версий резюме.
+func (s *Server) handleRenderDiff(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.Method != stdhttp.MethodPost {
+		writeJSONError(w, stdhttp.StatusMethodNotAllowed, "method_not_allowed", "Only POST is allowed")
+		return
+	}
+
+	r.Body = stdhttp.MaxBytesReader(w, r.Body, s.maxBodySize)
+	defer r.Body.Close()
+
+	doc, err := contract.DecodeDiff(r.Body)
+	if err != nil {
+		writeDecodeError(w, err)
+		return
+	}
+
+	pdf, err := s.renderer.RenderDiffFile(r.Context(), doc)
+	if err != nil {
+		s.writeRenderError(w, r, err)
+		return
+	}
+	defer pdf.Close()
+
+	writePDF(w, r, pdf)
+}
+
+// writeDecodeError отвечает на ошибку разбора тела: несовместимая версия
+// контракта — 400 unsupported_schema_version, тело больше предела —
+// 413 payload_too_large, остальное — 400 invalid_json.
+func writeDecodeError(w stdhttp.ResponseWriter, err error) {
+	var (
+		ve  *contract.VersionError
+		mbe *stdhttp.MaxBytesError
+	)
+	switch {
+	case errors.As(err, &ve):
+		writeJSONError(w, stdhttp.StatusBadRequest, contract.ErrCodeUnsupportedSchemaVersion, ve.Error())
+	case errors.As(err, &mbe):
+		writeJSONError(w, stdhttp.StatusRequestEntityTooLarge, contract.ErrCodePayloadTooLarge, fmt.Sprintf("Request body exceeds %d bytes", mbe.Limit))
+	default:
+		writeJSONError(w, stdhttp.StatusBadRequest, "invalid_json", fmt.Sprintf("Failed to decode JSON: %v", err))
 	}
 }
 
+// writeJSONError отвечает ошибкой с идентификатором запроса из заголовка
+// X-Request-ID ответа (его ставит Server.ServeHTTP).
 func writeJSONError(w stdhttp.ResponseWriter, status int, code, msg string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
 	_ = json.NewEncoder(w).Encode(errorResponse{
-		Error:   code,
-		Message: msg,
+		Error:     code,
+		Message:   msg,
+		RequestID: w.Header().Get(requestid.Header),
 	})
 }
@@ -0,0 +1,58 @@
+package http
+
+import (
+	stdhttp "net/http"
+	"strconv"
+	"time"
+
+	"resume_contract/metrics"
+)
+
+var (
+	httpRequests = metrics.Default.NewCounter(
+		"http_requests_total",
+		"HTTP requests by route pattern, method and status.",
+		"route", "method", "status",
+	)
+	httpDuration = metrics.Default.NewHistogram(
+		"http_request_duration_seconds",
+		"HTTP request latency by route pattern, method and status.",
+		metrics.DurationBuckets,
+		"route", "method", "status",
+	)
+)
+
+// statusWriter запоминает статус ответа для метрик.
+type statusWriter struct {
+	stdhttp.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(statusCode int) {
+	w.status = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap даёт http.ResponseController доступ к исходному ResponseWriter
+// (Flush для потока событий рендера).
+func (w *statusWriter) Unwrap() stdhttp.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// observe обслуживает запрос через mux и записывает его в метрики HTTP.
+// Запросы к неизвестным путям попадают в один ряд с пустым route.
+func (s *Server) observe(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	if r.URL.Path == "/metrics" {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	_, route := s.mux.Handler(r)
+	sw := &statusWriter{ResponseWriter: w, status: stdhttp.StatusOK}
+	start := time.Now()
+	s.mux.ServeHTTP(sw, r)
+
+	code, method := strconv.Itoa(sw.status), metrics.Method(r.Method)
+	httpRequests.Inc(route, method, code)
+	httpDuration.ObserveDuration(time.Since(start), route, method, code)
+}
@@ -1,39 +1,62 @@
 package http
 
 import (
-	"log"
+	"log/slog"
 	stdhttp "net/http"
 
 	"latex_service/internal/latex"
+
+	contract "resume_contract"
+	"resume_contract/metrics"
+	"resume_contract/requestid"
 )
 
 // Server инкапсулирует HTTP-маршрутизацию latex-service.
 type Server struct {
-	mux      *stdhttp.ServeMux
-	renderer *latex.Renderer
-	logger   *log.Logger
+	mux         *stdhttp.ServeMux
+	renderer    *latex.Renderer
+	logger      *slog.Logger
+	maxBodySize int64
 }
 
 // NewServer создаёт новый HTTP-сервер latex-service.
-func NewServer(renderer *latex.Renderer, logger *log.Logger) *Server {
+func NewServer(renderer *latex.Renderer, logger *slog.Logger) *Server {
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
 	}
 
 	mux := stdhttp.NewServeMux()
 	s := &Server{
-		mux:      mux,
-		renderer: renderer,
-		logger:   logger,
+		mux:         mux,
+		renderer:    renderer,
+		logger:      logger,
+		maxBodySize: contract.DefaultMaxRequestSize,
 	}
 
 	mux.HandleFunc("/healthz", s.handleHealth)
 	mux.HandleFunc("/internal/v1/render", s.handleRender)
+	mux.HandleFunc("/internal/v1/render/diff", s.handleRenderDiff)
+	mux.Handle("/metrics", metrics.Default.Handler())
 
 	return s
 }
 
-// ServeHTTP реализует http.Handler.
+// SetMaxBodySize ограничивает тело запросов рендера: тело больше n байт
+// отклоняется с 413 payload_too_large. n <= 0 возвращает значение по
+// умолчанию (contract.DefaultMaxRequestSize).
+func (s *Server) SetMaxBodySize(n int64) {
+	if n <= 0 {
+		n = contract.DefaultMaxRequestSize
+	}
+	s.maxBodySize = n
+}
+
+// ServeHTTP реализует http.Handler. Идентификатор запроса берётся из
+// заголовка X-Request-ID (его передаёт backend) или создаётся, попадает
+// в контекст запроса для логов и возвращается в заголовке ответа.
+// Запросы, кроме /metrics, учитываются в метриках HTTP.
 func (s *Server) ServeHTTP(w stdhttp.ResponseWriter, r *stdhttp.Request) {
-	s.mux.ServeHTTP(w, r)
+	id := requestid.FromRequest(r)
+	w.Header().Set(requestid.Header, id)
+	s.observe(w, r.WithContext(requestid.WithID(r.Context(), id)))
 }
@@ -0,0 +1,57 @@
+package latex
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+
+	"resume_contract/metrics"
+)
+
+var (
+	latexmkDuration = metrics.Default.NewHistogram(
+		"latexmk_duration_seconds",
+		"Duration of latexmk runs by result (ok or failed), without the wait for a free worker.",
+		metrics.DurationBuckets,
+		"result",
+	)
+	latexmkRuns = metrics.Default.NewCounter(
+		"latexmk_runs_total",
+		"latexmk runs by exit code; signal - killed on timeout or cancellation, start_failed - not started.",
+		"exit_code",
+	)
+	photoDuration = metrics.Default.NewHistogram(
+		"photo_processing_duration_seconds",
+		"Time to decode, crop and re-encode the resume photo by result (ok or failed).",
+		metrics.DurationBuckets,
+		"result",
+	)
+	pdfBytes = metrics.Default.NewHistogram(
+		"pdf_output_bytes",
+		"Size of PDFs produced by latexmk, including those rejected by MAX_PDF_SIZE.",
+		metrics.SizeBuckets,
+	)
+)
+
+// result — значение метки result.
+func result(err error) string {
+	if err != nil {
+		return "failed"
+	}
+	return "ok"
+}
+
+// exitCode — значение метки exit_code для результата cmd.Run.
+func exitCode(err error) string {
+	if err == nil {
+		return "0"
+	}
+	var ee *exec.ExitError
+	if !errors.As(err, &ee) {
+		return "start_failed"
+	}
+	if code := ee.ExitCode(); code >= 0 {
+		return strconv.Itoa(code)
+	}
+	return "signal"
+}
@@ -0,0 +1,73 @@
+package latex
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrOutputTooLarge — PDF больше предела, заданного SetMaxOutputSize.
+var ErrOutputTooLarge = errors.New("generated PDF exceeds the size limit")
+
+// PDF — готовый документ в каталоге компиляции. Файл можно читать и
+// позиционировать (например, для ответа на Range-запрос); Close удаляет
+// каталог вместе с ним.
+type PDF struct {
+	*os.File
+	// Size — размер документа в байтах.
+	Size int64
+	dir  string
+}
+
+// Close закрывает файл и удаляет каталог компиляции.
+func (p *PDF) Close() error {
+	err := p.File.Close()
+	if rerr := os.RemoveAll(p.dir); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// openPDF открывает результат компиляции и проверяет его размер.
+func (r *Renderer) openPDF(dir, path string) (*PDF, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read pdf: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("read pdf: %w", err)
+	}
+	if info.Size() > 0 {
+		pdfBytes.Observe(float64(info.Size()))
+	}
+
+	switch {
+	case info.Size() == 0:
+		err = fmt.Errorf("generated PDF is empty")
+	case r.maxOutput > 0 && info.Size() > r.maxOutput:
+		err = fmt.Errorf("%w: %d bytes, limit %d", ErrOutputTooLarge, info.Size(), r.maxOutput)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &PDF{File: f, Size: info.Size(), dir: dir}, nil
+}
+
+// readPDF читает документ целиком и удаляет его каталог.
+func readPDF(pdf *PDF, err error) ([]byte, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer pdf.Close()
+
+	data := make([]byte, pdf.Size)
+	if _, err := io.ReadFull(pdf, data); err != nil {
+		return nil, fmt.Errorf("read pdf: %w", err)
+	}
+	return data, nil
+}
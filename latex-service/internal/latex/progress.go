@@ -0,0 +1,95 @@
+package latex
+
+import (
+	"bytes"
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	contract "resume_contract"
+)
+
+// maxPasses — значение max_repeat latexmk по умолчанию: больше проходов
+// pdflatex он не делает, даже если ссылки так и не сошлись.
+const maxPasses = 5
+
+// ProgressFunc получает события рендера по мере их наступления.
+type ProgressFunc func(contract.RenderEvent)
+
+type progressKey struct{}
+
+// WithProgress возвращает контекст, рендер с которым сообщает о своих
+// этапах в fn. Без него Render работает как обычно.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func report(ctx context.Context, ev contract.RenderEvent) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(ev)
+	}
+}
+
+// runNumberRe — строка, которую latexmk печатает перед каждым запуском
+// pdflatex: "Run number 2 of rule 'pdflatex'".
+var runNumberRe = regexp.MustCompile(`Run number (\d+) of rule '(?:pdf)?latex'`)
+
+// tailLines — сколько последних строк вывода latexmk попадает в лог
+// при ошибке.
+const tailLines = 30
+
+// passWatcher — io.Writer для вывода latexmk: режет его на строки, сообщает
+// о каждом новом проходе pdflatex и хранит последние строки для лога.
+// stdout и stderr пишутся из разных горутин, поэтому запись под мьютексом.
+type passWatcher struct {
+	ctx context.Context
+
+	mu   sync.Mutex
+	buf  []byte
+	pass int
+	tail []string
+}
+
+func (w *passWatcher) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.line(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Tail возвращает последние строки вывода latexmk.
+func (w *passWatcher) Tail() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	lines := append(w.tail[:len(w.tail):len(w.tail)], string(w.buf))
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func (w *passWatcher) line(l []byte) {
+	if len(w.tail) == tailLines {
+		w.tail = w.tail[1:]
+	}
+	w.tail = append(w.tail, string(l))
+
+	m := runNumberRe.FindSubmatch(l)
+	if m == nil {
+		return
+	}
+	n, err := strconv.Atoi(string(m[1]))
+	if err != nil || n <= w.pass {
+		return
+	}
+	w.pass = n
+	report(w.ctx, contract.RenderEvent{Stage: contract.StageCompile, Pass: n, MaxPasses: maxPasses})
+}
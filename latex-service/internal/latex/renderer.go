@@ -3,34 +3,67 @@ package latex
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
-	"latex_service/internal/model"
+	contract "resume_contract"
 )
 
 // Renderer отвечает за генерацию LaTeX и PDF.
 type Renderer struct {
 	templatePath string
-	logger       *log.Logger
+	// slots ограничивает число одновременных запусков latexmk.
+	slots chan struct{}
+	// maxOutput — предельный размер PDF; 0 — без ограничения.
+	maxOutput int64
+	logger    *slog.Logger
 }
 
-// NewRenderer создаёт новый Renderer.
-func NewRenderer(templatePath string, logger *log.Logger) *Renderer {
+// NewRenderer создаёт новый Renderer, который запускает не больше workers
+// latexmk одновременно (workers < 1 означает 1).
+func NewRenderer(templatePath string, workers int, logger *slog.Logger) *Renderer {
 	if logger == nil {
-		logger = log.Default()
+		logger = slog.Default()
 	}
 	return &Renderer{
 		templatePath: templatePath,
+		slots:        make(chan struct{}, max(workers, 1)),
 		logger:       logger,
 	}
 }
 
-// Render генерирует PDF по данным резюме.
-func (r *Renderer) Render(ctx context.Context, resume model.Resume) ([]byte, error) {
+// Workers возвращает размер пула latexmk.
+func (r *Renderer) Workers() int {
+	return cap(r.slots)
+}
+
+// Busy возвращает число занятых слотов пула.
+func (r *Renderer) Busy() int {
+	return len(r.slots)
+}
+
+// SetMaxOutputSize ограничивает размер PDF: документ больше n байт
+// считается ошибкой ErrOutputTooLarge. n <= 0 снимает ограничение.
+func (r *Renderer) SetMaxOutputSize(n int64) {
+	r.maxOutput = max(n, 0)
+}
+
+// Render генерирует PDF по данным резюме и возвращает его целиком, см.
+// RenderFile.
+func (r *Renderer) Render(ctx context.Context, resume contract.Resume) ([]byte, error) {
+	return readPDF(r.RenderFile(ctx, resume))
+}
+
+// RenderFile генерирует PDF по данным резюме и возвращает открытый файл
+// результата, который можно отдавать потоком. О ходе работы (шаблон, фото,
+// проходы latexmk) он сообщает в ProgressFunc из контекста, см. WithProgress.
+func (r *Renderer) RenderFile(ctx context.Context, resume contract.Resume) (*PDF, error) {
+	report(ctx, contract.RenderEvent{Stage: contract.StageTemplate})
+
 	templateBytes, err := os.ReadFile(r.templatePath)
 	if err != nil {
 		return nil, fmt.Errorf("read template: %w", err)
@@ -53,9 +86,12 @@ func (r *Renderer) Render(ctx context.Context, resume model.Resume) ([]byte, err
 	var photoExt string
 
 	if resume.Photo != nil && strings.TrimSpace(resume.Photo.Data) != "" {
+		report(ctx, contract.RenderEvent{Stage: contract.StagePhoto})
+		start := time.Now()
 		pb, ext, err := processPhoto(resume.Photo.Data, resume.Photo.MimeType)
+		photoDuration.ObserveDuration(time.Since(start), result(err))
 		if err != nil {
-			r.logger.Printf("photo processing failed: %v", err)
+			r.logger.WarnContext(ctx, "photo processing failed", "error", err)
 			placeholders["Photo"] = ""
 		} else {
 			photoBytes = pb
@@ -70,44 +106,95 @@ func (r *Renderer) Render(ctx context.Context, resume model.Resume) ([]byte, err
 		latexSource = strings.ReplaceAll(latexSource, "{{"+key+"}}", val)
 	}
 
+	files := map[string][]byte{}
+	if len(photoBytes) > 0 && photoExt != "" {
+		files["photo."+photoExt] = photoBytes
+	}
+
+	return r.compile(ctx, latexSource, files)
+}
+
+// RenderDiff генерирует PDF с визуальным сравнением двух версий резюме и
+// возвращает его целиком, см. RenderDiffFile.
+func (r *Renderer) RenderDiff(ctx context.Context, doc contract.DiffDocument) ([]byte, error) {
+	return readPDF(r.RenderDiffFile(ctx, doc))
+}
+
+// RenderDiffFile генерирует PDF с визуальным сравнением двух версий резюме.
+// Шаблон diff_template.tex лежит рядом с основным шаблоном.
+func (r *Renderer) RenderDiffFile(ctx context.Context, doc contract.DiffDocument) (*PDF, error) {
+	templatePath := filepath.Join(filepath.Dir(r.templatePath), "diff_template.tex")
+	templateBytes, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("read diff template: %w", err)
+	}
+
+	latexSource := string(templateBytes)
+	placeholders := map[string]string{
+		"Title":    escapeLatex(doc.Title),
+		"Subtitle": escapeLatex(doc.Subtitle),
+		"Body":     buildDiffBody(doc.Sections),
+	}
+	for key, val := range placeholders {
+		latexSource = strings.ReplaceAll(latexSource, "{{"+key+"}}", val)
+	}
+
+	return r.compile(ctx, latexSource, nil)
+}
+
+// compile собирает PDF из исходника LaTeX во временном каталоге; files —
+// дополнительные файлы (например, фото), которые кладутся рядом. Каталог
+// удаляется при закрытии результата.
+func (r *Renderer) compile(ctx context.Context, latexSource string, files map[string][]byte) (_ *PDF, err error) {
 	workDir, err := os.MkdirTemp("", "resume-latex-*")
 	if err != nil {
 		return nil, fmt.Errorf("create temp dir: %w", err)
 	}
-	defer os.RemoveAll(workDir)
+	defer func() {
+		if err != nil {
+			os.RemoveAll(workDir)
+		}
+	}()
 
 	texPath := filepath.Join(workDir, "resume.tex")
 	if err := os.WriteFile(texPath, []byte(latexSource), 0o644); err != nil {
 		return nil, fmt.Errorf("write tex file: %w", err)
 	}
 
-	if len(photoBytes) > 0 && photoExt != "" {
-		photoPath := filepath.Join(workDir, "photo."+photoExt)
-		if err := os.WriteFile(photoPath, photoBytes, 0o644); err != nil {
-			r.logger.Printf("failed to write photo file: %v", err)
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(workDir, name), data, 0o644); err != nil {
+			r.logger.ErrorContext(ctx, "failed to write file", "file", name, "error", err)
 		}
 	}
 
-	cmd := exec.CommandContext(ctx, "latexmk", "-pdf", "-interaction=nonstopmode", "resume.tex")
-	cmd.Dir = workDir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("latexmk failed: %w", err)
+	select {
+	case r.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for latexmk slot: %w", ctx.Err())
 	}
+	defer func() { <-r.slots }()
 
-	pdfPath := filepath.Join(workDir, "resume.pdf")
-	pdfBytes, err := os.ReadFile(pdfPath)
+	cmd := exec.CommandContext(ctx, "latexmk", "-pdf", "-interaction=nonstopmode", "resume.tex")
+	cmd.Dir = workDir
+	// вывод latexmk не пишется в stdout, чтобы не смешивать его с JSON-логом:
+	// при ошибке его хвост попадает в запись лога
+	watcher := &passWatcher{ctx: ctx}
+	cmd.Stdout = watcher
+	cmd.Stderr = watcher
+
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start)
+	latexmkDuration.ObserveDuration(elapsed, result(err))
+	latexmkRuns.Inc(exitCode(err))
+	duration := float64(elapsed.Microseconds()) / 1000
 	if err != nil {
-		return nil, fmt.Errorf("read pdf: %w", err)
-	}
-
-	if len(pdfBytes) == 0 {
-		return nil, fmt.Errorf("generated PDF is empty")
+		r.logger.ErrorContext(ctx, "latexmk failed", "duration_ms", duration, "error", err, "output", watcher.Tail())
+		return nil, fmt.Errorf("latexmk failed: %w", err)
 	}
+	r.logger.InfoContext(ctx, "latexmk finished", "duration_ms", duration, "passes", watcher.pass)
 
-	return pdfBytes, nil
+	return r.openPDF(workDir, filepath.Join(workDir, "resume.pdf"))
 }
 
 func buildSummary(summary string) string {
@@ -117,7 +204,7 @@ func buildSummary(summary string) string {
 	return escapeLatex(summary)
 }
 
-func buildContacts(c model.Contacts) string {
+func buildContacts(c contract.Contacts) string {
 	var parts []string
 
 	if strings.TrimSpace(c.Email) != "" {
@@ -161,7 +248,7 @@ func buildSkills(skills []string) string {
 	return b.String()
 }
 
-func buildExperience(exps []model.ExperienceEntry) string {
+func buildExperience(exps []contract.ExperienceEntry) string {
 	if len(exps) == 0 {
 		return ""
 	}
@@ -214,7 +301,7 @@ func buildExperience(exps []model.ExperienceEntry) string {
 	return b.String()
 }
 
-func buildEducation(eds []model.EducationEntry) string {
+func buildEducation(eds []contract.EducationEntry) string {
 	if len(eds) == 0 {
 		return ""
 	}
@@ -258,7 +345,7 @@ func buildEducation(eds []model.EducationEntry) string {
 	return b.String()
 }
 
-func buildCustomSections(sections []model.CustomSection) string {
+func buildCustomSections(sections []contract.CustomSection) string {
 	if len(sections) == 0 {
 		return ""
 	}
@@ -292,3 +379,31 @@ func buildCustomSections(sections []model.CustomSection) string {
 
 	return b.String()
 }
+
+func buildDiffBody(sections []contract.DiffSection) string {
+	var b strings.Builder
+	for _, sec := range sections {
+		b.WriteString("\\section*{" + escapeLatex(sec.Title) + "}\n")
+		for _, l := range sec.Lines {
+			text := escapeLatex(l.Text)
+			old := escapeLatex(l.Old)
+			if l.Bullet {
+				text = "\\textbullet{} " + text
+				old = "\\textbullet{} " + old
+			}
+
+			switch l.Kind {
+			case contract.DiffAdded:
+				b.WriteString("\\diffadded{" + text + "}\n")
+			case contract.DiffRemoved:
+				b.WriteString("\\diffremoved{" + text + "}\n")
+			case contract.DiffChanged:
+				b.WriteString("\\diffremoved{" + old + "}\n")
+				b.WriteString("\\diffadded{" + text + "}\n")
+			default:
+				b.WriteString("\\diffsame{" + text + "}\n")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,258 @@
+package pdftext
+
+import (
+	"math"
+)
+
+// matrix — аффинная матрица PDF [a b c d e f].
+type matrix [6]float64
+
+var identity = matrix{1, 0, 0, 1, 0, 0}
+
+// mul возвращает m × n (сначала m, затем n), как в спецификации PDF.
+func (m matrix) mul(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// run — фрагмент текста, выведенный одним оператором показа текста.
+type run struct {
+	x, y   float64 // начало базовой линии в координатах страницы
+	endX   float64
+	size   float64 // эффективный кегль
+	text   string
+	strict string
+}
+
+// textState — состояние текста и графики, нужное для позиционирования.
+type textState struct {
+	ctm        matrix
+	tm, tlm    matrix
+	font       *font
+	fontSize   float64
+	charSpace  float64
+	wordSpace  float64
+	scale      float64
+	leading    float64
+	rise       float64
+	stack      []matrix
+	fonts      map[name]*font
+	fontDicts  dict
+	doc        *document
+	runs       []run
+	spaceRatio float64 // порог кернинга в TJ (в тысячных em), после которого вставляется пробел
+}
+
+// interpret выполняет поток содержимого страницы и собирает фрагменты текста.
+func (d *document) interpret(content []byte, resources dict) []run {
+	st := &textState{
+		ctm:        identity,
+		tm:         identity,
+		tlm:        identity,
+		scale:      1,
+		fonts:      make(map[name]*font),
+		doc:        d,
+		spaceRatio: 180,
+	}
+	if resources != nil {
+		st.fontDicts = d.dictOf(resources["Font"])
+	}
+
+	p := newParser(content)
+	var operands []any
+
+	for {
+		v, err := p.next()
+		if err != nil {
+			if err == errEOF {
+				break
+			}
+			operands = operands[:0]
+			continue
+		}
+		op, ok := v.(keyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
+		}
+
+		switch op {
+		case "BI":
+			skipInlineImage(p)
+		default:
+			st.apply(op, operands)
+		}
+		operands = operands[:0]
+	}
+
+	return st.runs
+}
+
+// skipInlineImage пропускает данные встроенного изображения до EI.
+func skipInlineImage(p *parser) {
+	for !p.eof() {
+		if p.buf[p.pos] == 'E' && p.pos+1 < len(p.buf) && p.buf[p.pos+1] == 'I' &&
+			p.pos > 0 && isWhitespace(p.buf[p.pos-1]) &&
+			(p.pos+2 == len(p.buf) || isWhitespace(p.buf[p.pos+2])) {
+			p.pos += 2
+			return
+		}
+		p.pos++
+	}
+}
+
+func num(operands []any, i int) float64 {
+	if i < 0 || i >= len(operands) {
+		return 0
+	}
+	v, _ := operands[i].(float64)
+	return v
+}
+
+func (st *textState) apply(op keyword, ops []any) {
+	switch op {
+	case "q":
+		st.stack = append(st.stack, st.ctm)
+	case "Q":
+		if n := len(st.stack); n > 0 {
+			st.ctm = st.stack[n-1]
+			st.stack = st.stack[:n-1]
+		}
+	case "cm":
+		if len(ops) == 6 {
+			st.ctm = matrix{num(ops, 0), num(ops, 1), num(ops, 2), num(ops, 3), num(ops, 4), num(ops, 5)}.mul(st.ctm)
+		}
+	case "BT":
+		st.tm, st.tlm = identity, identity
+	case "Tf":
+		if len(ops) == 2 {
+			if fn, ok := ops[0].(name); ok {
+				st.font = st.loadFont(fn)
+			}
+			st.fontSize = num(ops, 1)
+		}
+	case "Tc":
+		st.charSpace = num(ops, 0)
+	case "Tw":
+		st.wordSpace = num(ops, 0)
+	case "Tz":
+		st.scale = num(ops, 0) / 100
+	case "TL":
+		st.leading = num(ops, 0)
+	case "Ts":
+		st.rise = num(ops, 0)
+	case "Td":
+		st.moveLine(num(ops, 0), num(ops, 1))
+	case "TD":
+		st.leading = -num(ops, 1)
+		st.moveLine(num(ops, 0), num(ops, 1))
+	case "Tm":
+		if len(ops) == 6 {
+			st.tm = matrix{num(ops, 0), num(ops, 1), num(ops, 2), num(ops, 3), num(ops, 4), num(ops, 5)}
+			st.tlm = st.tm
+		}
+	case "T*":
+		st.moveLine(0, -st.leading)
+	case "Tj":
+		if len(ops) == 1 {
+			if s, ok := ops[0].(pdfString); ok {
+				st.show(array{s})
+			}
+		}
+	case "TJ":
+		if len(ops) == 1 {
+			if arr, ok := ops[0].(array); ok {
+				st.show(arr)
+			}
+		}
+	case "'":
+		st.moveLine(0, -st.leading)
+		if len(ops) == 1 {
+			if s, ok := ops[0].(pdfString); ok {
+				st.show(array{s})
+			}
+		}
+	case "\"":
+		if len(ops) == 3 {
+			st.wordSpace = num(ops, 0)
+			st.charSpace = num(ops, 1)
+			st.moveLine(0, -st.leading)
+			if s, ok := ops[2].(pdfString); ok {
+				st.show(array{s})
+			}
+		}
+	}
+}
+
+func (st *textState) moveLine(tx, ty float64) {
+	st.tlm = matrix{1, 0, 0, 1, tx, ty}.mul(st.tlm)
+	st.tm = st.tlm
+}
+
+func (st *textState) loadFont(fn name) *font {
+	if f, ok := st.fonts[fn]; ok {
+		return f
+	}
+	var fd dict
+	if st.fontDicts != nil {
+		fd = st.doc.dictOf(st.fontDicts[fn])
+	}
+	f := st.doc.loadFont(fd)
+	st.fonts[fn] = f
+	return f
+}
+
+// show обрабатывает операнд TJ: строки выводятся, числа сдвигают позицию,
+// а крупный отрицательный кернинг считается межсловным пробелом.
+func (st *textState) show(items array) {
+	if st.font == nil {
+		st.font = st.doc.loadFont(nil)
+	}
+
+	trm := matrix{st.fontSize * st.scale, 0, 0, st.fontSize, 0, st.rise}.mul(st.tm).mul(st.ctm)
+	r := run{
+		x:    trm[4],
+		y:    trm[5],
+		size: math.Hypot(trm[2], trm[3]),
+	}
+
+	for _, item := range items {
+		switch v := item.(type) {
+		case float64:
+			if v < -st.spaceRatio {
+				r.text += " "
+				r.strict += " "
+			}
+			st.advance(-v / 1000 * st.fontSize * st.scale)
+		case pdfString:
+			step := st.font.codeBytes
+			for i := 0; i+step <= len(v); i += step {
+				code := bytesToCode(v[i : i+step])
+				r.text += st.font.decode(code, true)
+				r.strict += st.font.decode(code, false)
+
+				tx := st.font.width(code)*st.fontSize + st.charSpace
+				if step == 1 && code == 32 {
+					tx += st.wordSpace
+				}
+				st.advance(tx * st.scale)
+			}
+		}
+	}
+
+	end := matrix{1, 0, 0, 1, 0, 0}.mul(st.tm).mul(st.ctm)
+	r.endX = end[4]
+	if r.text != "" {
+		st.runs = append(st.runs, r)
+	}
+}
+
+func (st *textState) advance(tx float64) {
+	st.tm = matrix{1, 0, 0, 1, tx, 0}.mul(st.tm)
+}
@@ -0,0 +1,228 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+var errEOF = errors.New("unexpected end of data")
+
+// objHeaderRe находит заголовки косвенных объектов "N G obj".
+var objHeaderRe = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// maxDecodedStream ограничивает размер распакованного потока.
+const maxDecodedStream = 32 << 20
+
+// document — загруженные объекты PDF. Таблица xref не используется:
+// объекты находятся сканированием файла, а сжатые — через потоки /ObjStm.
+// Для PDF, которые генерирует pdfTeX, этого достаточно.
+type document struct {
+	objects map[int]any
+}
+
+func loadDocument(data []byte) (*document, error) {
+	doc := &document{objects: make(map[int]any)}
+
+	for _, m := range objHeaderRe.FindAllSubmatchIndex(data, -1) {
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		p := newParser(data)
+		p.pos = m[1]
+		v, err := p.next()
+		if err != nil {
+			continue
+		}
+		if d, ok := v.(dict); ok {
+			p.skipSpace()
+			if bytes.HasPrefix(p.buf[p.pos:], []byte("stream")) {
+				p.pos += len("stream")
+				v = &stream{dict: d, raw: p.readStreamData(d)}
+			}
+		}
+		doc.objects[num] = v
+	}
+
+	if len(doc.objects) == 0 {
+		return nil, fmt.Errorf("no PDF objects found")
+	}
+
+	// Объекты внутри потоков /ObjStm (PDF 1.5+).
+	for _, v := range doc.objects {
+		s, ok := v.(*stream)
+		if !ok || s.dict["Type"] != name("ObjStm") {
+			continue
+		}
+		doc.loadObjectStream(s)
+	}
+
+	return doc, nil
+}
+
+func (d *document) loadObjectStream(s *stream) {
+	data, err := d.decodeStream(s)
+	if err != nil {
+		return
+	}
+	n, _ := d.resolve(s.dict["N"]).(float64)
+	first, _ := d.resolve(s.dict["First"]).(float64)
+
+	header := newParser(data[:min(int(first), len(data))])
+	for i := 0; i < int(n); i++ {
+		numV, err1 := header.next()
+		offV, err2 := header.next()
+		if err1 != nil || err2 != nil {
+			return
+		}
+		num, _ := numV.(float64)
+		off, _ := offV.(float64)
+		if _, exists := d.objects[int(num)]; exists {
+			continue
+		}
+		p := newParser(data)
+		p.pos = int(first) + int(off)
+		if p.pos >= len(data) {
+			continue
+		}
+		if v, err := p.next(); err == nil {
+			d.objects[int(num)] = v
+		}
+	}
+}
+
+// resolve разыменовывает ссылки (с защитой от циклов).
+func (d *document) resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		r, ok := v.(ref)
+		if !ok {
+			return v
+		}
+		v = d.objects[r.num]
+	}
+	return nil
+}
+
+func (d *document) dictOf(v any) dict {
+	switch x := d.resolve(v).(type) {
+	case dict:
+		return x
+	case *stream:
+		return x.dict
+	}
+	return nil
+}
+
+// decodeStream распаковывает поток. Поддерживается только FlateDecode без
+// предикторов — именно так pdfTeX сжимает потоки содержимого и объектов.
+func (d *document) decodeStream(s *stream) ([]byte, error) {
+	var filters []name
+	switch f := d.resolve(s.dict["Filter"]).(type) {
+	case nil:
+	case name:
+		filters = []name{f}
+	case array:
+		for _, item := range f {
+			if n, ok := d.resolve(item).(name); ok {
+				filters = append(filters, n)
+			}
+		}
+	}
+
+	data := s.raw
+	for _, f := range filters {
+		if f != "FlateDecode" && f != "Fl" {
+			return nil, fmt.Errorf("unsupported filter %s", f)
+		}
+		zr, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("flate: %w", err)
+		}
+		out, err := io.ReadAll(io.LimitReader(zr, maxDecodedStream))
+		zr.Close()
+		if err != nil && len(out) == 0 {
+			return nil, fmt.Errorf("flate: %w", err)
+		}
+		data = out
+	}
+	return data, nil
+}
+
+// catalog находит корневой словарь документа.
+func (d *document) catalog() dict {
+	for _, v := range d.objects {
+		if dd := d.dictOf(v); dd != nil && dd["Type"] == name("Catalog") {
+			return dd
+		}
+	}
+	return nil
+}
+
+// page — страница с унаследованными ресурсами.
+type page struct {
+	dict      dict
+	resources dict
+}
+
+// pages обходит дерево /Pages в порядке следования страниц.
+func (d *document) pages() ([]page, error) {
+	cat := d.catalog()
+	if cat == nil {
+		return nil, fmt.Errorf("catalog not found")
+	}
+
+	var out []page
+	var walk func(node dict, inherited dict, depth int)
+	walk = func(node dict, inherited dict, depth int) {
+		if node == nil || depth > 64 {
+			return
+		}
+		res := inherited
+		if r := d.dictOf(node["Resources"]); r != nil {
+			res = r
+		}
+		switch node["Type"] {
+		case name("Pages"):
+			kids, _ := d.resolve(node["Kids"]).(array)
+			for _, k := range kids {
+				walk(d.dictOf(k), res, depth+1)
+			}
+		case name("Page"):
+			out = append(out, page{dict: node, resources: res})
+		}
+	}
+	walk(d.dictOf(cat["Pages"]), nil, 0)
+
+	if len(out) == 0 {
+		return nil, fmt.Errorf("document has no pages")
+	}
+	return out, nil
+}
+
+// contents возвращает распакованные потоки содержимого страницы, склеенные вместе.
+func (d *document) contents(pg page) []byte {
+	var streams []*stream
+	switch c := d.resolve(pg.dict["Contents"]).(type) {
+	case *stream:
+		streams = append(streams, c)
+	case array:
+		for _, item := range c {
+			if s, ok := d.resolve(item).(*stream); ok {
+				streams = append(streams, s)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, s := range streams {
+		data, err := d.decodeStream(s)
+		if err != nil {
+			continue
+		}
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,93 @@
+// Package pdftext извлекает текст из PDF средствами чистого Go, имитируя
+// то, как его видит система отслеживания кандидатов (ATS): фрагменты
+// собираются в строки по координатам, а строки читаются сверху вниз.
+package pdftext
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Text — результат извлечения в двух вариантах.
+type Text struct {
+	// Strict — текст так, как его увидит простая ATS: лигатуры остаются
+	// отдельными символами, неизвестные коды заменены на U+FFFD.
+	Strict string
+	// Lenient — тот же текст с разложенными лигатурами и угаданными кодами
+	// TeX; используется, чтобы объяснить, почему строгий вариант не совпал.
+	Lenient string
+}
+
+// Extract извлекает текст всех страниц документа.
+func Extract(data []byte) (Text, error) {
+	doc, err := loadDocument(data)
+	if err != nil {
+		return Text{}, err
+	}
+	pages, err := doc.pages()
+	if err != nil {
+		return Text{}, err
+	}
+
+	var strict, lenient []string
+	for _, pg := range pages {
+		runs := doc.interpret(doc.contents(pg), pg.resources)
+		s, l := layout(runs)
+		strict = append(strict, s)
+		lenient = append(lenient, l)
+	}
+
+	return Text{
+		Strict:  strings.Join(strict, "\n"),
+		Lenient: strings.Join(lenient, "\n"),
+	}, nil
+}
+
+// layout группирует фрагменты в строки по базовой линии и склеивает их
+// слева направо, вставляя пробел при заметном зазоре.
+func layout(runs []run) (string, string) {
+	type line struct {
+		y    float64
+		runs []run
+	}
+	var lines []*line
+
+	for _, r := range runs {
+		var target *line
+		for _, ln := range lines {
+			if math.Abs(ln.y-r.y) <= math.Max(1, r.size*0.3) {
+				target = ln
+				break
+			}
+		}
+		if target == nil {
+			target = &line{y: r.y}
+			lines = append(lines, target)
+		}
+		target.runs = append(target.runs, r)
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool { return lines[i].y > lines[j].y })
+
+	var strict, lenient strings.Builder
+	for i, ln := range lines {
+		if i > 0 {
+			strict.WriteByte('\n')
+			lenient.WriteByte('\n')
+		}
+		sort.SliceStable(ln.runs, func(a, b int) bool { return ln.runs[a].x < ln.runs[b].x })
+		for j, r := range ln.runs {
+			if j > 0 {
+				prev := ln.runs[j-1]
+				if r.x-prev.endX > r.size*0.15 {
+					strict.WriteByte(' ')
+					lenient.WriteByte(' ')
+				}
+			}
+			strict.WriteString(r.strict)
+			lenient.WriteString(r.text)
+		}
+	}
+	return strict.String(), lenient.String()
+}
@@ -0,0 +1,223 @@
+package pdftext
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF собирает минимальный одностраничный PDF. Объект 5 — поток
+// содержимого страницы, шрифты идут начиная с объекта 6.
+func buildPDF(t *testing.T, content string, compress bool, fonts ...string) []byte {
+	t.Helper()
+
+	var res strings.Builder
+	for i := range fonts {
+		fmt.Fprintf(&res, "/F%d %d 0 R ", i+1, 6+i)
+	}
+
+	objs := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /Resources 4 0 R /Contents 5 0 R >>",
+		"<< /Font << " + res.String() + ">> >>",
+	}
+
+	data := []byte(content)
+	filter := ""
+	if compress {
+		var buf bytes.Buffer
+		zw := zlib.NewWriter(&buf)
+		zw.Write(data)
+		zw.Close()
+		data = buf.Bytes()
+		filter = " /Filter /FlateDecode"
+	}
+	objs = append(objs, fmt.Sprintf("<< /Length %d%s >>\nstream\n%s\nendstream", len(data), filter, data))
+	objs = append(objs, fonts...)
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.5\n")
+	for i, o := range objs {
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+	out.WriteString("trailer << /Root 1 0 R >>\n%%EOF\n")
+	return out.Bytes()
+}
+
+const helvetica = "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		compress bool
+		fonts    []string
+		strict   string
+		lenient  string
+	}{
+		{
+			name:    "lines top to bottom",
+			content: "BT /F1 12 Tf 72 700 Td (Ivan Ivanov) Tj 0 -20 Td (Backend developer) Tj ET",
+			fonts:   []string{helvetica},
+			strict:  "Ivan Ivanov\nBackend developer",
+		},
+		{
+			name:     "compressed stream",
+			content:  "BT /F1 12 Tf 72 700 Td (Go, Kubernetes) Tj ET",
+			compress: true,
+			fonts:    []string{helvetica},
+			strict:   "Go, Kubernetes",
+		},
+		{
+			name:    "runs on one baseline are joined left to right",
+			content: "BT /F1 12 Tf 200 700 Td (world) Tj ET BT /F1 12 Tf 72 700 Td (hello) Tj ET",
+			fonts:   []string{helvetica},
+			strict:  "hello world",
+		},
+		{
+			name:    "TJ kerning becomes a space",
+			content: "BT /F1 12 Tf 72 700 Td [(Senior) -600 (Engineer)] TJ ET",
+			fonts:   []string{helvetica},
+			strict:  "Senior Engineer",
+		},
+		{
+			name:    "ligature from Differences",
+			content: "BT /F1 12 Tf 72 700 Td (o\\001ce) Tj ET",
+			fonts: []string{
+				"<< /Type /Font /Subtype /Type1 /BaseFont /Foo /Encoding << /BaseEncoding /WinAnsiEncoding /Differences [1 /ffi] >> >>",
+			},
+			strict:  "oﬃce",
+			lenient: "office",
+		},
+		{
+			name:    "OT1 builtin ligature",
+			content: "BT /F1 12 Tf 72 700 Td (\\014nance) Tj ET",
+			fonts:   []string{"<< /Type /Font /Subtype /Type1 /BaseFont /ABCDEF+CMR10 >>"},
+			strict:  "�nance",
+			lenient: "finance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Extract(buildPDF(t, tt.content, tt.compress, tt.fonts...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Strict != tt.strict {
+				t.Errorf("Strict = %q, want %q", got.Strict, tt.strict)
+			}
+			lenient := tt.lenient
+			if lenient == "" {
+				lenient = tt.strict
+			}
+			if got.Lenient != lenient {
+				t.Errorf("Lenient = %q, want %q", got.Lenient, lenient)
+			}
+		})
+	}
+}
+
+func TestExtractErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not a PDF", []byte("hello")},
+		{"no catalog", []byte("1 0 obj\n<< /Type /Pages /Kids [] >>\nendobj\n")},
+		{"no pages", []byte("1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n2 0 obj\n<< /Type /Pages /Kids [] >>\nendobj\n")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Extract(tt.data); err == nil {
+				t.Error("Extract succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestParseCMap(t *testing.T) {
+	cmap := []byte(`
+/CIDInit /ProcSet findresource begin
+begincmap
+2 beginbfchar
+<0041> <0041>
+<0001> <FB01>
+endbfchar
+1 beginbfrange
+<0061> <0063> <0430>
+endbfrange
+1 beginbfrange
+<0010> <0011> [<0078> <D83DDE00>]
+endbfrange
+endcmap
+`)
+	got := parseCMap(cmap)
+	want := map[int]string{
+		0x41: "A",
+		0x01: "ﬁ",
+		0x61: "а", 0x62: "б", 0x63: "в",
+		0x10: "x", 0x11: "😀",
+	}
+	for code, s := range want {
+		if got[code] != s {
+			t.Errorf("code %#x = %q, want %q", code, got[code], s)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("len = %d, want %d", len(got), len(want))
+	}
+}
+
+func TestGlyphToUnicode(t *testing.T) {
+	tests := []struct {
+		glyph string
+		want  string
+		ok    bool
+	}{
+		{"A", "A", true},
+		{"hyphen", "-", true},
+		{"endash", "–", true},
+		{"eacute", "é", true},
+		{"Scaron", "Š", true},
+		{"uni0416", "Ж", true},
+		{"u1F600", "😀", true},
+		{"a.sc", "a", true},
+		{"one.oldstyle", "1", true},
+		{"notaglyph", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := glyphToUnicode(tt.glyph)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("glyphToUnicode(%q) = %q, %v; want %q, %v", tt.glyph, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReadLiteralString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`(plain)`, "plain"},
+		{`(nested (parens))`, "nested (parens)"},
+		{`(esc\) \\ \n)`, "esc) \\ \n"},
+		{`(\101\102)`, "AB"},
+		{"(line\\\ncontinued)", "linecontinued"},
+	}
+	for _, tt := range tests {
+		v, err := newParser([]byte(tt.in)).next()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.in, err)
+		}
+		if s, _ := v.(pdfString); string(s) != tt.want {
+			t.Errorf("%s = %q, want %q", tt.in, s, tt.want)
+		}
+	}
+
+	if _, err := newParser([]byte("(unterminated")).next(); err == nil {
+		t.Error("unterminated string parsed without error")
+	}
+}
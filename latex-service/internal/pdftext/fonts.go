@@ -0,0 +1,365 @@
+package pdftext
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// font — всё, что нужно для перевода кодов символов в текст и расчёта
+// ширин глифов.
+type font struct {
+	toUnicode  map[int]string // из /ToUnicode
+	codeBytes  int            // 1 для простых шрифтов, 2 для Type0
+	encoding   [256]string    // имя глифа по коду (простые шрифты)
+	hasEncTbl  bool
+	builtin    bool // нет /Encoding: встроенная кодировка шрифта TeX
+	symbolic   bool // математический/символьный шрифт TeX (cmsy, msam, ...)
+	firstChar  int
+	widths     []float64
+	widthScale float64 // 1/1000 для Type1/TrueType, FontMatrix[0] для Type3
+}
+
+// loadFont разбирает словарь шрифта.
+func (d *document) loadFont(fd dict) *font {
+	f := &font{codeBytes: 1, widthScale: 0.001}
+	if fd == nil {
+		f.builtin = true
+		return f
+	}
+
+	if base, ok := d.resolve(fd["BaseFont"]).(name); ok {
+		f.symbolic = isSymbolFont(string(base))
+	}
+
+	subtype, _ := fd["Subtype"].(name)
+	if subtype == "Type0" {
+		f.codeBytes = 2
+	}
+	if subtype == "Type3" {
+		if m, ok := d.resolve(fd["FontMatrix"]).(array); ok && len(m) > 0 {
+			if v, ok := d.resolve(m[0]).(float64); ok {
+				f.widthScale = v
+			}
+		}
+	}
+
+	if fc, ok := d.resolve(fd["FirstChar"]).(float64); ok {
+		f.firstChar = int(fc)
+	}
+	if ws, ok := d.resolve(fd["Widths"]).(array); ok {
+		for _, w := range ws {
+			v, _ := d.resolve(w).(float64)
+			f.widths = append(f.widths, v)
+		}
+	}
+
+	if s, ok := d.resolve(fd["ToUnicode"]).(*stream); ok {
+		if data, err := d.decodeStream(s); err == nil {
+			f.toUnicode = parseCMap(data)
+		}
+	}
+
+	switch enc := d.resolve(fd["Encoding"]).(type) {
+	case name:
+		f.setBaseEncoding(enc)
+	case dict:
+		if base, ok := d.resolve(enc["BaseEncoding"]).(name); ok {
+			f.setBaseEncoding(base)
+		} else {
+			f.setBaseEncoding("StandardEncoding")
+		}
+		if diffs, ok := d.resolve(enc["Differences"]).(array); ok {
+			code := 0
+			for _, item := range diffs {
+				switch v := d.resolve(item).(type) {
+				case float64:
+					code = int(v)
+				case name:
+					if code >= 0 && code < 256 {
+						f.encoding[code] = string(v)
+					}
+					code++
+				}
+			}
+		}
+	default:
+		f.builtin = true
+	}
+
+	return f
+}
+
+// setBaseEncoding заполняет таблицу ASCII-частью стандартных кодировок.
+// Отличия Standard/WinAnsi/MacRoman за пределами ASCII для резюме несущественны.
+func (f *font) setBaseEncoding(base name) {
+	f.hasEncTbl = true
+	for c := 32; c < 127; c++ {
+		f.encoding[c] = asciiGlyphNames[c-32]
+	}
+	if base == "StandardEncoding" {
+		f.encoding['\''] = "quoteright"
+		f.encoding['`'] = "quoteleft"
+	}
+}
+
+// width возвращает ширину глифа в единицах текстового пространства (до Tfs).
+func (f *font) width(code int) float64 {
+	i := code - f.firstChar
+	if i >= 0 && i < len(f.widths) {
+		return f.widths[i] * f.widthScale
+	}
+	return 0.5
+}
+
+// decode переводит код символа в текст. В строгом режиме лигатуры остаются
+// символами U+FB00..U+FB04, а коды без известного отображения превращаются в
+// U+FFFD — так текст видит простая ATS. В мягком режиме (expand) лигатуры
+// раскладываются на буквы, а управляющие коды кодировок TeX (OT1/T1)
+// угадываются.
+func (f *font) decode(code int, expand bool) string {
+	if f.toUnicode != nil {
+		if s, ok := f.toUnicode[code]; ok {
+			if expand {
+				return expandLigatures(s)
+			}
+			return s
+		}
+	}
+
+	if f.codeBytes == 1 && code < 256 {
+		if f.hasEncTbl && f.encoding[code] != "" {
+			if s, ok := glyphToUnicode(f.encoding[code]); ok {
+				if expand {
+					return expandLigatures(s)
+				}
+				return s
+			}
+			return "�"
+		}
+		if f.builtin {
+			if code >= 32 && code < 127 {
+				return string(rune(code))
+			}
+			if expand && f.symbolic && code == 0x0F {
+				return "•" // \textbullet в OMS (cmsy)
+			}
+			if expand && !f.symbolic {
+				if s, ok := texBuiltinLigatures[code]; ok {
+					return s
+				}
+			}
+		}
+	}
+	return "�"
+}
+
+// texBuiltinLigatures — лигатуры на управляющих позициях OT1 (0x0B..0x0F)
+// и T1 (0x1B..0x1F) для шрифтов без /Encoding.
+var texBuiltinLigatures = map[int]string{
+	0x0B: "ff", 0x0C: "fi", 0x0D: "fl", 0x0E: "ffi", 0x0F: "ffl",
+	0x1B: "ff", 0x1C: "fi", 0x1D: "fl", 0x1E: "ffi", 0x1F: "ffl",
+}
+
+// isSymbolFont распознаёт символьные шрифты TeX, где управляющие коды
+// означают не лигатуры, а математические знаки.
+func isSymbolFont(baseFont string) bool {
+	// Подмножество шрифта имеет префикс вида "ABCDEF+".
+	if _, after, ok := strings.Cut(baseFont, "+"); ok {
+		baseFont = after
+	}
+	baseFont = strings.ToLower(baseFont)
+	for _, prefix := range []string{"cmsy", "cmmi", "cmex", "msam", "msbm", "lasy", "wasy", "symbol", "zapfdingbats", "dingbat"} {
+		if strings.HasPrefix(baseFont, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+var ligatureExpander = strings.NewReplacer(
+	"ﬀ", "ff",
+	"ﬁ", "fi",
+	"ﬂ", "fl",
+	"ﬃ", "ffi",
+	"ﬄ", "ffl",
+)
+
+func expandLigatures(s string) string {
+	return ligatureExpander.Replace(s)
+}
+
+// asciiGlyphNames — имена глифов для кодов 32..126.
+var asciiGlyphNames = [95]string{
+	"space", "exclam", "quotedbl", "numbersign", "dollar", "percent", "ampersand", "quotesingle",
+	"parenleft", "parenright", "asterisk", "plus", "comma", "hyphen", "period", "slash",
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+	"colon", "semicolon", "less", "equal", "greater", "question", "at",
+	"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M",
+	"N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z",
+	"bracketleft", "backslash", "bracketright", "asciicircum", "underscore", "grave",
+	"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m",
+	"n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z",
+	"braceleft", "bar", "braceright", "asciitilde",
+}
+
+// extraGlyphs — распространённые имена глифов вне ASCII (AGL), в том числе
+// те, что использует кодировка T1 (cm-super).
+var extraGlyphs = map[string]string{
+	"quoteleft": "‘", "quoteright": "’", "quotedblleft": "“", "quotedblright": "”",
+	"quotesinglbase": "‚", "quotedblbase": "„", "guilsinglleft": "‹", "guilsinglright": "›",
+	"guillemotleft": "«", "guillemotright": "»", "endash": "–", "emdash": "—",
+	"bullet": "•", "ellipsis": "…", "dagger": "†", "daggerdbl": "‡", "section": "§",
+	"paragraph": "¶", "copyright": "©", "registered": "®", "trademark": "™", "degree": "°",
+	"plusminus": "±", "multiply": "×", "divide": "÷", "minus": "−", "sterling": "£",
+	"yen": "¥", "Euro": "€", "cent": "¢", "perthousand": "‰", "exclamdown": "¡",
+	"questiondown": "¿", "dotlessi": "ı", "dotlessj": "ȷ", "germandbls": "ß",
+	"ae": "æ", "AE": "Æ", "oe": "œ", "OE": "Œ", "oslash": "ø", "Oslash": "Ø",
+	"lslash": "ł", "Lslash": "Ł", "eth": "ð", "Eth": "Ð", "thorn": "þ", "Thorn": "Þ",
+	"ff": "ﬀ", "fi": "ﬁ", "fl": "ﬂ", "ffi": "ﬃ", "ffl": "ﬄ",
+	"visiblespace": "␣", "compwordmark": "", "nbspace": " ", "hyphenchar": "-", "sfthyphen": "-",
+	"grave": "`", "acute": "´", "circumflex": "ˆ", "tilde": "˜", "dieresis": "¨",
+	"ring": "˚", "cedilla": "¸", "caron": "ˇ", "breve": "˘", "macron": "¯",
+	"dotaccent": "˙", "hungarumlaut": "˝", "ogonek": "˛",
+}
+
+// accentedGlyphs строит имена вида eacute, Odieresis и т.п. для Latin-1
+// и Latin Extended-A по таблице комбинаций.
+var accentedGlyphs = func() map[string]string {
+	m := make(map[string]string)
+	add := func(names, chars string, suffix string) {
+		cs := []rune(chars)
+		for i, base := range names {
+			if i < len(cs) && cs[i] != '_' {
+				m[string(base)+suffix] = string(cs[i])
+			}
+		}
+	}
+	add("AEIOUaeiou", "ÀÈÌÒÙàèìòù", "grave")
+	add("AEIOUYaeiouyCcNnSsZzRrLl", "ÁÉÍÓÚÝáéíóúýĆćŃńŚśŹźŔŕĹĺ", "acute")
+	add("AEIOUaeiouCcGgHhJjSsWwYy", "ÂÊÎÔÛâêîôûĈĉĜĝĤĥĴĵŜŝŴŵŶŷ", "circumflex")
+	add("AEIOUaeiouyY", "ÄËÏÖÜäëïöüÿŸ", "dieresis")
+	add("ANOanoIiUu", "ÃÑÕãñõĨĩŨũ", "tilde")
+	add("AaUu", "ÅåŮů", "ring")
+	add("CcSsTt", "ÇçŞşŢţ", "cedilla")
+	add("CcDdEeNnRrSsTtZz", "ČčĎďĚěŇňŘřŠšŤťŽž", "caron")
+	add("AaEeIiOoUu", "ĀāĒēĪīŌōŪū", "macron")
+	add("AaGgUu", "ĂăĞğŬŭ", "breve")
+	add("AaEeIiUu", "ĄąĘęĮįŲų", "ogonek")
+	add("CcEeGgIZz", "ĊċĖėĠġİŻż", "dotaccent")
+	add("OoUu", "ŐőŰű", "hungarumlaut")
+	return m
+}()
+
+// glyphToUnicode переводит имя глифа в текст.
+func glyphToUnicode(glyph string) (string, bool) {
+	if len(glyph) == 1 {
+		return glyph, true
+	}
+	for i, n := range asciiGlyphNames {
+		if n == glyph {
+			return string(rune(32 + i)), true
+		}
+	}
+	if s, ok := extraGlyphs[glyph]; ok {
+		return s, true
+	}
+	if s, ok := accentedGlyphs[glyph]; ok {
+		return s, true
+	}
+	// uniXXXX и uXXXX[XX] из Adobe Glyph List Specification.
+	if strings.HasPrefix(glyph, "uni") && len(glyph) == 7 {
+		if v, err := strconv.ParseUint(glyph[3:], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+	if strings.HasPrefix(glyph, "u") && len(glyph) >= 5 && len(glyph) <= 7 {
+		if v, err := strconv.ParseUint(glyph[1:], 16, 32); err == nil {
+			return string(rune(v)), true
+		}
+	}
+	// Суффиксы вариантов: a.sc, one.oldstyle.
+	if base, _, ok := strings.Cut(glyph, "."); ok && base != "" {
+		return glyphToUnicode(base)
+	}
+	return "", false
+}
+
+// parseCMap разбирает секции bfchar/bfrange потока /ToUnicode.
+func parseCMap(data []byte) map[int]string {
+	out := make(map[int]string)
+	p := newParser(data)
+	var operands []any
+
+	for {
+		v, err := p.next()
+		if err != nil {
+			break
+		}
+		kw, ok := v.(keyword)
+		if !ok {
+			operands = append(operands, v)
+			continue
+		}
+		switch kw {
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					out[bytesToCode(src)] = utf16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := bytesToCode(lo), bytesToCode(hi)
+				if end-start > 0xFFFF {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					base := []rune(utf16BE(dst))
+					if len(base) == 0 {
+						continue
+					}
+					for c := start; c <= end; c++ {
+						r := append([]rune(nil), base...)
+						r[len(r)-1] += rune(c - start)
+						out[c] = string(r)
+					}
+				case array:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && start+j <= end {
+							out[start+j] = utf16BE(s)
+						}
+					}
+				}
+			}
+		}
+		if strings.HasPrefix(string(kw), "begin") || strings.HasPrefix(string(kw), "end") {
+			operands = operands[:0]
+		}
+	}
+	return out
+}
+
+func bytesToCode(b []byte) int {
+	v := 0
+	for _, c := range b {
+		v = v<<8 | int(c)
+	}
+	return v
+}
+
+func utf16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,343 @@
+package pdftext
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// Типы значений PDF после разбора.
+type (
+	name    string
+	keyword string
+	dict    map[name]any
+	array   []any
+	ref     struct{ num, gen int }
+	// pdfString — строка PDF как есть (байты в кодировке шрифта).
+	pdfString []byte
+	// stream — словарь потока и его неразжатые данные.
+	stream struct {
+		dict dict
+		raw  []byte
+	}
+)
+
+// parser — минимальный разборщик синтаксиса PDF (ISO 32000-1, раздел 7.3),
+// достаточный для объектов документа и потоков содержимого страниц.
+type parser struct {
+	buf []byte
+	pos int
+}
+
+func newParser(buf []byte) *parser {
+	return &parser{buf: buf}
+}
+
+func isWhitespace(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ':
+		return true
+	}
+	return false
+}
+
+func isDelimiter(c byte) bool {
+	switch c {
+	case '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.buf)
+}
+
+// skipSpace пропускает пробелы и комментарии.
+func (p *parser) skipSpace() {
+	for !p.eof() {
+		c := p.buf[p.pos]
+		switch {
+		case isWhitespace(c):
+			p.pos++
+		case c == '%':
+			for !p.eof() && p.buf[p.pos] != '\n' && p.buf[p.pos] != '\r' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// next разбирает следующее значение. Операторы и служебные слова
+// (obj, stream, R, Tj, ...) возвращаются как keyword.
+func (p *parser) next() (any, error) {
+	p.skipSpace()
+	if p.eof() {
+		return nil, errEOF
+	}
+
+	c := p.buf[p.pos]
+	switch {
+	case c == '/':
+		return p.readName(), nil
+	case c == '(':
+		return p.readLiteralString()
+	case c == '<':
+		if p.pos+1 < len(p.buf) && p.buf[p.pos+1] == '<' {
+			return p.readDict()
+		}
+		return p.readHexString()
+	case c == '[':
+		return p.readArray()
+	case c == ']' || c == '{' || c == '}':
+		p.pos++
+		return keyword(c), nil
+	case c == '>':
+		if p.pos+1 < len(p.buf) && p.buf[p.pos+1] == '>' {
+			p.pos += 2
+			return keyword(">>"), nil
+		}
+		p.pos++
+		return keyword(">"), nil
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.readNumberOrRef()
+	}
+
+	start := p.pos
+	for !p.eof() && !isWhitespace(p.buf[p.pos]) && !isDelimiter(p.buf[p.pos]) {
+		p.pos++
+	}
+	if p.pos == start {
+		p.pos++
+		return nil, fmt.Errorf("unexpected byte %q at %d", c, start)
+	}
+	switch word := string(p.buf[start:p.pos]); word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return keyword(word), nil
+	}
+}
+
+func (p *parser) readName() name {
+	p.pos++ // '/'
+	var b []byte
+	for !p.eof() && !isWhitespace(p.buf[p.pos]) && !isDelimiter(p.buf[p.pos]) {
+		c := p.buf[p.pos]
+		if c == '#' && p.pos+2 < len(p.buf) {
+			if v, err := strconv.ParseUint(string(p.buf[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		p.pos++
+	}
+	return name(b)
+}
+
+func (p *parser) readLiteralString() (pdfString, error) {
+	p.pos++ // '('
+	var out []byte
+	depth := 1
+	for !p.eof() {
+		c := p.buf[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return out, nil
+			}
+			out = append(out, c)
+		case '\\':
+			if p.eof() {
+				return out, nil
+			}
+			e := p.buf[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if !p.eof() && p.buf[p.pos] == '\n' {
+					p.pos++
+				}
+			case '\n':
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && !p.eof() && p.buf[p.pos] >= '0' && p.buf[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.buf[p.pos]-'0')
+						p.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return nil, fmt.Errorf("unterminated string")
+}
+
+func (p *parser) readHexString() (pdfString, error) {
+	p.pos++ // '<'
+	var digits []byte
+	for !p.eof() && p.buf[p.pos] != '>' {
+		c := p.buf[p.pos]
+		if !isWhitespace(c) {
+			digits = append(digits, c)
+		}
+		p.pos++
+	}
+	if p.eof() {
+		return nil, fmt.Errorf("unterminated hex string")
+	}
+	p.pos++ // '>'
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, 0, len(digits)/2)
+	for i := 0; i < len(digits); i += 2 {
+		v, err := strconv.ParseUint(string(digits[i:i+2]), 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("bad hex string: %w", err)
+		}
+		out = append(out, byte(v))
+	}
+	return out, nil
+}
+
+func (p *parser) readArray() (array, error) {
+	p.pos++ // '['
+	var out array
+	for {
+		v, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if k, ok := v.(keyword); ok && k == "]" {
+			return out, nil
+		}
+		out = append(out, v)
+	}
+}
+
+func (p *parser) readDict() (dict, error) {
+	p.pos += 2 // '<<'
+	out := make(dict)
+	for {
+		k, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		if kw, ok := k.(keyword); ok && kw == ">>" {
+			return out, nil
+		}
+		key, ok := k.(name)
+		if !ok {
+			return nil, fmt.Errorf("dictionary key is %T, not a name", k)
+		}
+		v, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		out[key] = v
+	}
+}
+
+// readNumberOrRef читает число; целое, за которым следуют "<int> R",
+// превращается в ссылку на объект.
+func (p *parser) readNumberOrRef() (any, error) {
+	n, isInt, err := p.readNumber()
+	if err != nil || !isInt {
+		return n, err
+	}
+
+	save := p.pos
+	p.skipSpace()
+	if !p.eof() && p.buf[p.pos] >= '0' && p.buf[p.pos] <= '9' {
+		gen, genInt, err := p.readNumber()
+		if err == nil && genInt {
+			p.skipSpace()
+			if !p.eof() && p.buf[p.pos] == 'R' && (p.pos+1 == len(p.buf) || isWhitespace(p.buf[p.pos+1]) || isDelimiter(p.buf[p.pos+1])) {
+				p.pos++
+				return ref{num: int(n), gen: int(gen)}, nil
+			}
+		}
+	}
+	p.pos = save
+	return n, nil
+}
+
+func (p *parser) readNumber() (float64, bool, error) {
+	start := p.pos
+	isInt := true
+	for !p.eof() {
+		c := p.buf[p.pos]
+		if c == '.' {
+			isInt = false
+		} else if !(c >= '0' && c <= '9') && !(p.pos == start && (c == '+' || c == '-')) {
+			break
+		}
+		p.pos++
+	}
+	v, err := strconv.ParseFloat(string(p.buf[start:p.pos]), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("bad number %q", p.buf[start:p.pos])
+	}
+	return v, isInt, nil
+}
+
+// readStreamData читает данные потока сразу после ключевого слова stream.
+// Длина берётся из /Length, если она задана числом и данные заканчиваются
+// endstream; иначе — до ближайшего endstream.
+func (p *parser) readStreamData(d dict) []byte {
+	if !p.eof() && p.buf[p.pos] == '\r' {
+		p.pos++
+	}
+	if !p.eof() && p.buf[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+
+	if l, ok := d["Length"].(float64); ok {
+		end := start + int(l)
+		if end <= len(p.buf) {
+			rest := bytes.TrimLeft(p.buf[end:], "\r\n \t")
+			if bytes.HasPrefix(rest, []byte("endstream")) {
+				p.pos = end
+				return p.buf[start:end]
+			}
+		}
+	}
+
+	idx := bytes.Index(p.buf[start:], []byte("endstream"))
+	if idx < 0 {
+		p.pos = len(p.buf)
+		return p.buf[start:]
+	}
+	p.pos = start + idx
+	return bytes.TrimRight(p.buf[start:start+idx], "\r\n")
+}
@@ -0,0 +1,193 @@
+// Package rpc — gRPC-API latex-service (renderpb.RenderService): тот же
+// рендер, что /internal/v1/render*, но фото передаётся байтами, а PDF —
+// частями в потоке ответа.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"latex_service/internal/ats"
+	"latex_service/internal/latex"
+
+	contract "resume_contract"
+	"resume_contract/renderpb"
+	"resume_contract/requestid"
+)
+
+// chunkSize — размер части PDF в потоке ответа.
+const chunkSize = 64 * 1024
+
+// Server реализует renderpb.RenderService поверх latex.Renderer.
+type Server struct {
+	renderpb.UnimplementedRenderServiceServer
+
+	renderer *latex.Renderer
+	logger   *slog.Logger
+}
+
+// NewServer создаёт grpc.Server с RenderService и стандартным сервисом
+// проверки здоровья grpc.health.v1, по которому балансирует клиент.
+// Идентификатор запроса из метаданных x-request-id попадает в контекст
+// вызова и в логи. Сообщение больше maxRequestSize байт (как у тела
+// HTTP-запроса рендера; <= 0 — contract.DefaultMaxRequestSize) grpc-go
+// отклоняет с RESOURCE_EXHAUSTED.
+func NewServer(renderer *latex.Renderer, maxRequestSize int64, logger *slog.Logger) *grpc.Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if maxRequestSize <= 0 {
+		maxRequestSize = contract.DefaultMaxRequestSize
+	}
+
+	s := &Server{renderer: renderer, logger: logger}
+	gs := grpc.NewServer(
+		grpc.MaxRecvMsgSize(int(maxRequestSize)),
+		grpc.ChainUnaryInterceptor(requestid.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(requestid.StreamServerInterceptor()),
+	)
+	renderpb.RegisterRenderServiceServer(gs, s)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus(renderpb.RenderService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(gs, hs)
+
+	return gs
+}
+
+// Render рендерит резюме и отправляет PDF частями; при необходимости перед
+// ними идут события этапов и отчёт ATS-проверки.
+func (s *Server) Render(req *renderpb.RenderRequest, stream renderpb.RenderService_RenderServer) error {
+	if req.GetResume() == nil {
+		return renderpb.Error(codes.InvalidArgument, "invalid_request", "resume is required")
+	}
+	if req.GetProgress() && req.GetCheckAts() {
+		return renderpb.Error(codes.InvalidArgument, "invalid_request", "progress cannot be combined with check_ats")
+	}
+
+	payload := renderpb.ResumeToContract(req.GetResume())
+	if err := contract.CheckSchemaVersion(payload.SchemaVersion); err != nil {
+		return renderpb.Error(codes.InvalidArgument, contract.ErrCodeUnsupportedSchemaVersion, err.Error())
+	}
+
+	ctx := stream.Context()
+	if req.GetProgress() {
+		// события проходов приходят из горутин, читающих вывод latexmk
+		var mu sync.Mutex
+		ctx = latex.WithProgress(ctx, func(ev contract.RenderEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			err := stream.Send(&renderpb.RenderResponse{Payload: &renderpb.RenderResponse_Progress{Progress: &renderpb.Progress{
+				Stage:     ev.Stage,
+				Pass:      int32(ev.Pass),
+				MaxPasses: int32(ev.MaxPasses),
+			}}})
+			if err != nil {
+				s.logger.WarnContext(ctx, "failed to send progress event", "error", err)
+			}
+		})
+	}
+
+	pdf, err := s.renderer.RenderFile(ctx, payload)
+	if err != nil {
+		return s.renderError(stream.Context(), err)
+	}
+	defer pdf.Close()
+
+	if req.GetCheckAts() {
+		data, err := io.ReadAll(pdf)
+		if err != nil {
+			return s.renderError(stream.Context(), err)
+		}
+		report := ats.Check(payload, data)
+		err = stream.Send(&renderpb.RenderResponse{Payload: &renderpb.RenderResponse_AtsReport{AtsReport: renderpb.ATSReportFromContract(report)}})
+		if err != nil {
+			return err
+		}
+		if _, err := pdf.Seek(0, io.SeekStart); err != nil {
+			return s.renderError(stream.Context(), err)
+		}
+	}
+
+	return sendPDF(stream, pdf)
+}
+
+// RenderDiff рендерит визуальный diff и отправляет PDF частями.
+func (s *Server) RenderDiff(req *renderpb.RenderDiffRequest, stream renderpb.RenderService_RenderDiffServer) error {
+	if req.GetDocument() == nil {
+		return renderpb.Error(codes.InvalidArgument, "invalid_request", "document is required")
+	}
+
+	doc := renderpb.DiffToContract(req.GetDocument())
+	if err := contract.CheckSchemaVersion(doc.SchemaVersion); err != nil {
+		return renderpb.Error(codes.InvalidArgument, contract.ErrCodeUnsupportedSchemaVersion, err.Error())
+	}
+
+	pdf, err := s.renderer.RenderDiffFile(stream.Context(), doc)
+	if err != nil {
+		return s.renderError(stream.Context(), err)
+	}
+	defer pdf.Close()
+
+	return sendPDF(stream, pdf)
+}
+
+// Status возвращает размер пула latexmk и число занятых слотов.
+func (s *Server) Status(context.Context, *renderpb.StatusRequest) (*renderpb.StatusResponse, error) {
+	return &renderpb.StatusResponse{
+		Workers: int32(s.renderer.Workers()),
+		Busy:    int32(s.renderer.Busy()),
+	}, nil
+}
+
+// renderError переводит ошибку рендера в статус gRPC. Если истёк дедлайн
+// или клиент отменил вызов, latexmk прерван именно поэтому, и статус
+// должен это отражать.
+func (s *Server) renderError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		s.logger.WarnContext(ctx, "render interrupted", "error", err)
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	s.logger.ErrorContext(ctx, "failed to render PDF", "error", err)
+	if errors.Is(err, latex.ErrOutputTooLarge) {
+		return renderpb.Error(codes.ResourceExhausted, "output_too_large", "Generated PDF exceeds the size limit")
+	}
+	return renderpb.Error(codes.Internal, "render_failed", "Failed to render PDF")
+}
+
+// sendPDF отправляет PDF из файла частями по chunkSize; в первой части —
+// полный размер, последняя помечена last.
+func sendPDF(stream grpc.ServerStream, pdf *latex.PDF) error {
+	buf := make([]byte, chunkSize)
+	var sent int64
+	for first := true; ; first = false {
+		n, err := io.ReadFull(pdf, buf)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return status.Errorf(codes.Internal, "read pdf: %v", err)
+		}
+		sent += int64(n)
+		chunk := &renderpb.PdfChunk{Data: buf[:n], Last: sent >= pdf.Size}
+		if first {
+			chunk.Size = pdf.Size
+		}
+		if err := stream.SendMsg(&renderpb.RenderResponse{Payload: &renderpb.RenderResponse_Chunk{Chunk: chunk}}); err != nil {
+			return err
+		}
+		if chunk.Last {
+			return nil
+		}
+		if n == 0 {
+			return status.Errorf(codes.Internal, "read pdf: file is shorter than %d bytes", pdf.Size)
+		}
+	}
+}